@@ -5,6 +5,8 @@
 //
 // Server parameters
 //
+//  -db.driver string
+//        database driver, postgres or memory (in memory demo mode without postgres). default is postgres
 //  -db.name string
 //        sportos database name
 //  -db.host string
@@ -15,6 +17,8 @@
 //        sportos database user
 //  -db.pass string
 //        database password
+//  -db.migrate boolean
+//        apply pending schema migrations (sportos/repo/migrations) at startup. default is true
 //  -api.pub.port string
 //        public API service port. default is 8080
 //  -api.bo.port string
@@ -33,6 +37,44 @@
 //        scheduler interval (in miliseconds)
 // 	-business.webhookNotificationsEndpoint
 //        Sportos endpoint for receiving webhook notifications
+//  -jwt.keys string
+//        comma separated list of jwt signing keys kid:alg:base64(secret), alg is HS256 (secret at least 32 bytes)
+//        or EdDSA (secret is 32 byte ed25519 seed). All listed keys are accepted for verification.
+//        If empty random key is generated on start
+//  -jwt.kid string
+//        kid of key used for signing new tokens, default is first key from jwt.keys
+//  -jwt.access.ttl duration
+//        access token validity. default is 20m
+//  -jwt.refresh.ttl duration
+//        refresh token validity. default is 4h
+//  -mail.driver string
+//        mail delivery driver: smtp, file (writes .eml files to mail.dir) or memory. default is file
+//  -mail.from string
+//        sender address of all mails
+//  -mail.app.url string
+//        frontend url used in links sent by mail. default is https://localhost:4200
+//  -mail.smtp.host, -mail.smtp.port, -mail.smtp.user, -mail.smtp.pass string
+//        smtp server used by smtp driver. default port is 587
+//  -mail.dir string
+//        directory where file driver writes mails. default is mails
+//  -mail.outbox.interval duration
+//        how often mail outbox is checked for pending mails. default is 5s
+//  -mail.outbox.attempts int
+//        how many times sending is attempted before mail is marked as FAILED. default is 8
+//  -events.interval duration
+//        how often outbox is checked for pending domain events. default is 1s
+//  -events.attempts int
+//        how many times dispatch is attempted before domain event is marked as FAILED. default is 8
+//  -webhook.interval duration
+//        how often pending webhook deliveries are sent. default is 5s
+//  -webhook.attempts int
+//        how many times delivery is attempted before it is marked as FAILED. default is 10
+//  -webhook.timeout duration
+//        how long to wait for response of webhook. default is 10s
+//  -results.window duration
+//        how long other side has to confirm or dispute submitted match result. default is 48h
+//  -results.interval duration
+//        how often match results with passed deadline are auto confirmed. default is 1m
 //	-audit.enable boolean
 //		  should audit table be filled when application start. default is false
 //  Example: .\sportos.exe -'db.name' sportos -'db.host' localhost -'db.port' 5432 -'db.user' postgres -'db.pass' secret -'scheduler.enable' true -'scheduler.interval' 1000 -'audit.enable' true -'business.webhookNotificationsEndpoint' https://sportos-notifications.fincoreltd.rs
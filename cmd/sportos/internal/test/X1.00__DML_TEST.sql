@@ -3,9 +3,9 @@ INSERT INTO "user" VALUES ('test', 'test1@test.com', 1, 'player', 'eqUybZ4Dnmtem
 INSERT INTO "user" VALUES ('google_107022849182831576055', 'google_andrija.novakovic.1998@gmail.com', 1, 'player', '', 'L4EMrOKyrNZIwqRysNX7JRZT9gW0NpIi56gT3cvKD0A=', '2023-08-26 15:41:09.469352+00', '2023-08-26 16:13:01.070685+00', '2023-08-26 11:26:04.519908+00', 'system', '2023-08-26 15:21:09.469352+00', 'system', NULL, NULL);
 INSERT INTO "user" VALUES ('andros', 'andrija.novakovic.1998@gmail.com', 9, 'coach', 'xohH_bV_vI9b__FOJ5cIt9ao2mdRi58CCp6_EHg1qrQ=', NULL, NULL, NULL, '2023-08-26 15:43:36.201022+00', 'system', NULL, NULL, NULL, NULL);
 
-INSERT INTO coach VALUES ('andros', 'Andrija Novakovic', 'Belgrade', 'Table tennis', NULL, NULL, '2023-08-26 15:43:36.212245+00', 'system', NULL, NULL, NULL, NULL);
+INSERT INTO coach VALUES ('andros', 'Andrija Novakovic', 'Belgrade', 'Table tennis', NULL, '2023-08-26 15:43:36.212245+00', 'system', NULL, NULL, NULL, NULL);
 
-INSERT INTO place VALUES ('facebook_2476912072449980', 'Андрија Новаковић', 'Belgrade', 'Table tennis', NULL, NULL, '2023-08-26 11:36:09.946896+00', 'system', '2023-08-26 12:12:52.696817+00', 'system', NULL, NULL);
+INSERT INTO place VALUES ('facebook_2476912072449980', 'Андрија Новаковић', 'Belgrade', 'Table tennis', NULL, '2023-08-26 11:36:09.946896+00', 'system', '2023-08-26 12:12:52.696817+00', 'system', NULL, NULL);
 
 INSERT INTO player VALUES ('google_107022849182831576055', 'Andrija Novaković', 'Belgrade', NULL, NULL, '2023-08-26 11:26:04.530313+00', 'system', '2023-08-26 12:16:54.997987+00', 'system', NULL, NULL);
 INSERT INTO player VALUES ('test', 'Test Testic', 'Belgrade', NULL, NULL, '2023-08-26 11:37:07.418552+00', 'system', '2023-08-26 12:16:55.011192+00', 'system', NULL, NULL);
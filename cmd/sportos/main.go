@@ -3,10 +3,20 @@ package main
 import (
 	L "backend/internal/logging"
 	"backend/sportos/api"
+	"backend/sportos/auth"
+	"backend/sportos/events"
+	"backend/sportos/mail"
+	"backend/sportos/repo/crud"
+	"backend/sportos/repo/migrations"
+	"backend/sportos/results"
+	"backend/sportos/webhook"
+	"context"
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"text/tabwriter"
 )
 
 var CLAPIPort = flag.String("api.pub.port", ":8080", "Public API service port")
@@ -14,11 +24,39 @@ var BOAPIPort = flag.String("api.bo.port", ":8081", "Backoffice API service port
 var LOAPIPort = flag.String("api.lo.port", ":8082", "Login service port")
 var corsEnable = flag.Bool("cors.enable", false, "Enable CORS headers")
 
+var dbDriver = flag.String("db.driver", api.DB_DRIVER_POSTGRES, "database driver: postgres or memory (demo mode without postgres, data is lost on restart)")
 var dbName = flag.String("db.name", "", "name of database")
 var dbHost = flag.String("db.host", "", "host where db is located")
 var dbPort = flag.String("db.port", "", "port on whitch database is listening")
 var dbUser = flag.String("db.user", "", "db user")
 var dbPass = flag.String("db.pass", "", "password for database")
+var dbMigrate = flag.Bool("db.migrate", true, "apply pending schema migrations at startup")
+
+var jwtKeys = flag.String("jwt.keys", "", "comma separated list of jwt signing keys in format kid:alg:base64(secret), alg is HS256 or EdDSA")
+var jwtKid = flag.String("jwt.kid", "", "kid of key used for signing new tokens, first key from jwt.keys if empty")
+var jwtAccessTTL = flag.Duration("jwt.access.ttl", auth.DEFAULT_ACCESS_TTL, "access token validity")
+var jwtRefreshTTL = flag.Duration("jwt.refresh.ttl", auth.DEFAULT_REFRESH_TTL, "refresh token validity")
+
+var mailDriver = flag.String("mail.driver", "file", "mail delivery driver: smtp, file or memory")
+var mailFrom = flag.String("mail.from", "", "sender address of all mails")
+var mailAppUrl = flag.String("mail.app.url", "https://localhost:4200", "frontend url used in links sent by mail")
+var mailSMTPHost = flag.String("mail.smtp.host", "", "smtp server host")
+var mailSMTPPort = flag.String("mail.smtp.port", "587", "smtp server port")
+var mailSMTPUser = flag.String("mail.smtp.user", "", "smtp user")
+var mailSMTPPass = flag.String("mail.smtp.pass", "", "smtp password")
+var mailDir = flag.String("mail.dir", "mails", "directory where file driver writes mails")
+var mailOutboxInterval = flag.Duration("mail.outbox.interval", mail.DEFAULT_OUTBOX_INTERVAL, "how often mail outbox is checked for pending mails")
+var mailOutboxAttempts = flag.Int("mail.outbox.attempts", mail.DEFAULT_OUTBOX_MAX_ATTEMPTS, "how many times sending of mail is attempted before it is marked as failed")
+
+var eventsInterval = flag.Duration("events.interval", events.DEFAULT_DISPATCH_INTERVAL, "how often outbox is checked for pending domain events")
+var eventsAttempts = flag.Int("events.attempts", events.DEFAULT_DISPATCH_MAX_ATTEMPTS, "how many times dispatch of domain event is attempted before it is marked as failed")
+
+var webhookInterval = flag.Duration("webhook.interval", webhook.DEFAULT_WORKER_INTERVAL, "how often pending webhook deliveries are sent")
+var webhookAttempts = flag.Int("webhook.attempts", webhook.DEFAULT_WORKER_MAX_ATTEMPTS, "how many times webhook delivery is attempted before it is marked as failed")
+var webhookTimeout = flag.Duration("webhook.timeout", webhook.DEFAULT_TIMEOUT, "how long to wait for response of webhook")
+
+var resultsWindow = flag.Duration("results.window", results.DEFAULT_CONFIRM_WINDOW, "how long other side has to confirm or dispute submitted match result")
+var resultsInterval = flag.Duration("results.interval", results.DEFAULT_WORKER_INTERVAL, "how often match results with passed deadline are auto confirmed")
 
 var auditEnable = flag.Bool("audit.enable", false, "should audit table start logging when applications starts")
 
@@ -31,10 +69,41 @@ func main() {
 	defer L.L.Sync()
 
 	L.L.Info("Server is starting...")
-	L.L.Info("db params", L.String("db.name", *dbName), L.String("db.host", *dbHost), L.String("db.port", *dbPort), L.String("db.user", *dbUser), L.String("db.pass", *dbPass))
+	L.L.Info("db params", L.String("db.driver", *dbDriver), L.String("db.name", *dbName), L.String("db.host", *dbHost), L.String("db.port", *dbPort), L.String("db.user", *dbUser), L.String("db.pass", *dbPass))
 	L.L.Info("audit params", L.Bool("audit.enable", *auditEnable))
 
-	s.Init(*CLAPIPort, *BOAPIPort, *LOAPIPort, *corsEnable, *dbName, *dbHost, *dbPort, *dbUser, *dbPass, *auditEnable)
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(flag.Arg(1), flag.Arg(2)); err != nil {
+			L.L.Fatal("Migration failed", L.Error(err))
+		}
+		return
+	}
+
+	if err := auth.Init(*jwtKeys, *jwtKid, *jwtAccessTTL, *jwtRefreshTTL); err != nil {
+		L.L.Fatal("jwt keys are not configured correctly", L.Error(err))
+	}
+
+	mailConfig := mail.Config{
+		Driver:            mail.Driver(*mailDriver),
+		From:              *mailFrom,
+		AppUrl:            *mailAppUrl,
+		SMTPHost:          *mailSMTPHost,
+		SMTPPort:          *mailSMTPPort,
+		SMTPUser:          *mailSMTPUser,
+		SMTPPass:          *mailSMTPPass,
+		Dir:               *mailDir,
+		OutboxInterval:    *mailOutboxInterval,
+		OutboxMaxAttempts: *mailOutboxAttempts,
+	}
+	if err := mail.Init(mailConfig); err != nil {
+		L.L.Fatal("mail is not configured correctly", L.Error(err))
+	}
+
+	events.Init(events.Config{Interval: *eventsInterval, MaxAttempts: *eventsAttempts})
+	webhook.Init(webhook.Config{Interval: *webhookInterval, MaxAttempts: *webhookAttempts, Timeout: *webhookTimeout})
+	results.Init(results.Config{Window: *resultsWindow, Interval: *resultsInterval})
+
+	s.Init(*CLAPIPort, *BOAPIPort, *LOAPIPort, *corsEnable, *dbDriver, *dbName, *dbHost, *dbPort, *dbUser, *dbPass, *dbMigrate, *auditEnable)
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
@@ -44,3 +113,73 @@ func main() {
 	//Graceful shutdown
 	s.Stop()
 }
+
+// migrate runs migrate subcommand: sportos [flags] migrate up|down|status|baseline [version]
+func migrate(command, version string) error {
+	if *dbDriver != api.DB_DRIVER_POSTGRES {
+		return fmt.Errorf("migrations are supported only for %s driver", api.DB_DRIVER_POSTGRES)
+	}
+	dbConnection := crud.DBConnection{
+		DBName:   *dbName,
+		Host:     *dbHost,
+		Port:     *dbPort,
+		User:     *dbUser,
+		Password: *dbPass,
+	}
+	db, err := dbConnection.Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	switch command {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migrations\n", count)
+	case "down":
+		version, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("undone migration %s\n", version)
+	case "baseline":
+		if version == "" {
+			version = migrations.BASELINE_VERSION
+		}
+		count, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("recorded %d migrations up to %s as applied\n", count, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED AT\tCHECKSUM")
+		for _, st := range statuses {
+			appliedAt, checksum := "pending", ""
+			if st.AppliedAt != nil {
+				appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+				checksum = "ok"
+				if !st.ChecksumOk {
+					checksum = "changed"
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.Version, st.Description, appliedAt, checksum)
+		}
+		w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q, use up, down, status or baseline", command)
+	}
+	return nil
+}
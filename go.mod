@@ -9,7 +9,11 @@ require (
 	github.com/rs/xid v1.5.0
 	github.com/shopspring/decimal v1.3.1
 	go.uber.org/zap v1.25.0
+	golang.org/x/crypto v0.14.0
 	google.golang.org/grpc v1.57.0
 )
 
-require go.uber.org/multierr v1.10.0 // indirect
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+)
@@ -0,0 +1,59 @@
+// Package periodic runs batches of background workers on interval and computes backoff of their retries.
+package periodic
+
+import (
+	L "backend/internal/logging"
+	"context"
+	"sync"
+	"time"
+)
+
+// Runner calls batch function of worker on interval, zero value is ready to use
+type Runner struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Start runs batch every interval in background until Stop is called, error of batch is logged with name
+func (r *Runner) Start(name string, interval time.Duration, batch func(ctx context.Context) (int, error)) {
+	r.stop = make(chan struct{})
+	r.wg.Add(1)
+	go func(stop chan struct{}) {
+		defer r.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := batch(context.Background()); err != nil {
+					L.L.Error(name, L.Error(err))
+				}
+			}
+		}
+	}(r.stop)
+}
+
+// Stop waits for batch in progress to finish
+func (r *Runner) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	r.wg.Wait()
+	r.stop = nil
+}
+
+// Backoff returns delay before next retry after attempts failed attempts,
+// it starts with base and is doubled after every attempt up to max
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
@@ -0,0 +1,59 @@
+package periodic
+
+import (
+	L "backend/internal/logging"
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts, 30*time.Second, time.Hour); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRunnerRunsBatchUntilStopped(t *testing.T) {
+	var calls int32
+	r := Runner{}
+	r.Start("test", time.Millisecond, func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return 0, errors.New("failed batch doesn't stop runner")
+	})
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&calls) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected batch to run repeatedly, it ran %d times", atomic.LoadInt32(&calls))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	r.Stop()
+	stopped := atomic.LoadInt32(&calls)
+	time.Sleep(10 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != stopped {
+		t.Errorf("batch ran %d times after Stop", got-stopped)
+	}
+	// second Stop is no-op
+	r.Stop()
+}
@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	apitest.Main(m)
+}
+
+func TestGetRoutesRespond(t *testing.T) {
+	h := apitest.New(t)
+	player := h.Player()
+
+	routes := []struct {
+		sub   DR.SubServer
+		hn    string
+		query url.Values
+	}{
+		{DR.SUB_LO, DA.HN_SPORTS, nil},
+		{DR.SUB_CL, DA.HN_TOURNAMENTS, nil},
+		{DR.SUB_CL, DA.HN_MATCHES, url.Values{"playerId": {apitest.FIXTURE_PLAYER}}},
+		{DR.SUB_CL, DA.HN_PRACTICES, nil},
+		{DR.SUB_CL, DA.HN_PLACES, nil},
+		{DR.SUB_CL, DA.HN_COACHES, nil},
+		{DR.SUB_CL, DA.HN_USERPOSTS, nil},
+		{DR.SUB_CL, DA.HN_STATS, url.Values{"sport": {"Table tennis"}}},
+		{DR.SUB_CL, DA.HN_STATS_ANALYTICS, url.Values{"sport": {"Table tennis"}, "from": {"2024-01-01"}}},
+		{DR.SUB_CL, DA.HN_LEADERBOARD, url.Values{"sport": {"Tennis"}}},
+		{DR.SUB_CL, DA.HN_LEADERBOARDS, url.Values{"sport": {"Tennis"}, "city": {"Belgrade"}, "by": {"rating"}}},
+		{DR.SUB_CL, DA.HN_TEAMS, nil},
+		{DR.SUB_CL, DA.HN_REVIEWS, url.Values{"id": {apitest.FIXTURE_COACH}}},
+		{DR.SUB_CL, "/name/" + apitest.FIXTURE_PLAYER, nil},
+	}
+	for _, route := range routes {
+		res := player.Get(route.sub, route.hn, route.query)
+		if res.Code != http.StatusOK {
+			t.Errorf("GET %s: expected 200, got %d: %s", route.hn, res.Code, res.Body)
+		}
+	}
+}
+
+// TestWriteRoutesRespondInMemory creates, changes and deletes entities through write routes of every sub server
+func TestWriteRoutesRespondInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	player, second, coach, place := h.Player(), h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER), h.Coach(), h.Place()
+	admin := h.As("admin", DR.UT_ADMIN)
+	start := time.Now().UTC().Truncate(time.Hour).Add(72 * time.Hour)
+
+	expectOK := func(res apitest.Response, method, hn string, v interface{}) {
+		t.Helper()
+		if res.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected 200, got %d: %s", method, hn, res.Code, res.Body)
+		}
+		if v != nil {
+			res.Decode(t, v)
+		}
+	}
+	write := func(c *apitest.Client, sub DR.SubServer, method, hn string, body interface{}, v interface{}) {
+		t.Helper()
+		expectOK(c.Do(sub, method, hn, body), method, hn, v)
+	}
+
+	write(place, DR.SUB_CL, http.MethodPut, DA.HN_SCHEDULE, DR.Schedule{
+		Hours:       []DR.OpeningHours{{Weekday: start.Weekday(), Open: "00:00", Close: "23:59"}},
+		SlotMinutes: 60,
+	}, nil)
+
+	var match DR.Match
+	write(player, DR.SUB_CL, http.MethodPost, DA.HN_MATCHES, map[string]interface{}{
+		"startTime": start, "placeId": apitest.FIXTURE_PLACE, "players": apitest.FIXTURE_PLAYER, "sport": "Table tennis",
+	}, &match)
+	write(player, DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "player": apitest.FIXTURE_SECOND_PLAYER}, nil)
+	write(player, DR.SUB_CL, http.MethodDelete, DA.HN_MATCHES+"?id="+match.MatchId, nil, nil)
+
+	var practice DR.Practice
+	write(player, DR.SUB_CL, http.MethodPost, DA.HN_PRACTICES, map[string]interface{}{
+		"startTime": start, "coachId": apitest.FIXTURE_COACH, "sport": "Table tennis",
+	}, &practice)
+	write(coach, DR.SUB_CL, http.MethodPatch, DA.HN_PRACTICES, map[string]interface{}{"id": practice.PracticeId, "status": string(DR.PS_ACCEPTED)}, nil)
+	write(player, DR.SUB_CL, http.MethodDelete, DA.HN_PRACTICES+"?id="+practice.PracticeId, nil, nil)
+
+	var team DR.Team
+	write(player, DR.SUB_CL, http.MethodPost, DA.HN_TEAMS, map[string]interface{}{"name": "Dunkers", "sport": "Basketball"}, &team)
+	write(player, DR.SUB_CL, http.MethodPatch, DA.HN_TEAMS, map[string]interface{}{"id": team.TeamId, "player": apitest.FIXTURE_SECOND_PLAYER}, nil)
+	write(player, DR.SUB_CL, http.MethodDelete, DA.HN_TEAMS+"?id="+team.TeamId, nil, nil)
+	write(player, DR.SUB_CL, http.MethodPost, DA.HN_TEAMS, map[string]interface{}{"name": "Smashers", "sport": "Table tennis"}, &team)
+
+	write(second, DR.SUB_CL, http.MethodPatch, DA.HN_REVIEWS, map[string]interface{}{"id": apitest.FIXTURE_COACH, "comment": "Great coach", "grade": 5}, nil)
+	write(player, DR.SUB_CL, http.MethodPatch, DA.HN_NOTIFICATIONS, map[string]interface{}{"all": true}, nil)
+
+	var event DR.Event
+	write(place, DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENTS, map[string]interface{}{"startTime": start, "name": "Open"}, &event)
+	write(player, DR.SUB_CL, http.MethodPatch, DA.HN_TOURNAMENTS, map[string]interface{}{"id": event.EventId, "team": team.TeamId}, nil)
+	write(place, DR.SUB_CL, http.MethodDelete, DA.HN_TOURNAMENTS+"?id="+event.EventId, nil, nil)
+
+	var webhook DA.Webhook
+	write(admin, DR.SUB_BO, http.MethodPost, DA.HN_WEBHOOKS, map[string]interface{}{"placeId": apitest.FIXTURE_PLACE, "url": "https://93.184.216.34/hooks"}, &webhook)
+	write(admin, DR.SUB_BO, http.MethodPatch, DA.HN_WEBHOOKS, map[string]interface{}{"id": webhook.Id, "active": false}, nil)
+	write(admin, DR.SUB_BO, http.MethodDelete, DA.HN_WEBHOOKS+"?id="+webhook.Id, nil, nil)
+
+	write(second, DR.SUB_CL, http.MethodDelete, DA.HN_USER, nil, nil)
+}
+
+func TestTokenIsRequired(t *testing.T) {
+	h := apitest.New(t)
+
+	res := h.Anonymous().Get(DR.SUB_CL, DA.HN_TOURNAMENTS, nil)
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without token, got %d", res.Code)
+	}
+}
+
+func TestRequiredRolesAreEnforced(t *testing.T) {
+	h := apitest.New(t)
+
+	for _, c := range []*apitest.Client{h.Player(), h.Coach()} {
+		res := c.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": "1000000000"})
+		if res.Code != http.StatusForbidden {
+			t.Errorf("expected 403 for tournament round, got %d: %s", res.Code, res.Body)
+		}
+		res = c.Do(DR.SUB_CL, http.MethodDelete, DA.HN_TOURNAMENTS+"?id=1000000000", nil)
+		if res.Code != http.StatusForbidden {
+			t.Errorf("expected 403 for tournament delete, got %d: %s", res.Code, res.Body)
+		}
+	}
+	res := h.Place().Do(DR.SUB_BO, http.MethodGet, DA.HN_AUDITS, nil)
+	if res.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for backoffice audits, got %d: %s", res.Code, res.Body)
+	}
+}
@@ -0,0 +1,340 @@
+// Package apitest contains integration test harness: it runs api.Server against throwaway postgres database
+// with migrated schema and test data, and exposes http clients logged in as fixture users.
+//
+// Postgres is taken from SPORTOS_TEST_DB_* environment variables or started from local initdb/pg_ctl binaries,
+// tests are skipped if neither is available, or fail if SPORTOS_REQUIRE_DB is set. NewMemory runs the same server on in memory repo with fixture users,
+// it doesn't need postgres. Test packages should call apitest.Main from TestMain.
+package apitest
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/api"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/xid"
+)
+
+// Users from X1.00__DML_TEST.sql
+const (
+	FIXTURE_PLAYER        = "test"
+	FIXTURE_SECOND_PLAYER = "google_107022849182831576055"
+	FIXTURE_COACH         = "andros"
+	FIXTURE_PLACE         = "facebook_2476912072449980"
+)
+
+var initOnce sync.Once
+
+// Main runs tests and stops postgres started by harness
+func Main(m *testing.M) {
+	code := m.Run()
+	stopPostgres()
+	os.Exit(code)
+}
+
+// Harness is api.Server running on its own database
+type Harness struct {
+	T      testing.TB
+	Repo   *crud.Repo
+	Server *api.Server
+
+	servers map[DR.SubServer]*httptest.Server
+}
+
+// New creates database, applies migrations and test data and starts all sub servers,
+// everything is cleaned up when test finishes
+func New(t testing.TB) *Harness {
+	t.Helper()
+	initGlobals(t)
+
+	p, dbName := createDatabase(t)
+	dbConnection := crud.DBConnection{
+		DBName:   dbName,
+		Host:     p.host,
+		Port:     p.port,
+		User:     p.user,
+		Password: p.password,
+		Migrate:  true,
+	}
+	h := &Harness{
+		T:       t,
+		Repo:    dbConnection.InitRepo(),
+		Server:  &api.Server{},
+		servers: map[DR.SubServer]*httptest.Server{},
+	}
+	t.Cleanup(func() {
+		h.Server.Notifications.Close()
+		for _, ts := range h.servers {
+			ts.Close()
+		}
+		h.Repo.DB.Close()
+	})
+
+	h.LoadSQL(fixturePath("X1.00__DML_TEST.sql"))
+	h.serve()
+	return h
+}
+
+// NewDB returns connection to new empty database without migrations, it is dropped when test finishes
+func NewDB(t testing.TB) *sql.DB {
+	t.Helper()
+	initGlobals(t)
+
+	p, dbName := createDatabase(t)
+	db, err := sql.Open("postgres", p.connection(dbName))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+// createDatabase creates empty database on test postgres and drops it when test finishes,
+// test is skipped if postgres isn't available and ENV_REQUIRE_DB isn't set
+func createDatabase(t testing.TB) (*postgres, string) {
+	t.Helper()
+	p, err := getPostgres()
+	if err != nil {
+		if os.Getenv(ENV_REQUIRE_DB) != "" {
+			t.Fatal(err)
+		}
+		t.Skip(err)
+	}
+
+	dbName := "sportos_test_" + xid.New().String()
+	admin, err := sql.Open("postgres", p.connection("postgres"))
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	if _, err := admin.Exec(`create database ` + dbName); err != nil {
+		admin.Close()
+		t.Fatalf("create database: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(`drop database if exists ` + dbName)
+		admin.Close()
+	})
+	return p, dbName
+}
+
+// NewMemory starts all sub servers on in memory repo that contains fixture users
+func NewMemory(t testing.TB) *Harness {
+	t.Helper()
+	initGlobals(t)
+
+	h := &Harness{
+		T:       t,
+		Repo:    memory.InitRepo(),
+		Server:  &api.Server{},
+		servers: map[DR.SubServer]*httptest.Server{},
+	}
+	t.Cleanup(func() {
+		h.Server.Notifications.Close()
+		for _, ts := range h.servers {
+			ts.Close()
+		}
+	})
+
+	h.seedMemory()
+	h.serve()
+	return h
+}
+
+func initGlobals(t testing.TB) {
+	initOnce.Do(func() {
+		L.Init()
+		if err := auth.Init("", "", auth.DEFAULT_ACCESS_TTL, auth.DEFAULT_REFRESH_TTL); err != nil {
+			t.Fatalf("auth init: %v", err)
+		}
+	})
+}
+
+func (h *Harness) serve() {
+	h.Server.Setup(h.Repo, "", "", "", false)
+	for sub, ss := range h.Server.SubServers {
+		h.servers[sub] = httptest.NewServer(ss.HttpServer.Handler)
+	}
+}
+
+// seedMemory creates fixture users with the same usernames as X1.00__DML_TEST.sql
+func (h *Harness) seedMemory() {
+	h.T.Helper()
+	ctx := context.Background()
+	users := []DR.User{
+		{Username: FIXTURE_PLAYER, Email: "test1@test.com", EmailVerified: DR.EMAIL_VERIFIED, UserType: DR.UT_PLAYER},
+		{Username: FIXTURE_SECOND_PLAYER, Email: "google_player@test.com", EmailVerified: DR.EMAIL_VERIFIED, UserType: DR.UT_PLAYER},
+		{Username: FIXTURE_COACH, Email: "coach@test.com", EmailVerified: DR.EMAIL_VERIFIED, UserType: DR.UT_COACH},
+		{Username: FIXTURE_PLACE, Email: "facebook_place@test.com", EmailVerified: DR.EMAIL_VERIFIED, UserType: DR.UT_PLACE},
+	}
+	for _, usr := range users {
+		if _, err := h.Repo.UserCrud.Create(ctx, usr, nil, nil); err != nil {
+			h.T.Fatalf("seed user %s: %v", usr.Username, err)
+		}
+	}
+	for _, id := range []string{FIXTURE_PLAYER, FIXTURE_SECOND_PLAYER} {
+		if _, err := h.Repo.PlayerCrud.Create(ctx, DR.Player{Username: id, Name: id, City: "Belgrade"}, nil, nil); err != nil {
+			h.T.Fatalf("seed player %s: %v", id, err)
+		}
+	}
+	if _, err := h.Repo.CoachCrud.Create(ctx, DR.Coach{Username: FIXTURE_COACH, Name: "Coach", City: "Belgrade", Sport: "Table tennis"}, nil, nil); err != nil {
+		h.T.Fatalf("seed coach: %v", err)
+	}
+	if _, err := h.Repo.PlaceCrud.Create(ctx, DR.Place{Username: FIXTURE_PLACE, Name: "Place", City: "Belgrade", Sport: "Table tennis"}, nil, nil); err != nil {
+		h.T.Fatalf("seed place: %v", err)
+	}
+}
+
+// LoadSQL executes sql file on harness database, it can't be used with NewMemory
+func (h *Harness) LoadSQL(path string) {
+	h.T.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		h.T.Fatalf("read %s: %v", path, err)
+	}
+	if _, err := h.Repo.DB.Exec(string(content)); err != nil {
+		h.T.Fatalf("load %s: %v", path, err)
+	}
+}
+
+// URL returns full url of route on sub server
+func (h *Harness) URL(sub DR.SubServer, hn string) string {
+	return h.servers[sub].URL + DA.API_V1 + hn
+}
+
+// As returns client logged in as user with given type, tokens are issued directly so fixture passwords aren't needed
+func (h *Harness) As(userId string, userType DR.UserType) *Client {
+	h.T.Helper()
+	// token of user that doesn't exist is rejected, users other than fixtures are created on first use
+	ctx := context.Background()
+	usr, err := h.Repo.UserCrud.GetById(ctx, userId, nil)
+	if err != nil {
+		usr, err = h.Repo.UserCrud.Create(ctx, DR.User{Username: userId, Email: userId + "@test.com", EmailVerified: DR.EMAIL_VERIFIED, UserType: userType}, nil, nil)
+		if err != nil {
+			h.T.Fatalf("create user %s: %v", userId, err)
+		}
+	}
+	tokens, err := auth.IssueTokens(userId, userType, usr.TokenVersion)
+	if err != nil {
+		h.T.Fatalf("issue tokens: %v", err)
+	}
+	return &Client{h: h, token: tokens.AccessToken}
+}
+
+func (h *Harness) Player() *Client {
+	return h.As(FIXTURE_PLAYER, DR.UT_PLAYER)
+}
+
+func (h *Harness) Coach() *Client {
+	return h.As(FIXTURE_COACH, DR.UT_COACH)
+}
+
+func (h *Harness) Place() *Client {
+	return h.As(FIXTURE_PLACE, DR.UT_PLACE)
+}
+
+// Anonymous returns client without token
+func (h *Harness) Anonymous() *Client {
+	return &Client{h: h}
+}
+
+// Client calls harness routes with bearer token
+type Client struct {
+	h     *Harness
+	token string
+}
+
+// Response is status code and raw body of response
+type Response struct {
+	Code int
+	Body []byte
+}
+
+// Do sends request with body encoded as json, body is skipped if it is nil
+func (c *Client) Do(sub DR.SubServer, method, hn string, body interface{}) Response {
+	c.h.T.Helper()
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			c.h.T.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.h.URL(sub, hn), reader)
+	if err != nil {
+		c.h.T.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.h.T.Fatalf("%s %s: %v", method, hn, err)
+	}
+	defer res.Body.Close()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.h.T.Fatalf("read response: %v", err)
+	}
+	return Response{Code: res.StatusCode, Body: resBody}
+}
+
+// Get sends GET request with query parameters
+func (c *Client) Get(sub DR.SubServer, hn string, query url.Values) Response {
+	c.h.T.Helper()
+	if len(query) > 0 {
+		hn += "?" + query.Encode()
+	}
+	return c.Do(sub, http.MethodGet, hn, nil)
+}
+
+// Stream opens GET request whose body is read while server writes it (notification stream), caller closes body
+func (c *Client) Stream(sub DR.SubServer, hn string) *http.Response {
+	c.h.T.Helper()
+	req, err := http.NewRequest(http.MethodGet, c.h.URL(sub, hn), nil)
+	if err != nil {
+		c.h.T.Fatalf("new request: %v", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.h.T.Fatalf("GET %s: %v", hn, err)
+	}
+	return res
+}
+
+// Decode unmarshals response body into v, test fails if it isn't valid json
+func (r Response) Decode(t testing.TB, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode response %s: %v", strings.TrimSpace(string(r.Body)), err)
+	}
+}
+
+// fixturePath returns path of file in cmd/sportos/internal/test
+func fixturePath(name string) string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "cmd", "sportos", "internal", "test", name)
+}
@@ -0,0 +1,149 @@
+package apitest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+const (
+	ENV_DB_HOST = "SPORTOS_TEST_DB_HOST"
+	ENV_DB_PORT = "SPORTOS_TEST_DB_PORT"
+	ENV_DB_USER = "SPORTOS_TEST_DB_USER"
+	ENV_DB_PASS = "SPORTOS_TEST_DB_PASS"
+	// directory with initdb and pg_ctl binaries, PATH is searched if it isn't set
+	ENV_PG_BIN = "SPORTOS_TEST_PG_BIN"
+	// if set, tests that need postgres fail instead of being skipped when it isn't available (CI)
+	ENV_REQUIRE_DB = "SPORTOS_REQUIRE_DB"
+)
+
+var errNoPostgres = errors.New("postgres isn't available: set " + ENV_DB_HOST + " or put initdb and pg_ctl into PATH (or " + ENV_PG_BIN + ")")
+
+// postgres is server in which every harness creates its own database
+type postgres struct {
+	host     string
+	port     string
+	user     string
+	password string
+
+	// set only for server started by harness
+	dir   string
+	pgCtl string
+}
+
+var (
+	pg      *postgres
+	pgErr   error
+	pgOnce  sync.Once
+	pgMutex sync.Mutex
+)
+
+// getPostgres returns server from environment or starts throwaway local server in temp directory,
+// server is started only once per test binary and stopped by Main
+func getPostgres() (*postgres, error) {
+	pgOnce.Do(func() {
+		pgMutex.Lock()
+		defer pgMutex.Unlock()
+		pg, pgErr = startPostgres()
+	})
+	return pg, pgErr
+}
+
+func startPostgres() (*postgres, error) {
+	if host := os.Getenv(ENV_DB_HOST); host != "" {
+		return &postgres{
+			host:     host,
+			port:     envOrDefault(ENV_DB_PORT, "5432"),
+			user:     envOrDefault(ENV_DB_USER, "postgres"),
+			password: envOrDefault(ENV_DB_PASS, "secret"),
+		}, nil
+	}
+
+	initdb, err := lookPgBinary("initdb")
+	if err != nil {
+		return nil, errNoPostgres
+	}
+	pgCtl, err := lookPgBinary("pg_ctl")
+	if err != nil {
+		return nil, errNoPostgres
+	}
+
+	dir, err := os.MkdirTemp("", "sportos-pg-")
+	if err != nil {
+		return nil, err
+	}
+	p := &postgres{
+		host:     "127.0.0.1",
+		user:     "sportos",
+		password: "secret",
+		dir:      dir,
+		pgCtl:    pgCtl,
+	}
+	dataDir := filepath.Join(dir, "data")
+	if out, err := exec.Command(initdb, "-D", dataDir, "-U", p.user, "-A", "trust", "-E", "UTF8").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("initdb failed: %w: %s", err, out)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	p.port = strconv.Itoa(port)
+	options := fmt.Sprintf("-p %s -k %s -c listen_addresses=%s -c fsync=off", p.port, dir, p.host)
+	if out, err := exec.Command(pgCtl, "-D", dataDir, "-o", options, "-l", filepath.Join(dir, "postgres.log"), "-w", "start").CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("pg_ctl start failed: %w: %s", err, out)
+	}
+	return p, nil
+}
+
+// stopPostgres stops server started by harness, server from environment is left running
+func stopPostgres() {
+	pgMutex.Lock()
+	defer pgMutex.Unlock()
+
+	if pg == nil || pg.dir == "" {
+		return
+	}
+	exec.Command(pg.pgCtl, "-D", filepath.Join(pg.dir, "data"), "-m", "immediate", "stop").Run()
+	os.RemoveAll(pg.dir)
+	pg = nil
+}
+
+func (p *postgres) connection(dbName string) string {
+	return fmt.Sprintf("dbname=%s host=%s port=%s user=%s password=%s sslmode=disable", dbName, p.host, p.port, p.user, p.password)
+}
+
+func lookPgBinary(name string) (string, error) {
+	if dir := os.Getenv(ENV_PG_BIN); dir != "" {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+	return exec.LookPath(name)
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -214,6 +214,8 @@ const (
 	PRE_ERR_MAIL_NOT_VERIFIED PredefinedError = "mail_not_verified"
 	// parameters have bad format
 	PRE_ERR_BAD_FORMAT PredefinedError = "bad_format"
+	// too many requests of same kind in short time
+	PRE_ERR_TOO_MANY_REQUESTS PredefinedError = "too_many_requests"
 )
 
 // default is 404 (Not Found) if not set
@@ -226,4 +228,5 @@ var ApiErrorHTTPCodesMap = map[PredefinedError]int{
 	PRE_ERR_FORBIDDEN_ID:         http.StatusBadRequest,
 	PRE_ERR_MAIL_NOT_VERIFIED:    http.StatusMethodNotAllowed,
 	PRE_ERR_BAD_FORMAT:           http.StatusBadRequest,
+	PRE_ERR_TOO_MANY_REQUESTS:    http.StatusTooManyRequests,
 }
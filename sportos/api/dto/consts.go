@@ -19,27 +19,37 @@ const (
 	HN_SOCIAL_USER    string = "/social-user"
 	HN_SOCIAL_LOGIN   string = "/social-login"
 	HN_VERIFY         string = "/verify"
+	HN_RESEND_VERIFY  string = "/resend-verification"
 	HN_LOGOUT         string = "/logout"
 	HN_SEND_RESET     string = "/send-reset"
 	HN_RESET_PASSWORD string = "/reset-password"
 	HN_SPORTS         string = "/sports"
 	//public
-	HN_TOURNAMENT_ROUND string = "/tournament-round"
-	HN_TOURNAMENTS      string = "/tournaments"
-	HN_MATCHES          string = "/matches"
-	HN_PRACTICES        string = "/practices"
-	HN_PLACES           string = "/places"
-	HN_COACHES          string = "/coaches"
-	HN_TIMES            string = "/times"
-	HN_IMAGES           string = "/assets/images/{id}"
-	HN_USERPOSTS        string = "/userposts"
-	HN_STATS            string = "/statistics"
-	HN_TEAMS            string = "/teams"
-	HN_REVIEWS          string = "/reviews"
-	HN_NAME_ID          string = "/name/{id}"
+	HN_TOURNAMENT_ROUND  string = "/tournament-round"
+	HN_TOURNAMENTS       string = "/tournaments"
+	HN_MATCHES           string = "/matches"
+	HN_PRACTICES         string = "/practices"
+	HN_PLACES            string = "/places"
+	HN_COACHES           string = "/coaches"
+	HN_TIMES             string = "/times"
+	HN_SCHEDULE          string = "/schedule"
+	HN_IMAGES            string = "/assets/images/{id}"
+	HN_USERPOSTS         string = "/userposts"
+	HN_STATS             string = "/statistics"
+	HN_STATS_ANALYTICS   string = "/statistics/analytics"
+	HN_LEADERBOARD       string = "/leaderboard"
+	HN_LEADERBOARDS      string = "/leaderboards"
+	HN_TEAMS             string = "/teams"
+	HN_REVIEWS           string = "/reviews"
+	HN_NAME_ID           string = "/name/{id}"
+	HN_NOTIFICATIONS     string = "/notifications"
+	HN_NOTIFICATIONS_SSE string = "/notifications/stream"
 	//Backoffice
-	HN_API_JOURNALS string = "/api-journals"
-	HN_AUDITS       string = "/audits"
+	HN_API_JOURNALS       string = "/api-journals"
+	HN_AUDITS             string = "/audits"
+	HN_WEBHOOKS           string = "/webhooks"
+	HN_WEBHOOK_DELIVERIES string = "/webhook-deliveries"
+	HN_MATCH_RESULTS      string = "/match-results"
 )
 
 const (
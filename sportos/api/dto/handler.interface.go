@@ -12,6 +12,8 @@ type Handler interface {
 	SupportedMethod() string
 	// On which subServers is the handler exposed
 	SupportedSubservers() []DR.SubServer
+	// Which user types are allowed to call the handler, empty means any user
+	RequiredRoles() []DR.UserType
 	// Init should read the http request data and store the data into the Handler struct
 	Init(*http.Request) Error
 	// Validation of Handler data that were initialized
@@ -19,3 +21,9 @@ type Handler interface {
 	// Main work is done here
 	Process(context.Context, *crud.Repo) (interface{}, Error)
 }
+
+// OwnershipChecker is implemented by handlers that change entities owned by a single user.
+// CheckOwnership is called after Validate so it can use data loaded there.
+type OwnershipChecker interface {
+	CheckOwnership(context.Context, *crud.Repo) Error
+}
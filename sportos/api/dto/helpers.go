@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/smtp"
 	"net/url"
 	"strconv"
 	"strings"
@@ -77,6 +76,16 @@ func GetUserIdFromContext(ctx context.Context) string {
 	}
 }
 
+// Returns the UserType that was set from token by middleware
+func GetUserTypeFromContext(ctx context.Context) DR.UserType {
+	val := ctx.Value(sportos.CONTEXT_USER_TYPE_KEY)
+	if val == nil {
+		return ""
+	} else {
+		return val.(DR.UserType)
+	}
+}
+
 // Returns the User PAM token that was set from token by middleware
 func GetPamTokenFromContext(ctx context.Context) string {
 	val := ctx.Value(sportos.CONTEXT_PAM_TOKEN_KEY)
@@ -294,40 +303,15 @@ func ParseAuditSortParams(sort *string, sp *DR.AuditSearchParams) {
 	}
 }
 
-func BeginingOfDay() time.Time {
-	t := time.Now()
-	year, month, day := t.Date()
-	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
-}
-
-func EndOfDay() time.Time {
-	t := time.Now().Add(time.Hour * 24)
-	year, month, day := t.Date()
-	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+// BeginingOfDay returns midnight that started today in loc, loc is time zone of place or coach
+func BeginingOfDay(loc *time.Location) time.Time {
+	year, month, day := time.Now().In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
 }
 
-func SendMail(message string, title string, to []string) {
-
-	// Sender data.
-	from := "andrija.novakovic.1998@gmail.com"
-	password := "prexhpmjccsyaulp"
-
-	// smtp server configuration.
-	smtpHost := "smtp.gmail.com"
-	smtpPort := "587"
-
-	// Authentication.
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-
-	sendMessage := fmt.Sprintf("From: <%s>\r\n", from) + fmt.Sprintf("To: <%s>\r\n", to) + fmt.Sprintf("Subject: %s\r\n", title) + "\r\n" + message
-
-	// Sending email.
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, to, []byte(sendMessage))
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	fmt.Println("Email Sent Successfully!")
+// EndOfDay returns midnight that ends today in loc, day doesn't have 24 hours when daylight saving changes
+func EndOfDay(loc *time.Location) time.Time {
+	return BeginingOfDay(loc).AddDate(0, 0, 1)
 }
 
 func AfterEqual(time1, time2 time.Time) bool {
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	DR "backend/sportos/repo/dto"
+	"time"
+)
+
+// MatchResult
+//
+// Match result submitted by player of one side, disputed results wait for review in backoffice
+// swagger:model MatchResult
+type MatchResult struct {
+	// Match result ID
+	Id      string `json:"id"`
+	MatchId string `json:"matchId"`
+	// Submitted result, like 2:1 (6:4 3:6 7:5)
+	Result string `json:"result"`
+	// Side (0 or 1) and id of player that submitted result
+	Side        int                  `json:"side"`
+	SubmittedBy string               `json:"submittedBy"`
+	Status      DR.MatchResultStatus `json:"status"`
+	// Time until which other side can confirm or dispute result, it is auto confirmed after it
+	Deadline      time.Time `json:"deadline"`
+	DisputedBy    string    `json:"disputedBy,omitempty"`
+	DisputeReason string    `json:"disputeReason,omitempty"`
+	// Explanation of backoffice decision
+	ReviewNote string     `json:"reviewNote,omitempty"`
+	ReviewedBy string     `json:"reviewedBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+}
+
+func (p *MatchResult) InitWithDatabaseStruct(do DR.MatchResult) {
+	p.Id = do.ResultId
+	p.MatchId = do.MatchId
+	p.Result = do.Result
+	p.Side = do.Side
+	p.SubmittedBy = do.CreatedBy
+	p.Status = do.Status
+	p.Deadline = do.Deadline
+	if do.DisputedBy != nil {
+		p.DisputedBy = *do.DisputedBy
+	}
+	if do.DisputeReason != nil {
+		p.DisputeReason = *do.DisputeReason
+	}
+	if do.ReviewNote != nil {
+		p.ReviewNote = *do.ReviewNote
+	}
+	if (do.Status == DR.MRS_RESOLVED || do.Status == DR.MRS_REJECTED) && do.UpdatedBy != nil {
+		p.ReviewedBy = *do.UpdatedBy
+	}
+	p.CreatedAt = do.CreatedAt
+	p.UpdatedAt = do.UpdatedAt
+}
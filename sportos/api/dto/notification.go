@@ -0,0 +1,33 @@
+package dto
+
+import (
+	DR "backend/sportos/repo/dto"
+	"time"
+)
+
+// Notification is message in inbox of user
+type Notification struct {
+	Id          string              `json:"id"`
+	Type        DR.NotificationType `json:"type"`
+	ReferenceId string              `json:"referenceId"`
+	Message     string              `json:"message"`
+	Read        bool                `json:"read"`
+	ReadAt      *time.Time          `json:"readAt,omitempty"`
+	CreatedAt   time.Time           `json:"createdAt"`
+}
+
+// Notifications is page of inbox with number of all unread notifications of user
+type Notifications struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+}
+
+func (p *Notification) InitWithDatabaseStruct(do DR.Notification) {
+	p.Id = do.NotificationId
+	p.Type = do.Type
+	p.ReferenceId = do.ReferenceId
+	p.Message = do.Message
+	p.Read = do.ReadAt != nil
+	p.ReadAt = do.ReadAt
+	p.CreatedAt = do.CreatedAt
+}
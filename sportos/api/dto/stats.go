@@ -1,20 +1,48 @@
 package dto
 
 import (
+	DR "backend/sportos/repo/dto"
+	"fmt"
+	"math"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
+// Statistic is match of player, teams are names of players
 type Statistic struct {
-	MyTeam  []string  `json:"myTeam,omitempty"`
-	OppTeam []string  `json:"oppTeam,omitempty"`
-	Date    time.Time `json:"date,omitempty"`
-	Score   string    `json:"score,omitempty"`
+	MatchId string          `json:"matchId,omitempty"`
+	MyTeam  []string        `json:"myTeam,omitempty"`
+	OppTeam []string        `json:"oppTeam,omitempty"`
+	Date    time.Time       `json:"date,omitempty"`
+	Score   string          `json:"score,omitempty"`
+	Outcome DR.MatchOutcome `json:"outcome,omitempty"`
 }
 
+// Statistics of player in sport, Matches are from newest and can be paged with offset and limit
 type Statistics struct {
-	Matches     []Statistic  `json:"matches,omitempty"`
-	WinRatio    string       `json:"winRatio,omitempty"`
-	Tournaments []Tournament `json:"tournaments,omitempty"`
+	Matches  []Statistic `json:"matches,omitempty"`
+	WinRatio string      `json:"winRatio,omitempty"`
+	Wins     int         `json:"wins"`
+	Draws    int         `json:"draws"`
+	Losses   int         `json:"losses"`
+	// CurrentStreak is number of last matches that ended with StreakOutcome
+	CurrentStreak    int               `json:"currentStreak"`
+	StreakOutcome    DR.MatchOutcome   `json:"streakOutcome,omitempty"`
+	LongestWinStreak int               `json:"longestWinStreak"`
+	Months           []MonthStatistics `json:"months,omitempty"`
+	Tournaments      []Tournament      `json:"tournaments,omitempty"`
+	Rating           *Rating           `json:"rating,omitempty"`
+}
+
+// MonthStatistics are matches of player in one month, Month is formatted like 2006-01
+type MonthStatistics struct {
+	Month    string `json:"month"`
+	Matches  int    `json:"matches"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+	WinRatio string `json:"winRatio"`
 }
 
 type Tournament struct {
@@ -24,3 +52,171 @@ type Tournament struct {
 }
 
 type StatMap map[string]Statistics
+
+// Rating is skill of player in sport, rating and deviation are rounded to one decimal
+type Rating struct {
+	System    DR.RatingSystem `json:"system"`
+	Rating    float64         `json:"rating"`
+	Deviation float64         `json:"deviation,omitempty"`
+	Matches   int             `json:"matches"`
+	History   []RatingChange  `json:"history,omitempty"`
+}
+
+// RatingChange is change of rating after match, score is 1 for win, 0.5 for draw and 0 for loss
+type RatingChange struct {
+	MatchId string    `json:"matchId"`
+	Date    time.Time `json:"date"`
+	Score   float64   `json:"score"`
+	Before  float64   `json:"before"`
+	After   float64   `json:"after"`
+}
+
+// Ranking is player on leaderboard of sport and city ranked by one of metrics, Rank starts from 1
+type Ranking struct {
+	Rank             int      `json:"rank"`
+	PlayerId         string   `json:"playerId"`
+	Name             string   `json:"name"`
+	City             string   `json:"city,omitempty"`
+	WinRatio         string   `json:"winRatio"`
+	Matches          int      `json:"matches"`
+	Tournaments      int      `json:"tournaments"`
+	TournamentPoints int      `json:"tournamentPoints"`
+	Rating           *float64 `json:"rating,omitempty"`
+}
+
+// Analytics are records of player in sport grouped by opponent, teammate, venue, weekday and hour of match.
+// Groups are ordered from most matches, weekdays start with Monday and hours with midnight
+type Analytics struct {
+	Form          Form     `json:"form"`
+	HeadToHead    []Record `json:"headToHead"`
+	Teammates     []Record `json:"teammates"`
+	BestTeammate  *Record  `json:"bestTeammate,omitempty"`
+	WorstTeammate *Record  `json:"worstTeammate,omitempty"`
+	Venues        []Record `json:"venues"`
+	Weekdays      []Record `json:"weekdays"`
+	Hours         []Record `json:"hours"`
+}
+
+// Record is result of player in matches of one group, Id is id of opponent, teammate or venue,
+// day of week (0 is Sunday) or hour
+type Record struct {
+	Id       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Matches  int    `json:"matches"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+	WinRatio string `json:"winRatio"`
+}
+
+// Form is result of last matches of player, Results has letter W, D or L for every match from newest
+type Form struct {
+	Results  string `json:"results"`
+	Matches  int    `json:"matches"`
+	Wins     int    `json:"wins"`
+	Draws    int    `json:"draws"`
+	Losses   int    `json:"losses"`
+	WinRatio string `json:"winRatio"`
+}
+
+func (p *Statistics) InitWithDatabaseStruct(do DR.PlayerMatchSummary) {
+	if do.Matches > 0 {
+		p.WinRatio = percent(do.WinRatio())
+	}
+	p.Wins = do.Wins
+	p.Draws = do.Draws
+	p.Losses = do.Losses
+	p.CurrentStreak = do.CurrentStreak
+	p.StreakOutcome = do.StreakOutcome
+	p.LongestWinStreak = do.LongestWinStreak
+}
+
+func (p *Statistic) InitWithDatabaseStruct(do DR.PlayerMatch) {
+	p.MatchId = do.MatchId
+	p.MyTeam = do.MyTeam
+	p.OppTeam = do.OppTeam
+	p.Date = do.PlayedAt
+	p.Score = do.Score()
+	p.Outcome = do.Outcome
+}
+
+func (p *MonthStatistics) InitWithDatabaseStruct(do DR.PlayerMatchMonth) {
+	p.Month = do.Month.Format("2006-01")
+	p.Matches = do.Matches
+	p.Wins = do.Wins
+	p.Draws = do.Draws
+	p.Losses = do.Losses
+	p.WinRatio = percent(DR.PlayerMatchSummary{Matches: do.Matches, Wins: do.Wins}.WinRatio())
+}
+
+func (p *Tournament) InitWithDatabaseStruct(do DR.PlayerTournament) {
+	p.MyTeam = do.MyTeam
+	p.Ranking = do.Ranking
+	p.Tournament = do.Tournament
+}
+
+func (p *Rating) InitWithDatabaseStruct(do DR.Rating) {
+	p.System = do.System
+	p.Rating = round(do.Rating)
+	if do.System == DR.RS_GLICKO2 {
+		p.Deviation = round(do.Deviation)
+	}
+	p.Matches = do.Matches
+}
+
+func (p *RatingChange) InitWithDatabaseStruct(do DR.RatingHistory) {
+	p.MatchId = do.MatchId
+	p.Date = do.CreatedAt
+	p.Score = do.Score
+	p.Before = round(do.RatingBefore)
+	p.After = round(do.RatingAfter)
+}
+
+func (p *Ranking) InitWithDatabaseStruct(do DR.Leaderboard) {
+	p.PlayerId = do.PlayerId
+	p.Name = do.Name
+	p.City = do.City
+	p.WinRatio = percent(do.WinRatio)
+	p.Matches = do.Matches
+	p.Tournaments = do.Tournaments
+	p.TournamentPoints = do.TournamentPoints
+	if do.Rating != nil {
+		rating := round(*do.Rating)
+		p.Rating = &rating
+	}
+}
+
+func (p *Record) InitWithDatabaseStruct(do DR.PlayerMatchGroup) {
+	p.Id = do.Key
+	p.Name = do.Name
+	p.Matches = do.Matches
+	p.Wins = do.Wins
+	p.Draws = do.Draws
+	p.Losses = do.Losses
+	p.WinRatio = percent(DR.PlayerMatchSummary{Matches: do.Matches, Wins: do.Wins}.WinRatio())
+}
+
+func (p *Form) InitWithDatabaseStruct(do []DR.PlayerMatch) {
+	for _, pm := range do {
+		p.Results += string(pm.Outcome[:1])
+		p.Matches++
+		switch pm.Outcome {
+		case DR.MO_WIN:
+			p.Wins++
+		case DR.MO_DRAW:
+			p.Draws++
+		case DR.MO_LOSS:
+			p.Losses++
+		}
+	}
+	p.WinRatio = percent(DR.PlayerMatchSummary{Matches: p.Matches, Wins: p.Wins}.WinRatio())
+}
+
+// percent formats ratio like 66.67%
+func percent(ratio decimal.Decimal) string {
+	return fmt.Sprintf("%.2f%%", ratio.InexactFloat64()*100)
+}
+
+func round(f float64) float64 {
+	return math.Round(f*10) / 10
+}
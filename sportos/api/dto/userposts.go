@@ -3,6 +3,7 @@ package dto
 import "time"
 
 type UserPost struct {
+	Id         string    `json:"id,omitempty"`
 	Name       string    `json:"name,omitempty"`
 	UserText   string    `json:"userText,omitempty"`
 	ImageNames []string  `json:"imageNames,omitempty"`
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"backend/sportos/auth"
+	"backend/sportos/mail"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/url"
+	"time"
+)
+
+// IssueVerificationToken creates new single use token for user and invalidates older tokens of same purpose.
+// Returned token is the one that should be sent to user, only its hash is saved.
+func IssueVerificationToken(ctx context.Context, Repo *crud.Repo, userId string, purpose DR.TokenPurpose, qa crud.QueryAble) (string, Error) {
+	since := time.Now().Add(-auth.VERIFICATION_TOKEN_WINDOW)
+	sp := DR.VerificationTokenSearchParams{
+		UserId:                &userId,
+		Purpose:               &purpose,
+		EditInfoCSearchParams: DR.EditInfoCSearchParams{CreatedAtFrom: &since},
+	}
+	cnt, err := Repo.VerificationTokenCrud.GetCount(ctx, sp, qa)
+	if err != nil {
+		return "", InternalServerError(err)
+	}
+	if cnt >= auth.VERIFICATION_TOKEN_LIMIT {
+		return "", NewApiError().WithPredefinedError(PRE_ERR_TOO_MANY_REQUESTS).WithMessage("Too many requests, try again later")
+	}
+	token, tokenHash, err := auth.NewVerificationToken()
+	if err != nil {
+		return "", InternalServerError(err)
+	}
+	validity := auth.VERIFY_EMAIL_TOKEN_TTL
+	if purpose == DR.TP_RESET_PASSWORD {
+		validity = auth.RESET_PASSWORD_TOKEN_TTL
+	}
+	err = Repo.VerificationTokenCrud.Invalidate(ctx, userId, purpose, qa)
+	if err != nil {
+		return "", InternalServerError(err)
+	}
+	_, err = Repo.VerificationTokenCrud.Create(ctx, DR.VerificationToken{
+		TokenHash:  tokenHash,
+		UserId:     userId,
+		Purpose:    purpose,
+		ValidUntil: time.Now().Add(validity),
+	}, qa, nil)
+	if err != nil {
+		return "", InternalServerError(err)
+	}
+	return token, nil
+}
+
+// SendVerificationMail issues new token of given purpose and puts mail with link into outbox
+func SendVerificationMail(ctx context.Context, Repo *crud.Repo, user DR.User, purpose DR.TokenPurpose, qa crud.QueryAble) Error {
+	token, apiErr := IssueVerificationToken(ctx, Repo, user.Username, purpose, qa)
+	if apiErr != nil {
+		return apiErr
+	}
+	var tpl mail.Template
+	data := mail.LinkData{Username: user.Username}
+	switch purpose {
+	case DR.TP_VERIFY_EMAIL:
+		tpl = mail.TPL_VERIFY_EMAIL
+		data.Link = mail.AppLink("/verify", url.Values{"verifyToken": {token}})
+		data.ValidFor = mail.FormatDuration(auth.VERIFY_EMAIL_TOKEN_TTL)
+	case DR.TP_RESET_PASSWORD:
+		tpl = mail.TPL_RESET_PASSWORD
+		data.Link = mail.AppLink("/reset-password", url.Values{"resetToken": {token}})
+		data.ValidFor = mail.FormatDuration(auth.RESET_PASSWORD_TOKEN_TTL)
+	}
+	err := mail.Enqueue(ctx, Repo, qa, tpl, []string{user.Email}, data)
+	if err != nil {
+		return InternalServerError(err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	DR "backend/sportos/repo/dto"
+	"time"
+)
+
+// Webhook
+//
+// Webhook receives signed domain events of place
+// swagger:model Webhook
+type Webhook struct {
+	// Webhook ID
+	Id string `json:"id"`
+	// Place whose events are delivered
+	PlaceId string `json:"placeId"`
+	// Url that receives POST with event
+	Url string `json:"url"`
+	// Secret used for X-Sportos-Signature, returned only when webhook is created
+	Secret string `json:"secret,omitempty"`
+	// Delivered event types, all webhook events if empty
+	Events []string `json:"events"`
+	// Inactive webhooks don't receive events
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (p *Webhook) InitWithDatabaseStruct(do DR.Webhook) {
+	p.Id = do.WebhookId
+	p.PlaceId = do.PlaceId
+	p.Url = do.Url
+	p.Events = do.Events
+	if p.Events == nil {
+		p.Events = []string{}
+	}
+	p.Active = do.Active
+	p.CreatedAt = do.CreatedAt
+}
+
+// WebhookDelivery
+//
+// Webhook delivery records every event sent to webhook with request and last response
+// swagger:model WebhookDelivery
+type WebhookDelivery struct {
+	// Delivery ID, sent in X-Sportos-Delivery header
+	Id            string                   `json:"id"`
+	WebhookId     string                   `json:"webhookId"`
+	EventType     DR.OutboxEventType       `json:"eventType"`
+	Status        DR.WebhookDeliveryStatus `json:"status"`
+	Attempts      int                      `json:"attempts"`
+	NextAttemptAt *time.Time               `json:"nextAttemptAt,omitempty"`
+	// Body that was sent to webhook
+	Request string `json:"request"`
+	// Body and status code webhook responded with on last attempt
+	Response     string     `json:"response,omitempty"`
+	ResponseCode *int       `json:"responseCode,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	DeliveredAt  *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+func (p *WebhookDelivery) InitWithDatabaseStruct(do DR.WebhookDelivery) {
+	p.Id = do.DeliveryId
+	p.WebhookId = do.WebhookId
+	p.EventType = do.EventType
+	p.Status = do.Status
+	p.Attempts = do.Attempts
+	if do.Status == DR.WDS_PENDING {
+		p.NextAttemptAt = &do.NextAttemptAt
+	}
+	p.Request = do.Request
+	if do.Response != nil {
+		p.Response = *do.Response
+	}
+	p.ResponseCode = do.ResponseCode
+	if do.LastError != nil {
+		p.LastError = *do.LastError
+	}
+	p.DeliveredAt = do.DeliveredAt
+	p.CreatedAt = do.CreatedAt
+}
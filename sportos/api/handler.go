@@ -8,6 +8,7 @@ import (
 	BO "backend/sportos/api/handlers/backoffice"
 	LO "backend/sportos/api/handlers/login"
 	CL "backend/sportos/api/handlers/public"
+	"backend/sportos/notify"
 	DR "backend/sportos/repo/dto"
 	"fmt"
 	"net/http"
@@ -49,6 +50,15 @@ func registerHandlersForMux(s *Server, router *mux.Router, apiVersion string, su
 	router.HandleFunc(string(DA.HN_AUDITS), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_AUDITS, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_WEBHOOKS), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_WEBHOOKS, apiVersion, subServer)
+	})
+	router.HandleFunc(string(DA.HN_WEBHOOK_DELIVERIES), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_WEBHOOK_DELIVERIES, apiVersion, subServer)
+	})
+	router.HandleFunc(string(DA.HN_MATCH_RESULTS), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_MATCH_RESULTS, apiVersion, subServer)
+	})
 	router.HandleFunc(string(DA.HN_LOGIN), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_LOGIN, apiVersion, subServer)
 	})
@@ -64,6 +74,9 @@ func registerHandlersForMux(s *Server, router *mux.Router, apiVersion string, su
 	router.HandleFunc(string(DA.HN_VERIFY), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_VERIFY, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_RESEND_VERIFY), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_RESEND_VERIFY, apiVersion, subServer)
+	})
 	router.HandleFunc(string(DA.HN_LOGOUT), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_LOGOUT, apiVersion, subServer)
 	})
@@ -97,12 +110,24 @@ func registerHandlersForMux(s *Server, router *mux.Router, apiVersion string, su
 	router.HandleFunc(string(DA.HN_TIMES), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_TIMES, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_SCHEDULE), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_SCHEDULE, apiVersion, subServer)
+	})
 	router.HandleFunc(string(DA.HN_USERPOSTS), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_USERPOSTS, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_LEADERBOARD), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_LEADERBOARD, apiVersion, subServer)
+	})
+	router.HandleFunc(string(DA.HN_LEADERBOARDS), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_LEADERBOARDS, apiVersion, subServer)
+	})
 	router.HandleFunc(string(DA.HN_STATS), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_STATS, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_STATS_ANALYTICS), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_STATS_ANALYTICS, apiVersion, subServer)
+	})
 	router.HandleFunc(string(DA.HN_TEAMS), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_TEAMS, apiVersion, subServer)
 	})
@@ -112,6 +137,15 @@ func registerHandlersForMux(s *Server, router *mux.Router, apiVersion string, su
 	router.HandleFunc(string(DA.HN_NAME_ID), func(w http.ResponseWriter, r *http.Request) {
 		HandleRequest(w, r, s, DA.HN_NAME_ID, apiVersion, subServer)
 	})
+	router.HandleFunc(string(DA.HN_NOTIFICATIONS), func(w http.ResponseWriter, r *http.Request) {
+		HandleRequest(w, r, s, DA.HN_NOTIFICATIONS, apiVersion, subServer)
+	})
+	// notification stream isn't json response, it is served without handler
+	if subServer == DR.SUB_CL {
+		router.HandleFunc(string(DA.HN_NOTIFICATIONS_SSE), func(w http.ResponseWriter, r *http.Request) {
+			serveNotifications(w, r, s)
+		}).Methods(http.MethodGet)
+	}
 	absPath, _ := filepath.Abs("../../assets/images")
 	fs := http.FileServer(http.Dir(absPath))
 	router.Handle(string(DA.HN_IMAGES), http.StripPrefix("/v1/assets/images", fs)).Methods(http.MethodGet)
@@ -143,6 +177,8 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 				h = &CL.TournamentsGetHandler{}
 			case http.MethodPatch:
 				h = &CL.TournamentPatchHandler{}
+			case http.MethodDelete:
+				h = &CL.TournamentDeleteHandler{}
 			}
 		case DA.HN_TOURNAMENT_ROUND:
 			switch r.Method {
@@ -157,6 +193,8 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 				h = &CL.MatchGetHandler{}
 			case http.MethodPatch:
 				h = &CL.MatchPatchHandler{}
+			case http.MethodDelete:
+				h = &CL.MatchDeleteHandler{}
 			}
 		case DA.HN_PRACTICES:
 			switch r.Method {
@@ -166,6 +204,15 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 				h = &CL.PracticeGetHandler{}
 			case http.MethodPatch:
 				h = &CL.PracticePatchHandler{}
+			case http.MethodDelete:
+				h = &CL.PracticeDeleteHandler{}
+			}
+		case DA.HN_NOTIFICATIONS:
+			switch r.Method {
+			case http.MethodGet:
+				h = &CL.NotificationsGetHandler{}
+			case http.MethodPatch:
+				h = &CL.NotificationsPatchHandler{}
 			}
 		case DA.HN_REVIEWS:
 			switch r.Method {
@@ -190,12 +237,19 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 				h = &CL.UserpostPostHandler{}
 			case http.MethodGet:
 				h = &CL.UserpostGetHandler{}
+			case http.MethodDelete:
+				h = &CL.UserpostDeleteHandler{}
 			}
 		case DA.HN_TIMES:
 			switch r.Method {
 			case http.MethodGet:
 				h = &CL.TimesGetHandler{}
 			}
+		case DA.HN_SCHEDULE:
+			switch r.Method {
+			case http.MethodPut:
+				h = &CL.SchedulePutHandler{}
+			}
 		case DA.HN_VERIFY:
 			switch r.Method {
 			case http.MethodPost:
@@ -205,6 +259,8 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 			switch r.Method {
 			case http.MethodPost:
 				h = &LO.UserPostHandler{}
+			case http.MethodDelete:
+				h = &CL.UserDeleteHandler{}
 			}
 		case DA.HN_SOCIAL_USER:
 			switch r.Method {
@@ -216,6 +272,11 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 			case http.MethodPost:
 				h = &LO.SocialLoginPostHandler{}
 			}
+		case DA.HN_RESEND_VERIFY:
+			switch r.Method {
+			case http.MethodPost:
+				h = &LO.ResendVerificationPostHandler{}
+			}
 		case DA.HN_SEND_RESET:
 			switch r.Method {
 			case http.MethodPost:
@@ -241,11 +302,45 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 			case http.MethodGet:
 				h = &BO.AuditsGetHandler{}
 			}
+		case DA.HN_WEBHOOKS:
+			switch r.Method {
+			case http.MethodGet:
+				h = &BO.WebhooksGetHandler{}
+			case http.MethodPost:
+				h = &BO.WebhooksPostHandler{}
+			case http.MethodPatch:
+				h = &BO.WebhooksPatchHandler{}
+			case http.MethodDelete:
+				h = &BO.WebhooksDeleteHandler{}
+			}
+		case DA.HN_WEBHOOK_DELIVERIES:
+			switch r.Method {
+			case http.MethodGet:
+				h = &BO.WebhookDeliveriesGetHandler{}
+			}
+		case DA.HN_MATCH_RESULTS:
+			switch r.Method {
+			case http.MethodGet:
+				h = &BO.MatchResultsGetHandler{}
+			case http.MethodPatch:
+				h = &BO.MatchResultsPatchHandler{}
+			}
 		case DA.HN_STATS:
 			switch r.Method {
 			case http.MethodGet:
 				h = &CL.StatisticsGetHandler{}
 			}
+		case DA.HN_STATS_ANALYTICS:
+			switch r.Method {
+			case http.MethodGet:
+				h = &CL.StatisticsAnalyticsGetHandler{}
+			}
+		// leaderboards is alias of leaderboard
+		case DA.HN_LEADERBOARD, DA.HN_LEADERBOARDS:
+			switch r.Method {
+			case http.MethodGet:
+				h = &CL.LeaderboardGetHandler{}
+			}
 		case DA.HN_TEAMS:
 			switch r.Method {
 			case http.MethodGet:
@@ -254,6 +349,8 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 				h = &CL.TeamsPostHandler{}
 			case http.MethodPatch:
 				h = &CL.TeamsPatchHandler{}
+			case http.MethodDelete:
+				h = &CL.TeamsDeleteHandler{}
 			}
 		case DA.HN_NAME_ID:
 			switch r.Method {
@@ -279,6 +376,7 @@ func makeHandler(requestInfo *DA.RequestInfo, r *http.Request) (h DA.Handler, er
 func HandleRequest(w http.ResponseWriter, r *http.Request, s *Server, hurl string, apiVersion string, subServer DR.SubServer) {
 
 	L.L.WithRequestID(r.Context()).Info("handleRequest", L.Any("hurl", hurl), L.Any("mux.vars", mux.Vars(r)), L.Any("Body", r.Body))
+	r = r.WithContext(notify.WithHub(r.Context(), s.Notifications))
 	requestInfo := DA.NewRequestInfo(hurl, apiVersion, subServer, r)
 	h, err := makeHandler(&requestInfo, r)
 	ctx := r.Context()
@@ -296,11 +394,22 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, s *Server, hurl strin
 		aPIJSONErrorResponse(ctx, w, DA.ErrorNotFound(), s.Repo)
 		return
 	}
+	if !hasRequiredRole(h, DA.GetUserTypeFromContext(requestInfo.Context)) {
+		aPIJSONErrorResponse(ctx, w, DA.ErrorForbidden(), s.Repo)
+		return
+	}
 	apiErr := h.Validate(requestInfo.Context, s.Repo)
 	if apiErr != nil {
 		aPIJSONErrorResponse(ctx, w, apiErr, s.Repo)
 		return
 	}
+	if oc, ok := h.(DA.OwnershipChecker); ok {
+		apiErr = oc.CheckOwnership(requestInfo.Context, s.Repo)
+		if apiErr != nil {
+			aPIJSONErrorResponse(ctx, w, apiErr, s.Repo)
+			return
+		}
+	}
 	res, apiErr := h.Process(requestInfo.Context, s.Repo)
 	if apiErr != nil {
 		aPIJSONErrorResponse(ctx, w, apiErr, s.Repo)
@@ -324,3 +433,17 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, s *Server, hurl strin
 
 	aPIJSONResponseOK(ctx, w, responseMap["body"], s.Repo)
 }
+
+// hasRequiredRole checks if user type from token is allowed to call handler
+func hasRequiredRole(h DA.Handler, userType DR.UserType) bool {
+	roles := h.RequiredRoles()
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if role == userType {
+			return true
+		}
+	}
+	return false
+}
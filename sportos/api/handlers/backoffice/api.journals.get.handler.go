@@ -96,6 +96,10 @@ func (r ApiJournalsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_BO}
 }
 
+func (r ApiJournalsGetHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
 func (r *ApiJournalsGetHandler) Init(httpReq *http.Request) DA.Error {
 	errorMessages := make([]string, 0)
 	var errorMessage string
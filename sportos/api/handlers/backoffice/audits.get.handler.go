@@ -79,6 +79,10 @@ func (r AuditsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_BO}
 }
 
+func (r AuditsGetHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
 func (r *AuditsGetHandler) Init(httpReq *http.Request) DA.Error {
 	errorMessages := make([]string, 0)
 	var errorMessage string
@@ -0,0 +1,89 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+// MatchResultsGetHandler returns review queue of disputed results, oldest first. Results in other status
+// are returned when status is requested
+type MatchResultsGetHandler struct {
+	SearchParams *DR.MatchResultSearchParams
+}
+
+func (r MatchResultsGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r MatchResultsGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r MatchResultsGetHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *MatchResultsGetHandler) Init(httpReq *http.Request) DA.Error {
+	errorMessages := make([]string, 0)
+	var errorMessage string
+	r.SearchParams = &DR.MatchResultSearchParams{}
+	r.SearchParams.MatchId = DA.GetParameterFromURLQuery(httpReq, "matchId")
+	r.SearchParams.Status = (*DR.MatchResultStatus)(DA.ToUpperPointer(DA.GetParameterFromURLQuery(httpReq, "status")))
+
+	r.SearchParams.CreatedAtFrom, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "createdFrom"), "createdFrom")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.CreatedAtBefore, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "createdBefore"), "createdBefore")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *MatchResultsGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if s := r.SearchParams.Status; s != nil && !s.IsValid() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Status: '" + string(*s) + "' is not valid")
+	}
+	if r.SearchParams.Status == nil {
+		disputed := DR.MRS_DISPUTED
+		r.SearchParams.Status = &disputed
+	}
+	return nil
+}
+
+func (r *MatchResultsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	res, err := Repo.MatchResultCrud.Search(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	result := make([]DA.MatchResult, 0)
+	for _, mr := range res {
+		matchResult := DA.MatchResult{}
+		matchResult.InitWithDatabaseStruct(mr)
+		result = append(result, matchResult)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	cnt, err := Repo.MatchResultCrud.GetCount(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	resMap["headers"], err = DA.GenerateRangeHeader(cnt, r.SearchParams.PagingSearchParams)
+	if err != nil {
+		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_RANGE)
+	}
+	return resMap, nil
+}
@@ -0,0 +1,134 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/notify"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/results"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// MatchResultsPatchHandler reviews disputed result. RESOLVED result (optionally corrected) finishes match,
+// REJECTED result returns match to players so they can submit result again
+type MatchResultsPatchHandler struct {
+	MatchResultsPatchRequest
+	userId  string
+	matchId string
+}
+
+type MatchResultsPatchRequest struct {
+	Id     string               `json:"id"`
+	Status DR.MatchResultStatus `json:"status"`
+	// Result is corrected result, RESOLVED only
+	Result *string `json:"result,omitempty"`
+	Note   *string `json:"note,omitempty"`
+}
+
+func (r MatchResultsPatchHandler) SupportedMethod() string {
+	return http.MethodPatch
+}
+
+func (r MatchResultsPatchHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r MatchResultsPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *MatchResultsPatchHandler) Init(httpReq *http.Request) DA.Error {
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	err := decode.Decode(&r.MatchResultsPatchRequest)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *MatchResultsPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	mr, err := Repo.MatchResultCrud.GetById(ctx, r.Id, nil)
+	if err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Match result with id " + r.Id + " doesn't exist")
+	}
+	if mr.Status != DR.MRS_DISPUTED {
+		return DA.ErrorBadRequest().WithMessage("Only disputed result can be reviewed")
+	}
+	r.matchId = mr.MatchId
+	if r.Status != DR.MRS_RESOLVED && r.Status != DR.MRS_REJECTED {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Status must be " + string(DR.MRS_RESOLVED) + " or " + string(DR.MRS_REJECTED))
+	}
+	if r.Result != nil && r.Status != DR.MRS_RESOLVED {
+		return DA.ErrorBadRequest().WithMessage("Result can be corrected only when dispute is resolved")
+	}
+	match, err := Repo.MatchCrud.GetById(ctx, mr.MatchId, nil)
+	if err != nil {
+		return DA.InternalServerError(err)
+	}
+	if r.Result != nil {
+		result, err := results.Validate(match.Sport, *r.Result)
+		if err != nil {
+			return DA.ErrorBadRequest().WithMessage("Result isn't valid: " + err.Error())
+		}
+		r.Result = &result
+	}
+	return nil
+}
+
+func (r *MatchResultsPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	// match is locked before its result, in the same order as players and worker lock them
+	if _, err = Repo.MatchCrud.GetById(ctx, r.matchId, tx); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	mr, err := Repo.MatchResultCrud.GetById(ctx, r.Id, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if mr.Status != DR.MRS_DISPUTED {
+		return nil, DA.ErrorBadRequest().WithMessage("Result was already reviewed")
+	}
+	up := DR.MatchResultUpdateParams{Id: r.Id, Status: &r.Status, Result: r.Result, ReviewNote: r.Note}
+	var notes notify.Batch
+	if r.Status == DR.MRS_RESOLVED {
+		if _, err = results.Finish(ctx, Repo, tx, &notes, mr, up, &r.userId); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	} else {
+		if err = Repo.MatchResultCrud.Update(ctx, up, tx, &r.userId); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		full := DR.MS_FULL
+		match, err := Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: mr.MatchId, Status: &full}, tx, &r.userId)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		place, _ := Repo.GetNameForId(ctx, match.PlaceId)
+		n := DR.Notification{Type: DR.NT_MATCH_REJECTED, ReferenceId: match.MatchId, Message: "Result " + mr.Result + " of your match at " + place + " was rejected, submit result again"}
+		if err = notes.Store(ctx, Repo, tx, n, match, match.Players...); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	ret, err := Repo.MatchResultCrud.GetById(ctx, r.Id, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	notes.Send(ctx)
+	result := DA.MatchResult{}
+	result.InitWithDatabaseStruct(ret)
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	return resMap, nil
+}
@@ -2,14 +2,11 @@ package backoffice
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"math/rand"
 	"net/http"
 	"net/mail"
 	"unicode"
@@ -34,6 +31,10 @@ func (r UserPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r UserPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
 func (r *UserPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -65,18 +66,18 @@ func (r *UserPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Erro
 }
 
 func (r *UserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
-	data, _ := json.Marshal(r.Password)
-	sha256Hasher.Write(data)
-	dataHash := base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
+	dataHash, err := auth.HashPassword(r.Password)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	user := DR.User{
 		Username:      r.Username,
 		Email:         r.Email,
-		EmailVerified: rand.Intn(9) - 9,
+		EmailVerified: DR.EMAIL_NOT_VERIFIED,
 		PasswordHash:  dataHash,
 		UserType:      DR.UserType(r.UserType),
 	}
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -111,9 +112,14 @@ func (r *UserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interfa
 			return nil, DA.InternalServerError(err)
 		}
 	}
-	host := "https://localhost:4200"
-	message := "\nPlease verify your email address for sportos by clicking on link " + host + "/verify?verifyToken=" + r.Username + "___" + fmt.Sprint(-user.EmailVerified) + "___" + user.PasswordHash
-	DA.SendMail(message, "Verify email", []string{r.Email})
+	apiErr := DA.SendVerificationMail(ctx, Repo, user, DR.TP_VERIFY_EMAIL, tx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = "Please verify your email to complete you registration"
 	return resMap, nil
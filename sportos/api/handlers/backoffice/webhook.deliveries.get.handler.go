@@ -0,0 +1,83 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type WebhookDeliveriesGetHandler struct {
+	SearchParams *DR.WebhookDeliverySearchParams
+}
+
+func (r WebhookDeliveriesGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r WebhookDeliveriesGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r WebhookDeliveriesGetHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *WebhookDeliveriesGetHandler) Init(httpReq *http.Request) DA.Error {
+	errorMessages := make([]string, 0)
+	var errorMessage string
+	r.SearchParams = &DR.WebhookDeliverySearchParams{}
+	r.SearchParams.WebhookId = DA.GetParameterFromURLQuery(httpReq, "webhookId")
+	r.SearchParams.Status = (*DR.WebhookDeliveryStatus)(DA.ToUpperPointer(DA.GetParameterFromURLQuery(httpReq, "status")))
+
+	r.SearchParams.CreatedAtFrom, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "createdFrom"), "createdFrom")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.CreatedAtBefore, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "createdBefore"), "createdBefore")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *WebhookDeliveriesGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if s := r.SearchParams.Status; s != nil && *s != DR.WDS_PENDING && *s != DR.WDS_DELIVERED && *s != DR.WDS_FAILED {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Status: '" + string(*s) + "' is not valid")
+	}
+	return nil
+}
+
+func (r *WebhookDeliveriesGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	res, err := Repo.WebhookDeliveryCrud.Search(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	result := make([]DA.WebhookDelivery, 0)
+	for _, wd := range res {
+		delivery := DA.WebhookDelivery{}
+		delivery.InitWithDatabaseStruct(wd)
+		result = append(result, delivery)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	cnt, err := Repo.WebhookDeliveryCrud.GetCount(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	resMap["headers"], err = DA.GenerateRangeHeader(cnt, r.SearchParams.PagingSearchParams)
+	if err != nil {
+		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_RANGE)
+	}
+	return resMap, nil
+}
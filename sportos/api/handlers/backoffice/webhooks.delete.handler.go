@@ -0,0 +1,52 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type WebhooksDeleteHandler struct {
+	Id     *string
+	userId string
+}
+
+func (r WebhooksDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r WebhooksDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r WebhooksDeleteHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *WebhooksDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *WebhooksDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	if wh, err := Repo.WebhookCrud.GetById(ctx, *r.Id, nil); err != nil || wh.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Webhook with id " + *r.Id + " doesn't exist")
+	}
+	return nil
+}
+
+// Process deletes webhook, its pending deliveries fail on next attempt
+func (r *WebhooksDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	if err := Repo.WebhookCrud.Delete(ctx, *r.Id, nil, &r.userId); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
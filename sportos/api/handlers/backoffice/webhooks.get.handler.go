@@ -0,0 +1,77 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type WebhooksGetHandler struct {
+	SearchParams *DR.WebhookSearchParams
+}
+
+func (r WebhooksGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r WebhooksGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r WebhooksGetHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *WebhooksGetHandler) Init(httpReq *http.Request) DA.Error {
+	errorMessages := make([]string, 0)
+	var errorMessage string
+	r.SearchParams = &DR.WebhookSearchParams{}
+	r.SearchParams.PlaceId = DA.GetParameterFromURLQuery(httpReq, "placeId")
+	r.SearchParams.EventType = (*DR.OutboxEventType)(DA.ToUpperPointer(DA.GetParameterFromURLQuery(httpReq, "event")))
+
+	r.SearchParams.Active, errorMessage = DA.ParseBool(DA.GetParameterFromURLQuery(httpReq, "active"), "active")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *WebhooksGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	return nil
+}
+
+func (r *WebhooksGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	res, err := Repo.WebhookCrud.Search(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	result := make([]DA.Webhook, 0)
+	for _, wh := range res {
+		webhook := DA.Webhook{}
+		webhook.InitWithDatabaseStruct(wh)
+		result = append(result, webhook)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	cnt, err := Repo.WebhookCrud.GetCount(ctx, *r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.NewApiError().WithInternalError(err)
+	}
+	resMap["headers"], err = DA.GenerateRangeHeader(cnt, r.SearchParams.PagingSearchParams)
+	if err != nil {
+		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_RANGE)
+	}
+	return resMap, nil
+}
@@ -0,0 +1,90 @@
+package backoffice
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type WebhooksPatchHandler struct {
+	WebhooksPatchRequest
+	userId  string
+	webhook DR.Webhook
+}
+
+type WebhooksPatchRequest struct {
+	Id     string    `json:"id"`
+	Url    *string   `json:"url,omitempty"`
+	Secret *string   `json:"secret,omitempty"`
+	Events *[]string `json:"events,omitempty"`
+	Active *bool     `json:"active,omitempty"`
+}
+
+func (r WebhooksPatchHandler) SupportedMethod() string {
+	return http.MethodPatch
+}
+
+func (r WebhooksPatchHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r WebhooksPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *WebhooksPatchHandler) Init(httpReq *http.Request) DA.Error {
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	err := decode.Decode(&r.WebhooksPatchRequest)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *WebhooksPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	wh, err := Repo.WebhookCrud.GetById(ctx, r.Id, nil)
+	if err != nil || wh.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Webhook with id " + r.Id + " doesn't exist")
+	}
+	if r.Secret != nil && *r.Secret == "" {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Secret can't be empty")
+	}
+	rawUrl := wh.Url
+	if r.Url != nil {
+		rawUrl = *r.Url
+	}
+	var events []string
+	if r.Events != nil {
+		events = *r.Events
+	}
+	if err := validateWebhook(ctx, rawUrl, events); err != nil {
+		return err
+	}
+	r.webhook = wh
+	return nil
+}
+
+func (r *WebhooksPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	up := DR.WebhookUpdateParams{
+		Id:     r.Id,
+		Url:    r.Url,
+		Secret: r.Secret,
+		Events: r.Events,
+		Active: r.Active,
+	}
+	ret, err := Repo.WebhookCrud.Update(ctx, up, nil, &r.userId)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	result := DA.Webhook{}
+	result.InitWithDatabaseStruct(ret)
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	return resMap, nil
+}
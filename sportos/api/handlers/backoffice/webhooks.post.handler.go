@@ -0,0 +1,99 @@
+package backoffice
+
+import (
+	H "backend/internal/helpers"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/webhook"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type WebhooksPostHandler struct {
+	WebhooksPostRequest
+	userId string
+}
+
+type WebhooksPostRequest struct {
+	PlaceId string   `json:"placeId"`
+	Url     string   `json:"url"`
+	Secret  string   `json:"secret,omitempty"`
+	Events  []string `json:"events,omitempty"`
+}
+
+func (r WebhooksPostHandler) SupportedMethod() string {
+	return http.MethodPost
+}
+
+func (r WebhooksPostHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_BO}
+}
+
+func (r WebhooksPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_ADMIN}
+}
+
+func (r *WebhooksPostHandler) Init(httpReq *http.Request) DA.Error {
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	err := decode.Decode(&r.WebhooksPostRequest)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *WebhooksPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.PlaceId == "" || r.Url == "" {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Place and url are mandatory")
+	}
+	if place, err := Repo.PlaceCrud.GetById(ctx, r.PlaceId, nil); err != nil || place.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Place " + r.PlaceId + " doesn't exist")
+	}
+	if err := validateWebhook(ctx, r.Url, r.Events); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *WebhooksPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	secret := r.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhook.NewSecret(); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	events := r.Events
+	if events == nil {
+		events = []string{}
+	}
+	ret, err := Repo.WebhookCrud.Create(ctx, DR.Webhook{PlaceId: r.PlaceId, Url: r.Url, Secret: secret, Events: events, Active: true}, nil, &r.userId)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	result := DA.Webhook{}
+	result.InitWithDatabaseStruct(ret)
+	// secret is shown only once, integrator needs it to verify signatures
+	result.Secret = ret.Secret
+	resMap := make(map[string]interface{})
+	resMap["body"] = result
+	return resMap, nil
+}
+
+// validateWebhook checks that url is absolute http(s) url of public host and that events can be delivered to webhooks
+func validateWebhook(ctx context.Context, rawUrl string, events []string) DA.Error {
+	if err := webhook.CheckUrl(ctx, rawUrl); err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Url isn't valid, " + err.Error())
+	}
+	for _, e := range events {
+		if !H.Contains(DR.WebhookEvents, DR.OutboxEventType(e)) {
+			return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Event " + e + " can't be delivered to webhooks")
+		}
+	}
+	return nil
+}
@@ -61,14 +61,12 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"net/http"
-	"time"
 )
 
 type LoginPostRequest struct {
@@ -81,10 +79,11 @@ type LoginPostHandler struct {
 }
 
 type LoginPostResponse struct {
-	Username    string `json:"username,omitempty"`
-	Type        string `json:"type,omitempty"`
-	City        string `json:"city,omitempty"`
-	AccessToken string `json:"accessToken,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Type         string `json:"type,omitempty"`
+	City         string `json:"city,omitempty"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
 }
 
 func (r LoginPostHandler) SupportedMethod() string {
@@ -95,6 +94,10 @@ func (r LoginPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r LoginPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *LoginPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -117,35 +120,28 @@ func (r *LoginPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Err
 }
 
 func (r *LoginPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
-	data, _ := json.Marshal(*r.Password)
-	sha256Hasher.Write(data)
-	dataHash := base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
 	user, err := Repo.UserCrud.GetById(ctx, *r.Username, nil)
-	if err != nil {
+	if err != nil || user.IsDeleted() {
 		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Username doesn't exist")
 	}
-	if user.PasswordHash != dataHash {
+	ok, needsRehash, err := auth.VerifyPassword(*r.Password, user.PasswordHash)
+	if err != nil || !ok {
 		return nil, DA.ErrorUnauthorized().WithMessage("Incorrect password")
 	}
 	if user.EmailVerified < 0 {
 		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MAIL_NOT_VERIFIED).WithMessage("You must verify your email")
 	}
-	token := *r.Username
-	validUntil := time.Now().Add(20 * time.Minute)
-	refreshUntil := time.Now().Add(240 * time.Minute)
-	token += validUntil.Format(time.RFC3339Nano)
-	data, _ = json.Marshal(token)
-	sha256Hasher.Reset()
-	sha256Hasher.Write(data)
-	token = base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
-	up := DR.UserUpdateParams{
-		Id:                *r.Username,
-		Token:             &token,
-		TokenValidUntil:   &validUntil,
-		TokenRefreshUntil: &refreshUntil,
+	if needsRehash {
+		passwordHash, err := auth.HashPassword(*r.Password)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		_, err = Repo.UserCrud.Update(ctx, DR.UserUpdateParams{Id: user.Username, PasswordHash: &passwordHash}, nil, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
 	}
-	_, err = Repo.UserCrud.Update(ctx, up, nil, nil)
+	tokens, err := auth.IssueTokens(user.Username, user.UserType, user.TokenVersion)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -162,6 +158,6 @@ func (r *LoginPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interf
 		city = co.City
 	}
 	resMap := make(map[string]interface{})
-	resMap["body"] = LoginPostResponse{AccessToken: token, Type: string(user.UserType), City: city, Username: user.Username}
+	resMap["body"] = LoginPostResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken, Type: string(user.UserType), City: city, Username: user.Username}
 	return resMap, nil
 }
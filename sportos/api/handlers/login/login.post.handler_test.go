@@ -0,0 +1,55 @@
+package login_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoginRehashesLegacyPasswordInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	// base64url(sha256(json("S3cret-password"))), format of hashes made before argon2id
+	legacy := "e6D93VbPTXm4zIbOJ_ZPyawiVvU6mpvpYGxTPxMsjuo="
+	if _, err := h.Repo.UserCrud.Update(ctx, DR.UserUpdateParams{Id: apitest.FIXTURE_PLAYER, PasswordHash: &legacy}, nil, nil); err != nil {
+		t.Fatalf("set legacy hash: %v", err)
+	}
+	login := func(password string) apitest.Response {
+		t.Helper()
+		return h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_LOGIN, map[string]string{"username": apitest.FIXTURE_PLAYER, "password": password})
+	}
+
+	if res := login("wrong"); res.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for wrong password, got %d: %s", res.Code, res.Body)
+	}
+	usr, _ := h.Repo.UserCrud.GetById(ctx, apitest.FIXTURE_PLAYER, nil)
+	if usr.PasswordHash != legacy {
+		t.Fatalf("expected legacy hash to stay after failed login, got %s", usr.PasswordHash)
+	}
+
+	if res := login("S3cret-password"); res.Code != http.StatusOK {
+		t.Fatalf("login with legacy hash: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	usr, _ = h.Repo.UserCrud.GetById(ctx, apitest.FIXTURE_PLAYER, nil)
+	if !strings.HasPrefix(usr.PasswordHash, auth.PH_ARGON2ID+"$") {
+		t.Fatalf("expected legacy hash to be rewritten with %s, got %s", auth.PH_ARGON2ID, usr.PasswordHash)
+	}
+	if ok, needsRehash, err := auth.VerifyPassword("S3cret-password", usr.PasswordHash); !ok || needsRehash || err != nil {
+		t.Errorf("expected rewritten hash to verify without rehash, got %v %v %v", ok, needsRehash, err)
+	}
+	rewritten := usr.PasswordHash
+
+	if res := login("S3cret-password"); res.Code != http.StatusOK {
+		t.Fatalf("login with rewritten hash: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	usr, _ = h.Repo.UserCrud.GetById(ctx, apitest.FIXTURE_PLAYER, nil)
+	if usr.PasswordHash != rewritten {
+		t.Errorf("expected hash to be rewritten only once, got %s", usr.PasswordHash)
+	}
+}
@@ -61,30 +61,30 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"net/http"
-	"time"
 )
 
 type LoginPutRequest struct {
-	Username *string `json:"username"`
-	Token    *string `json:"accessToken"`
+	Username     *string `json:"username"`
+	RefreshToken *string `json:"refreshToken"`
 }
 
 type LoginPutHandler struct {
 	LoginPutRequest
+	claims auth.Claims
 }
 
 type LoginPutResponse struct {
-	Username    string `json:"username,omitempty"`
-	Type        string `json:"type,omitempty"`
-	City        string `json:"city,omitempty"`
-	AccessToken string `json:"accessToken,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Type         string `json:"type,omitempty"`
+	City         string `json:"city,omitempty"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
 }
 
 func (r LoginPutHandler) SupportedMethod() string {
@@ -95,6 +95,10 @@ func (r LoginPutHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r LoginPutHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *LoginPutHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -107,53 +111,45 @@ func (r *LoginPutHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *LoginPutHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	if r.Username == nil {
-		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Username is mandatory")
+	if r.RefreshToken == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Refresh token is mandatory")
 	}
-	if r.Token == nil {
-		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Token is mandatory")
+	claims, err := auth.VerifyRefreshToken(*r.RefreshToken)
+	if err != nil {
+		return DA.ErrorUnauthorized().WithMessage("token can't be refreshed, " + err.Error())
 	}
+	if r.Username != nil && *r.Username != claims.Subject {
+		return DA.ErrorUnauthorized().WithMessage("token doesn't belong to user")
+	}
+	r.claims = claims
 	return nil
 }
 
 func (r *LoginPutHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
-	user, err := Repo.UserCrud.GetById(ctx, *r.Username, nil)
-	if err != nil {
+	user, err := Repo.UserCrud.GetById(ctx, r.claims.Subject, nil)
+	if err != nil || user.IsDeleted() {
 		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Username doesn't exist")
 	}
-	token := *r.Username
-	validUntil := time.Now().Add(20 * time.Minute)
-	if validUntil.After(*user.TokenRefreshUntil) {
-		return nil, DA.ErrorUnauthorized().WithMessage("token can't  be refreshed, it expired")
-	}
-	token += validUntil.Format(time.RFC3339Nano)
-	data, _ := json.Marshal(token)
-	sha256Hasher.Reset()
-	sha256Hasher.Write(data)
-	token = base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
-	up := DR.UserUpdateParams{
-		Id:              *r.Username,
-		Token:           &token,
-		TokenValidUntil: &validUntil,
+	if r.claims.Version != user.TokenVersion {
+		return nil, DA.ErrorUnauthorized().WithMessage("token can't be refreshed, it was revoked")
 	}
-	_, err = Repo.UserCrud.Update(ctx, up, nil, nil)
+	tokens, err := auth.RefreshTokens(r.claims)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
 	city := ""
 	switch user.UserType {
 	case DR.UT_PLACE:
-		pla, _ := Repo.PlaceCrud.GetById(ctx, *r.Username, nil)
+		pla, _ := Repo.PlaceCrud.GetById(ctx, user.Username, nil)
 		city = pla.City
 	case DR.UT_PLAYER:
-		pl, _ := Repo.PlayerCrud.GetById(ctx, *r.Username, nil)
+		pl, _ := Repo.PlayerCrud.GetById(ctx, user.Username, nil)
 		city = pl.City
 	case DR.UT_COACH:
-		co, _ := Repo.CoachCrud.GetById(ctx, *r.Username, nil)
+		co, _ := Repo.CoachCrud.GetById(ctx, user.Username, nil)
 		city = co.City
 	}
 	resMap := make(map[string]interface{})
-	resMap["body"] = LoginPutResponse{AccessToken: token, Type: string(user.UserType), City: city, Username: user.Username}
+	resMap["body"] = LoginPutResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken, Type: string(user.UserType), City: city, Username: user.Username}
 	return resMap, nil
 }
@@ -61,6 +61,7 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
@@ -69,7 +70,9 @@ import (
 )
 
 type LogoutPostHandler struct {
-	Username string `json:"username"`
+	Username     *string `json:"username"`
+	RefreshToken *string `json:"refreshToken"`
+	claims       auth.Claims
 }
 
 func (r LogoutPostHandler) SupportedMethod() string {
@@ -80,6 +83,10 @@ func (r LogoutPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r LogoutPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *LogoutPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -92,19 +99,29 @@ func (r *LogoutPostHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *LogoutPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	_, err := Repo.UserCrud.GetById(ctx, r.Username, nil)
+	if r.RefreshToken == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Refresh token is mandatory")
+	}
+	claims, err := auth.VerifyRefreshToken(*r.RefreshToken)
+	if err != nil {
+		return DA.ErrorUnauthorized().WithMessage("token is invalid, " + err.Error())
+	}
+	if r.Username != nil && *r.Username != claims.Subject {
+		return DA.ErrorUnauthorized().WithMessage("token doesn't belong to user")
+	}
+	_, err = Repo.UserCrud.GetById(ctx, claims.Subject, nil)
 	if err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Username doesn't exist")
 	}
+	r.claims = claims
 	return nil
 }
 
+// Process revokes all access and refresh tokens of user
 func (r *LogoutPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
 	up := DR.UserUpdateParams{
-		Id:                r.Username,
-		Token:             nil,
-		TokenValidUntil:   nil,
-		TokenRefreshUntil: nil,
+		Id:           r.claims.Subject,
+		RevokeTokens: true,
 	}
 	_, err := Repo.UserCrud.Update(ctx, up, nil, nil)
 	if err != nil {
@@ -0,0 +1,70 @@
+package login_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	LO "backend/sportos/api/handlers/login"
+	"backend/sportos/auth"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func refresh(h *apitest.Harness, token string) apitest.Response {
+	h.T.Helper()
+	return h.Anonymous().Do(DR.SUB_LO, http.MethodPut, DA.HN_LOGIN, LO.LoginPutRequest{RefreshToken: &token})
+}
+
+func TestLogoutRevokesTokensInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	player := h.Player()
+	if res := player.Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, nil); res.Code != http.StatusOK {
+		t.Fatalf("notifications: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	login, err := auth.IssueTokens(apitest.FIXTURE_PLAYER, DR.UT_PLAYER, 0)
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	res := refresh(h, login.RefreshToken)
+	if res.Code != http.StatusOK {
+		t.Fatalf("refresh: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var refreshed LO.LoginPutResponse
+	res.Decode(t, &refreshed)
+
+	res = h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_LOGOUT, map[string]string{"username": apitest.FIXTURE_SECOND_PLAYER, "refreshToken": refreshed.RefreshToken})
+	if res.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for logout with token of other user, got %d: %s", res.Code, res.Body)
+	}
+
+	res = h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_LOGOUT, map[string]string{"refreshToken": refreshed.RefreshToken})
+	if res.Code != http.StatusOK {
+		t.Fatalf("logout: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	for name, token := range map[string]string{"login": login.RefreshToken, "refreshed": refreshed.RefreshToken} {
+		if res = refresh(h, token); res.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for %s refresh token after logout, got %d: %s", name, res.Code, res.Body)
+		}
+	}
+	if res = player.Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, nil); res.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for access token after logout, got %d: %s", res.Code, res.Body)
+	}
+	if res = h.Player().Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, nil); res.Code != http.StatusOK {
+		t.Errorf("expected 200 for access token of new login, got %d: %s", res.Code, res.Body)
+	}
+
+	usr, err := h.Repo.UserCrud.GetById(context.Background(), apitest.FIXTURE_PLAYER, nil)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	login, err = auth.IssueTokens(usr.Username, usr.UserType, usr.TokenVersion)
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	if res = refresh(h, login.RefreshToken); res.Code != http.StatusOK {
+		t.Errorf("expected 200 for refresh token of new login, got %d: %s", res.Code, res.Body)
+	}
+}
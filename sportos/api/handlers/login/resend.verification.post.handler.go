@@ -0,0 +1,80 @@
+package login
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/mail"
+)
+
+type ResendVerificationPostHandler struct {
+	ResendVerificationPostRequest
+	user DR.User
+}
+
+type ResendVerificationPostRequest struct {
+	Email string `json:"email,omitempty"`
+}
+
+func (r ResendVerificationPostHandler) SupportedMethod() string {
+	return http.MethodPost
+}
+
+func (r ResendVerificationPostHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_LO}
+}
+
+func (r ResendVerificationPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *ResendVerificationPostHandler) Init(httpReq *http.Request) DA.Error {
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	err := decode.Decode(&r.ResendVerificationPostRequest)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *ResendVerificationPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Email == "" {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Email is mandatory")
+	}
+	_, err := mail.ParseAddress(r.Email)
+	if err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithMessage("Email isn't valid")
+	}
+	r.user, err = Repo.UserCrud.GetByEmail(ctx, r.Email, nil)
+	if err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Email doesn't belong to any user")
+	}
+	if r.user.EmailVerified >= 0 {
+		return DA.ErrorBadRequest().WithMessage("Email is already verified")
+	}
+	return nil
+}
+
+func (r *ResendVerificationPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	apiErr := DA.SendVerificationMail(ctx, Repo, r.user, DR.TP_VERIFY_EMAIL, tx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = "Please verify your email to complete you registration"
+	return resMap, nil
+}
@@ -0,0 +1,78 @@
+package login_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerificationTokensInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+	email := "unverified@test.com"
+	if _, err := h.Repo.UserCrud.Create(ctx, DR.User{Username: "unverified", Email: email, EmailVerified: DR.EMAIL_NOT_VERIFIED, UserType: DR.UT_PLAYER}, nil, nil); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	resend := func() apitest.Response {
+		return h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_RESEND_VERIFY, map[string]string{"email": email})
+	}
+	verify := func(token string) apitest.Response {
+		return h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_VERIFY, map[string]string{"verifyToken": token})
+	}
+	var tokens []string
+	for i := 0; i < auth.VERIFICATION_TOKEN_LIMIT; i++ {
+		if res := resend(); res.Code != http.StatusOK {
+			t.Fatalf("resend %d: expected 200, got %d: %s", i+1, res.Code, res.Body)
+		}
+		sent := sendMails(h)
+		if len(sent) != 1 || sent[0].To[0] != email {
+			t.Fatalf("resend %d: expected verification mail to %s, got %+v", i+1, email, sent)
+		}
+		tokens = append(tokens, linkParam(t, sent[0].Text, "verifyToken"))
+	}
+	if res := resend(); res.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 for request over limit in %v, got %d: %s", auth.VERIFICATION_TOKEN_WINDOW, res.Code, res.Body)
+	}
+	if sent := sendMails(h); len(sent) != 0 {
+		t.Errorf("expected no mail for refused request, got %+v", sent)
+	}
+
+	// new token invalidates previous ones
+	for i, token := range tokens[:len(tokens)-1] {
+		if res := verify(token); res.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for token %d replaced by newer one, got %d: %s", i+1, res.Code, res.Body)
+		}
+	}
+
+	expired, expiredHash, err := auth.NewVerificationToken()
+	if err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	vt := DR.VerificationToken{TokenHash: expiredHash, UserId: "unverified", Purpose: DR.TP_VERIFY_EMAIL, ValidUntil: time.Now().Add(-time.Minute)}
+	if _, err := h.Repo.VerificationTokenCrud.Create(ctx, vt, nil, nil); err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if res := verify(expired); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for expired token, got %d: %s", res.Code, res.Body)
+	}
+
+	last := tokens[len(tokens)-1]
+	if res := verify(last); res.Code != http.StatusOK {
+		t.Fatalf("verify: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if usr, err := h.Repo.UserCrud.GetById(ctx, "unverified", nil); err != nil || usr.EmailVerified != DR.EMAIL_VERIFIED {
+		t.Errorf("expected verified email, got %+v, %v", usr, err)
+	}
+	if res := verify(last); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for token that was used, got %d: %s", res.Code, res.Body)
+	}
+	if res := resend(); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for resend to verified email, got %d: %s", res.Code, res.Body)
+	}
+}
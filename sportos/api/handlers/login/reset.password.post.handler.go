@@ -61,14 +61,12 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"net/http"
-	"strings"
 )
 
 type ResetPasswordPostHandler struct {
@@ -89,6 +87,10 @@ func (r ResetPasswordPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r ResetPasswordPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *ResetPasswordPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -101,39 +103,41 @@ func (r *ResetPasswordPostHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *ResetPasswordPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	token, err := base64.URLEncoding.DecodeString(r.ResetToken)
+	token, err := Repo.VerificationTokenCrud.GetValid(ctx, auth.HashVerificationToken(r.ResetToken), DR.TP_RESET_PASSWORD, nil)
 	if err != nil {
-		return DA.ErrorBadRequest()
-	}
-	decoded := strings.Split(string(token), "___")
-	if len(decoded) != 2 {
-		return DA.ErrorBadRequest()
-	}
-	username, password := decoded[0], decoded[1]
-	user, err := Repo.UserCrud.GetById(ctx, username, nil)
-	if err != nil {
-		return DA.ErrorBadRequest()
-	}
-	if user.PasswordHash != password {
-		return DA.ErrorBadRequest()
+		return DA.ErrorBadRequest().WithMessage("Reset link is invalid or expired")
 	}
 	if !verifyPassword(r.Password) {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Password isn't strong enough")
 	}
-	r.Username = username
+	r.Username = token.UserId
 	return nil
 }
 
 func (r *ResetPasswordPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
-	data, _ := json.Marshal(r.Password)
-	sha256Hasher.Write(data)
-	dataHash := base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
+	dataHash, err := auth.HashPassword(r.Password)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	_, err = Repo.VerificationTokenCrud.Consume(ctx, auth.HashVerificationToken(r.ResetToken), DR.TP_RESET_PASSWORD, tx)
+	if err != nil {
+		return nil, DA.ErrorBadRequest().WithMessage("Reset link is invalid or expired")
+	}
 	up := DR.UserUpdateParams{
 		Id:           r.Username,
 		PasswordHash: &dataHash,
+		RevokeTokens: true,
+	}
+	_, err = Repo.UserCrud.Update(ctx, up, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	_, err := Repo.UserCrud.Update(ctx, up, nil, nil)
+	err = tx.Commit()
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
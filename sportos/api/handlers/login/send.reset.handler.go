@@ -5,7 +5,6 @@ import (
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/mail"
@@ -27,6 +26,10 @@ func (r SendResetPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r SendResetPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *SendResetPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -54,11 +57,20 @@ func (r *SendResetPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA
 }
 
 func (r *SendResetPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	host := "https://localhost:4200"
 	user, _ := Repo.UserCrud.GetByEmail(ctx, r.Email, nil)
-	resetToken := base64.URLEncoding.EncodeToString([]byte(user.Username + "___" + user.PasswordHash))
-	message := "\nReset your sportos password by clicking on link " + host + "/reset-password?resetToken=" + resetToken
-	DA.SendMail(message, "Reset password", []string{r.Email})
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	apiErr := DA.SendVerificationMail(ctx, Repo, user, DR.TP_RESET_PASSWORD, tx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = struct{}{}
 	return resMap, nil
@@ -0,0 +1,85 @@
+package login_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
+	"backend/sportos/mail"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// sendMails sends queued mails with memory mailer and returns them
+func sendMails(h *apitest.Harness) []mail.Message {
+	h.T.Helper()
+	mm := mail.NewMemoryMailer()
+	old := mail.M
+	mail.M = mm
+	defer func() { mail.M = old }()
+	if _, err := mail.NewOutboxWorker(h.Repo).ProcessPending(context.Background()); err != nil {
+		h.T.Fatalf("send mails: %v", err)
+	}
+	return mm.Messages()
+}
+
+// linkParam returns query parameter of first link in text
+func linkParam(t *testing.T, text, param string) string {
+	t.Helper()
+	for _, field := range strings.Fields(text) {
+		if !strings.HasPrefix(field, "http") {
+			continue
+		}
+		link, err := url.Parse(field)
+		if err != nil {
+			t.Fatalf("parse link %s: %v", field, err)
+		}
+		return link.Query().Get(param)
+	}
+	t.Fatalf("no link in %q", text)
+	return ""
+}
+
+func TestSendResetQueuesMailInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+
+	res := h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_SEND_RESET, map[string]string{"email": "nobody@test.com"})
+	if res.Code == http.StatusOK {
+		t.Errorf("expected error for email without user, got %d", res.Code)
+	}
+	if sent := sendMails(h); len(sent) != 0 {
+		t.Fatalf("expected no mail for unknown email, got %+v", sent)
+	}
+
+	login, err := auth.IssueTokens(apitest.FIXTURE_PLAYER, DR.UT_PLAYER, 0)
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	res = h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_SEND_RESET, map[string]string{"email": "test1@test.com"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("send reset: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	sent := sendMails(h)
+	if len(sent) != 1 || len(sent[0].To) != 1 || sent[0].To[0] != "test1@test.com" || sent[0].Subject != "Reset password" {
+		t.Fatalf("expected reset mail to test1@test.com, got %+v", sent)
+	}
+	if !strings.Contains(sent[0].Text, "/reset-password?resetToken=") || sent[0].Html == "" {
+		t.Errorf("expected reset link in mail, got %q", sent[0].Text)
+	}
+	token := linkParam(t, sent[0].Text, "resetToken")
+
+	res = h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_RESET_PASSWORD, map[string]string{"resetToken": token, "password": "N3w-password"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("reset password: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if res = refresh(h, login.RefreshToken); res.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for refresh token issued before password reset, got %d: %s", res.Code, res.Body)
+	}
+	res = h.Anonymous().Do(DR.SUB_LO, http.MethodPost, DA.HN_RESET_PASSWORD, map[string]string{"resetToken": token, "password": "An0ther-password"})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for reset token that was used, got %d: %s", res.Code, res.Body)
+	}
+}
@@ -61,14 +61,12 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"net/http"
-	"time"
 )
 
 type SocialLoginPostRequest struct {
@@ -80,10 +78,11 @@ type SocialLoginPostHandler struct {
 }
 
 type SocialLoginPostResponse struct {
-	Username    string `json:"username,omitempty"`
-	Type        string `json:"type,omitempty"`
-	City        string `json:"city,omitempty"`
-	AccessToken string `json:"accessToken,omitempty"`
+	Username     string `json:"username,omitempty"`
+	Type         string `json:"type,omitempty"`
+	City         string `json:"city,omitempty"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
 }
 
 func (r SocialLoginPostHandler) SupportedMethod() string {
@@ -94,6 +93,10 @@ func (r SocialLoginPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r SocialLoginPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *SocialLoginPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -113,26 +116,11 @@ func (r *SocialLoginPostHandler) Validate(ctx context.Context, Repo *crud.Repo)
 }
 
 func (r *SocialLoginPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
 	user, err := Repo.UserCrud.GetById(ctx, *r.Username, nil)
-	if err != nil {
+	if err != nil || user.IsDeleted() {
 		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Username doesn't exist")
 	}
-	token := *r.Username
-	validUntil := time.Now().Add(20 * time.Minute)
-	refreshUntil := time.Now().Add(240 * time.Minute)
-	token += validUntil.Format(time.RFC3339Nano)
-	data, _ := json.Marshal(token)
-	sha256Hasher.Reset()
-	sha256Hasher.Write(data)
-	token = base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
-	up := DR.UserUpdateParams{
-		Id:                *r.Username,
-		Token:             &token,
-		TokenValidUntil:   &validUntil,
-		TokenRefreshUntil: &refreshUntil,
-	}
-	_, err = Repo.UserCrud.Update(ctx, up, nil, nil)
+	tokens, err := auth.IssueTokens(user.Username, user.UserType, user.TokenVersion)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -149,6 +137,6 @@ func (r *SocialLoginPostHandler) Process(ctx context.Context, Repo *crud.Repo) (
 		city = co.City
 	}
 	resMap := make(map[string]interface{})
-	resMap["body"] = SocialLoginPostResponse{AccessToken: token, Type: string(user.UserType), City: city, Username: user.Username}
+	resMap["body"] = SocialLoginPostResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken, Type: string(user.UserType), City: city, Username: user.Username}
 	return resMap, nil
 }
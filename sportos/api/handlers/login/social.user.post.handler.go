@@ -2,6 +2,7 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/events"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
@@ -22,6 +23,8 @@ type SocialUserPostRequest struct {
 	Name     string `json:"name,omitempty"`
 	Sport    string `json:"sport,omitempty"`
 	City     string `json:"city,omitempty"`
+	// TimeZone is IANA time zone of place or coach, zone of known city is used if it's missing
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 func (r SocialUserPostHandler) SupportedMethod() string {
@@ -32,6 +35,10 @@ func (r SocialUserPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r SocialUserPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *SocialUserPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -81,6 +88,12 @@ func (r *SocialUserPostHandler) Validate(ctx context.Context, Repo *crud.Repo) D
 	if r.City == "" {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("City is mandatory")
 	}
+	if r.UserType == string(DR.UT_PLACE) || r.UserType == string(DR.UT_COACH) {
+		var apiErr DA.Error
+		if r.TimeZone, apiErr = timeZone(r.City, r.TimeZone); apiErr != nil {
+			return apiErr
+		}
+	}
 	return nil
 }
 
@@ -92,7 +105,7 @@ func (r *SocialUserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (i
 		PasswordHash:  "",
 		UserType:      DR.UserType(r.UserType),
 	}
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -118,6 +131,7 @@ func (r *SocialUserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (i
 			Name:     r.Name,
 			Sport:    r.Sport,
 			City:     r.City,
+			TimeZone: r.TimeZone,
 		}
 		_, err := Repo.CoachCrud.Create(ctx, coach, tx, nil)
 		if err != nil {
@@ -129,12 +143,16 @@ func (r *SocialUserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (i
 			Name:     r.Name,
 			Sport:    r.Sport,
 			City:     r.City,
+			TimeZone: r.TimeZone,
 		}
 		_, err := Repo.PlaceCrud.Create(ctx, place, tx, nil)
 		if err != nil {
 			return nil, DA.InternalServerError(err)
 		}
 	}
+	if err = events.Publish(ctx, Repo, tx, events.UserRegistered{Username: r.Username, UserType: DR.UserType(r.UserType)}); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	tx.Commit()
 	resMap := make(map[string]interface{})
 	resMap["body"] = struct{}{}
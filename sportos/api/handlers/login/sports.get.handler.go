@@ -78,6 +78,10 @@ func (r SportsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r SportsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *SportsGetHandler) Init(httpReq *http.Request) DA.Error {
 	return nil
 }
@@ -2,14 +2,13 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
+	"backend/sportos/availability"
+	"backend/sportos/events"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"math/rand"
 	"net/http"
 	"net/mail"
 	"strings"
@@ -28,6 +27,8 @@ type UserPostRequest struct {
 	Name     string `json:"name,omitempty"`
 	Sport    string `json:"sport,omitempty"`
 	City     string `json:"city,omitempty"`
+	// TimeZone is IANA time zone of place or coach, zone of known city is used if it's missing
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 func (r UserPostHandler) SupportedMethod() string {
@@ -38,6 +39,10 @@ func (r UserPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r UserPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *UserPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -92,22 +97,28 @@ func (r *UserPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Erro
 	if r.City == "" {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("City is mandatory")
 	}
+	if r.UserType == string(DR.UT_PLACE) || r.UserType == string(DR.UT_COACH) {
+		var apiErr DA.Error
+		if r.TimeZone, apiErr = timeZone(r.City, r.TimeZone); apiErr != nil {
+			return apiErr
+		}
+	}
 	return nil
 }
 
 func (r *UserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sha256Hasher := sha256.New()
-	data, _ := json.Marshal(r.Password)
-	sha256Hasher.Write(data)
-	dataHash := base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
+	dataHash, err := auth.HashPassword(r.Password)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	user := DR.User{
 		Username:      r.Username,
 		Email:         r.Email,
-		EmailVerified: rand.Intn(9) - 9,
+		EmailVerified: DR.EMAIL_NOT_VERIFIED,
 		PasswordHash:  dataHash,
 		UserType:      DR.UserType(r.UserType),
 	}
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -133,6 +144,7 @@ func (r *UserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interfa
 			Name:     r.Name,
 			Sport:    r.Sport,
 			City:     r.City,
+			TimeZone: r.TimeZone,
 		}
 		_, err := Repo.CoachCrud.Create(ctx, coach, tx, nil)
 		if err != nil {
@@ -144,21 +156,44 @@ func (r *UserPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interfa
 			Name:     r.Name,
 			Sport:    r.Sport,
 			City:     r.City,
+			TimeZone: r.TimeZone,
 		}
 		_, err := Repo.PlaceCrud.Create(ctx, place, tx, nil)
 		if err != nil {
 			return nil, DA.InternalServerError(err)
 		}
 	}
-	host := "https://localhost:4200"
-	message := "\nPlease verify your email address for sportos by clicking on link " + host + "/verify?verifyToken=" + r.Username + "___" + fmt.Sprint(-user.EmailVerified) + "___" + user.PasswordHash
-	DA.SendMail(message, "Verify email", []string{r.Email})
-	tx.Commit()
+	if err = events.Publish(ctx, Repo, tx, events.UserRegistered{Username: r.Username, UserType: user.UserType}); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	apiErr := DA.SendVerificationMail(ctx, Repo, user, DR.TP_VERIFY_EMAIL, tx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = "Please verify your email to complete you registration"
 	return resMap, nil
 }
 
+// timeZone returns time zone of place or coach, tz is checked if it's sent, otherwise zone of city is used
+func timeZone(city, tz string) (string, DA.Error) {
+	if tz == "" {
+		zone, ok := DR.TimeZoneOfCity(city)
+		if !ok {
+			return "", DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Time zone is mandatory for city " + city)
+		}
+		return zone, nil
+	}
+	if _, err := availability.Location(tz); err != nil {
+		return "", DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Time zone " + tz + " doesn't exist")
+	}
+	return tz, nil
+}
+
 func verifyPassword(s string) bool {
 	letters := len(s)
 	var number, upper, special, lower bool
@@ -2,12 +2,12 @@ package login
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 )
 
 type UserVerifyPostHandler struct {
@@ -22,6 +22,10 @@ func (r UserVerifyPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_LO}
 }
 
+func (r UserVerifyPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *UserVerifyPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -34,29 +38,32 @@ func (r *UserVerifyPostHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *UserVerifyPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.VerifyToken == "" {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Verify token is mandatory")
+	}
 	return nil
 }
 
 func (r *UserVerifyPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	decoded := strings.Split(r.VerifyToken, "___")
-	if len(decoded) != 3 {
-		return nil, DA.ErrorBadRequest()
-	}
-	username := decoded[0]
-	salt := int(decoded[1][0] - '0')
-	passwordHash := decoded[2]
-	user, err := Repo.UserCrud.GetById(ctx, username, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
-		return nil, DA.ErrorBadRequest()
+		return nil, DA.InternalServerError(err)
 	}
-	if user.PasswordHash != passwordHash || user.EmailVerified != -salt {
-		return nil, DA.ErrorBadRequest()
+	defer tx.Rollback()
+	token, err := Repo.VerificationTokenCrud.Consume(ctx, auth.HashVerificationToken(r.VerifyToken), DR.TP_VERIFY_EMAIL, tx)
+	if err != nil {
+		return nil, DA.ErrorBadRequest().WithMessage("Verification link is invalid or expired")
 	}
+	verified := DR.EMAIL_VERIFIED
 	up := DR.UserUpdateParams{
-		Id:            username,
-		EmailVerified: &salt,
+		Id:            token.UserId,
+		EmailVerified: &verified,
+	}
+	_, err = Repo.UserCrud.Update(ctx, up, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	_, err = Repo.UserCrud.Update(ctx, up, nil, nil)
+	err = tx.Commit()
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -23,6 +23,10 @@ func (r CoachsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r CoachsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *CoachsGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.CoachId = DA.GetParameterFromURLQuery(httpReq, "playerId")
 	r.City = DA.GetParameterFromURLQuery(httpReq, "city")
@@ -0,0 +1,102 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+// LeaderboardGetHandler returns players of sport, optionally only from one city, ranked by win ratio,
+// number of matches, tournament placements or rating. Leaderboard is read from materialized rows
+// that are updated together with statistics of player
+type LeaderboardGetHandler struct {
+	SearchParams DR.LeaderboardSearchParams
+	minMatches   *int64
+}
+
+func (r LeaderboardGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r LeaderboardGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r LeaderboardGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *LeaderboardGetHandler) Init(httpReq *http.Request) DA.Error {
+	errorMessages := make([]string, 0)
+	var errorMessage string
+	r.SearchParams.Sport = DA.GetParameterFromURLQuery(httpReq, "sport")
+	r.SearchParams.City = DA.GetParameterFromURLQuery(httpReq, "city")
+	if by := DA.GetParameterFromURLQuery(httpReq, "by"); by != nil {
+		r.SearchParams.By = DR.LeaderboardMetric(*by)
+	}
+
+	r.minMatches, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "minMatches"), "minMatches")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *LeaderboardGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.SearchParams.Sport == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Sport is mandatory")
+	}
+	if _, err := DR.GetSportByName(*r.SearchParams.Sport); err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Sport doesn't exist")
+	}
+	if r.SearchParams.By == "" {
+		r.SearchParams.By = DR.LM_WIN_RATIO
+	} else if !r.SearchParams.By.IsValid() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Leaderboard can be ranked by winRatio, matches, tournaments or rating")
+	}
+	if r.minMatches != nil {
+		minMatches := int(*r.minMatches)
+		r.SearchParams.MinMatches = &minMatches
+	}
+	return nil
+}
+
+func (r *LeaderboardGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	rows, err := Repo.LeaderboardCrud.Search(ctx, r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	rank := 1
+	if r.SearchParams.Offset != nil && *r.SearchParams.Offset > 0 {
+		rank += int(*r.SearchParams.Offset)
+	}
+	ret := []DA.Ranking{}
+	for i, lb := range rows {
+		entry := DA.Ranking{Rank: rank + i}
+		entry.InitWithDatabaseStruct(lb)
+		ret = append(ret, entry)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = ret
+	cnt, err := Repo.LeaderboardCrud.GetCount(ctx, r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap["headers"], err = DA.GenerateRangeHeader(cnt, r.SearchParams.PagingSearchParams)
+	if err != nil {
+		return nil, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_RANGE)
+	}
+	return resMap, nil
+}
@@ -0,0 +1,88 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/events"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type MatchDeleteHandler struct {
+	Id     *string `json:"id,omitempty"`
+	userId string
+	match  DR.Match
+}
+
+func (r MatchDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r MatchDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r MatchDeleteHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
+func (r *MatchDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *MatchDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	match, err := Repo.MatchCrud.GetById(ctx, *r.Id, nil)
+	if err != nil || match.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Match with id " + *r.Id + " doesn't exist")
+	}
+	if match.Status == DR.MS_FINISHED {
+		return DA.ErrorBadRequest().WithMessage("Can't delete match that is over")
+	}
+	if match.Status == DR.MS_RESULT_PENDING || match.Status == DR.MS_DISPUTED {
+		return DA.ErrorBadRequest().WithMessage("Can't delete match that has submitted result")
+	}
+	r.match = match
+	return nil
+}
+
+// CheckOwnership allows only player who created match (first player) to delete it
+func (r *MatchDeleteHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.match.Creator() != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only player who created match can delete it")
+	}
+	return nil
+}
+
+func (r *MatchDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	err = Repo.MatchCrud.Delete(ctx, r.match.MatchId, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	// free appointment that was booked for match at place
+	err = cancelBookings(ctx, Repo, DR.BK_MATCH, r.match.MatchId, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = events.Publish(ctx, Repo, tx, events.MatchCancelled{MatchId: r.match.MatchId, PlaceId: r.match.PlaceId})
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
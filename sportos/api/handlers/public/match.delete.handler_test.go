@@ -0,0 +1,53 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMatchDeleteByCreatorInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	players := DR.StrArr{apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER}
+	start := time.Now().UTC().Add(time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	hn := DA.HN_MATCHES + "?id=" + match.MatchId
+	res := h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER).Do(DR.SUB_CL, http.MethodDelete, hn, nil)
+	if res.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for player who didn't create match, got %d: %s", res.Code, res.Body)
+	}
+
+	res = h.Player().Do(DR.SUB_CL, http.MethodDelete, hn, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("delete match: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	matches, err := h.Repo.MatchCrud.Search(ctx, DR.MatchSearchParams{}, nil)
+	if err != nil {
+		t.Fatalf("search matches: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected deleted match to be excluded from search, got %+v", matches)
+	}
+
+	res = h.Player().Do(DR.SUB_CL, http.MethodDelete, hn, nil)
+	if res.Code == http.StatusOK {
+		t.Errorf("expected error for match that is already deleted, got %d", res.Code)
+	}
+}
@@ -1,7 +1,6 @@
 package public
 
 import (
-	H "backend/internal/helpers"
 	DA "backend/sportos/api/dto"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
@@ -23,6 +22,10 @@ func (r MatchGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r MatchGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *MatchGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.PlayerId = DA.GetParameterFromURLQuery(httpReq, "playerId")
 	r.Sports = DA.ParseCommaSeparated(DA.GetParameterFromURLQuery(httpReq, "sports"))
@@ -74,36 +77,25 @@ func (r *MatchGetHandler) formatMatches(ctx context.Context, Repo *crud.Repo, ma
 	for i := range matches {
 		place, _ := Repo.PlaceCrud.GetById(ctx, matches[i].PlaceId, nil)
 		matches[i].PlaceId = place.Name
-		players := strings.Split(*matches[i].Players, ",")
-		playerNames := ""
-		for j := range players {
-			player, _ := Repo.PlayerCrud.GetById(ctx, players[j], nil)
-			playerNames = playerNames + player.Name + ","
-		}
-		playerNames = playerNames[0 : len(playerNames)-1]
-		matches[i].PlayerNames = &playerNames
-		if len(matches[i].Teams) != 0 {
-			team := strings.Split(matches[i].Teams[0], ",")
-			teamNames := ""
-			for j := range team {
-				player, _ := Repo.PlayerCrud.GetById(ctx, team[j], nil)
-				teamNames = teamNames + player.Name + ","
-			}
-			teamNames = teamNames[0 : len(teamNames)-1]
-			matches[i].Teams[0] = teamNames
-			//
-			team = strings.Split(matches[i].Teams[1], ",")
-			teamNames = ""
-			for j := range team {
-				player, _ := Repo.PlayerCrud.GetById(ctx, team[j], nil)
-				teamNames = teamNames + player.Name + ","
-			}
-			teamNames = teamNames[0 : len(teamNames)-1]
-			matches[i].Teams[1] = teamNames
+		matches[i].StartTime, matches[i].TimeZone = localTime(matches[i].StartTime, place.TimeZone)
+		names := strings.Join(playerNames(ctx, Repo, matches[i].Players), ",")
+		matches[i].PlayerNames = &names
+		for j := range matches[i].Teams {
+			matches[i].Teams[j] = playerNames(ctx, Repo, matches[i].Teams[j])
 		}
-		if !(matches[i].Status == DR.MS_FINISHED || (matches[i].Status == DR.MS_FULL && !H.Contains(players, *r.PlayerId))) {
+		if !(matches[i].Status == DR.MS_FINISHED || (matches[i].Status != DR.MS_CREATED && !matches[i].HasPlayer(*r.PlayerId))) {
 			ret = append(ret, matches[i])
 		}
 	}
 	return ret
 }
+
+// playerNames returns names of players with given ids
+func playerNames(ctx context.Context, Repo *crud.Repo, ids []string) []string {
+	names := []string{}
+	for _, id := range ids {
+		player, _ := Repo.PlayerCrud.GetById(ctx, id, nil)
+		names = append(names, player.Name)
+	}
+	return names
+}
@@ -2,29 +2,36 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/balance"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
+	"backend/sportos/results"
 	"context"
 	"encoding/json"
-	"math/rand"
 	"net/http"
-	"strconv"
 	"strings"
-
-	"github.com/shopspring/decimal"
+	"time"
 )
 
+// MatchPatchHandler adds player to match, submits result of full match or answers result submitted by other
+// side. Result isn't final until player of other side confirms it, disputed result is reviewed in backoffice
 type MatchPatchHandler struct {
 	MatchPatchRequest
-	Players string
-	sport   string
-	status  DR.MatchStatus
+	match  DR.Match
+	userId string
+	// pending is result that waits for confirmation, when request answers it
+	pending DR.MatchResult
 }
 
 type MatchPatchRequest struct {
 	Id     string  `json:"id,omitempty"`
 	Player *string `json:"player,omitempty"`
 	Result *string `json:"result,omitempty"`
+	// Confirm accepts result submitted by other side, it finishes match
+	Confirm *bool `json:"confirm,omitempty"`
+	// Dispute is reason why result submitted by other side isn't accepted
+	Dispute *string `json:"dispute,omitempty"`
 }
 
 func (r MatchPatchHandler) SupportedMethod() string {
@@ -35,7 +42,12 @@ func (r MatchPatchHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r MatchPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *MatchPatchHandler) Init(httpReq *http.Request) DA.Error {
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
 	err := decode.Decode(&r.MatchPatchRequest)
@@ -47,156 +59,250 @@ func (r *MatchPatchHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *MatchPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	if match, err := Repo.MatchCrud.GetById(ctx, r.Id, nil); err != nil {
+	if match, err := Repo.MatchCrud.GetById(ctx, r.Id, nil); err != nil || match.IsDeleted() {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Match with id " + r.Id + " doesn't exist")
 	} else {
-		if r.Player != nil && strings.Contains(*match.Players, *r.Player) {
+		if r.Player != nil && match.HasPlayer(*r.Player) {
 			return DA.ErrorBadRequest().WithMessage("Player is already in that match")
 		}
+		if r.Confirm != nil && !*r.Confirm {
+			return DA.ErrorBadRequest().WithMessage("Result can only be confirmed, dispute it instead")
+		}
+		if (r.Result != nil && r.answers()) || (r.Confirm != nil && r.Dispute != nil) {
+			return DA.ErrorBadRequest().WithMessage("Only one of result, confirm and dispute can be sent")
+		}
 		if r.Result != nil && match.Status != DR.MS_FULL {
 			return DA.ErrorBadRequest().WithMessage("Can't submit result for match that isn't full")
 		}
+		if r.Result != nil && match.SideOf(r.userId) < 0 {
+			return DA.ErrorForbidden().WithMessage("Only players of match can submit its result")
+		}
+		if r.Result != nil {
+			result, err := results.Validate(match.Sport, *r.Result)
+			if err != nil {
+				return DA.ErrorBadRequest().WithMessage("Result isn't valid: " + err.Error())
+			}
+			r.Result = &result
+		}
+		if r.answers() {
+			if apiErr := r.validateAnswer(ctx, Repo, match); apiErr != nil {
+				return apiErr
+			}
+		}
 		if match.Status == DR.MS_FINISHED {
 			return DA.ErrorBadRequest().WithMessage("Can't change match that is over")
 		}
-		if r.Player != nil {
-			r.Players = *match.Players + "," + *r.Player
-		} else {
-			r.Players = *match.Players
+		if match.Status == DR.MS_DISPUTED {
+			return DA.ErrorBadRequest().WithMessage("Can't change match whose result is reviewed")
 		}
-		r.sport = match.Sport
-		r.status = match.Status
+		r.match = match
+	}
+	return nil
+}
+
+// answers reports whether request confirms or disputes submitted result
+func (r *MatchPatchHandler) answers() bool {
+	return r.Confirm != nil || r.Dispute != nil
+}
+
+// validateAnswer checks that match has pending result and that caller plays for other side than player
+// who submitted it
+func (r *MatchPatchHandler) validateAnswer(ctx context.Context, Repo *crud.Repo, match DR.Match) DA.Error {
+	if match.Status != DR.MS_RESULT_PENDING {
+		return DA.ErrorBadRequest().WithMessage("Match doesn't have result that waits for confirmation")
+	}
+	if r.Dispute != nil {
+		reason := strings.TrimSpace(*r.Dispute)
+		if reason == "" {
+			return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Reason of dispute is mandatory")
+		}
+		r.Dispute = &reason
+	}
+	pending := DR.MRS_PENDING
+	found, err := Repo.MatchResultCrud.Search(ctx, DR.MatchResultSearchParams{MatchId: &match.MatchId, Status: &pending}, nil)
+	if err != nil {
+		return DA.InternalServerError(err)
+	}
+	if len(found) == 0 {
+		return DA.ErrorBadRequest().WithMessage("Match doesn't have result that waits for confirmation")
+	}
+	r.pending = found[len(found)-1]
+	side := match.SideOf(r.userId)
+	if side < 0 {
+		return DA.ErrorForbidden().WithMessage("Only players of match can answer its result")
+	}
+	if side == r.pending.Side {
+		return DA.ErrorForbidden().WithMessage("Result must be confirmed or disputed by player of other side")
 	}
 	return nil
 }
 
 func (r *MatchPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	if r.answers() {
+		return r.answer(ctx, Repo)
+	}
 	up := DR.MatchUpdateParams{
-		Id:      r.Id,
-		Players: &r.Players,
-		Result:  r.Result,
+		Id: r.Id,
+	}
+	sport, err := DR.GetSportByName(r.match.Sport)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	sport, err := DR.GetSportByName(r.sport)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	defer tx.Rollback()
+	// match is locked and read again, concurrent request could submit result or add player since Validate
+	r.match, err = Repo.MatchCrud.GetById(ctx, r.Id, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if r.Result != nil && r.match.Status != DR.MS_FULL {
+		return nil, DA.ErrorBadRequest().WithMessage("Result of match was already submitted")
+	}
+	if r.Player != nil && r.match.HasPlayer(*r.Player) {
+		return nil, DA.ErrorBadRequest().WithMessage("Player is already in that match")
+	}
+	var submitted DR.MatchResult
 	if r.Result != nil {
-		fin := DR.MS_FINISHED
-		up.Status = &fin
-		updateStats(ctx, Repo, r.Id, *r.Result)
+		pending := DR.MS_RESULT_PENDING
+		up.Status = &pending
+		submitted, err = Repo.MatchResultCrud.Create(ctx, DR.MatchResult{
+			MatchId:  r.Id,
+			Result:   *r.Result,
+			Side:     r.match.SideOf(r.userId),
+			Deadline: results.Deadline(time.Now().UTC()),
+		}, tx, &r.userId)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
 	}
-	if len(strings.Split(r.Players, ",")) == 2*sport.TeamSize && r.status == DR.MS_CREATED {
+	players := r.match.Players
+	if r.Player != nil {
+		_, err = Repo.MatchPlayerCrud.Create(ctx, DR.MatchPlayer{MatchId: r.Id, PlayerId: *r.Player, Role: DR.MR_PLAYER}, tx, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		players = append(players, *r.Player)
+	}
+	if len(players) == 2*sport.TeamSize && r.match.Status == DR.MS_CREATED {
 		full := DR.MS_FULL
 		up.Status = &full
-		up.Teams = generateTeams(r.Players)
+		members, err := balancedPlayers(ctx, Repo, tx, sport.Name, players)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		for i, side := range balance.Split(r.match.Balancing, members, sport.Positions) {
+			side := side
+			_, err = Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: r.Id, PlayerId: players[i], Side: &side}, tx, nil)
+			if err != nil {
+				return nil, DA.InternalServerError(err)
+			}
+		}
 	}
-	ret, err := Repo.MatchCrud.Update(ctx, up, nil, nil)
+	ret, err := Repo.MatchCrud.Update(ctx, up, tx, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	var notes notify.Batch
+	if up.Status != nil {
+		place, _ := Repo.GetNameForId(ctx, ret.PlaceId)
+		n := DR.Notification{Type: DR.NT_MATCH_FULL, ReferenceId: ret.MatchId, Message: "Your match at " + place + " is full"}
+		players := ret.Players
+		if *up.Status == DR.MS_RESULT_PENDING {
+			n.Type = DR.NT_MATCH_RESULT
+			n.Message = "Result " + submitted.Result + " of your match at " + place + " was submitted, confirm or dispute it until " +
+				submitted.Deadline.Format(time.RFC3339)
+			players = nil
+			if other := 1 - submitted.Side; other < len(ret.Teams) {
+				players = ret.Teams[other]
+			}
+		}
+		if err = notes.Store(ctx, Repo, tx, n, ret, players...); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	notes.Send(ctx)
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
 }
 
-func generateTeams(players string) *DR.StrArr {
-	retArr := DR.StrArr{"", ""}
-	playersArr := strings.Split(players, ",")
-	perm := rand.Perm(len(playersArr))
-	for i, elem := range perm {
-		if i < len(playersArr)/2 {
-			retArr[0] += playersArr[elem] + ","
-		} else {
-			retArr[1] += playersArr[elem] + ","
-		}
-	}
-	retArr[0] = retArr[0][0 : len(retArr[0])-1]
-	retArr[1] = retArr[1][0 : len(retArr[1])-1]
-	return &retArr
-}
-
-func updateStats(ctx context.Context, Repo *crud.Repo, id, result string) error {
-	match, _ := Repo.MatchCrud.GetById(ctx, id, nil)
-	pointsFirst, err := strconv.Atoi(strings.Split(result, ":")[0])
+// answer confirms pending result, which finishes match, or disputes it, which sends it to review in backoffice
+func (r *MatchPatchHandler) answer(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
-		return err
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	// match is locked before its result, in the same order as submit and worker lock them
+	if _, err = Repo.MatchCrud.GetById(ctx, r.Id, tx); err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	pointsSecond, err := strconv.Atoi(strings.Split(result, ":")[1])
+	// result is locked, worker could auto confirm it meanwhile
+	mr, err := Repo.MatchResultCrud.GetById(ctx, r.pending.ResultId, tx)
 	if err != nil {
-		return err
+		return nil, DA.InternalServerError(err)
 	}
-	winner := 1
-	if pointsFirst > pointsSecond {
-		winner = 0
+	if mr.Status != DR.MRS_PENDING {
+		return nil, DA.ErrorBadRequest().WithMessage("Result was already answered")
 	}
-	for _, id := range strings.Split(match.Teams[winner], ",") {
-		player, err := Repo.PlayerCrud.GetById(ctx, id, nil)
+	var notes notify.Batch
+	var ret DR.Match
+	if r.Confirm != nil {
+		confirmed := DR.MRS_CONFIRMED
+		ret, err = results.Finish(ctx, Repo, tx, &notes, mr, DR.MatchResultUpdateParams{Status: &confirmed}, &r.userId)
 		if err != nil {
-			return err
-		}
-		stats := player.Statistics
-		if stats == nil {
-			stats = DR.StatMap{}
-		}
-		teamResult := result
-		if winner != 0 {
-			loseResults := strings.Split(result, ":")
-			teamResult = loseResults[1] + ":" + loseResults[0]
-		}
-		nrOfMatches := float64(len(stats[match.Sport].Matches))
-		winRation := stats[match.Sport].WinRatio.Mul(decimal.NewFromFloat(nrOfMatches)).Add(decimal.NewFromFloat(1)).Div(decimal.NewFromFloat(nrOfMatches + 1))
-		stats[match.Sport] = DR.Statistics{
-			WinRatio: winRation,
-			Matches: append(stats[match.Sport].Matches, DR.Statistic{
-				Date:    *match.StartTime,
-				Score:   teamResult,
-				MyTeam:  strings.Split(match.Teams[winner], ","),
-				OppTeam: strings.Split(match.Teams[1-winner], ","),
-			}),
-		}
-		up := DR.PlayerUpdateParams{
-			Id:         id,
-			Statistics: &stats,
-		}
-		_, err = Repo.PlayerCrud.Update(ctx, up, nil, nil)
+			return nil, DA.InternalServerError(err)
+		}
+	} else {
+		disputed := DR.MRS_DISPUTED
+		err = Repo.MatchResultCrud.Update(ctx, DR.MatchResultUpdateParams{Id: mr.ResultId, Status: &disputed, DisputedBy: &r.userId, DisputeReason: r.Dispute}, tx, &r.userId)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		status := DR.MS_DISPUTED
+		ret, err = Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: r.Id, Status: &status}, tx, &r.userId)
 		if err != nil {
-			return err
+			return nil, DA.InternalServerError(err)
 		}
+		place, _ := Repo.GetNameForId(ctx, ret.PlaceId)
+		n := DR.Notification{Type: DR.NT_MATCH_DISPUTED, ReferenceId: ret.MatchId, Message: "Result " + mr.Result + " of your match at " + place + " is disputed and will be reviewed"}
+		if err = notes.Store(ctx, Repo, tx, n, ret, ret.Players...); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	for _, id := range strings.Split(match.Teams[1-winner], ",") {
-		player, err := Repo.PlayerCrud.GetById(ctx, id, nil)
+	notes.Send(ctx)
+	resMap := make(map[string]interface{})
+	resMap["body"] = ret
+	return resMap, nil
+}
+
+// balancedPlayers loads preferences and ratings in sport of players that are split into teams
+func balancedPlayers(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, sport string, ids []string) ([]balance.Player, error) {
+	members := make([]balance.Player, 0, len(ids))
+	for _, id := range ids {
+		id := id
+		player, err := Repo.PlayerCrud.GetById(ctx, id, qa)
 		if err != nil {
-			return err
-		}
-		stats := player.Statistics
-		if stats == nil {
-			stats = DR.StatMap{}
-		}
-		teamResult := result
-		if winner != 1 {
-			loseResults := strings.Split(result, ":")
-			teamResult = loseResults[1] + ":" + loseResults[0]
-		}
-		nrOfMatches := float64(len(stats[match.Sport].Matches))
-		winRation := stats[match.Sport].WinRatio.Mul(decimal.NewFromFloat(nrOfMatches)).Div(decimal.NewFromFloat(nrOfMatches + 1))
-		stats[match.Sport] = DR.Statistics{
-			WinRatio: winRation,
-			Matches: append(stats[match.Sport].Matches, DR.Statistic{
-				Date:    *match.StartTime,
-				Score:   teamResult,
-				MyTeam:  strings.Split(match.Teams[1-winner], ","),
-				OppTeam: strings.Split(match.Teams[winner], ","),
-			}),
-		}
-		up := DR.PlayerUpdateParams{
-			Id:         id,
-			Statistics: &stats,
-		}
-		_, err = Repo.PlayerCrud.Update(ctx, up, nil, nil)
+			return nil, err
+		}
+		ratings, err := Repo.RatingCrud.Search(ctx, DR.RatingSearchParams{PlayerId: &id, Sport: &sport}, qa)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		var r *DR.Rating
+		if len(ratings) > 0 {
+			r = &ratings[0]
 		}
+		members = append(members, balance.NewPlayer(player, sport, r))
 	}
-
-	return nil
+	return members, nil
 }
@@ -0,0 +1,505 @@
+package public_test
+
+import (
+	"backend/sportos"
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/api/handlers/public"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/results"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMatchResultUpdatesStatistics(t *testing.T) {
+	h := apitest.New(t)
+	ctx := context.Background()
+
+	first, second := apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER
+	players := DR.StrArr{first, second}
+	start := time.Now().UTC().Add(-time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	for side, id := range players {
+		side := side
+		_, err = h.Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: match.MatchId, PlayerId: id, Side: &side}, nil, nil)
+		if err != nil {
+			t.Fatalf("update side of %s: %v", id, err)
+		}
+	}
+	full := DR.MS_FULL
+	_, err = h.Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: match.MatchId, Status: &full}, nil, nil)
+	if err != nil {
+		t.Fatalf("update match: %v", err)
+	}
+
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "3:1"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res = h.As(second, DR.UT_PLAYER).Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "confirm": true})
+	if res.Code != http.StatusOK {
+		t.Fatalf("confirm result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	// statistics are updated by subscriber of match finished event
+	if n, err := h.Server.Events.ProcessPending(ctx); err != nil || n != 1 {
+		t.Fatalf("dispatch events: expected 1 dispatched event, got %d, %v", n, err)
+	}
+
+	match, err = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if err != nil {
+		t.Fatalf("get match: %v", err)
+	}
+	if match.Status != DR.MS_FINISHED {
+		t.Errorf("expected match to be %s, got %s", DR.MS_FINISHED, match.Status)
+	}
+
+	expected := []struct {
+		id       string
+		score    string
+		winRatio decimal.Decimal
+	}{
+		{first, "3:1", decimal.NewFromInt(1)},
+		{second, "1:3", decimal.Zero},
+	}
+	sport := "Table tennis"
+	for _, e := range expected {
+		id := e.id
+		matches, err := h.Repo.PlayerMatchCrud.Search(ctx, DR.PlayerMatchSearchParams{PlayerId: &id, Sport: &sport}, nil)
+		if err != nil {
+			t.Fatalf("search matches of %s: %v", e.id, err)
+		}
+		if len(matches) != 1 {
+			t.Fatalf("player %s: expected 1 match in statistics, got %d", e.id, len(matches))
+		}
+		if matches[0].Score() != e.score {
+			t.Errorf("player %s: expected score %s, got %s", e.id, e.score, matches[0].Score())
+		}
+		summary, err := h.Repo.PlayerMatchCrud.Summary(ctx, e.id, sport, nil)
+		if err != nil {
+			t.Fatalf("summary of %s: %v", e.id, err)
+		}
+		if !summary.WinRatio().Equal(e.winRatio) {
+			t.Errorf("player %s: expected win ratio %s, got %s", e.id, e.winRatio, summary.WinRatio())
+		}
+	}
+}
+
+func TestMatchResultUpdatesRatingInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	first, second := apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER
+	players := DR.StrArr{first, second}
+	start := time.Now().UTC().Add(-time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	for side, id := range players {
+		side := side
+		if _, err = h.Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: match.MatchId, PlayerId: id, Side: &side}, nil, nil); err != nil {
+			t.Fatalf("update side of %s: %v", id, err)
+		}
+	}
+	full := DR.MS_FULL
+	if _, err = h.Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: match.MatchId, Status: &full}, nil, nil); err != nil {
+		t.Fatalf("update match: %v", err)
+	}
+
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "1:3"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res = h.As(second, DR.UT_PLAYER).Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "confirm": true})
+	if res.Code != http.StatusOK {
+		t.Fatalf("confirm result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if _, err := h.Server.Events.ProcessPending(ctx); err != nil {
+		t.Fatalf("dispatch events: %v", err)
+	}
+
+	// + in query isn't decoded as space, see DA.GetParameterFromURLQuery
+	res = h.As(second, DR.UT_PLAYER).Get(DR.SUB_CL, DA.HN_STATS+"?sport=Table%20tennis", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("statistics: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var stats DA.Statistics
+	res.Decode(t, &stats)
+	if stats.Rating == nil || stats.Rating.System != DR.RS_ELO || stats.Rating.Rating != 1520 || stats.Rating.Matches != 1 {
+		t.Fatalf("expected elo rating 1520 after first win, got %+v", stats.Rating)
+	}
+	if len(stats.Rating.History) != 1 || stats.Rating.History[0].MatchId != match.MatchId || stats.Rating.History[0].Before != 1500 {
+		t.Errorf("expected one rating change, got %+v", stats.Rating.History)
+	}
+	if len(stats.Matches) != 1 || stats.Matches[0].Score != "3:1" || stats.Matches[0].Outcome != DR.MO_WIN ||
+		len(stats.Matches[0].MyTeam) != 1 || stats.Matches[0].MyTeam[0] != second || stats.Matches[0].OppTeam[0] != first {
+		t.Errorf("expected won match with names of players, got %+v", stats.Matches)
+	}
+	if stats.WinRatio != "100.00%" || stats.Wins != 1 || stats.CurrentStreak != 1 || stats.StreakOutcome != DR.MO_WIN || len(stats.Months) != 1 {
+		t.Errorf("expected aggregated statistics of one win, got %+v", stats)
+	}
+
+	res = h.Player().Get(DR.SUB_CL, DA.HN_LEADERBOARD+"?sport=Table%20tennis&by=rating", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("leaderboard: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var rankings []DA.Ranking
+	res.Decode(t, &rankings)
+	if len(rankings) != 2 || rankings[0].PlayerId != second || rankings[0].Rank != 1 || rankings[1].Rating == nil || *rankings[1].Rating != 1480 {
+		t.Errorf("expected winner first on rating leaderboard, got %+v", rankings)
+	}
+
+	res = h.Player().Get(DR.SUB_CL, DA.HN_LEADERBOARDS+"?sport=Table%20tennis&city=Belgrade", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("leaderboard of city: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	rankings = nil
+	res.Decode(t, &rankings)
+	if len(rankings) != 2 || rankings[0].PlayerId != second || rankings[0].WinRatio != "100.00%" || rankings[0].Matches != 1 ||
+		rankings[0].Rating == nil || *rankings[0].Rating != 1520 || rankings[1].Rank != 2 {
+		t.Errorf("expected winner first on win ratio leaderboard of Belgrade, got %+v", rankings)
+	}
+	res = h.Player().Get(DR.SUB_CL, DA.HN_LEADERBOARD+"?sport=Table%20tennis&city=Novi%20Sad", nil)
+	rankings = nil
+	res.Decode(t, &rankings)
+	if res.Code != http.StatusOK || len(rankings) != 0 {
+		t.Errorf("expected empty leaderboard of other city, got %d: %+v", res.Code, rankings)
+	}
+	if res = h.Player().Get(DR.SUB_CL, DA.HN_LEADERBOARD+"?sport=Table%20tennis&by=goals", nil); res.Code != http.StatusBadRequest {
+		t.Errorf("unknown metric: expected 400, got %d", res.Code)
+	}
+}
+
+func TestMatchResultWithSetsInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	players := DR.StrArr{apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER}
+	start := time.Now().UTC().Add(-time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	for side, id := range players {
+		side := side
+		if _, err = h.Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: match.MatchId, PlayerId: id, Side: &side}, nil, nil); err != nil {
+			t.Fatalf("update side of %s: %v", id, err)
+		}
+	}
+	full := DR.MS_FULL
+	if _, err = h.Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: match.MatchId, Status: &full}, nil, nil); err != nil {
+		t.Fatalf("update match: %v", err)
+	}
+
+	for _, result := range []string{"2:2", "3:1 (11:5 11:7)", "4:1", "3-1"} {
+		res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": result})
+		if res.Code != http.StatusBadRequest {
+			t.Errorf("result %s: expected 400, got %d: %s", result, res.Code, res.Body)
+		}
+	}
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "3:1 (11:5, 9:11, 11:7, 12:10)"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res = h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER).Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "confirm": true})
+	if res.Code != http.StatusOK {
+		t.Fatalf("confirm result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if _, err := h.Server.Events.ProcessPending(ctx); err != nil {
+		t.Fatalf("dispatch events: %v", err)
+	}
+
+	match, err = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if err != nil {
+		t.Fatalf("get match: %v", err)
+	}
+	if match.Result == nil || *match.Result != "3:1 (11:5 9:11 11:7 12:10)" {
+		t.Errorf("expected result with sets in canonical format, got %v", match.Result)
+	}
+	id, sport := apitest.FIXTURE_PLAYER, "Table tennis"
+	matches, err := h.Repo.PlayerMatchCrud.Search(ctx, DR.PlayerMatchSearchParams{PlayerId: &id, Sport: &sport}, nil)
+	if err != nil || len(matches) != 1 || matches[0].Score() != "3:1" || matches[0].Outcome != DR.MO_WIN {
+		t.Errorf("expected won match 3:1 in sets, got %+v, %v", matches, err)
+	}
+}
+
+// fullMatch creates full table tennis match of fixture player (side 0) and second fixture player (side 1)
+func fullMatch(t *testing.T, h *apitest.Harness) DR.Match {
+	t.Helper()
+	ctx := context.Background()
+	players := DR.StrArr{apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER}
+	start := time.Now().UTC().Add(-time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	for side, id := range players {
+		side := side
+		if _, err = h.Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: match.MatchId, PlayerId: id, Side: &side}, nil, nil); err != nil {
+			t.Fatalf("update side of %s: %v", id, err)
+		}
+	}
+	full := DR.MS_FULL
+	if match, err = h.Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: match.MatchId, Status: &full}, nil, nil); err != nil {
+		t.Fatalf("update match: %v", err)
+	}
+	return match
+}
+
+func TestMatchResultDisputeIsReviewedInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+	match := fullMatch(t, h)
+	second := h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER)
+	admin := h.As("admin", DR.UT_ADMIN)
+
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "3:1"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if res = h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "confirm": true}); res.Code != http.StatusForbidden {
+		t.Errorf("confirm by same side: expected 403, got %d: %s", res.Code, res.Body)
+	}
+	if res = second.Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "dispute": " "}); res.Code != http.StatusBadRequest {
+		t.Errorf("dispute without reason: expected 400, got %d: %s", res.Code, res.Body)
+	}
+	res = second.Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "dispute": "I won 3:1"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("dispute result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if n, err := h.Server.Events.ProcessPending(ctx); err != nil || n != 0 {
+		t.Errorf("expected no event before review, got %d, %v", n, err)
+	}
+	match, _ = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if match.Status != DR.MS_DISPUTED || match.Result != nil {
+		t.Errorf("expected disputed match without result, got %s %v", match.Status, match.Result)
+	}
+
+	if res = h.Player().Get(DR.SUB_BO, DA.HN_MATCH_RESULTS, nil); res.Code != http.StatusForbidden {
+		t.Errorf("review queue for player: expected 403, got %d", res.Code)
+	}
+	res = admin.Get(DR.SUB_BO, DA.HN_MATCH_RESULTS, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("review queue: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var queue []DA.MatchResult
+	res.Decode(t, &queue)
+	if len(queue) != 1 || queue[0].MatchId != match.MatchId || queue[0].SubmittedBy != apitest.FIXTURE_PLAYER ||
+		queue[0].DisputedBy != apitest.FIXTURE_SECOND_PLAYER || queue[0].DisputeReason != "I won 3:1" {
+		t.Fatalf("expected disputed result in review queue, got %+v", queue)
+	}
+
+	if res = admin.Do(DR.SUB_BO, http.MethodPatch, DA.HN_MATCH_RESULTS, map[string]interface{}{"id": queue[0].Id, "status": "REJECTED", "result": "1:3"}); res.Code != http.StatusBadRequest {
+		t.Errorf("rejected with corrected result: expected 400, got %d: %s", res.Code, res.Body)
+	}
+	res = admin.Do(DR.SUB_BO, http.MethodPatch, DA.HN_MATCH_RESULTS, map[string]interface{}{"id": queue[0].Id, "status": "RESOLVED", "result": "1:3", "note": "Sides were swapped"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("resolve dispute: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var resolved DA.MatchResult
+	res.Decode(t, &resolved)
+	if resolved.Status != DR.MRS_RESOLVED || resolved.Result != "1:3" || resolved.ReviewedBy != "admin" {
+		t.Errorf("expected resolved corrected result, got %+v", resolved)
+	}
+	if _, err := h.Server.Events.ProcessPending(ctx); err != nil {
+		t.Fatalf("dispatch events: %v", err)
+	}
+	match, _ = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if match.Status != DR.MS_FINISHED || match.Result == nil || *match.Result != "1:3" {
+		t.Errorf("expected match finished with corrected result, got %s %v", match.Status, match.Result)
+	}
+	id, sport := apitest.FIXTURE_SECOND_PLAYER, "Table tennis"
+	matches, err := h.Repo.PlayerMatchCrud.Search(ctx, DR.PlayerMatchSearchParams{PlayerId: &id, Sport: &sport}, nil)
+	if err != nil || len(matches) != 1 || matches[0].Outcome != DR.MO_WIN {
+		t.Errorf("expected win of second player in statistics, got %+v, %v", matches, err)
+	}
+	if res = admin.Do(DR.SUB_BO, http.MethodPatch, DA.HN_MATCH_RESULTS, map[string]interface{}{"id": queue[0].Id, "status": "REJECTED"}); res.Code != http.StatusBadRequest {
+		t.Errorf("review of resolved result: expected 400, got %d: %s", res.Code, res.Body)
+	}
+}
+
+func TestMatchResultIsAutoConfirmedInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+	match := fullMatch(t, h)
+	second := h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER)
+
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "3:0"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res = second.Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "dispute": "Match wasn't played"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("dispute result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var queue []DA.MatchResult
+	h.As("admin", DR.UT_ADMIN).Get(DR.SUB_BO, DA.HN_MATCH_RESULTS, nil).Decode(t, &queue)
+	if len(queue) != 1 {
+		t.Fatalf("expected disputed result in review queue, got %+v", queue)
+	}
+	res = h.As("admin", DR.UT_ADMIN).Do(DR.SUB_BO, http.MethodPatch, DA.HN_MATCH_RESULTS, map[string]interface{}{"id": queue[0].Id, "status": "REJECTED"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("reject result: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	match, _ = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if match.Status != DR.MS_FULL {
+		t.Fatalf("expected rejected result to return match to %s, got %s", DR.MS_FULL, match.Status)
+	}
+
+	// result submitted again isn't answered, its deadline passes right away
+	results.Init(results.Config{Window: time.Nanosecond})
+	defer results.Init(results.Config{})
+	res = second.Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "1:3"})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit result again: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if n, err := h.Server.Results.ProcessExpired(ctx); err != nil || n != 1 {
+		t.Fatalf("auto confirm: expected 1 finished match, got %d, %v", n, err)
+	}
+	if _, err := h.Server.Events.ProcessPending(ctx); err != nil {
+		t.Fatalf("dispatch events: %v", err)
+	}
+	match, _ = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if match.Status != DR.MS_FINISHED || match.Result == nil || *match.Result != "1:3" {
+		t.Errorf("expected match finished with auto confirmed result, got %s %v", match.Status, match.Result)
+	}
+	if res = h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "confirm": true}); res.Code != http.StatusBadRequest {
+		t.Errorf("confirm of auto confirmed result: expected 400, got %d: %s", res.Code, res.Body)
+	}
+	id, sport := apitest.FIXTURE_PLAYER, "Table tennis"
+	matches, err := h.Repo.PlayerMatchCrud.Search(ctx, DR.PlayerMatchSearchParams{PlayerId: &id, Sport: &sport}, nil)
+	if err != nil || len(matches) != 1 || matches[0].Outcome != DR.MO_LOSS {
+		t.Errorf("expected loss of fixture player in statistics, got %+v, %v", matches, err)
+	}
+}
+
+func TestMatchResultRequiresFullMatch(t *testing.T) {
+	h := apitest.New(t)
+	ctx := context.Background()
+
+	players := DR.StrArr{apitest.FIXTURE_PLAYER}
+	start := time.Now().UTC().Add(time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	res := h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "result": "3:1"})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for result of match that isn't full, got %d: %s", res.Code, res.Body)
+	}
+}
+
+func TestMatchJoinFillsMatchInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	players := DR.StrArr{apitest.FIXTURE_PLAYER}
+	start := time.Now().UTC().Add(time.Hour)
+	match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+		StartTime: &start,
+		PlaceId:   apitest.FIXTURE_PLACE,
+		Status:    DR.MS_CREATED,
+		Players:   players,
+		Sport:     "Table tennis",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	second := apitest.FIXTURE_SECOND_PLAYER
+	res := h.As(second, DR.UT_PLAYER).Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "player": second})
+	if res.Code != http.StatusOK {
+		t.Fatalf("join match: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	match, err = h.Repo.MatchCrud.GetById(ctx, match.MatchId, nil)
+	if err != nil {
+		t.Fatalf("get match: %v", err)
+	}
+	if match.Status != DR.MS_FULL {
+		t.Errorf("expected match to be %s, got %s", DR.MS_FULL, match.Status)
+	}
+	if len(match.Teams) != 2 {
+		t.Errorf("expected 2 teams, got %v", match.Teams)
+	}
+
+	res = h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_MATCHES, map[string]interface{}{"id": match.MatchId, "player": second})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for player that is already in match, got %d: %s", res.Code, res.Body)
+	}
+}
+
+// TestMatchResultSubmittedOnceInMemory submits results of both sides that were validated before either was processed
+func TestMatchResultSubmittedOnceInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+	match := fullMatch(t, h)
+
+	var handlers []*public.MatchPatchHandler
+	for _, id := range []string{apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER} {
+		req := httptest.NewRequest(http.MethodPatch, DA.HN_MATCHES, strings.NewReader(`{"id":"`+match.MatchId+`","result":"3:1"}`))
+		req = req.WithContext(context.WithValue(ctx, sportos.CONTEXT_USER_ID_KEY, id))
+		handler := &public.MatchPatchHandler{}
+		if err := handler.Init(req); err != nil {
+			t.Fatalf("init request of %s: %v", id, err)
+		}
+		if err := handler.Validate(ctx, h.Repo); err != nil {
+			t.Fatalf("validate request of %s: %v", id, err)
+		}
+		handlers = append(handlers, handler)
+	}
+	if _, err := handlers[0].Process(ctx, h.Repo); err != nil {
+		t.Fatalf("process first result: %v", err)
+	}
+	if _, err := handlers[1].Process(ctx, h.Repo); err == nil || err.GetHTTPCode() != http.StatusBadRequest {
+		t.Errorf("expected 400 for second result, got %v", err)
+	}
+
+	submitted, err := h.Repo.MatchResultCrud.Search(ctx, DR.MatchResultSearchParams{MatchId: &match.MatchId}, nil)
+	if err != nil {
+		t.Fatalf("search results: %v", err)
+	}
+	if len(submitted) != 1 || submitted[0].CreatedBy != apitest.FIXTURE_PLAYER {
+		t.Errorf("expected one result submitted by %s, got %+v", apitest.FIXTURE_PLAYER, submitted)
+	}
+}
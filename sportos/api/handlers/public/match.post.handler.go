@@ -1,7 +1,10 @@
 package public
 
 import (
+	H "backend/internal/helpers"
 	DA "backend/sportos/api/dto"
+	"backend/sportos/availability"
+	"backend/sportos/events"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
@@ -12,6 +15,7 @@ import (
 
 type MatchPostHandler struct {
 	MatchPostRequest
+	slot availability.Slot
 }
 
 type MatchPostRequest struct {
@@ -19,6 +23,8 @@ type MatchPostRequest struct {
 	PlaceId   string     `json:"placeId,omitempty"`
 	Players   string     `json:"players,omitempty"`
 	Sport     string     `json:"sport,omitempty"`
+	// Balancing is algorithm that splits players into teams, default is DR.MB_POSITIONS
+	Balancing DR.MatchBalancing `json:"balancing,omitempty"`
 }
 
 type MatchPostResponse struct {
@@ -32,6 +38,10 @@ func (r MatchPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r MatchPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *MatchPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -50,33 +60,41 @@ func (r *MatchPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Err
 	if r.Players == "" {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Players are mandatory")
 	}
+	players := DA.ParseCommaSeparated(&r.Players)
+	for i := range players {
+		if H.Contains(players[:i], players[i]) {
+			return DA.ErrorBadRequest().WithMessage("Player " + players[i] + " is listed more than once")
+		}
+	}
 	place, err := Repo.PlaceCrud.GetById(ctx, r.PlaceId, nil)
 	if err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_UNIQUE_CONSTRAINT).WithMessage("Place doesn't exist")
 	}
-	endTime := r.StartTime.Add(time.Hour)
-	if place.Booking != nil {
-		for _, booking := range *place.Booking {
-			if !(DA.AfterEqual(*r.StartTime, booking.EndTime) || DA.BeforeEqual(endTime, booking.StartTime)) {
-				return DA.ErrorBadRequest().WithMessage("That appointment is already occupied")
-			}
-		}
+	var apiErr DA.Error
+	if r.slot, apiErr = bookable(ctx, Repo, r.PlaceId, place.Schedule, place.TimeZone, *r.StartTime); apiErr != nil {
+		return apiErr
 	}
 	if _, err := DR.GetSportByName(r.Sport); err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Sport doesn't exist")
 	}
+	if r.Balancing == "" {
+		r.Balancing = DR.MB_POSITIONS
+	} else if !r.Balancing.IsValid() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Balancing must be RANDOM, RATING or POSITIONS")
+	}
 	return nil
 }
 
 func (r *MatchPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
 	match := DR.Match{
-		Players:   &r.Players,
+		Players:   DA.ParseCommaSeparated(&r.Players),
 		PlaceId:   r.PlaceId,
 		StartTime: r.StartTime,
 		Status:    DR.MS_CREATED,
 		Sport:     r.Sport,
+		Balancing: r.Balancing,
 	}
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -85,21 +103,25 @@ func (r *MatchPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interf
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	place, _ := Repo.PlaceCrud.GetById(ctx, r.PlaceId, tx)
-	up := DR.PlaceUpdateParams{
-		Id: r.PlaceId,
+	// booking fails if other request booked same time after validation
+	booking := DR.Booking{
+		OwnerId:     r.PlaceId,
+		Kind:        DR.BK_MATCH,
+		ReferenceId: ret.MatchId,
+		StartTime:   r.slot.Start,
+		EndTime:     r.slot.End,
+		Status:      DR.BS_CONFIRMED,
 	}
-	booking := place.Booking
-	if booking == nil {
-		booking = &DR.Booking{}
+	if _, err := Repo.BookingCrud.Create(ctx, booking, tx, nil); err != nil {
+		return nil, bookingError(err)
 	}
-	*booking = append(*booking, DR.Apointment{StartTime: *r.StartTime, EndTime: r.StartTime.Add(time.Hour)})
-	up.Booking = booking
-	_, err = Repo.PlaceCrud.Update(ctx, up, tx, nil)
-	if err != nil {
+	booked := events.MatchBooked{MatchId: ret.MatchId, PlaceId: ret.PlaceId, Sport: ret.Sport, StartTime: r.slot.Start, EndTime: r.slot.End}
+	if err = events.Publish(ctx, Repo, tx, booked); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	tx.Commit()
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
@@ -0,0 +1,76 @@
+package public_test
+
+import (
+	"backend/sportos"
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/api/handlers/public"
+	DR "backend/sportos/repo/dto"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// processWithRacingBooking runs handler like router does, but booking of other request is created between
+// Validate and Process, so overlap is found only when booking of handler is created
+func processWithRacingBooking(t *testing.T, h *apitest.Harness, hn DA.Handler, userId string, body interface{}, booking DR.Booking) DA.Error {
+	t.Helper()
+	b, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	req := httptest.NewRequest(hn.SupportedMethod(), "/", bytes.NewReader(b))
+	ctx := context.WithValue(req.Context(), sportos.CONTEXT_USER_ID_KEY, userId)
+	if apiErr := hn.Init(req.WithContext(ctx)); apiErr != nil {
+		t.Fatalf("init: %v", apiErr)
+	}
+	if apiErr := hn.Validate(ctx, h.Repo); apiErr != nil {
+		t.Fatalf("validate: %v", apiErr)
+	}
+	if _, err := h.Repo.BookingCrud.Create(ctx, booking, nil, nil); err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+	_, apiErr := hn.Process(ctx, h.Repo)
+	return apiErr
+}
+
+func TestMatchDoubleBookingInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	body := func(start time.Time) map[string]interface{} {
+		return map[string]interface{}{
+			"startTime": start,
+			"placeId":   apitest.FIXTURE_PLACE,
+			"players":   apitest.FIXTURE_PLAYER + "," + apitest.FIXTURE_SECOND_PLAYER,
+			"sport":     "Table tennis",
+		}
+	}
+	if res := h.Player().Do(DR.SUB_CL, http.MethodPost, DA.HN_MATCHES, body(start)); res.Code != http.StatusOK {
+		t.Fatalf("create match: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if res := h.Player().Do(DR.SUB_CL, http.MethodPost, DA.HN_MATCHES, body(start)); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for match in booked slot, got %d: %s", res.Code, res.Body)
+	}
+
+	// other request books slot after validation
+	later := start.Add(24 * time.Hour)
+	booking := DR.Booking{OwnerId: apitest.FIXTURE_PLACE, Kind: DR.BK_MATCH, ReferenceId: "other", StartTime: later, EndTime: later.Add(time.Hour), Status: DR.BS_CONFIRMED}
+	apiErr := processWithRacingBooking(t, h, &public.MatchPostHandler{}, apitest.FIXTURE_PLAYER, body(later), booking)
+	if apiErr == nil || apiErr.GetHTTPCode() != http.StatusBadRequest || apiErr.GetMessage() != "That appointment is already occupied" {
+		t.Fatalf("expected 400 for booking that overlaps booking made after validation, got %v", apiErr)
+	}
+
+	matches, err := h.Repo.MatchCrud.Search(ctx, DR.MatchSearchParams{}, nil)
+	if err != nil {
+		t.Fatalf("search matches: %v", err)
+	}
+	if len(matches) != 1 || !matches[0].StartTime.Equal(start) {
+		t.Errorf("expected only first match to be created, got %+v", matches)
+	}
+}
@@ -22,6 +22,10 @@ func (r NameGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r NameGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *NameGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.id = mux.Vars(httpReq)["id"]
 	return nil
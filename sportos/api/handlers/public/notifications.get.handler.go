@@ -0,0 +1,84 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+// NotificationsGetHandler returns inbox of user that calls it, newest notifications first
+type NotificationsGetHandler struct {
+	SearchParams DR.NotificationSearchParams
+	userId       string
+}
+
+func (r NotificationsGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r NotificationsGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r NotificationsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *NotificationsGetHandler) Init(httpReq *http.Request) DA.Error {
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	errorMessages := make([]string, 0)
+	var errorMessage string
+
+	r.SearchParams.Unread, errorMessage = DA.ParseBool(DA.GetParameterFromURLQuery(httpReq, "unread"), "unread")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *NotificationsGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	return nil
+}
+
+func (r *NotificationsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	r.SearchParams.UserId = &r.userId
+	notifications, err := Repo.NotificationCrud.Search(ctx, r.SearchParams, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	unread, apiErr := unreadCount(ctx, Repo, r.userId)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	ret := DA.Notifications{Notifications: []DA.Notification{}, Unread: unread}
+	for _, nt := range notifications {
+		var notification DA.Notification
+		notification.InitWithDatabaseStruct(nt)
+		ret.Notifications = append(ret.Notifications, notification)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = ret
+	return resMap, nil
+}
+
+// unreadCount returns number of notifications in inbox of user that aren't read
+func unreadCount(ctx context.Context, Repo *crud.Repo, userId string) (int, DA.Error) {
+	unread := true
+	count, err := Repo.NotificationCrud.GetCount(ctx, DR.NotificationSearchParams{UserId: &userId, Unread: &unread}, nil)
+	if err != nil {
+		return 0, DA.InternalServerError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,101 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// NotificationsPatchHandler marks notifications of user that calls it as read, all unread notifications
+// are marked if All is true
+type NotificationsPatchHandler struct {
+	NotificationsPatchRequest
+	userId string
+}
+
+type NotificationsPatchRequest struct {
+	Ids []string `json:"ids,omitempty"`
+	All bool     `json:"all,omitempty"`
+}
+
+type NotificationsPatchResponse struct {
+	Unread int `json:"unread"`
+}
+
+func (r NotificationsPatchHandler) SupportedMethod() string {
+	return http.MethodPatch
+}
+
+func (r NotificationsPatchHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r NotificationsPatchHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *NotificationsPatchHandler) Init(httpReq *http.Request) DA.Error {
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	err := decode.Decode(&r.NotificationsPatchRequest)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *NotificationsPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if len(r.Ids) == 0 && !r.All {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Ids or all are mandatory")
+	}
+	for _, id := range r.Ids {
+		if nt, err := Repo.NotificationCrud.GetById(ctx, id, nil); err != nil || nt.UserId != r.userId {
+			return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Notification with id " + id + " doesn't exist")
+		}
+	}
+	return nil
+}
+
+func (r *NotificationsPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	if r.All {
+		if _, err := Repo.NotificationCrud.MarkAllRead(ctx, r.userId, tx, &r.userId); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	} else {
+		now := time.Now().UTC()
+		for _, id := range r.Ids {
+			nt, err := Repo.NotificationCrud.GetById(ctx, id, tx)
+			if err != nil {
+				return nil, DA.InternalServerError(err)
+			}
+			// read time of notification that is already read isn't moved
+			if nt.ReadAt != nil {
+				continue
+			}
+			if _, err := Repo.NotificationCrud.Update(ctx, DR.NotificationUpdateParams{Id: id, ReadAt: &now}, tx, &r.userId); err != nil {
+				return nil, DA.InternalServerError(err)
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	unread, apiErr := unreadCount(ctx, Repo, r.userId)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = NotificationsPatchResponse{Unread: unread}
+	return resMap, nil
+}
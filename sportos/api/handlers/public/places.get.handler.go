@@ -23,6 +23,10 @@ func (r PlacesGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r PlacesGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *PlacesGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.PlaceId = DA.GetParameterFromURLQuery(httpReq, "playerId")
 	r.City = DA.GetParameterFromURLQuery(httpReq, "city")
@@ -0,0 +1,76 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type PracticeDeleteHandler struct {
+	Id       *string `json:"id,omitempty"`
+	userId   string
+	practice DR.Practice
+}
+
+func (r PracticeDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r PracticeDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r PracticeDeleteHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER, DR.UT_COACH}
+}
+
+func (r *PracticeDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *PracticeDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	practice, err := Repo.PracticeCrud.GetById(ctx, *r.Id, nil)
+	if err != nil || practice.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Practice with id " + *r.Id + " doesn't exist")
+	}
+	r.practice = practice
+	return nil
+}
+
+func (r *PracticeDeleteHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.practice.PlayerId != r.userId && r.practice.CoachId != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only player or coach of practice can delete it")
+	}
+	return nil
+}
+
+func (r *PracticeDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	err = Repo.PracticeCrud.Delete(ctx, r.practice.PracticeId, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	// free time of coach that was booked for practice
+	err = cancelBookings(ctx, Repo, DR.BK_PRACTICE, r.practice.PracticeId, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
@@ -23,6 +23,10 @@ func (r PracticeGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r PracticeGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *PracticeGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.PlayerId = DA.GetParameterFromURLQuery(httpReq, "playerId")
 	r.CoachId = DA.GetParameterFromURLQuery(httpReq, "coachId")
@@ -69,6 +73,7 @@ func (r *PracticeGetHandler) formatPractices(ctx context.Context, Repo *crud.Rep
 		practices[i].PlayerId = player.Name
 		coach, _ := Repo.CoachCrud.GetById(ctx, practices[i].CoachId, nil)
 		practices[i].CoachId = coach.Name
+		practices[i].StartTime, practices[i].TimeZone = localTime(practices[i].StartTime, coach.TimeZone)
 	}
 	return practices
 }
@@ -2,17 +2,22 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/events"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
-	"time"
 )
 
 type PracticePatchHandler struct {
 	PracticePatchRequest
 	coachId string
+	userId  string
+	// denied are practices that overlapped accepted one
+	denied []DR.Practice
 }
 
 type PracticePatchRequest struct {
@@ -28,9 +33,14 @@ func (r PracticePatchHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r PracticePatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_COACH}
+}
+
 func (r *PracticePatchHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	err := decode.Decode(&r.PracticePatchRequest)
 	if err == nil {
 		return nil
@@ -40,7 +50,7 @@ func (r *PracticePatchHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *PracticePatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	if practice, err := Repo.PracticeCrud.GetById(ctx, r.Id, nil); err != nil {
+	if practice, err := Repo.PracticeCrud.GetById(ctx, r.Id, nil); err != nil || practice.IsDeleted() {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Match with id " + r.Id + " doesn't exist")
 	} else {
 		r.coachId = practice.CoachId
@@ -48,55 +58,103 @@ func (r *PracticePatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA
 	return nil
 }
 
+func (r *PracticePatchHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.coachId != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only coach can change practice")
+	}
+	return nil
+}
+
 func (r *PracticePatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
 	up := DR.PracticeUpdateParams{
 		Id:     r.Id,
 		Status: (*DR.PracticeStatus)(&r.Status),
 	}
 	if r.Status == string(DR.PS_ACCEPTED) {
-		coach, err := Repo.CoachCrud.GetById(ctx, r.coachId, nil)
-		if err != nil {
-			return nil, DA.InternalServerError(err)
-		}
-		var start, end time.Time
-		for i := range *coach.Booking {
-			if (*coach.Booking)[i].PracticeId == r.Id {
-				(*coach.Booking)[i].Accepted = true
-				start = (*coach.Booking)[i].StartTime
-				end = (*coach.Booking)[i].EndTime
-			}
-		}
-		newBooking := DR.Booking{}
-		for _, booking := range *coach.Booking {
-			if booking.Accepted || DA.AfterEqual(start, booking.EndTime) || DA.BeforeEqual(end, booking.StartTime) {
-				newBooking = append(newBooking, booking)
-			} else {
-				denied := DR.PS_DENIED
-				up := DR.PracticeUpdateParams{
-					Id:     booking.PracticeId,
-					Status: &denied,
-				}
-				_, err := Repo.PracticeCrud.Update(ctx, up, nil, nil)
-				if err != nil {
-					return nil, DA.InternalServerError(err)
-				}
-			}
+		if err := r.confirmBooking(ctx, Repo, tx); err != nil {
+			return nil, err
 		}
-		*coach.Booking = newBooking
-		up := DR.CoachUpdateParams{
-			Id:      coach.Username,
-			Booking: coach.Booking,
+	}
+	ret, err := Repo.PracticeCrud.Update(ctx, up, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if ret.Status == DR.PS_ACCEPTED {
+		if err = events.Publish(ctx, Repo, tx, events.PracticeAccepted{PracticeId: ret.PracticeId, CoachId: ret.CoachId, PlayerId: ret.PlayerId}); err != nil {
+			return nil, DA.InternalServerError(err)
 		}
-		_, err = Repo.CoachCrud.Update(ctx, up, nil, nil)
+	}
+	var notes notify.Batch
+	coach, _ := Repo.GetNameForId(ctx, r.coachId)
+	switch ret.Status {
+	case DR.PS_ACCEPTED:
+		err = notes.Store(ctx, Repo, tx, DR.Notification{Type: DR.NT_PRACTICE_ACCEPTED, ReferenceId: ret.PracticeId,
+			Message: "Your practice with coach " + coach + " was accepted"}, ret, ret.PlayerId)
+	case DR.PS_DENIED:
+		err = notes.Store(ctx, Repo, tx, DR.Notification{Type: DR.NT_PRACTICE_DENIED, ReferenceId: ret.PracticeId,
+			Message: "Your practice with coach " + coach + " was denied"}, ret, ret.PlayerId)
+	}
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	for _, practice := range r.denied {
+		err = notes.Store(ctx, Repo, tx, DR.Notification{Type: DR.NT_PRACTICE_DENIED, ReferenceId: practice.PracticeId,
+			Message: "Your practice with coach " + coach + " was denied, coach accepted other practice at that time"}, practice, practice.PlayerId)
 		if err != nil {
 			return nil, DA.InternalServerError(err)
 		}
 	}
-	ret, err := Repo.PracticeCrud.Update(ctx, up, nil, nil)
+	err = tx.Commit()
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	notes.Send(ctx)
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
 }
+
+// confirmBooking confirms booking of accepted practice and denies other practices of coach
+// that were requested for overlapping time
+func (r *PracticePatchHandler) confirmBooking(ctx context.Context, Repo *crud.Repo, tx crud.Tx) DA.Error {
+	kind := DR.BK_PRACTICE
+	bookings, err := Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{Kind: &kind, ReferenceId: &r.Id}, tx)
+	if err != nil {
+		return DA.InternalServerError(err)
+	}
+	if len(bookings) == 0 {
+		return DA.InternalServerError(fmt.Errorf("practice %s has no booking", r.Id))
+	}
+	accepted := bookings[0]
+
+	pending := DR.BS_PENDING
+	overlapping, err := Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{OwnerId: &r.coachId, Kind: &kind, Status: &pending, From: &accepted.StartTime, To: &accepted.EndTime}, tx)
+	if err != nil {
+		return DA.InternalServerError(err)
+	}
+	denied := DR.PS_DENIED
+	for _, booking := range overlapping {
+		if booking.BookingId == accepted.BookingId {
+			continue
+		}
+		practice, err := Repo.PracticeCrud.Update(ctx, DR.PracticeUpdateParams{Id: booking.ReferenceId, Status: &denied}, tx, nil)
+		if err != nil {
+			return DA.InternalServerError(err)
+		}
+		r.denied = append(r.denied, practice)
+		if err := Repo.BookingCrud.Delete(ctx, booking.BookingId, tx, nil); err != nil {
+			return DA.InternalServerError(err)
+		}
+	}
+
+	confirmed := DR.BS_CONFIRMED
+	if _, err := Repo.BookingCrud.Update(ctx, DR.BookingUpdateParams{Id: accepted.BookingId, Status: &confirmed}, tx, nil); err != nil {
+		return bookingError(err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/notify"
+	DR "backend/sportos/repo/dto"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPracticeAcceptBooksCoachInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	request := func(c *apitest.Client, start time.Time) apitest.Response {
+		return c.Do(DR.SUB_CL, http.MethodPost, DA.HN_PRACTICES, map[string]interface{}{
+			"startTime": start,
+			"coachId":   apitest.FIXTURE_COACH,
+			"sport":     "Table tennis",
+		})
+	}
+	// both players can ask for same time until coach accepts one of practices
+	var first, second DR.Practice
+	res := request(h.Player(), start)
+	if res.Code != http.StatusOK {
+		t.Fatalf("create first practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &first)
+	res = request(h.As(apitest.FIXTURE_SECOND_PLAYER, DR.UT_PLAYER), start.Add(30*time.Minute))
+	if res.Code != http.StatusOK {
+		t.Fatalf("create second practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &second)
+
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPatch, DA.HN_PRACTICES, map[string]interface{}{"id": first.PracticeId, "status": DR.PS_ACCEPTED})
+	if res.Code != http.StatusOK {
+		t.Fatalf("accept practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	second, err := h.Repo.PracticeCrud.GetById(ctx, second.PracticeId, nil)
+	if err != nil {
+		t.Fatalf("get practice: %v", err)
+	}
+	if second.Status != DR.PS_DENIED {
+		t.Errorf("expected overlapping practice to be %s, got %s", DR.PS_DENIED, second.Status)
+	}
+
+	res = request(h.Player(), start)
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for time that is booked, got %d: %s", res.Code, res.Body)
+	}
+	res = request(h.Player(), start.Add(time.Hour))
+	if res.Code != http.StatusOK {
+		t.Errorf("expected 200 for time after booked practice, got %d: %s", res.Code, res.Body)
+	}
+
+	owner := apitest.FIXTURE_COACH
+	confirmed := DR.BS_CONFIRMED
+	bookings, err := h.Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{OwnerId: &owner, Status: &confirmed}, nil)
+	if err != nil {
+		t.Fatalf("search bookings: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].ReferenceId != first.PracticeId {
+		t.Errorf("expected confirmed booking of first practice, got %+v", bookings)
+	}
+}
+
+func TestPracticeAcceptNotifiesPlayerInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+
+	stream := h.Player().Stream(DR.SUB_CL, DA.HN_NOTIFICATIONS_SSE)
+	defer stream.Body.Close()
+	if stream.StatusCode != http.StatusOK || !strings.HasPrefix(stream.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("open stream: expected event stream, got %d %s", stream.StatusCode, stream.Header.Get("Content-Type"))
+	}
+	events := make(chan notify.Event, 4)
+	go func() {
+		scanner := bufio.NewScanner(stream.Body)
+		for scanner.Scan() {
+			if data := strings.TrimPrefix(scanner.Text(), "data: "); data != scanner.Text() {
+				var ev notify.Event
+				if json.Unmarshal([]byte(data), &ev) == nil {
+					events <- ev
+				}
+			}
+		}
+		close(events)
+	}()
+
+	if res := h.Anonymous().Get(DR.SUB_CL, DA.HN_NOTIFICATIONS_SSE, nil); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for stream without token, got %d: %s", res.Code, res.Body)
+	}
+
+	var practice DR.Practice
+	res := h.Player().Do(DR.SUB_CL, http.MethodPost, DA.HN_PRACTICES, map[string]interface{}{
+		"startTime": time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour),
+		"coachId":   apitest.FIXTURE_COACH,
+		"sport":     "Table tennis",
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("create practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &practice)
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPatch, DA.HN_PRACTICES, map[string]interface{}{"id": practice.PracticeId, "status": DR.PS_ACCEPTED})
+	if res.Code != http.StatusOK {
+		t.Fatalf("accept practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Type != DR.NT_PRACTICE_ACCEPTED || ev.ReferenceId != practice.PracticeId {
+			t.Errorf("expected %s of practice %s, got %+v", DR.NT_PRACTICE_ACCEPTED, practice.PracticeId, ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected notification about accepted practice")
+	}
+}
+
+func TestPracticeNotificationsStayInInboxInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+
+	// player isn't connected to stream, notification waits in inbox
+	var practice DR.Practice
+	res := h.Player().Do(DR.SUB_CL, http.MethodPost, DA.HN_PRACTICES, map[string]interface{}{
+		"startTime": time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour),
+		"coachId":   apitest.FIXTURE_COACH,
+		"sport":     "Table tennis",
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("create practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &practice)
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPatch, DA.HN_PRACTICES, map[string]interface{}{"id": practice.PracticeId, "status": DR.PS_DENIED})
+	if res.Code != http.StatusOK {
+		t.Fatalf("deny practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	var inbox DA.Notifications
+	res = h.Player().Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, url.Values{"unread": {"true"}})
+	if res.Code != http.StatusOK {
+		t.Fatalf("get notifications: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &inbox)
+	if inbox.Unread != 1 || len(inbox.Notifications) != 1 || inbox.Notifications[0].Type != DR.NT_PRACTICE_DENIED || inbox.Notifications[0].ReferenceId != practice.PracticeId {
+		t.Fatalf("expected one unread notification about denied practice, got %+v", inbox)
+	}
+
+	// coach got notification about request, player can't mark it
+	var coachInbox DA.Notifications
+	h.Coach().Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, nil).Decode(t, &coachInbox)
+	if len(coachInbox.Notifications) != 1 || coachInbox.Notifications[0].Type != DR.NT_PRACTICE_REQUESTED {
+		t.Fatalf("expected notification about requested practice for coach, got %+v", coachInbox)
+	}
+	res = h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_NOTIFICATIONS, map[string]interface{}{"ids": []string{coachInbox.Notifications[0].Id}})
+	if res.Code == http.StatusOK {
+		t.Errorf("expected error when player marks notification of coach, got %d: %s", res.Code, res.Body)
+	}
+
+	res = h.Player().Do(DR.SUB_CL, http.MethodPatch, DA.HN_NOTIFICATIONS, map[string]interface{}{"ids": []string{inbox.Notifications[0].Id}})
+	if res.Code != http.StatusOK {
+		t.Fatalf("mark read: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	h.Player().Get(DR.SUB_CL, DA.HN_NOTIFICATIONS, nil).Decode(t, &inbox)
+	if inbox.Unread != 0 || len(inbox.Notifications) != 1 || !inbox.Notifications[0].Read {
+		t.Errorf("expected read notification in inbox, got %+v", inbox)
+	}
+}
@@ -2,6 +2,8 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/availability"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
@@ -12,6 +14,7 @@ import (
 
 type PracticePostHandler struct {
 	PracticePostRequest
+	slot availability.Slot
 }
 
 type PracticePostRequest struct {
@@ -29,6 +32,10 @@ func (r PracticePostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r PracticePostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *PracticePostHandler) Init(httpReq *http.Request) DA.Error {
 	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	decode := json.NewDecoder(httpReq.Body)
@@ -49,16 +56,10 @@ func (r *PracticePostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.
 	if err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_UNIQUE_CONSTRAINT).WithMessage("Coach doesn't exist")
 	}
-	endTime := r.StartTime.Add(time.Hour)
-	if coach.Booking != nil {
-		for _, booking := range *coach.Booking {
-			if !booking.Accepted {
-				continue
-			}
-			if !(DA.AfterEqual(*r.StartTime, booking.EndTime) || DA.BeforeEqual(endTime, booking.StartTime)) {
-				return DA.ErrorBadRequest().WithMessage("That appointment is already occupied")
-			}
-		}
+	// only accepted practices occupy coach, other requests for same time wait for coach to choose one
+	var apiErr DA.Error
+	if r.slot, apiErr = bookable(ctx, Repo, r.CoachId, coach.Schedule, coach.TimeZone, *r.StartTime); apiErr != nil {
+		return apiErr
 	}
 	if _, err := DR.GetSportByName(r.Sport); err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Sport doesn't exist")
@@ -74,7 +75,7 @@ func (r *PracticePostHandler) Process(ctx context.Context, Repo *crud.Repo) (int
 		Status:    DR.PS_CREATED,
 		Sport:     r.Sport,
 	}
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -83,21 +84,28 @@ func (r *PracticePostHandler) Process(ctx context.Context, Repo *crud.Repo) (int
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	place, _ := Repo.CoachCrud.GetById(ctx, r.CoachId, tx)
-	up := DR.CoachUpdateParams{
-		Id: r.CoachId,
+	booking := DR.Booking{
+		OwnerId:     r.CoachId,
+		Kind:        DR.BK_PRACTICE,
+		ReferenceId: ret.PracticeId,
+		StartTime:   r.slot.Start,
+		EndTime:     r.slot.End,
+		Status:      DR.BS_PENDING,
 	}
-	booking := place.Booking
-	if booking == nil {
-		booking = &DR.Booking{}
+	if _, err := Repo.BookingCrud.Create(ctx, booking, tx, nil); err != nil {
+		return nil, bookingError(err)
 	}
-	*booking = append(*booking, DR.Apointment{StartTime: *r.StartTime, EndTime: r.StartTime.Add(time.Hour), Accepted: false, PracticeId: ret.PracticeId})
-	up.Booking = booking
-	_, err = Repo.CoachCrud.Update(ctx, up, tx, nil)
+	var notes notify.Batch
+	player, _ := Repo.GetNameForId(ctx, r.userId)
+	err = notes.Store(ctx, Repo, tx, DR.Notification{Type: DR.NT_PRACTICE_REQUESTED, ReferenceId: ret.PracticeId,
+		Message: player + " requested practice with you"}, ret, r.CoachId)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	tx.Commit()
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	notes.Send(ctx)
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
@@ -0,0 +1,10 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	apitest.Main(m)
+}
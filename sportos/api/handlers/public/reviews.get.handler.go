@@ -21,6 +21,10 @@ func (r ReviewsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r ReviewsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *ReviewsGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
 	return nil
@@ -29,6 +29,10 @@ func (r ReviewsPatchHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r ReviewsPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *ReviewsPatchHandler) Init(httpReq *http.Request) DA.Error {
 	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	decode := json.NewDecoder(httpReq.Body)
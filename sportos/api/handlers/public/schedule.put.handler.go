@@ -0,0 +1,71 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/availability"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// SchedulePutHandler replaces opening hours, exceptions, slot length and buffer of place or coach that calls it
+type SchedulePutHandler struct {
+	DR.Schedule
+	userId   string
+	userType DR.UserType
+}
+
+func (r SchedulePutHandler) SupportedMethod() string {
+	return http.MethodPut
+}
+
+func (r SchedulePutHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r SchedulePutHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLACE, DR.UT_COACH}
+}
+
+func (r *SchedulePutHandler) Init(httpReq *http.Request) DA.Error {
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	r.userType = DA.GetUserTypeFromContext(httpReq.Context())
+	decode := json.NewDecoder(httpReq.Body)
+	decode.DisallowUnknownFields()
+	err := decode.Decode(&r.Schedule)
+	if err == nil {
+		return nil
+	} else {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(err.Error())
+	}
+}
+
+func (r *SchedulePutHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if err := availability.Validate(r.Schedule); err != nil {
+		return DA.ErrorBadRequest().WithMessage(err.Error())
+	}
+	if r.SlotMinutes == 0 {
+		r.SlotMinutes = availability.DefaultSlotMinutes
+	}
+	return nil
+}
+
+func (r *SchedulePutHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	resMap := make(map[string]interface{})
+	if r.userType == DR.UT_COACH {
+		coach, err := Repo.CoachCrud.Update(ctx, DR.CoachUpdateParams{Id: r.userId, Schedule: &r.Schedule}, nil, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		resMap["body"] = coach
+	} else {
+		place, err := Repo.PlaceCrud.Update(ctx, DR.PlaceUpdateParams{Id: r.userId, Schedule: &r.Schedule}, nil, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		resMap["body"] = place
+	}
+	return resMap, nil
+}
@@ -0,0 +1,169 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// formDefault is number of last matches in form of player when form isn't requested
+	formDefault = 10
+	formMax     = 100
+)
+
+// StatisticsAnalyticsGetHandler returns analytics of caller in sport: head-to-head records against opponents,
+// records with teammates, form in last matches and records by venue, weekday and hour of match.
+// Analytics can be limited to matches played from date and before date to, weekday and hour are on wall
+// clock of venue. Best and worst teammate are picked from teammates with at least minMatches matches
+type StatisticsAnalyticsGetHandler struct {
+	SearchParams DR.PlayerMatchSearchParams
+	form         *int64
+	minMatches   *int64
+}
+
+func (r StatisticsAnalyticsGetHandler) SupportedMethod() string {
+	return http.MethodGet
+}
+
+func (r StatisticsAnalyticsGetHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r StatisticsAnalyticsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *StatisticsAnalyticsGetHandler) Init(httpReq *http.Request) DA.Error {
+	userId := DA.GetUserIdFromContext(httpReq.Context())
+	r.SearchParams.PlayerId = &userId
+	r.SearchParams.Sport = DA.GetParameterFromURLQuery(httpReq, "sport")
+	errorMessages := make([]string, 0)
+	var errorMessage string
+
+	r.SearchParams.From, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "from"), "from")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.SearchParams.To, errorMessage = DA.ParseDate(DA.GetParameterFromURLQuery(httpReq, "to"), "to")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.form, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "form"), "form")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.minMatches, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "minMatches"), "minMatches")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
+	return nil
+}
+
+func (r *StatisticsAnalyticsGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.SearchParams.Sport == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Sport is mandatory")
+	}
+	if _, err := DR.GetSportByName(*r.SearchParams.Sport); err != nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("Sport doesn't exist")
+	}
+	if r.SearchParams.From != nil && r.SearchParams.To != nil && !r.SearchParams.From.Before(*r.SearchParams.To) {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage("From must be before to")
+	}
+	if r.form == nil {
+		form := int64(formDefault)
+		r.form = &form
+	} else if *r.form < 1 || *r.form > formMax {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_VALUE).WithMessage(fmt.Sprintf("Form can be between 1 and %d matches", formMax))
+	}
+	if r.minMatches == nil {
+		minMatches := int64(1)
+		r.minMatches = &minMatches
+	}
+	return nil
+}
+
+func (r *StatisticsAnalyticsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	ret := DA.Analytics{}
+	last := r.SearchParams
+	last.PagingSearchParams = DR.PagingSearchParams{Limit: r.form}
+	matches, err := Repo.PlayerMatchCrud.Search(ctx, last, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	ret.Form.InitWithDatabaseStruct(matches)
+
+	groups := []struct {
+		by      DR.PlayerMatchGroupBy
+		records *[]DA.Record
+	}{
+		{DR.PMG_OPPONENT, &ret.HeadToHead},
+		{DR.PMG_TEAMMATE, &ret.Teammates},
+		{DR.PMG_PLACE, &ret.Venues},
+		{DR.PMG_WEEKDAY, &ret.Weekdays},
+		{DR.PMG_HOUR, &ret.Hours},
+	}
+	for _, group := range groups {
+		rows, err := Repo.PlayerMatchCrud.Group(ctx, r.SearchParams, group.by, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		*group.records = make([]DA.Record, len(rows))
+		for i, row := range rows {
+			(*group.records)[i].InitWithDatabaseStruct(row)
+		}
+	}
+	sortByClock(ret.Weekdays, func(key int) (int, string) {
+		return (key + 6) % 7, time.Weekday(key).String()
+	})
+	sortByClock(ret.Hours, func(key int) (int, string) {
+		return key, fmt.Sprintf("%02d:00", key)
+	})
+	ret.BestTeammate, ret.WorstTeammate = bestAndWorst(ret.Teammates, int(*r.minMatches))
+
+	resMap := make(map[string]interface{})
+	resMap["body"] = ret
+	return resMap, nil
+}
+
+// sortByClock names records of weekdays or hours and sorts them by order that clock returns for key
+func sortByClock(records []DA.Record, clock func(key int) (int, string)) {
+	order := make(map[string]int, len(records))
+	for i := range records {
+		key, _ := strconv.Atoi(records[i].Id)
+		order[records[i].Id], records[i].Name = clock(key)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return order[records[i].Id] < order[records[j].Id]
+	})
+}
+
+// bestAndWorst returns teammates with highest and lowest win ratio among teammates with at least minMatches
+// matches, more matches decide between equal ratios
+func bestAndWorst(teammates []DA.Record, minMatches int) (*DA.Record, *DA.Record) {
+	var best, worst *DA.Record
+	var bestRatio, worstRatio decimal.Decimal
+	for i := range teammates {
+		t := &teammates[i]
+		if t.Matches < minMatches {
+			continue
+		}
+		ratio := DR.PlayerMatchSummary{Matches: t.Matches, Wins: t.Wins}.WinRatio()
+		if best == nil || ratio.GreaterThan(bestRatio) || (ratio.Equal(bestRatio) && t.Matches > best.Matches) {
+			best, bestRatio = t, ratio
+		}
+		if worst == nil || ratio.LessThan(worstRatio) || (ratio.Equal(worstRatio) && t.Matches > worst.Matches) {
+			worst, worstRatio = t, ratio
+		}
+	}
+	return best, worst
+}
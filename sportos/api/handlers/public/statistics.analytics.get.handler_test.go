@@ -0,0 +1,102 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatisticsAnalyticsInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	first, second := apitest.FIXTURE_PLAYER, apitest.FIXTURE_SECOND_PLAYER
+	sport := "Table tennis"
+	// Monday 18:00 and Saturday 10:00 on wall clock of fixture place in Belgrade
+	results := []struct {
+		start                    time.Time
+		pointsFor, pointsAgainst int
+	}{
+		{time.Date(2024, 3, 4, 17, 0, 0, 0, time.UTC), 3, 1},
+		{time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC), 0, 3},
+		{time.Date(2024, 3, 11, 17, 0, 0, 0, time.UTC), 2, 2},
+	}
+	for _, result := range results {
+		start := result.start
+		players := DR.StrArr{first, second}
+		match, err := h.Repo.MatchCrud.Create(ctx, DR.Match{
+			StartTime: &start,
+			PlaceId:   apitest.FIXTURE_PLACE,
+			Status:    DR.MS_FINISHED,
+			Players:   players,
+			Sport:     sport,
+		}, nil, nil)
+		if err != nil {
+			t.Fatalf("create match: %v", err)
+		}
+		for side, id := range players {
+			side := side
+			if _, err = h.Repo.MatchPlayerCrud.Update(ctx, DR.MatchPlayerUpdateParams{MatchId: match.MatchId, PlayerId: id, Side: &side}, nil, nil); err != nil {
+				t.Fatalf("update side of %s: %v", id, err)
+			}
+		}
+		err = h.Repo.PlayerMatchCrud.Create(ctx, DR.PlayerMatch{
+			MatchId:       match.MatchId,
+			PlayerId:      first,
+			Sport:         sport,
+			PlayedAt:      start,
+			Side:          0,
+			PointsFor:     result.pointsFor,
+			PointsAgainst: result.pointsAgainst,
+			Outcome:       DR.Outcome(result.pointsFor, result.pointsAgainst),
+		}, nil, nil)
+		if err != nil {
+			t.Fatalf("create player match: %v", err)
+		}
+	}
+
+	res := h.Player().Get(DR.SUB_CL, DA.HN_STATS_ANALYTICS+"?sport=Table%20tennis&form=2", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("analytics: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var analytics DA.Analytics
+	res.Decode(t, &analytics)
+	if analytics.Form.Results != "DL" || analytics.Form.Matches != 2 || analytics.Form.WinRatio != "0.00%" {
+		t.Errorf("expected form of last draw and loss, got %+v", analytics.Form)
+	}
+	if len(analytics.HeadToHead) != 1 || analytics.HeadToHead[0].Id != second || analytics.HeadToHead[0].Matches != 3 ||
+		analytics.HeadToHead[0].Wins != 1 || analytics.HeadToHead[0].Draws != 1 || analytics.HeadToHead[0].Losses != 1 {
+		t.Errorf("expected head-to-head of 3 matches against %s, got %+v", second, analytics.HeadToHead)
+	}
+	if len(analytics.Teammates) != 0 || analytics.BestTeammate != nil {
+		t.Errorf("expected no teammates in singles, got %+v", analytics.Teammates)
+	}
+	if len(analytics.Venues) != 1 || analytics.Venues[0].Id != apitest.FIXTURE_PLACE || analytics.Venues[0].Matches != 3 {
+		t.Errorf("expected all matches at fixture place, got %+v", analytics.Venues)
+	}
+	if len(analytics.Weekdays) != 2 || analytics.Weekdays[0].Name != "Monday" || analytics.Weekdays[0].Matches != 2 ||
+		analytics.Weekdays[1].Name != "Saturday" || analytics.Weekdays[1].Losses != 1 {
+		t.Errorf("expected Monday before Saturday, got %+v", analytics.Weekdays)
+	}
+	if len(analytics.Hours) != 2 || analytics.Hours[0].Name != "10:00" || analytics.Hours[1].Name != "18:00" || analytics.Hours[1].WinRatio != "50.00%" {
+		t.Errorf("expected hours on wall clock of place, got %+v", analytics.Hours)
+	}
+
+	res = h.Player().Get(DR.SUB_CL, DA.HN_STATS_ANALYTICS+"?sport=Table%20tennis&from=2024-03-05&to=2024-03-10", nil)
+	analytics = DA.Analytics{}
+	res.Decode(t, &analytics)
+	if res.Code != http.StatusOK || analytics.Form.Results != "L" || len(analytics.HeadToHead) != 1 || analytics.HeadToHead[0].Losses != 1 {
+		t.Errorf("expected only loss between from and to, got %d: %+v", res.Code, analytics)
+	}
+
+	if res = h.Player().Get(DR.SUB_CL, DA.HN_STATS_ANALYTICS+"?from=2024-03-05", nil); res.Code != http.StatusBadRequest {
+		t.Errorf("missing sport: expected 400, got %d", res.Code)
+	}
+	if res = h.Player().Get(DR.SUB_CL, DA.HN_STATS_ANALYTICS+"?sport=Table%20tennis&from=2024-03-10&to=2024-03-05", nil); res.Code != http.StatusBadRequest {
+		t.Errorf("from after to: expected 400, got %d", res.Code)
+	}
+}
@@ -5,13 +5,18 @@ import (
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"fmt"
 	"net/http"
 )
 
+// ratingHistoryLimit is number of last rating changes returned with statistics
+const ratingHistoryLimit = 20
+
+// StatisticsGetHandler returns statistics of caller in sport aggregated from results of matches,
+// offset and limit page list of matches
 type StatisticsGetHandler struct {
 	userId string
 	sport  string
+	paging DR.PagingSearchParams
 }
 
 func (r StatisticsGetHandler) SupportedMethod() string {
@@ -22,54 +27,98 @@ func (r StatisticsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r StatisticsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *StatisticsGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.userId = DA.GetUserIdFromContext(httpReq.Context())
-	r.sport = *DA.GetParameterFromURLQuery(httpReq, "sport")
+	if sport := DA.GetParameterFromURLQuery(httpReq, "sport"); sport != nil {
+		r.sport = *sport
+	}
+	errorMessages := make([]string, 0)
+	var errorMessage string
+
+	r.paging.Offset, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "offset"), "offset")
+	errorMessages = append(errorMessages, errorMessage)
+
+	r.paging.Limit, errorMessage = DA.ParseInt(DA.GetParameterFromURLQuery(httpReq, "limit"), "limit")
+	errorMessages = append(errorMessages, errorMessage)
+
+	errorMessages = DA.TrimEmpty(errorMessages)
+
+	if len(errorMessages) > 0 {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_WRONG_REQUEST_PARAMS).WithPredefinedPayload(errorMessages)
+	}
 	return nil
 }
 
 func (r *StatisticsGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.sport == "" {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Sport is mandatory")
+	}
 	return nil
 }
 
 func (r *StatisticsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	player, err := Repo.PlayerCrud.GetById(ctx, r.userId, nil)
+	summary, err := Repo.PlayerMatchCrud.Summary(ctx, r.userId, r.sport, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
 	ret := DA.Statistics{}
-	if stat, ok := player.Statistics[r.sport]; ok {
-		ret = DA.Statistics{
-			WinRatio: fmt.Sprintf("%.2f%%", stat.WinRatio.InexactFloat64()*100),
-		}
-		for _, tournament := range stat.Tournaments {
-			singleApiTournament := DA.Tournament{
-				Tournament: tournament.Tournament,
-				Ranking:    tournament.Ranking,
-			}
-			for _, playerId := range tournament.MyTeam {
-				player, _ := Repo.PlayerCrud.GetById(ctx, playerId, nil)
-				singleApiTournament.MyTeam = append(singleApiTournament.MyTeam, player.Name)
-			}
-			ret.Tournaments = append(ret.Tournaments, singleApiTournament)
-		}
-		for _, singleStat := range stat.Matches {
-			singleApiStat := DA.Statistic{
-				Date:  singleStat.Date,
-				Score: singleStat.Score,
-			}
-			for _, playerId := range singleStat.MyTeam {
-				player, _ := Repo.PlayerCrud.GetById(ctx, playerId, nil)
-				singleApiStat.MyTeam = append(singleApiStat.MyTeam, player.Name)
-			}
-			for _, playerId := range singleStat.OppTeam {
-				player, _ := Repo.PlayerCrud.GetById(ctx, playerId, nil)
-				singleApiStat.OppTeam = append(singleApiStat.OppTeam, player.Name)
-			}
-			ret.Matches = append(ret.Matches, singleApiStat)
-		}
+	ret.InitWithDatabaseStruct(summary)
+	matches, err := Repo.PlayerMatchCrud.Search(ctx, DR.PlayerMatchSearchParams{PlayerId: &r.userId, Sport: &r.sport, PagingSearchParams: r.paging}, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	for _, pm := range matches {
+		stat := DA.Statistic{}
+		stat.InitWithDatabaseStruct(pm)
+		ret.Matches = append(ret.Matches, stat)
+	}
+	months, err := Repo.PlayerMatchCrud.Monthly(ctx, r.userId, r.sport, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	for _, m := range months {
+		month := DA.MonthStatistics{}
+		month.InitWithDatabaseStruct(m)
+		ret.Months = append(ret.Months, month)
+	}
+	tournaments, err := Repo.PlayerTournamentCrud.Search(ctx, DR.PlayerTournamentSearchParams{PlayerId: &r.userId, Sport: &r.sport}, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	for _, pt := range tournaments {
+		tournament := DA.Tournament{}
+		tournament.InitWithDatabaseStruct(pt)
+		ret.Tournaments = append(ret.Tournaments, tournament)
+	}
+	if ret.Rating, err = playerRating(ctx, Repo, r.userId, r.sport); err != nil {
+		return nil, DA.InternalServerError(err)
 	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
 }
+
+// playerRating returns rating of player in sport with last rating changes, nil if player didn't play rated match
+func playerRating(ctx context.Context, Repo *crud.Repo, playerId, sport string) (*DA.Rating, error) {
+	ratings, err := Repo.RatingCrud.Search(ctx, DR.RatingSearchParams{PlayerId: &playerId, Sport: &sport}, nil)
+	if err != nil || len(ratings) == 0 {
+		return nil, err
+	}
+	ret := &DA.Rating{}
+	ret.InitWithDatabaseStruct(ratings[0])
+	limit := int64(ratingHistoryLimit)
+	history, err := Repo.RatingHistoryCrud.Search(ctx, DR.RatingHistorySearchParams{PlayerId: &playerId, Sport: &sport, PagingSearchParams: DR.PagingSearchParams{Limit: &limit}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, rh := range history {
+		change := DA.RatingChange{}
+		change.InitWithDatabaseStruct(rh)
+		ret.History = append(ret.History, change)
+	}
+	return ret, nil
+}
@@ -0,0 +1,63 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type TeamsDeleteHandler struct {
+	Id     *string `json:"id,omitempty"`
+	userId string
+	team   DR.Team
+}
+
+func (r TeamsDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r TeamsDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r TeamsDeleteHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
+func (r *TeamsDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *TeamsDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	team, err := Repo.TeamCrud.GetById(ctx, *r.Id, nil)
+	if err != nil || team.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Team with id " + *r.Id + " doesn't exist")
+	}
+	r.team = team
+	return nil
+}
+
+// CheckOwnership allows only player who created team (first player) to delete it
+func (r *TeamsDeleteHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.team.Owner() != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only player who created team can delete it")
+	}
+	return nil
+}
+
+func (r *TeamsDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	err := Repo.TeamCrud.Delete(ctx, r.team.TeamId, nil, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
@@ -24,6 +24,10 @@ func (r TeamsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TeamsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *TeamsGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.skipUser = DA.GetParameterFromURLQuery(httpReq, "skipUser")
 	r.sports = DA.GetParameterFromURLQuery(httpReq, "sports")
@@ -56,10 +60,7 @@ func (r *TeamsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interfa
 			Name:  team.Name,
 			Sport: team.Sport,
 		}
-		for _, playerId := range strings.Split(team.Players, ",") {
-			player, _ := Repo.PlayerCrud.GetById(ctx, playerId, nil)
-			apiTeam.Players = append(apiTeam.Players, player.Name)
-		}
+		apiTeam.Players = playerNames(ctx, Repo, team.Players)
 		ret = append(ret, apiTeam)
 	}
 	resMap := make(map[string]interface{})
@@ -2,18 +2,17 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
 	"encoding/json"
 	"net/http"
-	"strings"
 )
 
 type TeamsPatchHandler struct {
 	TeamsPatchRequest
-	Players string
-	sport   string
+	team DR.Team
 }
 
 type TeamsPatchRequest struct {
@@ -29,6 +28,10 @@ func (r TeamsPatchHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TeamsPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *TeamsPatchHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
@@ -41,43 +44,67 @@ func (r *TeamsPatchHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *TeamsPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	if team, err := Repo.TeamCrud.GetById(ctx, r.Id, nil); err != nil {
+	if team, err := Repo.TeamCrud.GetById(ctx, r.Id, nil); err != nil || team.IsDeleted() {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Match with id " + r.Id + " doesn't exist")
 	} else {
-		if r.PlayerToAdd != nil && strings.Contains(team.Players, *r.PlayerToAdd) {
+		if r.PlayerToAdd != nil && team.HasPlayer(*r.PlayerToAdd) {
 			return DA.ErrorBadRequest().WithMessage("Player is already in that team")
 		}
 		if r.PlayerToAdd != nil && team.Status == DR.TS_FULL {
 			return DA.ErrorBadRequest().WithMessage("Team is full already")
 		}
-		r.sport = team.Sport
-		r.Players = team.Players
+		r.team = team
 	}
 	return nil
 }
 
 func (r *TeamsPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	sport, err := DR.GetSportByName(r.sport)
+	sport, err := DR.GetSportByName(r.team.Sport)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	defer tx.Rollback()
 	var status *DR.TeamStatus
+	players := len(r.team.Players)
 	if r.PlayerToAdd != nil {
-		r.Players += "," + *r.PlayerToAdd
+		_, err = Repo.TeamMemberCrud.Create(ctx, DR.TeamMember{TeamId: r.Id, PlayerId: *r.PlayerToAdd, Role: DR.TR_MEMBER}, tx, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		players++
 	}
-	if len(strings.Split(r.Players, ",")) == sport.TeamSize {
+	if players == sport.TeamSize {
 		status = new(DR.TeamStatus)
 		*status = DR.TS_FULL
 	}
 	up := DR.TeamUpdateParams{
-		Id:      r.Id,
-		Players: &r.Players,
-		Status:  status,
+		Id:     r.Id,
+		Status: status,
 	}
-	ret, err := Repo.TeamCrud.Update(ctx, up, nil, nil)
+	ret, err := Repo.TeamCrud.Update(ctx, up, tx, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	var notes notify.Batch
+	if r.PlayerToAdd != nil {
+		player, _ := Repo.GetNameForId(ctx, *r.PlayerToAdd)
+		n := DR.Notification{Type: DR.NT_TEAM_PLAYER_JOINED, ReferenceId: ret.TeamId, Message: player + " joined team " + ret.Name}
+		if status != nil {
+			n.Type = DR.NT_TEAM_FULL
+			n.Message = "Team " + ret.Name + " is full"
+		}
+		if err = notes.Store(ctx, Repo, tx, n, ret, ret.Players...); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	notes.Send(ctx)
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
@@ -31,6 +31,10 @@ func (r TeamsPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TeamsPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER}
+}
+
 func (r *TeamsPostHandler) Init(httpReq *http.Request) DA.Error {
 	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	decode := json.NewDecoder(httpReq.Body)
@@ -59,7 +63,7 @@ func (r *TeamsPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interf
 	team := DR.Team{
 		Status:  DR.TS_CREATED,
 		Sport:   r.Sport,
-		Players: r.userId,
+		Players: DR.StrArr{r.userId},
 		Name:    r.Name,
 	}
 	if r.teamSize == 1 {
@@ -2,10 +2,11 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/availability"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 )
@@ -13,10 +14,23 @@ import (
 type TimesGetHandler struct {
 	PlaceId *string   `json:"username,omitempty"`
 	Date    time.Time `json:"date,omitempty"`
+	// loc is time zone in which start and end of slots are returned, owner's zone if tz isn't sent
+	loc *time.Location
+	// day is midnight of requested date in owner's time zone
+	day      time.Time
+	schedule *DR.Schedule
+	timeZone string
 }
 
 type TimesGetResponse struct {
-	Value string `json:"value"`
+	// Value is start of slot on wall clock of owner
+	Value string    `json:"value"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// TimeZone is zone of Start and End, tz of request or zone of owner
+	TimeZone string `json:"timeZone"`
+	// OwnerTimeZone is zone of Value
+	OwnerTimeZone string `json:"ownerTimeZone"`
 }
 
 func (r TimesGetHandler) SupportedMethod() string {
@@ -27,69 +41,139 @@ func (r TimesGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TimesGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *TimesGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.PlaceId = DA.GetParameterFromURLQuery(httpReq, "username")
+	date := DA.GetParameterFromURLQuery(httpReq, "date")
+	if date == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Date is mandatory")
+	}
 	var err error
-	r.Date, err = time.Parse(time.RFC3339, *DA.GetParameterFromURLQuery(httpReq, "date"))
+	r.Date, err = time.Parse(time.RFC3339, *date)
 	if err != nil {
 		return DA.ErrorBadRequest().WithMessage("Bad date format")
 	}
+	if tz := DA.GetParameterFromURLQuery(httpReq, "tz"); tz != nil {
+		if r.loc, err = availability.Location(*tz); err != nil {
+			return DA.ErrorBadRequest().WithMessage("Unknown time zone " + *tz)
+		}
+	}
 	return nil
 }
 
 func (r *TimesGetHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	if r.Date.Before(time.Now()) {
-		return DA.ErrorBadRequest().WithMessage("Date must be after now")
-	}
 	if r.PlaceId == nil {
-		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Place is mandatory for creating match")
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Username of place or coach is mandatory")
 	}
-	if _, err := Repo.PlaceCrud.GetById(ctx, *r.PlaceId, nil); err != nil {
-		_, err := Repo.CoachCrud.GetById(ctx, *r.PlaceId, nil)
+	if place, err := Repo.PlaceCrud.GetById(ctx, *r.PlaceId, nil); err == nil {
+		r.schedule, r.timeZone = place.Schedule, place.TimeZone
+	} else {
+		coach, err := Repo.CoachCrud.GetById(ctx, *r.PlaceId, nil)
 		if err != nil {
 			return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("User with username " + *r.PlaceId + " doesn't exist")
 		}
+		r.schedule, r.timeZone = coach.Schedule, coach.TimeZone
+	}
+	// date is calendar date as client sent it, slots of that date are in owner's zone
+	owner := availability.OwnerLocation(r.timeZone)
+	y, m, d := r.Date.Date()
+	r.day = time.Date(y, m, d, 0, 0, 0, 0, owner)
+	if r.loc == nil {
+		r.loc = owner
+	}
+	if r.day.AddDate(0, 0, 1).Before(time.Now()) {
+		return DA.ErrorBadRequest().WithMessage("Date must not be in the past")
 	}
 	return nil
 }
 
 func (r *TimesGetHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	ret := []TimesGetResponse{}
-	var bookings *DR.Booking
-	place, err := Repo.PlaceCrud.GetById(ctx, *r.PlaceId, nil)
+	schedule := availability.Of(r.schedule)
+	// bookings of previous and next day can be closer than buffer to slots of requested day
+	from := r.day.AddDate(0, 0, -1)
+	to := r.day.AddDate(0, 0, 2)
+	confirmed := DR.BS_CONFIRMED
+	bookings, err := Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{OwnerId: r.PlaceId, Status: &confirmed, From: &from, To: &to}, nil)
 	if err != nil {
-		coach, _ := Repo.CoachCrud.GetById(ctx, *r.PlaceId, nil)
-		bookings = coach.Booking
-	} else {
-		bookings = place.Booking
+		return nil, DA.InternalServerError(err)
 	}
 
-	for i := 0; i < 24; i++ {
-		tempStart := r.Date.Add(time.Hour * time.Duration(i))
-		tempEnd := r.Date.Add(time.Hour * time.Duration(i+1))
-		toAppend := true
-		if bookings != nil {
-			for _, booking := range *bookings {
-				if booking.PracticeId != "" && booking.Accepted {
-					continue
-				}
-				if !(DA.AfterEqual(tempStart, booking.EndTime) || DA.BeforeEqual(tempEnd, booking.StartTime)) {
-					toAppend = false
-				}
-			}
-		}
-		if toAppend {
-			ret = append(ret, TimesGetResponse{Value: toTimeStr(i)})
+	ret := []TimesGetResponse{}
+	now := time.Now()
+	for _, slot := range availability.Free(schedule, r.day, bookings) {
+		if slot.Start.Before(now) {
+			continue
 		}
+		ret = append(ret, TimesGetResponse{
+			Value:         slot.Start.Format("15:04"),
+			Start:         slot.Start.In(r.loc),
+			End:           slot.End.In(r.loc),
+			TimeZone:      r.loc.String(),
+			OwnerTimeZone: r.day.Location().String(),
+		})
 	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
 }
 
-func toTimeStr(i int) string {
-	if i < 10 {
-		return "0" + fmt.Sprint(i) + ":00"
+// occupied returns true if owner (place or coach) has confirmed booking that overlaps [start, end)
+func occupied(ctx context.Context, Repo *crud.Repo, ownerId string, start, end time.Time) (bool, error) {
+	confirmed := DR.BS_CONFIRMED
+	count, err := Repo.BookingCrud.GetCount(ctx, DR.BookingSearchParams{OwnerId: &ownerId, Status: &confirmed, From: &start, To: &end}, nil)
+	return count != 0, err
+}
+
+// bookable returns slot of owner schedule that starts at start in time zone of owner, error is returned if owner
+// isn't open at that time or if confirmed booking is closer to slot than buffer of schedule
+func bookable(ctx context.Context, Repo *crud.Repo, ownerId string, schedule *DR.Schedule, timeZone string, start time.Time) (availability.Slot, DA.Error) {
+	sch := availability.Of(schedule)
+	// opening hours are wall clock of owner, not of client that sent start
+	slot, err := availability.SlotAt(sch, start.In(availability.OwnerLocation(timeZone)))
+	if err != nil {
+		return slot, DA.ErrorBadRequest().WithMessage("That appointment is outside of working hours")
+	}
+	from, to := availability.Padded(sch, slot)
+	if busy, err := occupied(ctx, Repo, ownerId, from, to); err != nil {
+		return slot, DA.InternalServerError(err)
+	} else if busy {
+		return slot, DA.ErrorBadRequest().WithMessage("That appointment is already occupied")
+	}
+	return slot, nil
+}
+
+// localTime returns t on wall clock of owner with time zone tz and name of that zone
+func localTime(t *time.Time, tz string) (*time.Time, *string) {
+	loc := availability.OwnerLocation(tz)
+	name := loc.String()
+	if t == nil {
+		return nil, &name
+	}
+	local := t.In(loc)
+	return &local, &name
+}
+
+// bookingError returns bad request if booking overlaps other booking, other errors are internal
+func bookingError(err error) DA.Error {
+	if errors.Is(err, crud.ErrBookingOverlap) {
+		return DA.ErrorBadRequest().WithMessage("That appointment is already occupied")
 	}
-	return fmt.Sprint(i) + ":00"
+	return DA.InternalServerError(err)
+}
+
+// cancelBookings deletes bookings made for match, practice or tournament with id, so their time is free again
+func cancelBookings(ctx context.Context, Repo *crud.Repo, kind DR.BookingKind, referenceId string, tx crud.QueryAble) error {
+	bookings, err := Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{Kind: &kind, ReferenceId: &referenceId}, tx)
+	if err != nil {
+		return err
+	}
+	for _, booking := range bookings {
+		if err := Repo.BookingCrud.Delete(ctx, booking.BookingId, tx, nil); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,187 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTimesFollowScheduleInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	loc, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	schedule := DR.Schedule{
+		Hours:         []DR.OpeningHours{{Weekday: time.Monday, Open: "08:00", Close: "11:00"}},
+		SlotMinutes:   60,
+		BufferMinutes: 30,
+	}
+	res := h.Player().Do(DR.SUB_CL, http.MethodPut, DA.HN_SCHEDULE, schedule)
+	if res.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for player, got %d: %s", res.Code, res.Body)
+	}
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPut, DA.HN_SCHEDULE, DR.Schedule{Hours: []DR.OpeningHours{{Open: "11:00", Close: "08:00"}}})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for hours that close before they open, got %d: %s", res.Code, res.Body)
+	}
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPut, DA.HN_SCHEDULE, schedule)
+	if res.Code != http.StatusOK {
+		t.Fatalf("put schedule: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	monday := time.Now().In(loc).AddDate(0, 0, 2)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	y, m, d := monday.Date()
+	at := func(hour, min int) time.Time {
+		return time.Date(y, m, d, hour, min, 0, 0, loc)
+	}
+	times := func() []string {
+		res := h.Player().Get(DR.SUB_CL, DA.HN_TIMES, url.Values{
+			"username": {apitest.FIXTURE_COACH},
+			"date":     {at(12, 0).UTC().Format(time.RFC3339)},
+			"tz":       {loc.String()},
+		})
+		if res.Code != http.StatusOK {
+			t.Fatalf("get times: expected 200, got %d: %s", res.Code, res.Body)
+		}
+		var slots []struct {
+			Start string `json:"start"`
+		}
+		res.Decode(t, &slots)
+		ret := []string{}
+		for _, slot := range slots {
+			ret = append(ret, slot.Start)
+		}
+		return ret
+	}
+
+	expected := []string{at(8, 0).Format(time.RFC3339), at(9, 30).Format(time.RFC3339)}
+	if got := times(); len(got) != 2 || got[0] != expected[0] || got[1] != expected[1] {
+		t.Errorf("expected slots %v, got %v", expected, got)
+	}
+
+	request := func(start time.Time) apitest.Response {
+		return h.Player().Do(DR.SUB_CL, http.MethodPost, DA.HN_PRACTICES, map[string]interface{}{
+			"startTime": start,
+			"coachId":   apitest.FIXTURE_COACH,
+			"sport":     "Table tennis",
+		})
+	}
+	res = request(at(10, 30))
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for practice after closing, got %d: %s", res.Code, res.Body)
+	}
+	var practice DR.Practice
+	res = request(at(8, 0))
+	if res.Code != http.StatusOK {
+		t.Fatalf("create practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &practice)
+	res = h.Coach().Do(DR.SUB_CL, http.MethodPatch, DA.HN_PRACTICES, map[string]interface{}{"id": practice.PracticeId, "status": DR.PS_ACCEPTED})
+	if res.Code != http.StatusOK {
+		t.Fatalf("accept practice: expected 200, got %d: %s", res.Code, res.Body)
+	}
+
+	if got := times(); len(got) != 1 || got[0] != expected[1] {
+		t.Errorf("expected only slot %s after practice is accepted, got %v", expected[1], got)
+	}
+	res = request(at(9, 0))
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for practice closer than buffer to accepted one, got %d: %s", res.Code, res.Body)
+	}
+}
+
+func TestTimesAreInZoneOfPlaceInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	zone := loc.String()
+	schedule := DR.Schedule{Hours: []DR.OpeningHours{{Weekday: time.Wednesday, Open: "20:00", Close: "22:00"}}}
+	if _, err := h.Repo.PlaceCrud.Update(ctx, DR.PlaceUpdateParams{Id: apitest.FIXTURE_PLACE, TimeZone: &zone, Schedule: &schedule}, nil, nil); err != nil {
+		t.Fatalf("update place: %v", err)
+	}
+
+	wednesday := time.Now().In(loc).AddDate(0, 0, 2)
+	for wednesday.Weekday() != time.Wednesday {
+		wednesday = wednesday.AddDate(0, 0, 1)
+	}
+	y, m, d := wednesday.Date()
+	at := func(hour int) time.Time {
+		return time.Date(y, m, d, hour, 0, 0, 0, loc)
+	}
+
+	// date is calendar date of place even though evening slots are on next day in UTC
+	res := h.Player().Get(DR.SUB_CL, DA.HN_TIMES, url.Values{
+		"username": {apitest.FIXTURE_PLACE},
+		"date":     {time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)},
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("get times: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	var slots []struct {
+		Value         string `json:"value"`
+		Start         string `json:"start"`
+		TimeZone      string `json:"timeZone"`
+		OwnerTimeZone string `json:"ownerTimeZone"`
+	}
+	res.Decode(t, &slots)
+	if len(slots) != 2 || slots[0].Value != "20:00" || slots[0].Start != at(20).Format(time.RFC3339) || slots[0].TimeZone != zone || slots[0].OwnerTimeZone != zone {
+		t.Errorf("expected slots from 20:00 in %s, got %+v", zone, slots)
+	}
+
+	// start is converted to zone of caller, value stays on wall clock of place
+	res = h.Player().Get(DR.SUB_CL, DA.HN_TIMES, url.Values{
+		"username": {apitest.FIXTURE_PLACE},
+		"date":     {time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)},
+		"tz":       {"Europe/Belgrade"},
+	})
+	slots = nil
+	res.Decode(t, &slots)
+	belgrade, _ := time.LoadLocation("Europe/Belgrade")
+	if res.Code != http.StatusOK || len(slots) != 2 || slots[0].Value != "20:00" || slots[0].Start != at(20).In(belgrade).Format(time.RFC3339) ||
+		slots[0].TimeZone != "Europe/Belgrade" || slots[0].OwnerTimeZone != zone {
+		t.Errorf("expected slots from 20:00 in %s with start in Europe/Belgrade, got %d: %+v", zone, res.Code, slots)
+	}
+
+	res = h.Place().Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENTS, map[string]interface{}{
+		"startTime": at(20).UTC(),
+		"name":      "evening cup",
+	})
+	if res.Code != http.StatusOK {
+		t.Fatalf("create tournament: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	owner := apitest.FIXTURE_PLACE
+	bookings, err := h.Repo.BookingCrud.Search(ctx, DR.BookingSearchParams{OwnerId: &owner}, nil)
+	if err != nil {
+		t.Fatalf("search bookings: %v", err)
+	}
+	if len(bookings) != 1 || !bookings[0].StartTime.Equal(at(0)) || !bookings[0].EndTime.Equal(at(0).AddDate(0, 0, 1)) {
+		t.Errorf("expected tournament to book day of place from %v, got %+v", at(0), bookings)
+	}
+}
+
+func TestTimesRequireDateAndOwnerInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+
+	res := h.Player().Get(DR.SUB_CL, DA.HN_TIMES, url.Values{"username": {apitest.FIXTURE_PLACE}})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("missing date: expected 400, got %d: %s", res.Code, res.Body)
+	}
+	res = h.Player().Get(DR.SUB_CL, DA.HN_TIMES, url.Values{"date": {time.Now().Format(time.RFC3339)}})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("missing username: expected 400, got %d: %s", res.Code, res.Body)
+	}
+}
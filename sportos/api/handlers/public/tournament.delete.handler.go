@@ -0,0 +1,79 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type TournamentDeleteHandler struct {
+	Id     *string `json:"id,omitempty"`
+	userId string
+	event  DR.Event
+}
+
+func (r TournamentDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r TournamentDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r TournamentDeleteHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLACE}
+}
+
+func (r *TournamentDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *TournamentDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	event, err := Repo.EventCrud.GetById(ctx, *r.Id, nil)
+	if err != nil || event.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Tournament doesn't exist")
+	}
+	if event.Status == DR.ES_ACTIVE {
+		return DA.ErrorBadRequest().WithMessage("Tournament that is in progress must be cancelled before it is deleted")
+	}
+	r.event = event
+	return nil
+}
+
+func (r *TournamentDeleteHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.event.Owner != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only owner can delete tournament")
+	}
+	return nil
+}
+
+func (r *TournamentDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	err = Repo.EventCrud.Delete(ctx, r.event.EventId, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	// free day that was booked for tournament at place
+	err = cancelBookings(ctx, Repo, DR.BK_TOURNAMENT, r.event.EventId, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
@@ -1,9 +1,13 @@
 package public
 
 import (
+	H "backend/internal/helpers"
 	DA "backend/sportos/api/dto"
+	"backend/sportos/events"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
+	"backend/sportos/tournament"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -12,6 +16,9 @@ import (
 
 type TournamentPatchHandler struct {
 	TournamentPatchRequest
+	userId      string
+	ownerId     string
+	teamPlayers DR.StrArr
 }
 
 type TournamentPatchRequest struct {
@@ -22,17 +29,22 @@ type TournamentPatchRequest struct {
 	Round  *DR.Round `json:"round"`
 }
 
-func (r TournamentPatchRequest) SupportedMethod() string {
+func (r TournamentPatchHandler) SupportedMethod() string {
 	return http.MethodPatch
 }
 
-func (r TournamentPatchRequest) SupportedSubservers() []DR.SubServer {
+func (r TournamentPatchHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TournamentPatchHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLAYER, DR.UT_PLACE}
+}
+
 func (r *TournamentPatchHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	err := decode.Decode(&r.TournamentPatchRequest)
 	if err == nil {
 		return nil
@@ -43,25 +55,57 @@ func (r *TournamentPatchHandler) Init(httpReq *http.Request) DA.Error {
 
 func (r *TournamentPatchHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
 	event, err := Repo.EventCrud.GetById(ctx, r.Id, nil)
-	if err != nil {
+	if err != nil || event.IsDeleted() {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_UNIQUE_CONSTRAINT).WithMessage("Tournament doesn't exist")
 	}
+	r.ownerId = event.Owner
+	if err := r.checkStatus(event); err != nil {
+		return err
+	}
 	if r.Team != nil {
+		if event.Tournament != nil && len(event.Tournament.Rounds) != 0 {
+			return DA.ErrorBadRequest().WithMessage("Teams can't apply after tournament has started")
+		}
 		for _, team := range event.Teams {
 			if team.TeamId == *r.Team {
 				return DA.ErrorBadRequest().WithMessage("Team is already applied for event")
 			}
 		}
-		_, err = Repo.TeamCrud.GetById(ctx, *r.Team, nil)
-		if err != nil {
+		team, err := Repo.TeamCrud.GetById(ctx, *r.Team, nil)
+		if err != nil || team.IsDeleted() {
 			return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_UNIQUE_CONSTRAINT).WithMessage("Team doesn't exist")
 		}
+		r.teamPlayers = team.Players
+	}
+	return nil
+}
+
+// checkStatus allows cancelling tournament that isn't finished or cancelled already and finishing only
+// tournament in progress, status changes aren't repeated so their events and notifications aren't sent twice
+func (r *TournamentPatchHandler) checkStatus(event DR.Event) DA.Error {
+	cancel := r.Cancel != nil && *r.Cancel
+	finish := r.Finish != nil && *r.Finish
+	if (cancel || finish) && (event.Status == DR.ES_FINISHED || event.Status == DR.ES_CANCELLED) {
+		return DA.ErrorBadRequest().WithMessage("Tournament is already " + strings.ToLower(string(event.Status)))
+	}
+	if finish && event.Status != DR.ES_ACTIVE {
+		return DA.ErrorBadRequest().WithMessage("Tournament that hasn't started can't be finished")
+	}
+	return nil
+}
+
+func (r *TournamentPatchHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if (r.Cancel != nil || r.Finish != nil || r.Round != nil) && r.ownerId != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only owner can cancel or finish tournament")
+	}
+	if r.Team != nil && !H.Contains(r.teamPlayers, r.userId) {
+		return DA.ErrorForbidden().WithMessage("Only team member can apply team for tournament")
 	}
 	return nil
 }
 
 func (r *TournamentPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	tx, err := Repo.DB.Begin()
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
@@ -70,6 +114,10 @@ func (r *TournamentPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	// status is checked again on locked event, concurrent request could have changed it after Validate
+	if err := r.checkStatus(event); err != nil {
+		return nil, err
+	}
 	up := DR.EventUpdateParams{
 		Id: event.EventId,
 	}
@@ -89,41 +137,46 @@ func (r *TournamentPatchHandler) Process(ctx context.Context, Repo *crud.Repo) (
 	if r.Finish != nil && *r.Finish {
 		finished := DR.ES_FINISHED
 		up.Status = &finished
-		if r.Round != nil {
-			updateTournament(ctx, Repo, &event, r.Round)
+		if r.Round != nil && event.Tournament != nil {
+			sport, _ := DR.GetSportByName(event.Sport)
+			if err := tournament.ValidateScores(*r.Round, sport.Scoring); err != nil {
+				return nil, DA.ErrorBadRequest().WithMessage(err.Error())
+			}
+			if err := tournament.Submit(event.Tournament, event.Teams.Names(), *r.Round); err != nil {
+				return nil, DA.ErrorBadRequest().WithMessage(err.Error())
+			}
 			up.Tournament = event.Tournament
 		}
-		for _, teamRef := range event.Teams {
-			team, _ := Repo.TeamCrud.GetById(ctx, teamRef.TeamId, tx)
-			for _, standing := range event.Tournament.Standings {
-				if standing.TeamName == teamRef.Name {
-					for _, playerId := range strings.Split(team.Players, ",") {
-						player, _ := Repo.PlayerCrud.GetById(ctx, playerId, tx)
-						if player.Statistics == nil {
-							player.Statistics = DR.StatMap{}
-						}
-						stat := player.Statistics[event.Sport]
-						stat.Tournaments = append(stat.Tournaments, DR.TournamentFinish{
-							MyTeam:     strings.Split(team.Players, ","),
-							Tournament: event.Name,
-							Ranking:    *standing.Ranking,
-						})
-						player.Statistics[event.Sport] = stat
-						up := DR.PlayerUpdateParams{
-							Id:         player.Username,
-							Statistics: &player.Statistics,
-						}
-						Repo.PlayerCrud.Update(ctx, up, tx, nil)
-					}
-				}
-			}
+		if event.Tournament == nil || !tournament.IsOver(*event.Tournament, event.Teams.Names()) {
+			return nil, DA.ErrorBadRequest().WithMessage("Tournament can't be finished before all rounds are played")
+		}
+		if err = events.Publish(ctx, Repo, tx, events.TournamentFinished{EventId: event.EventId}); err != nil {
+			return nil, DA.InternalServerError(err)
 		}
 	}
 	ret, err := Repo.EventCrud.Update(ctx, up, tx, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	tx.Commit()
+	var notes notify.Batch
+	if up.Status != nil {
+		changed := events.TournamentStatusChanged{EventId: ret.EventId, PlaceId: ret.Owner, Name: ret.Name, Status: ret.Status}
+		if err = events.Publish(ctx, Repo, tx, changed); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+		n := DR.Notification{Type: DR.NT_TOURNAMENT_FINISHED, ReferenceId: ret.EventId, Message: "Tournament " + ret.Name + " finished"}
+		if *up.Status == DR.ES_CANCELLED {
+			n.Type = DR.NT_TOURNAMENT_CANCELLED
+			n.Message = "Tournament " + ret.Name + " was cancelled"
+		}
+		if err = notes.Store(ctx, Repo, tx, n, ret, teamPlayers(ctx, Repo, ret.Teams, tx)...); err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	notes.Send(ctx)
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
@@ -0,0 +1,61 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTournamentStatusChangesOnceInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	event := createTournament(t, h, "A", "B")
+	place := h.Place()
+
+	patch := func(body map[string]interface{}) apitest.Response {
+		body["id"] = event.EventId
+		return place.Do(DR.SUB_CL, http.MethodPatch, DA.HN_TOURNAMENTS, body)
+	}
+	if res := patch(map[string]interface{}{"finish": true}); res.Code != http.StatusBadRequest {
+		t.Errorf("finish before start: expected 400, got %d: %s", res.Code, res.Body)
+	}
+
+	res := place.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId})
+	if res.Code != http.StatusOK {
+		t.Fatalf("start tournament: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &event)
+	if res = patch(map[string]interface{}{"finish": true}); res.Code != http.StatusBadRequest {
+		t.Errorf("finish before last round: expected 400, got %d: %s", res.Code, res.Body)
+	}
+
+	round := event.Tournament.Rounds[0]
+	round.Pairing[0].Score = "2:1"
+	if res = patch(map[string]interface{}{"finish": true, "round": round}); res.Code != http.StatusOK {
+		t.Fatalf("finish with last round: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &event)
+	if event.Status != DR.ES_FINISHED {
+		t.Errorf("expected tournament to be %s, got %s", DR.ES_FINISHED, event.Status)
+	}
+
+	ctx := context.Background()
+	pending, err := h.Repo.OutboxEventCrud.GetPending(ctx, 100, nil)
+	if err != nil || len(pending) == 0 {
+		t.Fatalf("expected events of finished tournament in outbox, got %d, %v", len(pending), err)
+	}
+	for _, body := range []map[string]interface{}{{"finish": true}, {"cancel": true}} {
+		if res = patch(body); res.Code != http.StatusBadRequest {
+			t.Errorf("%v after finish: expected 400, got %d: %s", body, res.Code, res.Body)
+		}
+	}
+	again, err := h.Repo.OutboxEventCrud.GetPending(ctx, 100, nil)
+	if err != nil {
+		t.Fatalf("outbox: %v", err)
+	}
+	if len(again) != len(pending) {
+		t.Errorf("expected no events for rejected status changes, got %d more", len(again)-len(pending))
+	}
+}
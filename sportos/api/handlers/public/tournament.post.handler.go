@@ -2,8 +2,11 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/availability"
+	"backend/sportos/events"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
+	"backend/sportos/tournament"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -18,7 +21,15 @@ type TournamentPostRequest struct {
 	StartTime *time.Time `json:"startTime,omitempty"`
 	placeId   string
 	sport     string
-	Name      string `json:"name,omitempty"`
+	// day is midnight that starts day of tournament in time zone of place
+	day  time.Time
+	Name string `json:"name,omitempty"`
+	// Format, points and tie breakers of tournament, round robin with 3/1/0 points is played if they are missing
+	Format      DR.TournamentFormat `json:"format,omitempty"`
+	Points      *DR.PointsRule      `json:"points,omitempty"`
+	TieBreakers []DR.TieBreaker     `json:"tieBreakers,omitempty"`
+	Groups      int                 `json:"groups,omitempty"`
+	Advancing   int                 `json:"advancing,omitempty"`
 }
 
 type TournamentPostResponse struct {
@@ -32,6 +43,10 @@ func (r TournamentPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TournamentPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLACE}
+}
+
 func (r *TournamentPostHandler) Init(httpReq *http.Request) DA.Error {
 	r.placeId = DA.GetUserIdFromContext(httpReq.Context())
 	decode := json.NewDecoder(httpReq.Body)
@@ -48,71 +63,87 @@ func (r *TournamentPostHandler) Validate(ctx context.Context, Repo *crud.Repo) D
 	if r.StartTime == nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Start time is mandatory")
 	}
+	if err := tournament.ValidateRules(r.rules()); err != nil {
+		return DA.ErrorBadRequest().WithMessage(err.Error())
+	}
 	place, err := Repo.PlaceCrud.GetById(ctx, r.placeId, nil)
 	if err != nil {
 		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_UNIQUE_CONSTRAINT).WithMessage("Place doesn't exist")
 	}
-	events, err := Repo.EventCrud.Search(ctx, DR.EventSearchParams{Name: &r.Name}, nil)
+	sameName, err := Repo.EventCrud.Search(ctx, DR.EventSearchParams{Name: &r.Name}, nil)
 	if err != nil {
 		return DA.InternalServerError(err)
 	}
-	if len(events) != 0 {
+	if len(sameName) != 0 {
 		return DA.ErrorBadRequest().WithMessage("Event with same name alredy exists")
 	}
-	year, month, day := r.StartTime.Date()
-	startTime := time.Date(year, month, day, 0, 0, 0, 0, r.StartTime.Location())
-	if startTime.Before(time.Now()) {
+	r.day = availability.Day(*r.StartTime, availability.OwnerLocation(place.TimeZone))
+	if r.day.Before(time.Now()) {
 		return DA.ErrorBadRequest().WithMessage("Event must be in the future")
 	}
-	endTime := time.Date(year, month, day, 0, 0, 0, 0, r.StartTime.Location()).AddDate(0, 0, 1)
-	if place.Booking != nil {
-		for _, booking := range *place.Booking {
-			if (DA.AfterEqual(startTime, booking.StartTime) && !DA.AfterEqual(startTime, booking.EndTime)) ||
-				(DA.BeforeEqual(startTime, booking.StartTime) && !DA.BeforeEqual(endTime, booking.EndTime)) {
-				return DA.ErrorBadRequest().WithMessage("That day is already occupied")
-			}
-		}
+	if len(availability.Slots(availability.Of(place.Schedule), r.day)) == 0 {
+		return DA.ErrorBadRequest().WithMessage("Place is closed that day")
+	}
+	if busy, err := occupied(ctx, Repo, r.placeId, r.day, r.day.AddDate(0, 0, 1)); err != nil {
+		return DA.InternalServerError(err)
+	} else if busy {
+		return DA.ErrorBadRequest().WithMessage("That day is already occupied")
 	}
 	r.sport = place.Sport
 	return nil
 }
 
 func (r *TournamentPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
-	tx, err := Repo.DB.BeginTx(ctx, nil)
+	tx, err := Repo.BeginTx(ctx)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
 	defer tx.Rollback()
-	tournament := DR.Event{
-		Owner:  r.placeId,
-		Time:   r.StartTime,
-		Status: DR.ES_CREATED,
-		Sport:  r.sport,
-		Name:   r.Name,
-	}
-	ret, err := Repo.EventCrud.Create(ctx, tournament, tx, nil)
+	rules := r.rules()
+	tournament.ApplyDefaults(&rules)
+	event := DR.Event{
+		Owner:      r.placeId,
+		Time:       r.StartTime,
+		Status:     DR.ES_CREATED,
+		Sport:      r.sport,
+		Name:       r.Name,
+		Tournament: &rules,
+	}
+	ret, err := Repo.EventCrud.Create(ctx, event, tx, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	place, _ := Repo.PlaceCrud.GetById(ctx, r.placeId, tx)
-	up := DR.PlaceUpdateParams{
-		Id: r.placeId,
+	// tournament occupies whole day at place
+	booking := DR.Booking{
+		OwnerId:     r.placeId,
+		Kind:        DR.BK_TOURNAMENT,
+		ReferenceId: ret.EventId,
+		StartTime:   r.day,
+		EndTime:     r.day.AddDate(0, 0, 1),
+		Status:      DR.BS_CONFIRMED,
 	}
-	booking := place.Booking
-	if booking == nil {
-		booking = &DR.Booking{}
+	if _, err := Repo.BookingCrud.Create(ctx, booking, tx, nil); err != nil {
+		return nil, bookingError(err)
 	}
-	year, month, day := r.StartTime.Date()
-	startTime := time.Date(year, month, day, 0, 0, 0, 0, r.StartTime.Location())
-	endTime := time.Date(year, month, day, 0, 0, 0, 0, r.StartTime.Location()).AddDate(0, 0, 1)
-	*booking = append(*booking, DR.Apointment{StartTime: startTime, EndTime: endTime})
-	up.Booking = booking
-	_, err = Repo.PlaceCrud.Update(ctx, up, tx, nil)
-	if err != nil {
+	created := events.TournamentStatusChanged{EventId: ret.EventId, PlaceId: ret.Owner, Name: ret.Name, Status: ret.Status}
+	if err = events.Publish(ctx, Repo, tx, created); err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	tx.Commit()
 	resMap := make(map[string]interface{})
 	resMap["body"] = ret
 	return resMap, nil
 }
+
+// rules returns tournament without rounds and standings with rules from request
+func (r *TournamentPostHandler) rules() DR.Tournament {
+	return DR.Tournament{
+		Format:      r.Format,
+		Points:      r.Points,
+		TieBreakers: r.TieBreakers,
+		Groups:      r.Groups,
+		Advancing:   r.Advancing,
+	}
+}
@@ -0,0 +1,44 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	"backend/sportos/api/handlers/public"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTournamentDoubleBookingInMemory(t *testing.T) {
+	h := apitest.NewMemory(t)
+	ctx := context.Background()
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	body := func(name string, start time.Time) map[string]interface{} {
+		return map[string]interface{}{"startTime": start, "name": name}
+	}
+	if res := h.Place().Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENTS, body("first cup", start)); res.Code != http.StatusOK {
+		t.Fatalf("create tournament: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	if res := h.Place().Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENTS, body("second cup", start)); res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for tournament on booked day, got %d: %s", res.Code, res.Body)
+	}
+
+	// other request books match on that day after validation
+	later := start.Add(48 * time.Hour)
+	booking := DR.Booking{OwnerId: apitest.FIXTURE_PLACE, Kind: DR.BK_MATCH, ReferenceId: "other", StartTime: later, EndTime: later.Add(time.Hour), Status: DR.BS_CONFIRMED}
+	apiErr := processWithRacingBooking(t, h, &public.TournamentPostHandler{}, apitest.FIXTURE_PLACE, body("third cup", later), booking)
+	if apiErr == nil || apiErr.GetHTTPCode() != http.StatusBadRequest || apiErr.GetMessage() != "That appointment is already occupied" {
+		t.Fatalf("expected 400 for booking that overlaps booking made after validation, got %v", apiErr)
+	}
+
+	events, err := h.Repo.EventCrud.Search(ctx, DR.EventSearchParams{}, nil)
+	if err != nil {
+		t.Fatalf("search events: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "first cup" {
+		t.Errorf("expected only first tournament to be created, got %+v", events)
+	}
+}
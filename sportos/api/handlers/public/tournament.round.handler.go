@@ -61,14 +61,15 @@ package public
 
 import (
 	DA "backend/sportos/api/dto"
+	"backend/sportos/events"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
+	"backend/sportos/tournament"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
-	"sort"
-	"strconv"
-	"strings"
 )
 
 type TournamentRoundPostRequest struct {
@@ -78,6 +79,8 @@ type TournamentRoundPostRequest struct {
 
 type TournamentRoundPostHandler struct {
 	TournamentRoundPostRequest
+	userId  string
+	ownerId string
 }
 
 type TournamentRoundPostResponse struct {
@@ -93,9 +96,14 @@ func (r TournamentRoundPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TournamentRoundPostHandler) RequiredRoles() []DR.UserType {
+	return []DR.UserType{DR.UT_PLACE}
+}
+
 func (r *TournamentRoundPostHandler) Init(httpReq *http.Request) DA.Error {
 	decode := json.NewDecoder(httpReq.Body)
 	decode.DisallowUnknownFields()
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
 	err := decode.Decode(&r.TournamentRoundPostRequest)
 	if err == nil {
 		return nil
@@ -105,166 +113,100 @@ func (r *TournamentRoundPostHandler) Init(httpReq *http.Request) DA.Error {
 }
 
 func (r *TournamentRoundPostHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
-	_, err := Repo.EventCrud.GetById(ctx, r.Id, nil)
-	if err != nil {
+	event, err := Repo.EventCrud.GetById(ctx, r.Id, nil)
+	if err != nil || event.IsDeleted() {
 		return DA.ErrorBadRequest().WithMessage("Event doesn't exist")
 	}
+	r.ownerId = event.Owner
 	if r.Round != nil {
-		for _, elem := range r.Round.Pairing {
-			if elem.Score == "" {
-				return DA.ErrorBadRequest().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Not all results are submitted")
-			}
-			if len(strings.Split(elem.Score, ":")) != 2 {
-				return DA.ErrorBadRequest().WithMessage("Result format isn't valid")
-			}
-			if _, err = strconv.Atoi(strings.Split(elem.Score, ":")[0]); err != nil {
-				return DA.ErrorBadRequest().WithMessage("Result format isn't valid")
-			}
-			if _, err = strconv.Atoi(strings.Split(elem.Score, ":")[1]); err != nil {
-				return DA.ErrorBadRequest().WithMessage("Result format isn't valid")
+		if event.Tournament == nil {
+			return DA.ErrorBadRequest().WithMessage(tournament.ErrNotStarted.Error())
+		}
+		if err := tournament.ValidateRound(*event.Tournament, *r.Round); err != nil {
+			if err == tournament.ErrResultMissing {
+				return DA.ErrorBadRequest().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage(err.Error())
 			}
+			return DA.ErrorBadRequest().WithMessage(err.Error())
+		}
+		sport, _ := DR.GetSportByName(event.Sport)
+		if err := tournament.ValidateScores(*r.Round, sport.Scoring); err != nil {
+			return DA.ErrorBadRequest().WithMessage(err.Error())
 		}
 	}
 	return nil
 }
 
+func (r *TournamentRoundPostHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.ownerId != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only owner can submit tournament rounds")
+	}
+	return nil
+}
+
 func (r *TournamentRoundPostHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
 	event, err := Repo.EventCrud.GetById(ctx, r.Id, nil)
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
-	err = updateTournament(ctx, Repo, &event, r.Round)
-	if err != nil {
-		return nil, DA.InternalServerError(err)
-	}
-	nextRound(ctx, Repo, &event)
 	up := DR.EventUpdateParams{
-		Id:         r.Id,
-		Tournament: event.Tournament,
+		Id: r.Id,
 	}
-	if r.Round == nil && len(event.Tournament.Rounds) == 1 {
+	if event.Tournament == nil {
+		event.Tournament = &DR.Tournament{}
+	}
+	teams := event.Teams.Names()
+	if len(event.Tournament.Rounds) == 0 {
+		err = tournament.Start(event.Tournament, teams)
 		active := DR.ES_ACTIVE
 		up.Status = &active
+	} else if r.Round != nil {
+		err = tournament.Submit(event.Tournament, teams, *r.Round)
+	} else {
+		err = tournament.ErrAlreadyStarted
 	}
-	event, err = Repo.EventCrud.Update(ctx, up, nil, nil)
 	if err != nil {
-		return nil, DA.InternalServerError(err)
-	}
-	resMap := make(map[string]interface{})
-	resMap["body"] = event
-	return resMap, nil
-}
-
-// TODO update stats && DB
-func updateTournament(ctx context.Context, Repo *crud.Repo, event *DR.Event, round *DR.Round) error {
-	if event.Tournament == nil {
-		event.Tournament = &DR.Tournament{}
+		return nil, DA.ErrorBadRequest().WithMessage(err.Error())
 	}
-	if event.Tournament.Standings == nil {
-		event.Tournament.Standings = make([]DR.Standing, 0)
-		for i, elem := range event.Teams {
-			standing := DR.Standing{
-				TeamName: elem.Name,
-				Points:   new(int),
-				Ranking:  new(int),
-			}
-			*standing.Points = 0
-			*standing.Ranking = i + 1
-			event.Tournament.Standings = append(event.Tournament.Standings, standing)
-		}
+	up.Tournament = event.Tournament
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	if len(event.Tournament.Rounds) > len(event.Teams)-1 {
-		return nil
+	defer tx.Rollback()
+	event, err = Repo.EventCrud.Update(ctx, up, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	if round == nil {
-		if event.Status == DR.ES_CREATED && len(event.Tournament.Rounds) == 0 {
-			event.Status = DR.ES_ACTIVE
+	if up.Status != nil {
+		started := events.TournamentStatusChanged{EventId: event.EventId, PlaceId: event.Owner, Name: event.Name, Status: event.Status}
+		if err = events.Publish(ctx, Repo, tx, started); err != nil {
+			return nil, DA.InternalServerError(err)
 		}
-		return nil
 	}
-	for _, elem := range round.Pairing {
-		score1, _ := strconv.Atoi(strings.Split(elem.Score, ":")[0])
-		score2, _ := strconv.Atoi(strings.Split(elem.Score, ":")[1])
-		if score1 > score2 {
-			for _, standing := range event.Tournament.Standings {
-				if standing.TeamName == elem.TeamOne {
-					*standing.Points = *standing.Points + 3
-				}
-			}
-		}
-		if score1 == score2 {
-			for _, standing := range event.Tournament.Standings {
-				if standing.TeamName == elem.TeamOne {
-					*standing.Points = *standing.Points + 1
-				}
-				if standing.TeamName == elem.TeamTwo {
-					*standing.Points = *standing.Points + 1
-				}
-			}
-		}
-		if score1 < score2 {
-			for _, standing := range event.Tournament.Standings {
-				if standing.TeamName == elem.TeamTwo {
-					*standing.Points = *standing.Points + 3
-				}
-			}
-		}
+	var notes notify.Batch
+	n := DR.Notification{Type: DR.NT_TOURNAMENT_ROUND, ReferenceId: event.EventId,
+		Message: fmt.Sprintf("Round %d of tournament %s is ready", len(event.Tournament.Rounds), event.Name)}
+	if err = notes.Store(ctx, Repo, tx, n, event, teamPlayers(ctx, Repo, event.Teams, tx)...); err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	event.Tournament.Rounds[len(event.Tournament.Rounds)-1] = *round
-	sort.Slice(event.Tournament.Standings, func(i, j int) bool {
-		return *event.Tournament.Standings[i].Points > *event.Tournament.Standings[j].Points
-	})
-	for i := range event.Tournament.Standings {
-		*event.Tournament.Standings[i].Ranking = i + 1
+	if err = tx.Commit(); err != nil {
+		return nil, DA.InternalServerError(err)
 	}
-	return nil
+	notes.Send(ctx)
+	resMap := make(map[string]interface{})
+	resMap["body"] = event
+	return resMap, nil
 }
 
-// TODO DB
-func nextRound(ctx context.Context, Repo *crud.Repo, event *DR.Event) {
-	if event.Tournament.Rounds == nil {
-		event.Tournament.Rounds = make([]DR.Round, 0)
-		event.Tournament.Rounds = append(event.Tournament.Rounds, DR.Round{})
-		event.Tournament.Rounds[0].Pairing = make([]DR.Pairing, 0)
-		if len(event.Teams)%2 == 0 {
-			for i := 0; i < len(event.Teams)/2; i++ {
-				event.Tournament.Rounds[0].Pairing = append(event.Tournament.Rounds[0].Pairing, DR.Pairing{
-					TeamOne: event.Teams[i].Name,
-					TeamTwo: event.Teams[len(event.Teams)-i-1].Name,
-				})
-			}
-		} else {
-			event.Tournament.Rounds[0].Pairing = append(event.Tournament.Rounds[0].Pairing, DR.Pairing{
-				TeamOne: event.Teams[0].Name,
-				TeamTwo: "Bye",
-				Score:   "3:0",
-			})
-			for i := 1; i < (len(event.Teams)+1)/2; i++ {
-				event.Tournament.Rounds[0].Pairing = append(event.Tournament.Rounds[0].Pairing, DR.Pairing{
-					TeamOne: event.Teams[i].Name,
-					TeamTwo: event.Teams[len(event.Teams)-i].Name,
-				})
-			}
-		}
-	} else {
-		if len(event.Tournament.Rounds) >= len(event.Teams)-1 {
-			return
-		}
-		nextRound := DR.Round{
-			Pairing: make([]DR.Pairing, len(event.Tournament.Rounds[len(event.Tournament.Rounds)-1].Pairing)),
-		}
-		copy(nextRound.Pairing, event.Tournament.Rounds[len(event.Tournament.Rounds)-1].Pairing)
-		save := nextRound.Pairing[0].TeamTwo
-		for i := 0; i < len(nextRound.Pairing)-1; i++ {
-			nextRound.Pairing[i].TeamTwo = nextRound.Pairing[i+1].TeamTwo
-			nextRound.Pairing[i].Score = ""
-		}
-		nextRound.Pairing[len(nextRound.Pairing)-1].TeamTwo = nextRound.Pairing[len(nextRound.Pairing)-1].TeamOne
-		for i := len(nextRound.Pairing) - 1; i > 1; i-- {
-			nextRound.Pairing[i].TeamOne = nextRound.Pairing[i-1].TeamOne
+// teamPlayers returns players of all teams, teams that can't be loaded are skipped
+func teamPlayers(ctx context.Context, Repo *crud.Repo, teams DR.Teams, qa crud.QueryAble) []string {
+	players := []string{}
+	for _, ref := range teams {
+		team, err := Repo.TeamCrud.GetById(ctx, ref.TeamId, qa)
+		if err != nil {
+			continue
 		}
-		nextRound.Pairing[len(nextRound.Pairing)-1].Score = ""
-		nextRound.Pairing[1].TeamOne = save
-		event.Tournament.Rounds = append(event.Tournament.Rounds, nextRound)
+		players = append(players, team.Players...)
 	}
+	return players
 }
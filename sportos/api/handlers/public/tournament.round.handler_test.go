@@ -0,0 +1,110 @@
+package public_test
+
+import (
+	"backend/sportos/api/apitest"
+	DA "backend/sportos/api/dto"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func createTournament(t *testing.T, h *apitest.Harness, teams ...string) DR.Event {
+	t.Helper()
+	ctx := context.Background()
+	start := time.Now().UTC().Add(24 * time.Hour)
+	event, err := h.Repo.EventCrud.Create(ctx, DR.Event{
+		Name:   "Test tournament",
+		Owner:  apitest.FIXTURE_PLACE,
+		Sport:  "Football",
+		Status: DR.ES_CREATED,
+		Time:   &start,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	refs := DR.Teams{}
+	for _, name := range teams {
+		refs = append(refs, DR.TeamRef{Name: name})
+	}
+	event, err = h.Repo.EventCrud.Update(ctx, DR.EventUpdateParams{Id: event.EventId, Teams: &refs}, nil, nil)
+	if err != nil {
+		t.Fatalf("update event teams: %v", err)
+	}
+	return event
+}
+
+func TestTournamentRoundUpdatesStandings(t *testing.T) {
+	h := apitest.New(t)
+	event := createTournament(t, h, "A", "B", "C", "D")
+	place := h.Place()
+
+	res := place.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId})
+	if res.Code != http.StatusOK {
+		t.Fatalf("start tournament: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &event)
+	if event.Status != DR.ES_ACTIVE {
+		t.Errorf("expected tournament to be %s, got %s", DR.ES_ACTIVE, event.Status)
+	}
+	if len(event.Tournament.Rounds) != 1 || len(event.Tournament.Rounds[0].Pairing) != 2 {
+		t.Fatalf("expected first round with 2 pairs, got %+v", event.Tournament.Rounds)
+	}
+
+	round := event.Tournament.Rounds[0]
+	round.Pairing[0].Score = "2:1"
+	round.Pairing[1].Score = "1:1"
+	res = place.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId, "round": round})
+	if res.Code != http.StatusOK {
+		t.Fatalf("submit round: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	res.Decode(t, &event)
+
+	expected := map[string]int{
+		round.Pairing[0].TeamOne: 3,
+		round.Pairing[0].TeamTwo: 0,
+		round.Pairing[1].TeamOne: 1,
+		round.Pairing[1].TeamTwo: 1,
+	}
+	for i, standing := range event.Tournament.Standings {
+		if *standing.Points != expected[standing.TeamName] {
+			t.Errorf("team %s: expected %d points, got %d", standing.TeamName, expected[standing.TeamName], *standing.Points)
+		}
+		if *standing.Ranking != i+1 {
+			t.Errorf("team %s: expected ranking %d, got %d", standing.TeamName, i+1, *standing.Ranking)
+		}
+	}
+	if event.Tournament.Standings[0].TeamName != round.Pairing[0].TeamOne {
+		t.Errorf("expected %s to lead, got %s", round.Pairing[0].TeamOne, event.Tournament.Standings[0].TeamName)
+	}
+	if len(event.Tournament.Rounds) != 2 {
+		t.Errorf("expected next round to be generated, got %d rounds", len(event.Tournament.Rounds))
+	}
+}
+
+func TestTournamentRoundRequiresAllResults(t *testing.T) {
+	h := apitest.New(t)
+	event := createTournament(t, h, "A", "B")
+	place := h.Place()
+
+	res := place.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId})
+	if res.Code != http.StatusOK {
+		t.Fatalf("start tournament: expected 200, got %d: %s", res.Code, res.Body)
+	}
+	round := DR.Round{Pairing: []DR.Pairing{{TeamOne: "A", TeamTwo: "B"}}}
+	res = place.Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId, "round": round})
+	if res.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for missing result, got %d: %s", res.Code, res.Body)
+	}
+}
+
+func TestTournamentRoundOnlyOwner(t *testing.T) {
+	h := apitest.New(t)
+	event := createTournament(t, h, "A", "B")
+
+	res := h.As("other_place", DR.UT_PLACE).Do(DR.SUB_CL, http.MethodPost, DA.HN_TOURNAMENT_ROUND, map[string]interface{}{"id": event.EventId})
+	if res.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for place that doesn't own tournament, got %d: %s", res.Code, res.Body)
+	}
+}
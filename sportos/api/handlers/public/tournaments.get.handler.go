@@ -23,6 +23,10 @@ func (r TournamentsGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r TournamentsGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *TournamentsGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.sport = DA.GetParameterFromURLQuery(httpReq, "sports")
 	r.place = DA.GetParameterFromURLQuery(httpReq, "place")
@@ -46,6 +50,17 @@ func (r *TournamentsGetHandler) Process(ctx context.Context, Repo *crud.Repo) (i
 	if err != nil {
 		return nil, DA.InternalServerError(err)
 	}
+	// time of tournament is returned on wall clock of place, places are loaded once
+	zones := map[string]string{}
+	for i := range events {
+		zone, ok := zones[events[i].Owner]
+		if !ok {
+			place, _ := Repo.PlaceCrud.GetById(ctx, events[i].Owner, nil)
+			zone = place.TimeZone
+			zones[events[i].Owner] = zone
+		}
+		events[i].Time, events[i].TimeZone = localTime(events[i].Time, zone)
+	}
 	resMap := make(map[string]interface{})
 	resMap["body"] = events
 	return resMap, nil
@@ -0,0 +1,85 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+// UserDeleteHandler deletes account of logged in user together with player, coach or place of account and user's posts
+type UserDeleteHandler struct {
+	userId string
+	user   DR.User
+}
+
+func (r UserDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r UserDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r UserDeleteHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *UserDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *UserDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	user, err := Repo.UserCrud.GetById(ctx, r.userId, nil)
+	if err != nil || user.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Username doesn't exist")
+	}
+	r.user = user
+	return nil
+}
+
+func (r *UserDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	tx, err := Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	defer tx.Rollback()
+	switch r.user.UserType {
+	case DR.UT_PLAYER:
+		err = Repo.PlayerCrud.Delete(ctx, r.userId, tx, nil)
+	case DR.UT_COACH:
+		err = Repo.CoachCrud.Delete(ctx, r.userId, tx, nil)
+	case DR.UT_PLACE:
+		err = Repo.PlaceCrud.Delete(ctx, r.userId, tx, nil)
+	}
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	posts, err := Repo.UserPostsCrud.Search(ctx, DR.UserPostSearchParams{UserId: &r.userId}, tx)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	for _, post := range posts {
+		err = Repo.UserPostsCrud.Delete(ctx, post.PostId, tx, nil)
+		if err != nil {
+			return nil, DA.InternalServerError(err)
+		}
+	}
+	_, err = Repo.UserCrud.Update(ctx, DR.UserUpdateParams{Id: r.userId, RevokeTokens: true}, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = Repo.UserCrud.Delete(ctx, r.userId, tx, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
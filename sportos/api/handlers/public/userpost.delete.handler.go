@@ -0,0 +1,62 @@
+package public
+
+import (
+	DA "backend/sportos/api/dto"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"net/http"
+)
+
+type UserpostDeleteHandler struct {
+	Id     *string `json:"id,omitempty"`
+	userId string
+	post   DR.UserPost
+}
+
+func (r UserpostDeleteHandler) SupportedMethod() string {
+	return http.MethodDelete
+}
+
+func (r UserpostDeleteHandler) SupportedSubservers() []DR.SubServer {
+	return []DR.SubServer{DR.SUB_CL}
+}
+
+func (r UserpostDeleteHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
+func (r *UserpostDeleteHandler) Init(httpReq *http.Request) DA.Error {
+	r.Id = DA.GetParameterFromURLQuery(httpReq, "id")
+	r.userId = DA.GetUserIdFromContext(httpReq.Context())
+	return nil
+}
+
+func (r *UserpostDeleteHandler) Validate(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.Id == nil {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MANDATORY_MISSING).WithMessage("Id is mandatory")
+	}
+	post, err := Repo.UserPostsCrud.GetById(ctx, *r.Id, nil)
+	if err != nil || post.IsDeleted() {
+		return DA.NewApiError().WithPredefinedError(DA.PRE_ERR_FORBIDDEN_ID).WithMessage("Post with id " + *r.Id + " doesn't exist")
+	}
+	r.post = post
+	return nil
+}
+
+func (r *UserpostDeleteHandler) CheckOwnership(ctx context.Context, Repo *crud.Repo) DA.Error {
+	if r.post.UserId != r.userId {
+		return DA.ErrorForbidden().WithMessage("Only author can delete post")
+	}
+	return nil
+}
+
+func (r *UserpostDeleteHandler) Process(ctx context.Context, Repo *crud.Repo) (interface{}, DA.Error) {
+	err := Repo.UserPostsCrud.Delete(ctx, r.post.PostId, nil, nil)
+	if err != nil {
+		return nil, DA.InternalServerError(err)
+	}
+	resMap := make(map[string]interface{})
+	resMap["body"] = struct{}{}
+	return resMap, nil
+}
@@ -21,6 +21,10 @@ func (r UserpostGetHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r UserpostGetHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *UserpostGetHandler) Init(httpReq *http.Request) DA.Error {
 	r.UserId = DA.GetParameterFromURLQuery(httpReq, "userId")
 	r.NotUserId = DA.GetParameterFromURLQuery(httpReq, "notUserId")
@@ -56,6 +60,7 @@ func (r *UserpostGetHandler) Process(ctx context.Context, Repo *crud.Repo) (inte
 			name = player.Name
 		}
 		result = append(result, DA.UserPost{
+			Id:         post.PostId,
 			Name:       name,
 			UserText:   post.UserText,
 			ImageNames: post.ImageNames,
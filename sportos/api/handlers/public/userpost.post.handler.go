@@ -28,6 +28,10 @@ func (r UserpostPostHandler) SupportedSubservers() []DR.SubServer {
 	return []DR.SubServer{DR.SUB_CL}
 }
 
+func (r UserpostPostHandler) RequiredRoles() []DR.UserType {
+	return nil
+}
+
 func (r *UserpostPostHandler) Init(httpReq *http.Request) DA.Error {
 	err := httpReq.ParseMultipartForm(32 << 20)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	L "backend/internal/logging"
 	"backend/sportos"
 	DA "backend/sportos/api/dto"
+	"backend/sportos/auth"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
 	"bytes"
@@ -128,16 +129,22 @@ func (m *Middleware) jwtVerify(next http.Handler) http.Handler {
 				aPIJSONErrorResponse(r.Context(), w, DA.ErrorBadRequest().WithMessage("token is missing"), m.s.Repo)
 				return
 			}
-			sp := DR.UserSearchParams{Token: &token}
-			user, err := m.s.Repo.UserCrud.Search(r.Context(), sp, nil)
-			if len(user) > 0 && user[0].EmailVerified < 0 {
-				aPIJSONErrorResponse(r.Context(), w, DA.NewApiError().WithPredefinedError(DA.PRE_ERR_MAIL_NOT_VERIFIED), m.s.Repo)
+			claims, err := auth.VerifyAccessToken(token)
+			if err != nil {
+				L.L.WithRequestID(r.Context()).Info("jwtVerify", L.Error(err))
+				aPIJSONErrorResponse(r.Context(), w, DA.ErrorUnauthorized(), m.s.Repo)
+				return
 			}
-			if err != nil || len(user) == 0 || (user[0].TokenValidUntil != nil && user[0].TokenValidUntil.Before(time.Now())) {
+			// tokens issued before logout, password reset or deletion of user are revoked
+			user, err := m.s.Repo.UserCrud.GetById(r.Context(), claims.Subject, nil)
+			if err != nil || user.IsDeleted() || user.TokenVersion != claims.Version {
+				L.L.WithRequestID(r.Context()).Info("jwtVerify token is revoked", L.String("username", claims.Subject), L.Error(err))
 				aPIJSONErrorResponse(r.Context(), w, DA.ErrorUnauthorized(), m.s.Repo)
 				return
 			}
-			r = r.WithContext(context.WithValue(r.Context(), sportos.CONTEXT_USER_ID_KEY, user[0].Username))
+			ctx := context.WithValue(r.Context(), sportos.CONTEXT_USER_ID_KEY, claims.Subject)
+			ctx = context.WithValue(ctx, sportos.CONTEXT_USER_TYPE_KEY, claims.UserType)
+			r = r.WithContext(ctx)
 		}
 		next.ServeHTTP(w, r)
 	})
@@ -0,0 +1,64 @@
+package api
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos"
+	DA "backend/sportos/api/dto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// heartbeat keeps notification stream open through proxies that close idle connections
+const heartbeat = 25 * time.Second
+
+// serveNotifications streams domain events of user from bearer token as server-sent events,
+// stream ends when client disconnects or server stops
+func serveNotifications(w http.ResponseWriter, r *http.Request, s *Server) {
+	ctx := r.Context()
+	userId := DA.GetUserIdFromContext(ctx)
+	if userId == "" {
+		aPIJSONErrorResponse(ctx, w, DA.ErrorUnauthorized(), s.Repo)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		aPIJSONErrorResponse(ctx, w, DA.InternalServerError(fmt.Errorf("streaming isn't supported")), s.Repo)
+		return
+	}
+
+	sub := s.Notifications.Subscribe(userId)
+	defer sub.Close()
+	L.L.WithRequestID(ctx).Info("Notification stream opened", L.String("userId", userId))
+
+	w.Header().Set(string(sportos.HEADER_CONTENT_TYPE), "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			L.L.WithRequestID(ctx).Info("Notification stream closed", L.String("userId", userId))
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				L.L.WithRequestID(ctx).Error("Notification not sent", L.Error(err))
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.EventId, ev.Type, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -2,8 +2,15 @@ package api
 
 import (
 	L "backend/internal/logging"
+	"backend/sportos/events"
+	"backend/sportos/mail"
+	"backend/sportos/notify"
 	"backend/sportos/repo/crud"
 	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"backend/sportos/results"
+	"backend/sportos/stats"
+	"backend/sportos/webhook"
 	"context"
 	"net/http"
 	"sync"
@@ -15,12 +22,27 @@ import (
 // Timeout in seconds
 const Timeout = 5
 
+// Database drivers, memory driver keeps all data in process and doesn't need postgres
+const (
+	DB_DRIVER_POSTGRES = "postgres"
+	DB_DRIVER_MEMORY   = "memory"
+)
+
 // Server struct contains server configuration
 type Server struct {
 	Repo *crud.Repo
 	// Cache      repo.Cache
 	SubServers map[DR.SubServer]*SubServer
 	CorsEnable bool
+	MailOutbox *mail.OutboxWorker
+	// Events delivers domain events from outbox to subscribers (statistics...)
+	Events *events.Dispatcher
+	// Webhooks sends domain events of places to their webhooks
+	Webhooks *webhook.Worker
+	// Notifications sends domain events to users connected to notification stream
+	Notifications *notify.Hub
+	// Results auto confirms match results that weren't answered until deadline
+	Results *results.Worker
 }
 
 type SubServer struct {
@@ -37,34 +59,66 @@ func newSubServer(port string) (ss *SubServer) {
 }
 
 // Init starts server
-func (s *Server) Init(CLPort, BOPort, LOPort string, corsEnable bool, dbName, dbHost, dbPort, dbUser, dbPass string, auditEnable bool) {
+func (s *Server) Init(CLPort, BOPort, LOPort string, corsEnable bool, dbDriver, dbName, dbHost, dbPort, dbUser, dbPass string, dbMigrate, auditEnable bool) {
+	var repo *crud.Repo
+	switch dbDriver {
+	case DB_DRIVER_MEMORY:
+		L.L.Warn("Using in memory database, data is lost when server stops")
+		repo = memory.InitRepo()
+	case DB_DRIVER_POSTGRES, "":
+		dbConnection := crud.DBConnection{
+			DBName:   dbName,
+			Host:     dbHost,
+			Port:     dbPort,
+			User:     dbUser,
+			Password: dbPass,
+			Migrate:  dbMigrate,
+		}
+		repo = dbConnection.InitRepo()
+	default:
+		L.L.Fatal("Unknown database driver", L.String("db.driver", dbDriver))
+	}
+	s.Setup(repo, CLPort, BOPort, LOPort, corsEnable)
+
+	if auditEnable {
+		s.Repo.AuditCrud.Start()
+	}
+}
+
+// Setup creates sub servers and registers handlers on already initialized repo
+func (s *Server) Setup(Repo *crud.Repo, CLPort, BOPort, LOPort string, corsEnable bool) {
 	s.SubServers = make(map[DR.SubServer]*SubServer)
 	s.SubServers[DR.SUB_LO] = newSubServer(LOPort)
 	s.SubServers[DR.SUB_CL] = newSubServer(CLPort)
 	s.SubServers[DR.SUB_BO] = newSubServer(BOPort)
 
-	dbConnection := crud.DBConnection{
-		DBName:   dbName,
-		Host:     dbHost,
-		Port:     dbPort,
-		User:     dbUser,
-		Password: dbPass,
-	}
-	s.Repo = dbConnection.InitRepo()
+	s.Repo = Repo
 
 	s.CorsEnable = corsEnable
 
+	s.MailOutbox = mail.NewOutboxWorker(s.Repo)
+
+	s.Events = events.NewDispatcher(s.Repo)
+	stats.Subscribe(s.Events)
+	webhook.Subscribe(s.Events)
+
+	s.Webhooks = webhook.NewWorker(s.Repo)
+
+	s.Notifications = notify.NewHub()
+
+	s.Results = results.NewWorker(s.Repo, s.Notifications)
+
 	registerHandlers(s)
 
 	L.L.Info("Server is set up...", L.Any("SubServers", s.SubServers))
-
-	if auditEnable {
-		s.Repo.AuditCrud.Start()
-	}
 }
 
 // Run starts server
 func (s *Server) Run() {
+	s.MailOutbox.Start()
+	s.Events.Start()
+	s.Webhooks.Start()
+	s.Results.Start()
 	wg := new(sync.WaitGroup)
 	wg.Add(len(s.SubServers))
 	for _, ser := range s.SubServers {
@@ -85,6 +139,22 @@ func (s *Server) Stop() {
 	ctx, cancel := context.WithTimeout(context.Background(), Timeout*time.Second)
 	defer cancel()
 
+	L.L.Info("Stopping event dispatcher...")
+	s.Events.Stop()
+
+	L.L.Info("Stopping webhook worker...")
+	s.Webhooks.Stop()
+
+	L.L.Info("Stopping results worker...")
+	s.Results.Stop()
+
+	L.L.Info("Stopping mail outbox...")
+	s.MailOutbox.Stop()
+
+	// notification streams don't end by themselves, shutdown would wait for them
+	L.L.Info("Closing notification streams...")
+	s.Notifications.Close()
+
 	for _, ser := range s.SubServers {
 		L.L.Info("Stopping SubServer...", L.String("Addr", ser.HttpServer.Addr))
 		if err := ser.HttpServer.Shutdown(ctx); err != nil {
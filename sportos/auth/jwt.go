@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Algorithm string
+
+const (
+	ALG_HS256 Algorithm = "HS256"
+	ALG_EDDSA Algorithm = "EdDSA"
+)
+
+// minimal length of HS256 secret in bytes
+const minHS256SecretLength = 32
+
+var (
+	ErrTokenMalformed  = errors.New("token is malformed")
+	ErrTokenSignature  = errors.New("token signature is invalid")
+	ErrTokenUnknownKey = errors.New("token is signed with unknown key")
+	ErrTokenExpired    = errors.New("token is expired")
+	ErrTokenType       = errors.New("token is of wrong type")
+)
+
+type jwtHeader struct {
+	Alg Algorithm `json:"alg"`
+	Typ string    `json:"typ"`
+	Kid string    `json:"kid"`
+}
+
+// Key is a single signing key identified by kid
+type Key struct {
+	Id         string
+	Alg        Algorithm
+	secret     []byte
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// NewHS256Key creates HMAC-SHA256 key from shared secret
+func NewHS256Key(kid string, secret []byte) (*Key, error) {
+	if kid == "" {
+		return nil, errors.New("kid is mandatory")
+	}
+	if len(secret) < minHS256SecretLength {
+		return nil, fmt.Errorf("HS256 secret for key %s must be at least %d bytes long", kid, minHS256SecretLength)
+	}
+	return &Key{Id: kid, Alg: ALG_HS256, secret: secret}, nil
+}
+
+// NewEdDSAKey creates Ed25519 key from 32 byte seed
+func NewEdDSAKey(kid string, seed []byte) (*Key, error) {
+	if kid == "" {
+		return nil, errors.New("kid is mandatory")
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("EdDSA seed for key %s must be %d bytes long", kid, ed25519.SeedSize)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	return &Key{Id: kid, Alg: ALG_EDDSA, privateKey: privateKey, publicKey: privateKey.Public().(ed25519.PublicKey)}, nil
+}
+
+func (k *Key) sign(data []byte) []byte {
+	switch k.Alg {
+	case ALG_HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return mac.Sum(nil)
+	case ALG_EDDSA:
+		return ed25519.Sign(k.privateKey, data)
+	}
+	return nil
+}
+
+func (k *Key) verify(data, signature []byte) bool {
+	switch k.Alg {
+	case ALG_HS256:
+		return hmac.Equal(k.sign(data), signature)
+	case ALG_EDDSA:
+		return ed25519.Verify(k.publicKey, data, signature)
+	}
+	return false
+}
+
+// Keyring holds all keys that are accepted for verification,
+// new tokens are always signed with the active key
+type Keyring struct {
+	keys   map[string]*Key
+	active string
+
+	mutex sync.RWMutex
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{
+		keys: make(map[string]*Key),
+	}
+}
+
+// Add adds key to keyring, first added key becomes active
+func (kr *Keyring) Add(k *Key) {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+
+	kr.keys[k.Id] = k
+	if kr.active == "" {
+		kr.active = k.Id
+	}
+}
+
+// SetActive changes key used for signing new tokens
+func (kr *Keyring) SetActive(kid string) error {
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+
+	if _, ok := kr.keys[kid]; !ok {
+		return fmt.Errorf("key %s is not in keyring", kid)
+	}
+	kr.active = kid
+	return nil
+}
+
+// Sign serializes claims and signs them with active key
+func (kr *Keyring) Sign(claims interface{}) (string, error) {
+	kr.mutex.RLock()
+	key, ok := kr.keys[kr.active]
+	kr.mutex.RUnlock()
+	if !ok {
+		return "", errors.New("keyring has no active key")
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: key.Alg, Typ: "JWT", Kid: key.Id})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := key.sign([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks token signature against key from kid header and decodes claims,
+// claims content (expiry etc.) is not checked here
+func (kr *Keyring) Verify(token string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrTokenMalformed
+	}
+	headerJson, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJson, &header); err != nil {
+		return ErrTokenMalformed
+	}
+
+	kr.mutex.RLock()
+	key, ok := kr.keys[header.Kid]
+	kr.mutex.RUnlock()
+	if !ok {
+		return ErrTokenUnknownKey
+	}
+	// algorithm is bound to the key, header can't downgrade it
+	if key.Alg != header.Alg {
+		return ErrTokenSignature
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return ErrTokenSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrTokenMalformed
+	}
+	return nil
+}
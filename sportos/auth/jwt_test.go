@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+type testClaims struct {
+	Subject string `json:"sub"`
+}
+
+func testKey(t *testing.T, kid string, b byte) *Key {
+	t.Helper()
+	key, err := NewHS256Key(kid, bytes.Repeat([]byte{b}, minHS256SecretLength))
+	if err != nil {
+		t.Fatalf("new key %s: %v", kid, err)
+	}
+	return key
+}
+
+func TestKeyringRotation(t *testing.T) {
+	kr := NewKeyring()
+	kr.Add(testKey(t, "old", 1))
+	oldToken, err := kr.Sign(testClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("sign with old key: %v", err)
+	}
+
+	seed := bytes.Repeat([]byte{2}, 32)
+	newKey, err := NewEdDSAKey("new", seed)
+	if err != nil {
+		t.Fatalf("new EdDSA key: %v", err)
+	}
+	kr.Add(newKey)
+	if err = kr.SetActive("new"); err != nil {
+		t.Fatalf("set active: %v", err)
+	}
+	newToken, err := kr.Sign(testClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("sign with new key: %v", err)
+	}
+
+	for name, token := range map[string]string{"old": oldToken, "new": newToken} {
+		var claims testClaims
+		if err := kr.Verify(token, &claims); err != nil {
+			t.Errorf("token signed with %s key: %v", name, err)
+		} else if claims.Subject != "test" {
+			t.Errorf("token signed with %s key has subject %q", name, claims.Subject)
+		}
+	}
+
+	// keyring without the old key no longer accepts its tokens
+	rotated := NewKeyring()
+	rotated.Add(newKey)
+	if err := rotated.Verify(oldToken, &testClaims{}); err != ErrTokenUnknownKey {
+		t.Errorf("expected ErrTokenUnknownKey for removed key, got %v", err)
+	}
+	if err := rotated.Verify(newToken, &testClaims{}); err != nil {
+		t.Errorf("token of active key after rotation: %v", err)
+	}
+
+	if err := kr.SetActive("missing"); err == nil {
+		t.Error("expected error when activating key that isn't in keyring")
+	}
+}
+
+func TestKeyringRejectsTamperedTokens(t *testing.T) {
+	kr := NewKeyring()
+	kr.Add(testKey(t, "k1", 1))
+	token, err := kr.Sign(testClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	parts := strings.Split(token, ".")
+
+	other := NewKeyring()
+	other.Add(testKey(t, "k1", 3))
+	if err := other.Verify(token, &testClaims{}); err != ErrTokenSignature {
+		t.Errorf("expected ErrTokenSignature for other secret with same kid, got %v", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`))
+	if err := kr.Verify(parts[0]+"."+payload+"."+parts[2], &testClaims{}); err != ErrTokenSignature {
+		t.Errorf("expected ErrTokenSignature for changed payload, got %v", err)
+	}
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","typ":"JWT","kid":"k1"}`))
+	if err := kr.Verify(header+"."+parts[1]+"."+parts[2], &testClaims{}); err != ErrTokenSignature {
+		t.Errorf("expected ErrTokenSignature for changed algorithm, got %v", err)
+	}
+
+	if err := kr.Verify("abc.def", &testClaims{}); err != ErrTokenMalformed {
+		t.Errorf("expected ErrTokenMalformed, got %v", err)
+	}
+}
+
+func TestNewKeyValidatesSecret(t *testing.T) {
+	if _, err := NewHS256Key("short", []byte("secret")); err == nil {
+		t.Error("expected error for short HS256 secret")
+	}
+	if _, err := NewEdDSAKey("short", []byte("seed")); err == nil {
+		t.Error("expected error for EdDSA seed of wrong length")
+	}
+	if _, err := NewHS256Key("", bytes.Repeat([]byte{1}, minHS256SecretLength)); err == nil {
+		t.Error("expected error for key without kid")
+	}
+}
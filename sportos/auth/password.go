@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PasswordHasher hashes passwords into self describing string
+// in format <id>$<params...>, id is used to pick hasher on verification
+type PasswordHasher interface {
+	// Id is prefix of encoded hash
+	Id() string
+	Hash(password string) (string, error)
+	// Verify compares password with encoded hash, needsRehash is true when hash
+	// was made with weaker parameters than hasher currently uses
+	Verify(password, encoded string) (ok bool, needsRehash bool, err error)
+}
+
+const (
+	PH_ARGON2ID      = "argon2id"
+	PH_LEGACY_SHA256 = "sha256"
+)
+
+// default argon2id parameters are OWASP minimum of 19 MiB memory, 2 passes and 1 thread
+const (
+	DEFAULT_ARGON2_TIME    = 2
+	DEFAULT_ARGON2_MEMORY  = 19 * 1024
+	DEFAULT_ARGON2_THREADS = 1
+	argon2SaltLength       = 16
+	argon2KeyLength        = 32
+)
+
+var ErrUnknownPasswordHasher = errors.New("password hash has unknown algorithm")
+
+var passwordHashers = map[string]PasswordHasher{}
+var defaultPasswordHasher PasswordHasher
+var passwordHashersMutex sync.RWMutex
+
+func init() {
+	RegisterPasswordHasher(NewArgon2idHasher(DEFAULT_ARGON2_TIME, DEFAULT_ARGON2_MEMORY, DEFAULT_ARGON2_THREADS), true)
+}
+
+// RegisterPasswordHasher adds hasher that is used for verification of hashes with its prefix,
+// if isDefault is true new hashes are made with it and hashes of other hashers are marked for rehash
+func RegisterPasswordHasher(h PasswordHasher, isDefault bool) {
+	passwordHashersMutex.Lock()
+	defer passwordHashersMutex.Unlock()
+
+	passwordHashers[h.Id()] = h
+	if isDefault {
+		defaultPasswordHasher = h
+	}
+}
+
+// HashPassword hashes password with default hasher
+func HashPassword(password string) (string, error) {
+	passwordHashersMutex.RLock()
+	h := defaultPasswordHasher
+	passwordHashersMutex.RUnlock()
+	return h.Hash(password)
+}
+
+// VerifyPassword checks password against encoded hash. Hashes without algorithm prefix
+// are legacy base64url(sha256(json(password))) hashes and are always marked for rehash.
+func VerifyPassword(password, encoded string) (ok bool, needsRehash bool, err error) {
+	id := PH_LEGACY_SHA256
+	if i := strings.Index(encoded, "$"); i > 0 {
+		id = encoded[:i]
+	}
+	if id == PH_LEGACY_SHA256 {
+		return verifyLegacySha256(password, encoded), true, nil
+	}
+
+	passwordHashersMutex.RLock()
+	h, found := passwordHashers[id]
+	isDefault := h == defaultPasswordHasher
+	passwordHashersMutex.RUnlock()
+	if !found {
+		return false, false, ErrUnknownPasswordHasher
+	}
+	ok, needsRehash, err = h.Verify(password, encoded)
+	return ok, needsRehash || !isDefault, err
+}
+
+func verifyLegacySha256(password, encoded string) bool {
+	sha256Hasher := sha256.New()
+	data, _ := json.Marshal(password)
+	sha256Hasher.Write(data)
+	dataHash := base64.URLEncoding.EncodeToString(sha256Hasher.Sum(nil))
+	return subtle.ConstantTimeCompare([]byte(dataHash), []byte(encoded)) == 1
+}
+
+// Argon2idHasher is argon2id hasher (RFC 9106) with random salt,
+// encoded as argon2id$v=19$m=<memory KiB>,t=<time>,p=<threads>$<base64 salt>$<base64 key>
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+func NewArgon2idHasher(time, memory uint32, threads uint8) *Argon2idHasher {
+	return &Argon2idHasher{Time: time, Memory: memory, Threads: threads}
+}
+
+func (h *Argon2idHasher) Id() string {
+	return PH_ARGON2ID
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, argon2KeyLength)
+	return fmt.Sprintf("%s$v=%d$m=%d,t=%d,p=%d$%s$%s", h.Id(), argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != h.Id() {
+		return false, false, fmt.Errorf("%s hash is malformed", h.Id())
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[1], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, fmt.Errorf("%s hash has unsupported version", h.Id())
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[2], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil || memory == 0 || time == 0 || threads == 0 {
+		return false, false, fmt.Errorf("%s hash has invalid parameters", h.Id())
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, false, fmt.Errorf("%s hash has invalid salt", h.Id())
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(expected) == 0 {
+		return false, false, fmt.Errorf("%s hash has invalid key", h.Id())
+	}
+	key := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(expected)))
+	weaker := memory < h.Memory || time < h.Time || threads < h.Threads
+	return subtle.ConstantTimeCompare(key, expected) == 1, weaker, nil
+}
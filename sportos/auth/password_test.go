@@ -0,0 +1,76 @@
+package auth
+
+import (
+		"strings"
+	"testing"
+)
+
+// argon2id hash of "password" with salt "somesalt", 2 passes, 64 MiB and 1 thread,
+// test vector of reference implementation (github.com/P-H-C/phc-winner-argon2)
+const knownArgon2idHash = "argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+// legacy hash of "S3cret-password", base64url(sha256(json(password)))
+const knownLegacyHash = "e6D93VbPTXm4zIbOJ_ZPyawiVvU6mpvpYGxTPxMsjuo="
+
+func TestArgon2idHasherEncodedFormat(t *testing.T) {
+	h := NewArgon2idHasher(2, 64*1024, 1)
+	ok, needsRehash, err := h.Verify("password", knownArgon2idHash)
+	if err != nil || !ok || needsRehash {
+		t.Errorf("expected known hash to verify without rehash, got %v %v %v", ok, needsRehash, err)
+	}
+	if ok, _, _ = h.Verify("Password", knownArgon2idHash); ok {
+		t.Error("expected wrong password not to verify")
+	}
+	if _, needsRehash, _ = NewArgon2idHasher(3, 64*1024, 1).Verify("password", knownArgon2idHash); !needsRehash {
+		t.Error("expected hash with fewer passes than hasher uses to need rehash")
+	}
+
+	h = NewArgon2idHasher(1, 1024, 1)
+	encoded, err := h.Hash("S3cret-password")
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[0] != PH_ARGON2ID || parts[1] != "v=19" || parts[2] != "m=1024,t=1,p=1" {
+		t.Errorf("unexpected encoded hash %s", encoded)
+	}
+	if ok, _, err = h.Verify("S3cret-password", encoded); err != nil || !ok {
+		t.Errorf("expected hash to verify, got %v %v", ok, err)
+	}
+	if again, _ := h.Hash("S3cret-password"); again == encoded {
+		t.Error("expected random salt to give different hashes of same password")
+	}
+
+	for _, malformed := range []string{
+		"argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHQ",
+		"argon2id$v=16$m=1024,t=1,p=1$c29tZXNhbHQ$CTFh",
+		"argon2id$v=19$m=1024,t=0,p=1$c29tZXNhbHQ$CTFh",
+		"argon2id$v=19$m=1024,t=1,p=1$c29t*$CTFh",
+		"argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHQ$",
+	} {
+		if _, _, err := h.Verify("S3cret-password", malformed); err == nil {
+			t.Errorf("expected error for malformed hash %s", malformed)
+		}
+	}
+}
+
+func TestVerifyPasswordMarksLegacyHashForRehash(t *testing.T) {
+	ok, needsRehash, err := VerifyPassword("S3cret-password", knownLegacyHash)
+	if err != nil || !ok || !needsRehash {
+		t.Errorf("expected legacy hash to verify and need rehash, got %v %v %v", ok, needsRehash, err)
+	}
+	if ok, _, _ = VerifyPassword("wrong", knownLegacyHash); ok {
+		t.Error("expected wrong password not to verify against legacy hash")
+	}
+	if ok, needsRehash, err = VerifyPassword("password", knownArgon2idHash); err != nil || !ok || needsRehash {
+		t.Errorf("expected argon2id hash to verify without rehash, got %v %v %v", ok, needsRehash, err)
+	}
+	// hash with less memory than default hasher uses
+	weak, _ := NewArgon2idHasher(DEFAULT_ARGON2_TIME, 1024, DEFAULT_ARGON2_THREADS).Hash("password")
+	if ok, needsRehash, err = VerifyPassword("password", weak); err != nil || !ok || !needsRehash {
+		t.Errorf("expected weaker argon2id hash to verify and need rehash, got %v %v %v", ok, needsRehash, err)
+	}
+	if _, _, err = VerifyPassword("S3cret-password", "bcrypt$abc"); err != ErrUnknownPasswordHasher {
+		t.Errorf("expected %v, got %v", ErrUnknownPasswordHasher, err)
+	}
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+type TokenType string
+
+const (
+	TT_ACCESS  TokenType = "access"
+	TT_REFRESH TokenType = "refresh"
+)
+
+const (
+	DEFAULT_ACCESS_TTL  = 20 * time.Minute
+	DEFAULT_REFRESH_TTL = 240 * time.Minute
+)
+
+// Claims that are carried in sportos tokens
+type Claims struct {
+	Subject   string      `json:"sub"`
+	UserType  DR.UserType `json:"utp"`
+	TokenType TokenType   `json:"typ"`
+	Id        string      `json:"jti"`
+	// Version is token version of user at login, tokens with older version are revoked
+	Version   int   `json:"ver"`
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// TokenPair is issued on login and on refresh
+type TokenPair struct {
+	AccessToken       string
+	AccessValidUntil  time.Time
+	RefreshToken      string
+	RefreshValidUntil time.Time
+}
+
+var keyring = NewKeyring()
+var accessTTL = DEFAULT_ACCESS_TTL
+var refreshTTL = DEFAULT_REFRESH_TTL
+
+// Init sets up keyring used for signing and verifying tokens.
+// keys is comma separated list of kid:alg:base64(secret), alg is HS256 or EdDSA (secret is then ed25519 seed).
+// If keys is empty random HS256 key is generated, tokens then don't survive restart.
+func Init(keys string, activeKid string, accessValidity, refreshValidity time.Duration) error {
+	kr := NewKeyring()
+	for _, spec := range strings.Split(keys, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		key, err := parseKey(spec)
+		if err != nil {
+			return err
+		}
+		kr.Add(key)
+	}
+	if len(kr.keys) == 0 {
+		L.L.Warn("auth.Init no jwt keys configured, generating random key")
+		secret := make([]byte, minHS256SecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		key, _ := NewHS256Key("generated", secret)
+		kr.Add(key)
+	}
+	if activeKid != "" {
+		if err := kr.SetActive(activeKid); err != nil {
+			return err
+		}
+	}
+	keyring = kr
+	if accessValidity > 0 {
+		accessTTL = accessValidity
+	}
+	if refreshValidity > 0 {
+		refreshTTL = refreshValidity
+	}
+	L.L.Info("auth params", L.String("jwt.kid", kr.active), L.Int("jwt.keys", len(kr.keys)), L.Any("access.ttl", accessTTL), L.Any("refresh.ttl", refreshTTL))
+	return nil
+}
+
+func parseKey(spec string) (*Key, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("jwt key must be in format kid:alg:secret")
+	}
+	secret, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s secret is not base64: %v", parts[0], err)
+	}
+	switch Algorithm(parts[1]) {
+	case ALG_HS256:
+		return NewHS256Key(parts[0], secret)
+	case ALG_EDDSA:
+		return NewEdDSAKey(parts[0], secret)
+	default:
+		return nil, fmt.Errorf("jwt key %s has unsupported algorithm %s", parts[0], parts[1])
+	}
+}
+
+// IssueTokens creates new access and refresh token for user on login, version is current token version of user
+func IssueTokens(userId string, userType DR.UserType, version int) (TokenPair, error) {
+	now := time.Now()
+	return issueTokens(userId, userType, version, now, now.Add(refreshTTL))
+}
+
+// RefreshTokens creates new token pair for verified refresh token, new refresh token keeps expiry of login
+// so refreshing can't extend session
+func RefreshTokens(refresh Claims) (TokenPair, error) {
+	return issueTokens(refresh.Subject, refresh.UserType, refresh.Version, time.Now(), time.Unix(refresh.ExpiresAt, 0))
+}
+
+func issueTokens(userId string, userType DR.UserType, version int, now, refreshValidUntil time.Time) (TokenPair, error) {
+	tp := TokenPair{
+		AccessValidUntil:  now.Add(accessTTL),
+		RefreshValidUntil: refreshValidUntil,
+	}
+	if tp.AccessValidUntil.After(refreshValidUntil) {
+		tp.AccessValidUntil = refreshValidUntil
+	}
+	var err error
+	tp.AccessToken, err = keyring.Sign(Claims{
+		Subject:   userId,
+		UserType:  userType,
+		TokenType: TT_ACCESS,
+		Id:        xid.New().String(),
+		Version:   version,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: tp.AccessValidUntil.Unix(),
+	})
+	if err != nil {
+		return tp, err
+	}
+	tp.RefreshToken, err = keyring.Sign(Claims{
+		Subject:   userId,
+		UserType:  userType,
+		TokenType: TT_REFRESH,
+		Id:        xid.New().String(),
+		Version:   version,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: tp.RefreshValidUntil.Unix(),
+	})
+	return tp, err
+}
+
+// VerifyAccessToken checks signature, type and expiry of access token, version is checked against user by caller
+func VerifyAccessToken(token string) (Claims, error) {
+	return verify(token, TT_ACCESS)
+}
+
+// VerifyRefreshToken checks signature, type and expiry of refresh token
+func VerifyRefreshToken(token string) (Claims, error) {
+	return verify(token, TT_REFRESH)
+}
+
+func verify(token string, tt TokenType) (Claims, error) {
+	var claims Claims
+	if err := keyring.Verify(token, &claims); err != nil {
+		return claims, err
+	}
+	if claims.TokenType != tt {
+		return claims, ErrTokenType
+	}
+	if claims.Subject == "" {
+		return claims, ErrTokenMalformed
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return claims, ErrTokenExpired
+	}
+	return claims, nil
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	DR "backend/sportos/repo/dto"
+	"testing"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// useKeyring replaces keyring used by tokens for duration of test
+func useKeyring(t *testing.T) *Keyring {
+	t.Helper()
+	kr := NewKeyring()
+	kr.Add(testKey(t, "test", 1))
+	old := keyring
+	keyring = kr
+	t.Cleanup(func() { keyring = old })
+	return kr
+}
+
+func TestTokenTypes(t *testing.T) {
+	useKeyring(t)
+	tp, err := IssueTokens("test", DR.UT_PLAYER, 2)
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+
+	claims, err := VerifyAccessToken(tp.AccessToken)
+	if err != nil {
+		t.Fatalf("verify access token: %v", err)
+	}
+	if claims.Subject != "test" || claims.UserType != DR.UT_PLAYER || claims.Version != 2 {
+		t.Errorf("unexpected access claims %+v", claims)
+	}
+	if _, err = VerifyRefreshToken(tp.RefreshToken); err != nil {
+		t.Errorf("verify refresh token: %v", err)
+	}
+
+	if _, err = VerifyRefreshToken(tp.AccessToken); err != ErrTokenType {
+		t.Errorf("expected ErrTokenType for access token used as refresh token, got %v", err)
+	}
+	if _, err = VerifyAccessToken(tp.RefreshToken); err != ErrTokenType {
+		t.Errorf("expected ErrTokenType for refresh token used as access token, got %v", err)
+	}
+}
+
+func TestExpiredToken(t *testing.T) {
+	kr := useKeyring(t)
+	past := time.Now().Add(-time.Minute)
+	for _, tt := range []TokenType{TT_ACCESS, TT_REFRESH} {
+		token, err := kr.Sign(Claims{
+			Subject:   "test",
+			UserType:  DR.UT_PLAYER,
+			TokenType: tt,
+			Id:        xid.New().String(),
+			IssuedAt:  past.Add(-time.Hour).Unix(),
+			ExpiresAt: past.Unix(),
+		})
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if _, err = verify(token, tt); err != ErrTokenExpired {
+			t.Errorf("expected ErrTokenExpired for %s token, got %v", tt, err)
+		}
+	}
+}
+
+func TestRefreshKeepsLoginExpiry(t *testing.T) {
+	useKeyring(t)
+	login, err := IssueTokens("test", DR.UT_PLAYER, 1)
+	if err != nil {
+		t.Fatalf("issue tokens: %v", err)
+	}
+	claims, err := VerifyRefreshToken(login.RefreshToken)
+	if err != nil {
+		t.Fatalf("verify refresh token: %v", err)
+	}
+
+	refreshed, err := RefreshTokens(claims)
+	if err != nil {
+		t.Fatalf("refresh tokens: %v", err)
+	}
+	again, err := VerifyRefreshToken(refreshed.RefreshToken)
+	if err != nil {
+		t.Fatalf("verify refreshed token: %v", err)
+	}
+	if again.ExpiresAt != claims.ExpiresAt {
+		t.Errorf("refreshed token expires at %d, expected expiry of login %d", again.ExpiresAt, claims.ExpiresAt)
+	}
+	if again.Version != 1 || again.Id == claims.Id {
+		t.Errorf("unexpected refreshed claims %+v", again)
+	}
+
+	// access token never outlives session
+	claims.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	refreshed, err = RefreshTokens(claims)
+	if err != nil {
+		t.Fatalf("refresh tokens: %v", err)
+	}
+	if refreshed.AccessValidUntil.Unix() != claims.ExpiresAt {
+		t.Errorf("access token valid until %v, expected end of session %v", refreshed.AccessValidUntil, time.Unix(claims.ExpiresAt, 0))
+	}
+}
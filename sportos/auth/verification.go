@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	VERIFY_EMAIL_TOKEN_TTL   = 24 * time.Hour
+	RESET_PASSWORD_TOKEN_TTL = time.Hour
+	// how many tokens of same purpose user can request in VERIFICATION_TOKEN_WINDOW
+	VERIFICATION_TOKEN_LIMIT  = 3
+	VERIFICATION_TOKEN_WINDOW = time.Hour
+)
+
+const verificationTokenLength = 32
+
+// NewVerificationToken returns random url safe token that is sent to user
+// and its hash that is stored in database
+func NewVerificationToken() (token string, tokenHash string, err error) {
+	b := make([]byte, verificationTokenLength)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashVerificationToken(token), nil
+}
+
+// HashVerificationToken returns hash under which token is stored
+func HashVerificationToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestNewVerificationToken(t *testing.T) {
+	token, tokenHash, err := NewVerificationToken()
+	if err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	if b, err := base64.RawURLEncoding.DecodeString(token); err != nil || len(b) != verificationTokenLength {
+		t.Errorf("expected url safe token of %d bytes, got %q, %v", verificationTokenLength, token, err)
+	}
+	if tokenHash != HashVerificationToken(token) || tokenHash == token {
+		t.Errorf("expected hash of token to be stored, got %q", tokenHash)
+	}
+	other, otherHash, _ := NewVerificationToken()
+	if other == token || otherHash == tokenHash {
+		t.Errorf("expected random tokens, got %q twice", token)
+	}
+}
@@ -0,0 +1,287 @@
+// Package availability computes time slots in which places and coaches can be booked.
+//
+// Schedule of owner (DR.Schedule) has weekly opening hours and exceptions in wall clock time of owner's
+// time zone. They are turned into instants in location of requested day, so slots follow daylight saving
+// changes; callers pass days in location of owner (see Location) so schedule doesn't depend on time zone
+// of server or of user that asks. Opening hours are split into slots of schedule slot length separated
+// by buffer, free slots are slots that don't come closer than buffer to confirmed bookings.
+package availability
+
+import (
+	DR "backend/sportos/repo/dto"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	// zone database is embedded so owner zones load on servers without tzdata
+	_ "time/tzdata"
+)
+
+var (
+	ErrHoursFormat  = errors.New("opening hours must be in format 15:04")
+	ErrHoursRange   = errors.New("opening hours must close after they open")
+	ErrHoursOverlap = errors.New("opening hours of same day overlap")
+	ErrWeekday      = errors.New("weekday must be between 0 (sunday) and 6 (saturday)")
+	ErrDateFormat   = errors.New("exception date must be in format 2006-01-02")
+	ErrSlotLength   = errors.New("slot length must be between 5 minutes and 24 hours")
+	ErrBuffer       = errors.New("buffer must be between 0 and 24 hours")
+	ErrClosed       = errors.New("owner isn't open at that time")
+	ErrTimeZone     = errors.New("time zone must be IANA time zone like Europe/Belgrade")
+)
+
+// locations caches loaded time zones by name
+var locations sync.Map
+
+const (
+	DefaultSlotMinutes = 60
+	minSlotMinutes     = 5
+	dayMinutes         = 24 * 60
+	dateLayout         = "2006-01-02"
+)
+
+// Slot is interval [Start, End) in which owner can be booked
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Of returns schedule with defaults, owner without schedule is open whole day in slots of one hour
+func Of(s *DR.Schedule) DR.Schedule {
+	if s == nil {
+		hours := make([]DR.OpeningHours, 7)
+		for d := range hours {
+			hours[d] = DR.OpeningHours{Weekday: time.Weekday(d), Open: "00:00", Close: "24:00"}
+		}
+		return DR.Schedule{Hours: hours, SlotMinutes: DefaultSlotMinutes}
+	}
+	sch := *s
+	if sch.SlotMinutes == 0 {
+		sch.SlotMinutes = DefaultSlotMinutes
+	}
+	return sch
+}
+
+// Validate checks opening hours, exceptions, slot length and buffer of schedule
+func Validate(s DR.Schedule) error {
+	if s.SlotMinutes != 0 && (s.SlotMinutes < minSlotMinutes || s.SlotMinutes > dayMinutes) {
+		return ErrSlotLength
+	}
+	if s.BufferMinutes < 0 || s.BufferMinutes > dayMinutes {
+		return ErrBuffer
+	}
+	days := map[time.Weekday][]DR.OpeningHours{}
+	for _, h := range s.Hours {
+		if h.Weekday < time.Sunday || h.Weekday > time.Saturday {
+			return ErrWeekday
+		}
+		days[h.Weekday] = append(days[h.Weekday], h)
+	}
+	for _, hours := range days {
+		if err := validateDay(hours); err != nil {
+			return err
+		}
+	}
+	dates := map[string][]DR.OpeningHours{}
+	for _, e := range s.Exceptions {
+		if _, err := time.Parse(dateLayout, e.Date); err != nil {
+			return ErrDateFormat
+		}
+		if e.Closed() {
+			continue
+		}
+		dates[e.Date] = append(dates[e.Date], DR.OpeningHours{Open: e.Open, Close: e.Close})
+	}
+	for date, hours := range dates {
+		if err := validateDay(hours); err != nil {
+			return fmt.Errorf("exception %s: %w", date, err)
+		}
+	}
+	return nil
+}
+
+func validateDay(hours []DR.OpeningHours) error {
+	intervals := make([]interval, 0, len(hours))
+	for _, h := range hours {
+		iv, err := parseHours(h.Open, h.Close)
+		if err != nil {
+			return err
+		}
+		intervals = append(intervals, iv)
+	}
+	sortIntervals(intervals)
+	for i := 1; i < len(intervals); i++ {
+		if intervals[i].open < intervals[i-1].close {
+			return ErrHoursOverlap
+		}
+	}
+	return nil
+}
+
+// interval is opening time of day in minutes since midnight
+type interval struct {
+	open, close int
+}
+
+func parseHours(open, close string) (interval, error) {
+	o, err := parseClock(open)
+	if err != nil {
+		return interval{}, err
+	}
+	c, err := parseClock(close)
+	if err != nil {
+		return interval{}, err
+	}
+	if c <= o {
+		return interval{}, ErrHoursRange
+	}
+	return interval{o, c}, nil
+}
+
+// parseClock returns minutes since midnight of wall clock time, 24:00 is end of day
+func parseClock(s string) (int, error) {
+	if s == "24:00" {
+		return dayMinutes, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrHoursFormat
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func sortIntervals(intervals []interval) {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].open < intervals[j].open
+	})
+}
+
+// opening returns opening intervals on date of day, exceptions for date replace weekly opening hours
+func opening(s DR.Schedule, day time.Time) []interval {
+	date := day.Format(dateLayout)
+	intervals := []interval{}
+	exception := false
+	for _, e := range s.Exceptions {
+		if e.Date != date {
+			continue
+		}
+		if e.Closed() {
+			return nil
+		}
+		exception = true
+		if iv, err := parseHours(e.Open, e.Close); err == nil {
+			intervals = append(intervals, iv)
+		}
+	}
+	if !exception {
+		for _, h := range s.Hours {
+			if h.Weekday != day.Weekday() {
+				continue
+			}
+			if iv, err := parseHours(h.Open, h.Close); err == nil {
+				intervals = append(intervals, iv)
+			}
+		}
+	}
+	sortIntervals(intervals)
+	return intervals
+}
+
+// at returns instant of wall clock time (minutes since midnight) on date of day in location of day
+func at(day time.Time, minutes int) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, 0, minutes, 0, 0, day.Location())
+}
+
+// Slots returns all slots of schedule on date of day, slots are in location of day. Slots that
+// span daylight saving change are skipped because they aren't of slot length.
+func Slots(s DR.Schedule, day time.Time) []Slot {
+	s = Of(&s)
+	length := time.Duration(s.SlotMinutes) * time.Minute
+	slots := []Slot{}
+	for _, iv := range opening(s, day) {
+		for t := iv.open; t+s.SlotMinutes <= iv.close; t += s.SlotMinutes + s.BufferMinutes {
+			slot := Slot{Start: at(day, t), End: at(day, t+s.SlotMinutes)}
+			if slot.End.Sub(slot.Start) == length {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots
+}
+
+// Free returns slots of schedule on date of day that are at least buffer away from confirmed bookings
+func Free(s DR.Schedule, day time.Time, bookings []DR.Booking) []Slot {
+	free := []Slot{}
+	for _, slot := range Slots(s, day) {
+		if !Occupied(s, slot, bookings) {
+			free = append(free, slot)
+		}
+	}
+	return free
+}
+
+// Occupied returns true if confirmed booking is closer to slot than buffer of schedule
+func Occupied(s DR.Schedule, slot Slot, bookings []DR.Booking) bool {
+	start, end := Padded(s, slot)
+	for _, b := range bookings {
+		if b.Status == DR.BS_CONFIRMED && !b.IsDeleted() && b.Overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// Padded returns slot extended by buffer of schedule on both sides
+func Padded(s DR.Schedule, slot Slot) (time.Time, time.Time) {
+	buffer := time.Duration(s.BufferMinutes) * time.Minute
+	return slot.Start.Add(-buffer), slot.End.Add(buffer)
+}
+
+// SlotAt returns slot of schedule length that starts at start, ErrClosed is returned if slot
+// isn't within opening hours of that day. Start doesn't have to be aligned with slots of day.
+func SlotAt(s DR.Schedule, start time.Time) (Slot, error) {
+	s = Of(&s)
+	end := start.Add(time.Duration(s.SlotMinutes) * time.Minute)
+	for _, iv := range opening(s, start) {
+		if !start.Before(at(start, iv.open)) && !end.After(at(start, iv.close)) {
+			return Slot{Start: start, End: end}, nil
+		}
+	}
+	return Slot{}, ErrClosed
+}
+
+// Location returns location of IANA time zone of owner, empty zone is DR.DEFAULT_TIME_ZONE
+func Location(tz string) (*time.Location, error) {
+	if tz == "" {
+		tz = DR.DEFAULT_TIME_ZONE
+	}
+	if loc, ok := locations.Load(tz); ok {
+		return loc.(*time.Location), nil
+	}
+	// Local is zone of server, LoadLocation accepts it
+	if tz == "Local" {
+		return nil, ErrTimeZone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrTimeZone
+	}
+	locations.Store(tz, loc)
+	return loc, nil
+}
+
+// OwnerLocation returns location of owner, zones that can't be loaded fall back to DR.DEFAULT_TIME_ZONE
+func OwnerLocation(tz string) *time.Location {
+	if loc, err := Location(tz); err == nil {
+		return loc
+	}
+	loc, _ := Location(DR.DEFAULT_TIME_ZONE)
+	return loc
+}
+
+// Day returns midnight that starts calendar date of t in loc
+func Day(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
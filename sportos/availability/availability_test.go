@@ -0,0 +1,117 @@
+package availability_test
+
+import (
+	"backend/sportos/availability"
+	DR "backend/sportos/repo/dto"
+	"errors"
+	"testing"
+	"time"
+)
+
+func starts(slots []availability.Slot) []string {
+	ret := []string{}
+	for _, slot := range slots {
+		ret = append(ret, slot.Start.Format("15:04"))
+	}
+	return ret
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOwnerWithoutScheduleIsOpenWholeDay(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	slots := availability.Slots(availability.Of(nil), day)
+	if len(slots) != 24 || !slots[23].End.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("expected 24 slots of one hour, got %v", starts(slots))
+	}
+
+	loc, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Skip(err)
+	}
+	// clocks are moved from 02:00 to 03:00
+	slots = availability.Slots(availability.Of(nil), time.Date(2026, 3, 29, 0, 0, 0, 0, loc))
+	if len(slots) != 23 {
+		t.Errorf("expected 23 slots on day of daylight saving change, got %v", starts(slots))
+	}
+}
+
+func TestSlotsFollowHoursExceptionsAndBuffer(t *testing.T) {
+	schedule := DR.Schedule{
+		Hours: []DR.OpeningHours{
+			{Weekday: time.Monday, Open: "16:00", Close: "18:00"},
+			{Weekday: time.Monday, Open: "08:00", Close: "12:00"},
+		},
+		Exceptions: []DR.ScheduleException{
+			{Date: "2026-10-26", Reason: "holiday"},
+			{Date: "2026-11-02", Open: "10:00", Close: "11:00"},
+		},
+		SlotMinutes:   60,
+		BufferMinutes: 30,
+	}
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		day      time.Time
+		expected []string
+	}{
+		{monday, []string{"08:00", "09:30", "11:00", "16:00"}},
+		{monday.AddDate(0, 0, 1), []string{}},
+		{monday.AddDate(0, 0, 7), []string{}},
+		{monday.AddDate(0, 0, 14), []string{"10:00"}},
+	}
+	for _, test := range tests {
+		if got := starts(availability.Slots(schedule, test.day)); !equal(got, test.expected) {
+			t.Errorf("%s: expected slots %v, got %v", test.day.Format("2006-01-02"), test.expected, got)
+		}
+	}
+
+	// booking from 10:00 is closer than buffer to slots at 09:30 and 11:00
+	bookings := []DR.Booking{
+		{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(10*time.Hour + 30*time.Minute), Status: DR.BS_CONFIRMED},
+		{StartTime: monday.Add(16 * time.Hour), EndTime: monday.Add(17 * time.Hour), Status: DR.BS_PENDING},
+	}
+	if got := starts(availability.Free(schedule, monday, bookings)); !equal(got, []string{"08:00", "11:00", "16:00"}) {
+		t.Errorf("expected free slots 08:00, 11:00 and 16:00, got %v", got)
+	}
+
+	if _, err := availability.SlotAt(schedule, monday.Add(11*time.Hour+30*time.Minute)); !errors.Is(err, availability.ErrClosed) {
+		t.Errorf("expected slot that ends after closing to be rejected, got %v", err)
+	}
+	slot, err := availability.SlotAt(schedule, monday.Add(8*time.Hour+15*time.Minute))
+	if err != nil || !slot.End.Equal(monday.Add(9*time.Hour+15*time.Minute)) {
+		t.Errorf("expected slot from 08:15 to 09:15, got %+v, %v", slot, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		schedule DR.Schedule
+		err      error
+	}{
+		{DR.Schedule{Hours: []DR.OpeningHours{{Weekday: time.Sunday, Open: "00:00", Close: "24:00"}}}, nil},
+		{DR.Schedule{Hours: []DR.OpeningHours{{Weekday: 7, Open: "08:00", Close: "10:00"}}}, availability.ErrWeekday},
+		{DR.Schedule{Hours: []DR.OpeningHours{{Open: "8h", Close: "10:00"}}}, availability.ErrHoursFormat},
+		{DR.Schedule{Hours: []DR.OpeningHours{{Open: "10:00", Close: "08:00"}}}, availability.ErrHoursRange},
+		{DR.Schedule{Hours: []DR.OpeningHours{{Open: "08:00", Close: "10:00"}, {Open: "09:00", Close: "11:00"}}}, availability.ErrHoursOverlap},
+		{DR.Schedule{Exceptions: []DR.ScheduleException{{Date: "26.10.2026"}}}, availability.ErrDateFormat},
+		{DR.Schedule{Exceptions: []DR.ScheduleException{{Date: "2026-10-26", Open: "12:00", Close: "11:00"}}}, availability.ErrHoursRange},
+		{DR.Schedule{SlotMinutes: 1}, availability.ErrSlotLength},
+		{DR.Schedule{BufferMinutes: -5}, availability.ErrBuffer},
+	}
+	for i, test := range tests {
+		if err := availability.Validate(test.schedule); !errors.Is(err, test.err) {
+			t.Errorf("%d: expected %v, got %v", i, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package balance splits players of pickup match into two teams.
+//
+// Skill of player is rating in sport of match, players without rated matches get skill from
+// level in their preferences. Balanced split is found by trying every split of players, team sports
+// have at most 11 players per side so there are at most C(21,10) splits. Players are shuffled before
+// search so equally good splits are chosen randomly.
+package balance
+
+import (
+	"backend/sportos/rating"
+	DR "backend/sportos/repo/dto"
+	"math"
+	"math/rand"
+)
+
+const (
+	// PRO_SKILL is skill of professional player without rated matches
+	PRO_SKILL = rating.DEFAULT_RATING + 200
+	// AMATEUR_SKILL is skill of amateur player without rated matches
+	AMATEUR_SKILL = rating.DEFAULT_RATING - 100
+	// POSITION_PENALTY is added to difference of team skills for every position of sport
+	// that nobody in team prefers
+	POSITION_PENALTY = 100.0
+)
+
+// Player is member of match that is placed into team
+type Player struct {
+	Id    string
+	Skill float64
+	// Position is preferred position of player in sport of match, empty if there is none
+	Position string
+}
+
+// NewPlayer returns player with skill from rating, r is nil if player has no rating in sport yet
+func NewPlayer(p DR.Player, sport string, r *DR.Rating) Player {
+	player := Player{Id: p.Username, Skill: rating.DEFAULT_RATING}
+	var level DR.PlayerLevel
+	if p.Preferences != nil {
+		for _, pref := range *p.Preferences {
+			if pref.Sport == sport {
+				player.Position = pref.Position
+				level = pref.Level
+				break
+			}
+		}
+	}
+	switch {
+	case r != nil && r.Matches > 0:
+		player.Skill = r.Rating
+	case level == DR.LEVEL_PRO:
+		player.Skill = PRO_SKILL
+	case level == DR.LEVEL_AMA:
+		player.Skill = AMATEUR_SKILL
+	}
+	return player
+}
+
+// Split splits players into two teams of equal size, it returns side (0 or 1) of every player.
+// Positions are positions of sport, they are used only by DR.MB_POSITIONS, unknown algorithm splits randomly.
+func Split(algorithm DR.MatchBalancing, players []Player, positions []string) []int {
+	n := len(players)
+	sides := make([]int, n)
+	order := rand.Perm(n)
+	if algorithm != DR.MB_RATING && algorithm != DR.MB_POSITIONS {
+		for i, elem := range order {
+			if i >= n/2 {
+				sides[elem] = 1
+			}
+		}
+		return sides
+	}
+	if algorithm != DR.MB_POSITIONS {
+		positions = nil
+	}
+	shuffled := make([]Player, n)
+	for i, elem := range order {
+		shuffled[i] = players[elem]
+	}
+	best := search(shuffled, positions)
+	for i, elem := range order {
+		sides[elem] = best[i]
+	}
+	return sides
+}
+
+// Cost returns how unfair split of players is, lower is better
+func Cost(players []Player, sides []int, positions []string) float64 {
+	var skill [2]float64
+	for i, p := range players {
+		skill[sides[i]] += p.Skill
+	}
+	cost := math.Abs(skill[0] - skill[1])
+	for _, position := range positions {
+		var covered [2]bool
+		for i, p := range players {
+			if p.Position == position {
+				covered[sides[i]] = true
+			}
+		}
+		for _, c := range covered {
+			if !c {
+				cost += POSITION_PENALTY
+			}
+		}
+	}
+	return cost
+}
+
+// search returns split with lowest cost, first player is always in team 0 so mirrored splits
+// are checked only once
+func search(players []Player, positions []string) []int {
+	n := len(players)
+	sides := make([]int, n)
+	for i := n / 2; i < n; i++ {
+		sides[i] = 1
+	}
+	if n < 2 {
+		return sides
+	}
+	best := append([]int{}, sides...)
+	bestCost := Cost(players, sides, positions)
+
+	var choose func(i, left int)
+	choose = func(i, left int) {
+		if left == 0 || n-i == left {
+			for j := i; j < n; j++ {
+				sides[j] = 1
+				if left > 0 {
+					sides[j] = 0
+				}
+			}
+			if cost := Cost(players, sides, positions); cost < bestCost {
+				bestCost = cost
+				copy(best, sides)
+			}
+			return
+		}
+		sides[i] = 0
+		choose(i+1, left-1)
+		sides[i] = 1
+		choose(i+1, left)
+	}
+	sides[0] = 0
+	choose(1, n/2-1)
+	return best
+}
@@ -0,0 +1,78 @@
+package balance
+
+import (
+	DR "backend/sportos/repo/dto"
+	"testing"
+)
+
+func sideSkills(players []Player, sides []int) [2]float64 {
+	var skill [2]float64
+	for i, p := range players {
+		skill[sides[i]] += p.Skill
+	}
+	return skill
+}
+
+func checkSizes(t *testing.T, sides []int) {
+	var count [2]int
+	for _, side := range sides {
+		count[side]++
+	}
+	if count[0] != count[1] {
+		t.Fatalf("teams have %d and %d players", count[0], count[1])
+	}
+}
+
+func TestSplitRatingBalancesSkill(t *testing.T) {
+	players := []Player{{Id: "a", Skill: 1900}, {Id: "b", Skill: 1800}, {Id: "c", Skill: 1500}, {Id: "d", Skill: 1400}}
+	for i := 0; i < 20; i++ {
+		sides := Split(DR.MB_RATING, players, nil)
+		checkSizes(t, sides)
+		// best split is 1900+1400 against 1800+1500
+		if skill := sideSkills(players, sides); skill[0] != skill[1] {
+			t.Fatalf("teams have skill %v", skill)
+		}
+	}
+}
+
+func TestSplitPositionsCoversPositions(t *testing.T) {
+	positions := []string{"Goalkeeper", "Attack"}
+	// splitting only by skill would put both goalkeepers in same team
+	players := []Player{
+		{Id: "g1", Skill: 1600, Position: "Goalkeeper"},
+		{Id: "g2", Skill: 1400, Position: "Goalkeeper"},
+		{Id: "a1", Skill: 1610, Position: "Attack"},
+		{Id: "a2", Skill: 1390, Position: "Attack"},
+	}
+	if sides := Split(DR.MB_RATING, players, positions); sides[0] != sides[1] {
+		t.Fatalf("rating split ignores positions, goalkeepers should be together: %v", sides)
+	}
+	sides := Split(DR.MB_POSITIONS, players, positions)
+	checkSizes(t, sides)
+	if sides[0] == sides[1] || sides[2] == sides[3] {
+		t.Fatalf("positions aren't covered by both teams: %v", sides)
+	}
+	if got := Cost(players, sides, positions); got != 20 {
+		t.Fatalf("cost of split is %v, expected 20", got)
+	}
+}
+
+func TestSplitRandom(t *testing.T) {
+	sides := Split(DR.MB_RANDOM, make([]Player, 22), nil)
+	checkSizes(t, sides)
+}
+
+func TestNewPlayer(t *testing.T) {
+	prefs := DR.Prefernces{{Sport: "Football", Position: "Defence", Level: DR.LEVEL_PRO}}
+	p := DR.Player{Preferences: &prefs}
+	p.Username = "p1"
+	if got := NewPlayer(p, "Football", nil); got.Skill != PRO_SKILL || got.Position != "Defence" {
+		t.Fatalf("unrated pro player is %+v", got)
+	}
+	if got := NewPlayer(p, "Football", &DR.Rating{Rating: 1450, Matches: 3}); got.Skill != 1450 {
+		t.Fatalf("rated player is %+v", got)
+	}
+	if got := NewPlayer(p, "Basketball", nil); got.Skill != 1500 || got.Position != "" {
+		t.Fatalf("player without preference is %+v", got)
+	}
+}
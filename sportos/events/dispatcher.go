@@ -0,0 +1,174 @@
+package events
+
+import (
+	L "backend/internal/logging"
+	"backend/internal/periodic"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_DISPATCH_INTERVAL     = time.Second
+	DEFAULT_DISPATCH_MAX_ATTEMPTS = 8
+	dispatchBatchSize             = 50
+	dispatchBackoff               = 10 * time.Second
+	dispatchMaxBackoff            = time.Hour
+)
+
+// Config of dispatcher, filled from flags
+type Config struct {
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+var config = Config{Interval: DEFAULT_DISPATCH_INTERVAL, MaxAttempts: DEFAULT_DISPATCH_MAX_ATTEMPTS}
+
+// Init sets config used by dispatchers created after it
+func Init(c Config) {
+	config = c
+	L.L.Info("events params", L.Duration("events.interval", c.Interval), L.Int("events.attempts", c.MaxAttempts))
+}
+
+// Subscriber handles event delivered by dispatcher, qa is transaction in which event is marked as dispatched,
+// changes made through it are rolled back if subscriber returns error
+type Subscriber func(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error
+
+// Dispatcher periodically delivers pending events from outbox to subscribers,
+// failed events are retried with exponential backoff until MaxAttempts is reached
+type Dispatcher struct {
+	Repo        *crud.Repo
+	Interval    time.Duration
+	MaxAttempts int
+
+	subscribers map[DR.OutboxEventType][]Subscriber
+	mutex       sync.RWMutex
+
+	runner periodic.Runner
+}
+
+// NewDispatcher creates dispatcher without subscribers with interval and max attempts from config
+func NewDispatcher(Repo *crud.Repo) *Dispatcher {
+	interval := config.Interval
+	maxAttempts := config.MaxAttempts
+	if interval <= 0 {
+		interval = DEFAULT_DISPATCH_INTERVAL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DEFAULT_DISPATCH_MAX_ATTEMPTS
+	}
+	return &Dispatcher{
+		Repo:        Repo,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+		subscribers: map[DR.OutboxEventType][]Subscriber{},
+	}
+}
+
+// Subscribe registers subscriber for events of type, subscribers are called in order of registration
+func (d *Dispatcher) Subscribe(eventType DR.OutboxEventType, s Subscriber) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.subscribers[eventType] = append(d.subscribers[eventType], s)
+}
+
+// Start runs dispatcher in background until Stop is called
+func (d *Dispatcher) Start() {
+	d.runner.Start("Dispatcher.ProcessPending", d.Interval, d.ProcessPending)
+}
+
+// Stop waits for batch in progress to finish
+func (d *Dispatcher) Stop() {
+	d.runner.Stop()
+}
+
+// ProcessPending dispatches one batch of due events and returns number of successfully dispatched events
+func (d *Dispatcher) ProcessPending(ctx context.Context) (int, error) {
+	pending, err := d.Repo.OutboxEventCrud.GetPending(ctx, dispatchBatchSize, nil)
+	if err != nil {
+		return 0, err
+	}
+	dispatched := 0
+	for _, oe := range pending {
+		ok, err := d.dispatch(ctx, oe.OutboxEventId)
+		if err != nil {
+			return dispatched, err
+		}
+		if ok {
+			dispatched++
+		}
+	}
+	return dispatched, nil
+}
+
+// dispatch delivers event to its subscribers in one transaction, event is locked so other dispatchers
+// skip it once it is dispatched. Failure of subscriber is recorded on event, it isn't returned as error
+func (d *Dispatcher) dispatch(ctx context.Context, id string) (bool, error) {
+	tx, err := d.Repo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	oe, err := d.Repo.OutboxEventCrud.GetById(ctx, id, tx)
+	if err != nil {
+		return false, err
+	}
+	if oe.Status != DR.OES_PENDING {
+		return false, nil
+	}
+
+	if err := d.deliver(ctx, tx, oe); err != nil {
+		tx.Rollback()
+		return false, d.fail(ctx, oe, err)
+	}
+
+	status := DR.OES_DISPATCHED
+	now := time.Now().UTC()
+	attempts := oe.Attempts + 1
+	up := DR.OutboxEventUpdateParams{Id: oe.OutboxEventId, Status: &status, Attempts: &attempts, DispatchedAt: &now}
+	if err := d.Repo.OutboxEventCrud.Update(ctx, up, tx, nil); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// deliver calls all subscribers of event, panic of subscriber is reported as its error
+func (d *Dispatcher) deliver(ctx context.Context, tx crud.Tx, oe DR.OutboxEvent) (err error) {
+	d.mutex.RLock()
+	subscribers := d.subscribers[oe.Type]
+	d.mutex.RUnlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("subscriber of %s panicked: %v", oe.Type, r)
+		}
+	}()
+	for _, s := range subscribers {
+		if err := s(ctx, d.Repo, tx, oe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fail records failed attempt, event is marked as failed after MaxAttempts
+func (d *Dispatcher) fail(ctx context.Context, oe DR.OutboxEvent, dispatchErr error) error {
+	attempts := oe.Attempts + 1
+	L.L.Warn("Dispatcher subscriber failed", L.String("outboxEventId", oe.OutboxEventId), L.String("type", string(oe.Type)), L.Int("attempt", attempts), L.Error(dispatchErr))
+	lastError := dispatchErr.Error()
+	up := DR.OutboxEventUpdateParams{Id: oe.OutboxEventId, Attempts: &attempts, LastError: &lastError}
+	if attempts >= d.MaxAttempts {
+		failed := DR.OES_FAILED
+		up.Status = &failed
+		L.L.Error("Dispatcher gave up on event", L.String("outboxEventId", oe.OutboxEventId), L.String("type", string(oe.Type)), L.Error(dispatchErr))
+	} else {
+		next := time.Now().UTC().Add(periodic.Backoff(attempts, dispatchBackoff, dispatchMaxBackoff))
+		up.NextAttemptAt = &next
+	}
+	return d.Repo.OutboxEventCrud.Update(ctx, up, nil, nil)
+}
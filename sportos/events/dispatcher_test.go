@@ -0,0 +1,91 @@
+package events_test
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/events"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestDispatcherDeliversCommittedEvent(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	d := events.NewDispatcher(Repo)
+
+	var delivered []events.MatchFinished
+	d.Subscribe(DR.OE_MATCH_FINISHED, func(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+		var ev events.MatchFinished
+		if err := events.Decode(oe, &ev); err != nil {
+			return err
+		}
+		delivered = append(delivered, ev)
+		return nil
+	})
+
+	tx, _ := Repo.BeginTx(ctx)
+	if err := events.Publish(ctx, Repo, tx, events.MatchFinished{MatchId: "1", Result: "3:1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	// events without subscribers are dispatched too
+	if err := events.Publish(ctx, Repo, tx, events.UserRegistered{Username: "ana", UserType: DR.UT_PLAYER}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	tx.Commit()
+
+	if n, err := d.ProcessPending(ctx); err != nil || n != 2 {
+		t.Fatalf("expected 2 dispatched events, got %d, %v", n, err)
+	}
+	if len(delivered) != 1 || delivered[0].MatchId != "1" || delivered[0].Result != "3:1" {
+		t.Errorf("expected match finished event to be delivered once, got %+v", delivered)
+	}
+	if n, _ := d.ProcessPending(ctx); n != 0 {
+		t.Errorf("expected dispatched events not to be delivered again, got %d", n)
+	}
+}
+
+func TestDispatcherRetriesFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	d := events.NewDispatcher(Repo)
+	d.MaxAttempts = 2
+
+	calls := 0
+	d.Subscribe(DR.OE_PRACTICE_ACCEPTED, func(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+		calls++
+		return errors.New("subscriber is down")
+	})
+	if err := events.Publish(ctx, Repo, nil, events.PracticeAccepted{PracticeId: "7"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if n, err := d.ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected failed dispatch, got %d, %v", n, err)
+	}
+	oe, _ := Repo.OutboxEventCrud.GetById(ctx, "1", nil)
+	if oe.Status != DR.OES_PENDING || oe.Attempts != 1 || oe.LastError == nil || !oe.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("expected pending event with backoff after first failure, got %+v", oe)
+	}
+	// event isn't retried before backoff passes
+	if d.ProcessPending(ctx); calls != 1 {
+		t.Fatalf("expected event to wait for backoff, subscriber was called %d times", calls)
+	}
+
+	now := time.Now().UTC()
+	Repo.OutboxEventCrud.Update(ctx, DR.OutboxEventUpdateParams{Id: oe.OutboxEventId, NextAttemptAt: &now}, nil, nil)
+	d.ProcessPending(ctx)
+	oe, _ = Repo.OutboxEventCrud.GetById(ctx, oe.OutboxEventId, nil)
+	if calls != 2 || oe.Status != DR.OES_FAILED || oe.Attempts != 2 {
+		t.Errorf("expected event to fail after max attempts, got %d calls and %+v", calls, oe)
+	}
+}
@@ -0,0 +1,118 @@
+// Package events contains domain events, their transactional outbox and dispatcher that delivers them
+// to in process subscribers.
+//
+// Handlers publish event in the same transaction as change that caused it, so event is stored only if
+// change is committed. Dispatcher reads committed events and calls subscribers, event is retried with
+// exponential backoff if any subscriber fails, so subscribers have to tolerate repeated delivery.
+package events
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is payload of domain event
+type Event interface {
+	EventType() DR.OutboxEventType
+	// AggregateId is id of entity that changed
+	AggregateId() string
+}
+
+// MatchBooked is published when match is created and its time is booked at place
+type MatchBooked struct {
+	MatchId   string    `json:"matchId"`
+	PlaceId   string    `json:"placeId"`
+	Sport     string    `json:"sport"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+func (e MatchBooked) EventType() DR.OutboxEventType { return DR.OE_MATCH_BOOKED }
+func (e MatchBooked) AggregateId() string           { return e.MatchId }
+
+// MatchCancelled is published when match is deleted and its booking at place is freed
+type MatchCancelled struct {
+	MatchId string `json:"matchId"`
+	PlaceId string `json:"placeId"`
+}
+
+func (e MatchCancelled) EventType() DR.OutboxEventType { return DR.OE_MATCH_CANCELLED }
+func (e MatchCancelled) AggregateId() string           { return e.MatchId }
+
+// MatchFinished is published when result of match is confirmed, auto confirmed or resolved in backoffice
+type MatchFinished struct {
+	MatchId string `json:"matchId"`
+	Result  string `json:"result"`
+}
+
+func (e MatchFinished) EventType() DR.OutboxEventType { return DR.OE_MATCH_FINISHED }
+func (e MatchFinished) AggregateId() string           { return e.MatchId }
+
+// TournamentFinished is published when owner finishes tournament, standings are in tournament of event
+type TournamentFinished struct {
+	EventId string `json:"eventId"`
+}
+
+func (e TournamentFinished) EventType() DR.OutboxEventType { return DR.OE_TOURNAMENT_FINISHED }
+func (e TournamentFinished) AggregateId() string           { return e.EventId }
+
+// TournamentStatusChanged is published when tournament is created, started, finished or cancelled,
+// PlaceId is owner of tournament
+type TournamentStatusChanged struct {
+	EventId string         `json:"eventId"`
+	PlaceId string         `json:"placeId"`
+	Name    string         `json:"name"`
+	Status  DR.EventStatus `json:"status"`
+}
+
+func (e TournamentStatusChanged) EventType() DR.OutboxEventType {
+	return DR.OE_TOURNAMENT_STATUS_CHANGED
+}
+
+func (e TournamentStatusChanged) AggregateId() string { return e.EventId }
+
+// PracticeAccepted is published when coach accepts practice
+type PracticeAccepted struct {
+	PracticeId string `json:"practiceId"`
+	CoachId    string `json:"coachId"`
+	PlayerId   string `json:"playerId"`
+}
+
+func (e PracticeAccepted) EventType() DR.OutboxEventType { return DR.OE_PRACTICE_ACCEPTED }
+func (e PracticeAccepted) AggregateId() string           { return e.PracticeId }
+
+// UserRegistered is published when user registers with password or social account
+type UserRegistered struct {
+	Username string      `json:"username"`
+	UserType DR.UserType `json:"userType"`
+}
+
+func (e UserRegistered) EventType() DR.OutboxEventType { return DR.OE_USER_REGISTERED }
+func (e UserRegistered) AggregateId() string           { return e.Username }
+
+// Publish writes event to outbox, qa should be transaction of change that caused event
+func Publish(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, ev Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	oe, err := Repo.OutboxEventCrud.Create(ctx, DR.OutboxEvent{
+		Type:        ev.EventType(),
+		AggregateId: ev.AggregateId(),
+		Payload:     payload,
+	}, qa, nil)
+	if err != nil {
+		return err
+	}
+	L.L.WithRequestID(ctx).Debug("events.Publish", L.String("outboxEventId", oe.OutboxEventId), L.String("type", string(oe.Type)))
+	return nil
+}
+
+// Decode unmarshals payload of outbox event into ev
+func Decode(oe DR.OutboxEvent, ev Event) error {
+	return json.Unmarshal(oe.Payload, ev)
+}
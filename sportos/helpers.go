@@ -20,6 +20,7 @@ type ContextKey string
 
 const (
 	CONTEXT_USER_ID_KEY           = ContextKey("UserID")
+	CONTEXT_USER_TYPE_KEY         = ContextKey("UserType")
 	CONTEXT_PAM_TOKEN_KEY         = ContextKey("PamToken")
 	CONTEXT_API_JOURNAL_ID_KEY    = ContextKey("ApiJournalId")
 	CONTEXT_SOURCE_IP_KEY         = ContextKey("SourceIp")
@@ -260,31 +261,26 @@ func Exchange(currencyFrom string, currencyTo string, value decimal.Decimal) (*d
 	return &res, nil
 }
 
-// Get Monday as first day of the week
-func GetFirstDayOfWeek(tm time.Time) int {
-	_, _, day := tm.Date()
-	weekday := tm.Weekday()
+// Get Monday midnight that starts week of tm in loc, loc is time zone of place or coach
+func GetFirstDayOfWeek(tm time.Time, loc *time.Location) time.Time {
+	year, month, day := tm.In(loc).Date()
+	weekday := tm.In(loc).Weekday()
 	if weekday == 0 {
 		weekday = 7
 	}
-	firstDayOfWeek := day - int(weekday) + 1
-	return firstDayOfWeek
+	return time.Date(year, month, day-int(weekday)+1, 0, 0, 0, 0, loc)
 }
 
-// Get first day of the last week
-func GetFirstDayOfLastWeek() time.Time {
-	now := time.Now()
-	nowLastWeek := now.Add(-7 * 24 * time.Hour)
-	beginLastWeek := nowLastWeek.Add(-time.Duration(nowLastWeek.Weekday()) * 24 * time.Hour)
-	year, month, day := beginLastWeek.Date()
-	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+// Get first day (sunday) of the last week in loc
+func GetFirstDayOfLastWeek(loc *time.Location) time.Time {
+	year, month, day := time.Now().In(loc).Date()
+	nowLastWeek := time.Date(year, month, day-7, 0, 0, 0, 0, loc)
+	return nowLastWeek.AddDate(0, 0, -int(nowLastWeek.Weekday()))
 }
 
-// Get last day of the last week
-func GetLastDayOfLastWeek() time.Time {
-	now := time.Now()
-	nowLastWeek := now.Add(-7 * 24 * time.Hour)
-	endLastWeek := nowLastWeek.Add(time.Duration(7-nowLastWeek.Weekday()) * 24 * time.Hour)
-	year, month, day := endLastWeek.Date()
-	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+// Get last day of the last week (sunday that starts this week) in loc
+func GetLastDayOfLastWeek(loc *time.Location) time.Time {
+	year, month, day := time.Now().In(loc).Date()
+	nowLastWeek := time.Date(year, month, day-7, 0, 0, 0, 0, loc)
+	return nowLastWeek.AddDate(0, 0, 7-int(nowLastWeek.Weekday()))
 }
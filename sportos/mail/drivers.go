@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// SMTPMailer sends messages through SMTP server with PLAIN auth
+type SMTPMailer struct {
+	host     string
+	port     string
+	user     string
+	password string
+}
+
+func NewSMTPMailer(host, port, user, password string) *SMTPMailer {
+	return &SMTPMailer{host: host, port: port, user: user, password: password}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.password, m.host)
+	}
+	return smtp.SendMail(m.host+":"+m.port, auth, msg.From, msg.To, buildMIME(msg))
+}
+
+// FileMailer writes every message as .eml file into directory, used for local development
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405"), xid.New().String())
+	return os.WriteFile(filepath.Join(m.dir, name), buildMIME(msg), 0644)
+}
+
+// MemoryMailer keeps sent messages in memory so they can be asserted in tests
+type MemoryMailer struct {
+	messages []Message
+
+	mutex sync.RWMutex
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns copy of all sent messages
+func (m *MemoryMailer) Messages() []Message {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return append([]Message{}, m.messages...)
+}
+
+// Reset removes all sent messages
+func (m *MemoryMailer) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.messages = nil
+}
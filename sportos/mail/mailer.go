@@ -0,0 +1,119 @@
+// Package mail contains mail delivery (SMTP, file, in memory), message templates and outbox worker
+package mail
+
+import (
+	L "backend/internal/logging"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Message is a single email
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Text    string
+	Html    string
+}
+
+// Mailer delivers message to recipients
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Driver string
+
+const (
+	DRIVER_SMTP   Driver = "smtp"
+	DRIVER_FILE   Driver = "file"
+	DRIVER_MEMORY Driver = "memory"
+)
+
+// Config of mail subsystem, filled from flags
+type Config struct {
+	Driver Driver
+	From   string
+	// base url of frontend application used in links sent by mail
+	AppUrl string
+
+	SMTPHost string
+	SMTPPort string
+	SMTPUser string
+	SMTPPass string
+
+	// directory where file driver writes messages
+	Dir string
+
+	OutboxInterval    time.Duration
+	OutboxMaxAttempts int
+}
+
+// M is a global mailer used by outbox worker
+var M Mailer = NewMemoryMailer()
+
+var config = Config{Driver: DRIVER_MEMORY, AppUrl: "https://localhost:4200", OutboxInterval: DEFAULT_OUTBOX_INTERVAL, OutboxMaxAttempts: DEFAULT_OUTBOX_MAX_ATTEMPTS}
+
+// Init creates global mailer from config
+func Init(c Config) error {
+	var m Mailer
+	switch c.Driver {
+	case DRIVER_SMTP:
+		if c.SMTPHost == "" || c.From == "" {
+			return fmt.Errorf("smtp mail driver requires host and from address")
+		}
+		m = NewSMTPMailer(c.SMTPHost, c.SMTPPort, c.SMTPUser, c.SMTPPass)
+	case DRIVER_FILE:
+		if c.Dir == "" {
+			return fmt.Errorf("file mail driver requires directory")
+		}
+		m = NewFileMailer(c.Dir)
+	case DRIVER_MEMORY, "":
+		m = NewMemoryMailer()
+	default:
+		return fmt.Errorf("mail driver %s isn't supported", c.Driver)
+	}
+	M = m
+	config = c
+	L.L.Info("mail params", L.String("mail.driver", string(c.Driver)), L.String("mail.from", c.From), L.String("mail.app.url", c.AppUrl))
+	return nil
+}
+
+// AppLink returns link to frontend application page with query parameters
+func AppLink(path string, query url.Values) string {
+	link := strings.TrimSuffix(config.AppUrl, "/") + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// buildMIME serializes message into RFC 5322 message with text and optional html alternative
+func buildMIME(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: <%s>\r\n", msg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", formatRecipients(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if msg.Html == "" {
+		b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+		b.WriteString(msg.Text)
+		return []byte(b.String())
+	}
+	boundary := "sportos-alternative-boundary"
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s\r\n", boundary, msg.Text)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n%s\r\n", boundary, msg.Html)
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
+
+func formatRecipients(to []string) string {
+	recipients := make([]string, len(to))
+	for i, r := range to {
+		recipients[i] = "<" + r + ">"
+	}
+	return strings.Join(recipients, ", ")
+}
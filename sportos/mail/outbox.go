@@ -0,0 +1,106 @@
+package mail
+
+import (
+	L "backend/internal/logging"
+	"backend/internal/periodic"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"time"
+)
+
+const (
+	DEFAULT_OUTBOX_INTERVAL     = 5 * time.Second
+	DEFAULT_OUTBOX_MAX_ATTEMPTS = 8
+	outboxBatchSize             = 20
+	outboxBackoff               = 30 * time.Second
+	outboxMaxBackoff            = time.Hour
+)
+
+// OutboxWorker periodically sends pending mails from mail_outbox,
+// failed mails are retried with exponential backoff until MaxAttempts is reached
+type OutboxWorker struct {
+	Repo        *crud.Repo
+	Interval    time.Duration
+	MaxAttempts int
+
+	runner periodic.Runner
+}
+
+// NewOutboxWorker creates worker with interval and max attempts from config
+func NewOutboxWorker(Repo *crud.Repo) *OutboxWorker {
+	interval := config.OutboxInterval
+	maxAttempts := config.OutboxMaxAttempts
+	if interval <= 0 {
+		interval = DEFAULT_OUTBOX_INTERVAL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DEFAULT_OUTBOX_MAX_ATTEMPTS
+	}
+	return &OutboxWorker{
+		Repo:        Repo,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// Start runs worker in background until Stop is called
+func (w *OutboxWorker) Start() {
+	w.runner.Start("OutboxWorker.ProcessPending", w.Interval, w.ProcessPending)
+}
+
+// Stop waits for batch in progress to finish
+func (w *OutboxWorker) Stop() {
+	w.runner.Stop()
+}
+
+// ProcessPending sends one batch of due mails and returns number of successfully sent mails
+func (w *OutboxWorker) ProcessPending(ctx context.Context) (int, error) {
+	tx, err := w.Repo.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	mails, err := w.Repo.MailOutboxCrud.GetPending(ctx, outboxBatchSize, tx)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, mo := range mails {
+		msg := Message{
+			From:    config.From,
+			To:      mo.Recipients,
+			Subject: mo.Subject,
+			Text:    mo.TextBody,
+		}
+		if mo.HtmlBody != nil {
+			msg.Html = *mo.HtmlBody
+		}
+		up := DR.MailOutboxUpdateParams{Id: mo.MailId}
+		attempts := mo.Attempts + 1
+		up.Attempts = &attempts
+		if sendErr := M.Send(ctx, msg); sendErr != nil {
+			L.L.Warn("OutboxWorker send failed", L.String("mailId", mo.MailId), L.Int("attempt", attempts), L.Error(sendErr))
+			lastError := sendErr.Error()
+			up.LastError = &lastError
+			if attempts >= w.MaxAttempts {
+				failed := DR.MOS_FAILED
+				up.Status = &failed
+			} else {
+				next := time.Now().UTC().Add(periodic.Backoff(attempts, outboxBackoff, outboxMaxBackoff))
+				up.NextAttemptAt = &next
+			}
+		} else {
+			status := DR.MOS_SENT
+			now := time.Now().UTC()
+			up.Status = &status
+			up.SentAt = &now
+			sent++
+		}
+		if err := w.Repo.MailOutboxCrud.Update(ctx, up, tx, nil); err != nil {
+			return sent, err
+		}
+	}
+	return sent, tx.Commit()
+}
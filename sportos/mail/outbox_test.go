@@ -0,0 +1,125 @@
+package mail_test
+
+import (
+	"backend/sportos/mail"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// failingMailer fails every send until it is fixed
+type failingMailer struct {
+	failing bool
+	mm      *mail.MemoryMailer
+}
+
+func (m *failingMailer) Send(ctx context.Context, msg mail.Message) error {
+	if m.failing {
+		return errors.New("smtp is down")
+	}
+	return m.mm.Send(ctx, msg)
+}
+
+func useMailer(t *testing.T, m mail.Mailer) {
+	t.Helper()
+	old := mail.M
+	mail.M = m
+	t.Cleanup(func() { mail.M = old })
+}
+
+// makeDue moves next attempt of mail to past so worker picks it up
+func makeDue(t *testing.T, Repo *crud.Repo, id string) {
+	t.Helper()
+	due := time.Now().UTC().Add(-time.Second)
+	if err := Repo.MailOutboxCrud.Update(context.Background(), DR.MailOutboxUpdateParams{Id: id, NextAttemptAt: &due}, nil, nil); err != nil {
+		t.Fatalf("update mail: %v", err)
+	}
+}
+
+func enqueue(t *testing.T, Repo *crud.Repo) DR.MailOutbox {
+	t.Helper()
+	ctx := context.Background()
+	err := mail.Enqueue(ctx, Repo, nil, mail.TPL_VERIFY_EMAIL, []string{"test1@test.com"}, mail.LinkData{Username: "test", Link: "https://localhost:4200/verify", ValidFor: "1 hour"})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	mails, err := Repo.MailOutboxCrud.GetPending(ctx, 10, nil)
+	if err != nil || len(mails) != 1 {
+		t.Fatalf("expected one queued mail, got %+v, %v", mails, err)
+	}
+	return mails[0]
+}
+
+func TestOutboxRetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	m := &failingMailer{failing: true, mm: mail.NewMemoryMailer()}
+	useMailer(t, m)
+	mo := enqueue(t, Repo)
+	w := mail.NewOutboxWorker(Repo)
+
+	for attempt, wait := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute} {
+		before := time.Now().UTC()
+		if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+			t.Fatalf("attempt %d: expected failed send, got %d, %v", attempt+1, n, err)
+		}
+		mo, _ = Repo.MailOutboxCrud.GetById(ctx, mo.MailId, nil)
+		if mo.Status != DR.MOS_PENDING || mo.Attempts != attempt+1 || mo.LastError == nil || *mo.LastError != "smtp is down" {
+			t.Fatalf("attempt %d: expected pending mail with error, got %+v", attempt+1, mo)
+		}
+		if mo.NextAttemptAt.Before(before.Add(wait)) || mo.NextAttemptAt.After(time.Now().UTC().Add(wait)) {
+			t.Errorf("attempt %d: expected next attempt in %v, got %v", attempt+1, wait, mo.NextAttemptAt.Sub(before))
+		}
+		// mail isn't sent again before backoff passes
+		if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+			t.Fatalf("attempt %d: expected no mail due, got %d, %v", attempt+1, n, err)
+		}
+		if mo, _ = Repo.MailOutboxCrud.GetById(ctx, mo.MailId, nil); mo.Attempts != attempt+1 {
+			t.Fatalf("attempt %d: mail was retried before backoff passed, %+v", attempt+1, mo)
+		}
+		makeDue(t, Repo, mo.MailId)
+	}
+
+	m.failing = false
+	if n, err := w.ProcessPending(ctx); err != nil || n != 1 {
+		t.Fatalf("expected sent mail, got %d, %v", n, err)
+	}
+	mo, _ = Repo.MailOutboxCrud.GetById(ctx, mo.MailId, nil)
+	if mo.Status != DR.MOS_SENT || mo.Attempts != 4 || mo.SentAt == nil {
+		t.Errorf("expected sent mail, got %+v", mo)
+	}
+	sent := m.mm.Messages()
+	if len(sent) != 1 || sent[0].Subject != "Verify email" || len(sent[0].To) != 1 || sent[0].To[0] != "test1@test.com" {
+		t.Errorf("unexpected sent messages %+v", sent)
+	}
+}
+
+func TestOutboxGivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	useMailer(t, &failingMailer{failing: true})
+	mo := enqueue(t, Repo)
+	w := mail.NewOutboxWorker(Repo)
+	w.MaxAttempts = 2
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+			t.Fatalf("attempt %d: expected failed send, got %d, %v", attempt, n, err)
+		}
+		makeDue(t, Repo, mo.MailId)
+	}
+	mo, _ = Repo.MailOutboxCrud.GetById(ctx, mo.MailId, nil)
+	if mo.Status != DR.MOS_FAILED || mo.Attempts != 2 {
+		t.Fatalf("expected failed mail after 2 attempts, got %+v", mo)
+	}
+	if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected failed mail not to be retried, got %d, %v", n, err)
+	}
+	if mo, _ = Repo.MailOutboxCrud.GetById(ctx, mo.MailId, nil); mo.Attempts != 2 {
+		t.Errorf("expected failed mail not to be retried, got %+v", mo)
+	}
+}
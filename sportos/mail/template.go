@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	texttemplate "text/template"
+	"time"
+)
+
+type Template string
+
+const (
+	TPL_VERIFY_EMAIL   Template = "verify_email"
+	TPL_RESET_PASSWORD Template = "reset_password"
+)
+
+var subjects = map[Template]string{
+	TPL_VERIFY_EMAIL:   "Verify email",
+	TPL_RESET_PASSWORD: "Reset password",
+}
+
+// LinkData is data for templates that contain single link
+type LinkData struct {
+	Username string
+	Link     string
+	ValidFor string
+}
+
+//go:embed templates
+var templatesFS embed.FS
+
+var textTemplates = texttemplate.Must(texttemplate.ParseFS(templatesFS, "templates/*.txt"))
+var htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templatesFS, "templates/*.html"))
+
+// Render executes text and (if it exists) html template with data
+func Render(tpl Template, data interface{}) (Message, error) {
+	subject, ok := subjects[tpl]
+	if !ok {
+		return Message{}, fmt.Errorf("mail template %s doesn't exist", tpl)
+	}
+	msg := Message{Subject: subject}
+
+	var text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, string(tpl)+".txt", data); err != nil {
+		return msg, err
+	}
+	msg.Text = text.String()
+
+	if _, err := fs.Stat(templatesFS, "templates/"+string(tpl)+".html"); err == nil {
+		var html bytes.Buffer
+		if err := htmlTemplates.ExecuteTemplate(&html, string(tpl)+".html", data); err != nil {
+			return msg, err
+		}
+		msg.Html = html.String()
+	}
+	return msg, nil
+}
+
+// Enqueue renders template and saves mail into outbox, if qa is transaction mail is sent only if it commits
+func Enqueue(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, tpl Template, to []string, data interface{}) error {
+	msg, err := Render(tpl, data)
+	if err != nil {
+		return err
+	}
+	mo := DR.MailOutbox{
+		Recipients: to,
+		Subject:    msg.Subject,
+		TextBody:   msg.Text,
+	}
+	if msg.Html != "" {
+		mo.HtmlBody = &msg.Html
+	}
+	_, err = Repo.MailOutboxCrud.Create(ctx, mo, qa, nil)
+	return err
+}
+
+// FormatDuration formats validity of links in human readable form
+func FormatDuration(d time.Duration) string {
+	if d >= time.Hour {
+		hours := int(d.Hours())
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
@@ -0,0 +1,58 @@
+package mail_test
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/mail"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestRender(t *testing.T) {
+	data := mail.LinkData{Username: "<b>test</b>", Link: "https://localhost:4200/reset-password?resetToken=abc&x=1", ValidFor: "1 hour"}
+	msg, err := mail.Render(mail.TPL_RESET_PASSWORD, data)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if msg.Subject != "Reset password" {
+		t.Errorf("unexpected subject %q", msg.Subject)
+	}
+	for _, part := range []string{"Hello <b>test</b>,", data.Link, "valid for 1 hour"} {
+		if !strings.Contains(msg.Text, part) {
+			t.Errorf("expected text to contain %q, got %q", part, msg.Text)
+		}
+	}
+	for _, part := range []string{"Hello &lt;b&gt;test&lt;/b&gt;,", `href="https://localhost:4200/reset-password?resetToken=abc&amp;x=1"`} {
+		if !strings.Contains(msg.Html, part) {
+			t.Errorf("expected html to contain %q, got %q", part, msg.Html)
+		}
+	}
+
+	msg, err = mail.Render(mail.TPL_VERIFY_EMAIL, mail.LinkData{Username: "test", Link: "https://localhost:4200/verify?verifyToken=abc", ValidFor: "24 hours"})
+	if err != nil || msg.Subject != "Verify email" || !strings.Contains(msg.Text, "verifyToken=abc") || msg.Html == "" {
+		t.Errorf("unexpected verify email %+v, %v", msg, err)
+	}
+
+	if _, err = mail.Render("missing", data); err == nil {
+		t.Error("expected error for template that doesn't exist")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		30 * time.Minute: "30 minutes",
+		time.Hour:        "1 hour",
+		24 * time.Hour:   "24 hours",
+	}
+	for d, expected := range cases {
+		if got := mail.FormatDuration(d); got != expected {
+			t.Errorf("%v: expected %q, got %q", d, expected, got)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package notify delivers domain events (match is full, practice is accepted, tournament round is
+// generated ...) to users that are connected to notification stream.
+//
+// Handlers store notifications in inbox of users (Batch.Store) in transaction of change and send them (Batch.Send)
+// through hub that is put into request context after transaction is committed, hub sends them to
+// subscriptions of users the notification is for. Users that aren't connected read them from inbox.
+package notify
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// buffer is number of events that wait for slow subscriber, newer events are dropped when it is full
+const buffer = 32
+
+// Event is notification about change of match, practice, tournament or team with id ReferenceId sent
+// to connected user, EventId is id of notification in inbox and Payload is changed entity
+type Event struct {
+	EventId     string              `json:"eventId"`
+	Type        DR.NotificationType `json:"type"`
+	ReferenceId string              `json:"referenceId"`
+	Message     string              `json:"message"`
+	Time        time.Time           `json:"time"`
+	Payload     interface{}         `json:"payload,omitempty"`
+}
+
+// Subscription receives events of one user until it is closed
+type Subscription struct {
+	C      <-chan Event
+	c      chan Event
+	userId string
+	hub    *Hub
+}
+
+// Close unsubscribes, C is closed after that
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub keeps subscriptions of connected users
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: map[string]map[*Subscription]struct{}{}}
+}
+
+// Subscribe returns subscription to events of user, user can have many subscriptions (devices)
+func (h *Hub) Subscribe(userId string) *Subscription {
+	c := make(chan Event, buffer)
+	s := &Subscription{C: c, c: c, userId: userId, hub: h}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(c)
+		return s
+	}
+	if h.subs[userId] == nil {
+		h.subs[userId] = map[*Subscription]struct{}{}
+	}
+	h.subs[userId][s] = struct{}{}
+	return s
+}
+
+func (h *Hub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s.userId][s]; !ok {
+		return
+	}
+	delete(h.subs[s.userId], s)
+	if len(h.subs[s.userId]) == 0 {
+		delete(h.subs, s.userId)
+	}
+	close(s.c)
+}
+
+// Publish sends event to all subscriptions of users, every user gets event once even if he is listed more times
+func (h *Hub) Publish(ev Event, userIds ...string) {
+	if ev.EventId == "" {
+		ev.EventId = xid.New().String()
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sent := map[string]bool{}
+	for _, userId := range userIds {
+		if sent[userId] {
+			continue
+		}
+		sent[userId] = true
+		for s := range h.subs[userId] {
+			select {
+			case s.c <- ev:
+			default:
+				L.L.Warn("Notification dropped, subscriber is too slow", L.String("userId", userId), L.String("type", string(ev.Type)))
+			}
+		}
+	}
+}
+
+// Close closes all subscriptions, streams end so server can shut down
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, subs := range h.subs {
+		for s := range subs {
+			close(s.c)
+		}
+	}
+	h.subs = map[string]map[*Subscription]struct{}{}
+}
+
+type contextKey string
+
+const hubKey = contextKey("NotifyHub")
+
+// WithHub returns context with hub that Publish uses
+func WithHub(ctx context.Context, h *Hub) context.Context {
+	return context.WithValue(ctx, hubKey, h)
+}
+
+// Publish sends event to users through hub of context, it does nothing if context doesn't have hub
+func Publish(ctx context.Context, ev Event, userIds ...string) {
+	if h, ok := ctx.Value(hubKey).(*Hub); ok && h != nil {
+		h.Publish(ev, userIds...)
+	}
+}
+
+// Batch collects notifications that handler stored in its transaction, they are sent after commit
+type Batch struct {
+	stored []stored
+}
+
+type stored struct {
+	notification DR.Notification
+	payload      interface{}
+}
+
+// Store creates notification n in inbox of every user (once per user), payload is changed entity that
+// is sent with notification to connected users
+func (b *Batch) Store(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, n DR.Notification, payload interface{}, userIds ...string) error {
+	seen := map[string]bool{}
+	for _, userId := range userIds {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+		n.UserId = userId
+		nt, err := Repo.NotificationCrud.Create(ctx, n, qa, nil)
+		if err != nil {
+			return err
+		}
+		b.stored = append(b.stored, stored{notification: nt, payload: payload})
+	}
+	return nil
+}
+
+// Send publishes stored notifications to their users, it is called after transaction is committed
+func (b *Batch) Send(ctx context.Context) {
+	for _, st := range b.stored {
+		nt := st.notification
+		Publish(ctx, Event{
+			EventId:     nt.NotificationId,
+			Type:        nt.Type,
+			ReferenceId: nt.ReferenceId,
+			Message:     nt.Message,
+			Time:        nt.CreatedAt,
+			Payload:     st.payload,
+		}, nt.UserId)
+	}
+	b.stored = nil
+}
@@ -0,0 +1,55 @@
+package notify_test
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/notify"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestPublishReachesSubscribersOfUsers(t *testing.T) {
+	hub := notify.NewHub()
+	first, second := hub.Subscribe("ana"), hub.Subscribe("ana")
+	other := hub.Subscribe("bob")
+
+	notify.Publish(notify.WithHub(context.Background(), hub), notify.Event{Type: DR.NT_MATCH_FULL, ReferenceId: "match"}, "ana", "ana", "carl")
+	for _, sub := range []*notify.Subscription{first, second} {
+		select {
+		case ev := <-sub.C:
+			if ev.Type != DR.NT_MATCH_FULL || ev.ReferenceId != "match" || ev.EventId == "" || ev.Time.IsZero() {
+				t.Errorf("unexpected event %+v", ev)
+			}
+		default:
+			t.Errorf("expected event for every subscription of user")
+		}
+		if len(sub.C) != 0 {
+			t.Errorf("expected user listed twice to get event once")
+		}
+	}
+	if len(other.C) != 0 {
+		t.Errorf("expected no event for other user")
+	}
+
+	// publishing without hub in context does nothing
+	notify.Publish(context.Background(), notify.Event{Type: DR.NT_MATCH_FULL}, "ana")
+
+	first.Close()
+	if _, ok := <-first.C; ok {
+		t.Errorf("expected closed subscription")
+	}
+	first.Close()
+	hub.Close()
+	if _, ok := <-other.C; ok {
+		t.Errorf("expected subscriptions to be closed with hub")
+	}
+	if _, ok := <-hub.Subscribe("ana").C; ok {
+		t.Errorf("expected subscription of closed hub to be closed")
+	}
+}
@@ -0,0 +1,21 @@
+package rating
+
+import "math"
+
+// kFactor is maximal change of Elo rating in one match, new players converge faster
+func kFactor(matches int) float64 {
+	if matches < PROVISIONAL_MATCHES {
+		return 40
+	}
+	return 20
+}
+
+// ExpectedElo is expected score of player with rating against opponent
+func ExpectedElo(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// Elo returns rating of player after match against opponent in which player got score
+func Elo(rating, opponent, score, k float64) float64 {
+	return rating + k*(score-ExpectedElo(rating, opponent))
+}
@@ -0,0 +1,94 @@
+package rating
+
+import (
+	DR "backend/sportos/repo/dto"
+	"math"
+)
+
+const (
+	// glickoScale converts glicko ratings to glicko-2 scale
+	glickoScale = 173.7178
+	// tau constrains change of volatility, reasonable values are 0.3 to 1.2
+	tau = 0.5
+	// epsilon is convergence tolerance of volatility iteration
+	epsilon = 0.000001
+)
+
+// Result is score player got against opponent in rating period
+type Result struct {
+	Opponent DR.Rating
+	Score    float64
+}
+
+// Glicko2 returns rating of player after rating period with results, it follows
+// "Example of the Glicko-2 system" by Mark E. Glickman. Every match is rated as its own period.
+func Glicko2(player DR.Rating, results []Result) DR.Rating {
+	mu := (player.Rating - DEFAULT_RATING) / glickoScale
+	phi := player.Deviation / glickoScale
+	sigma := player.Volatility
+	if len(results) == 0 {
+		player.Deviation = math.Sqrt(phi*phi+sigma*sigma) * glickoScale
+		return player
+	}
+
+	// step 3 and 4: estimated variance and improvement
+	var vInv, delta float64
+	for _, r := range results {
+		muJ := (r.Opponent.Rating - DEFAULT_RATING) / glickoScale
+		gJ := g(r.Opponent.Deviation / glickoScale)
+		e := expected(mu, muJ, gJ)
+		vInv += gJ * gJ * e * (1 - e)
+		delta += gJ * (r.Score - e)
+	}
+	v := 1 / vInv
+	delta *= v
+
+	// step 5: new volatility by Illinois algorithm
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	sigma = math.Exp(A / 2)
+
+	// step 6 and 7: new deviation and rating
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * delta / v
+
+	player.Rating = mu*glickoScale + DEFAULT_RATING
+	player.Deviation = phi * glickoScale
+	player.Volatility = sigma
+	return player
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, muJ, gJ float64) float64 {
+	return 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+}
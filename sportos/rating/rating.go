@@ -0,0 +1,99 @@
+// Package rating calculates skill ratings of players from match results.
+//
+// Sports with one player per side use Elo. Team sports use Glicko-2 where every player is rated
+// against average of other team, so players of same team can move differently depending on how
+// certain their own rating is.
+package rating
+
+import (
+	DR "backend/sportos/repo/dto"
+	"math"
+)
+
+const (
+	// DEFAULT_RATING is rating of player before first rated match in sport
+	DEFAULT_RATING = 1500.0
+	// DEFAULT_DEVIATION is glicko-2 deviation of unrated player
+	DEFAULT_DEVIATION = 350.0
+	// DEFAULT_VOLATILITY is glicko-2 volatility of unrated player
+	DEFAULT_VOLATILITY = 0.06
+	// PROVISIONAL_MATCHES is number of matches in which Elo rating changes faster
+	PROVISIONAL_MATCHES = 30
+)
+
+// Scores of side, draw is half of win
+const (
+	LOSS = 0.0
+	DRAW = 0.5
+	WIN  = 1.0
+)
+
+// SystemOf returns rating system used for sport
+func SystemOf(sport DR.Sport) DR.RatingSystem {
+	if sport.TeamSize > 1 {
+		return DR.RS_GLICKO2
+	}
+	return DR.RS_ELO
+}
+
+// New returns rating of player who didn't play rated match in sport yet
+func New(playerId string, sport DR.Sport) DR.Rating {
+	return DR.Rating{
+		PlayerId:   playerId,
+		Sport:      sport.Name,
+		System:     SystemOf(sport),
+		Rating:     DEFAULT_RATING,
+		Deviation:  DEFAULT_DEVIATION,
+		Volatility: DEFAULT_VOLATILITY,
+	}
+}
+
+// Scores returns score of first and second side from points of match
+func Scores(pointsFirst, pointsSecond int) (float64, float64) {
+	switch {
+	case pointsFirst > pointsSecond:
+		return WIN, LOSS
+	case pointsFirst < pointsSecond:
+		return LOSS, WIN
+	default:
+		return DRAW, DRAW
+	}
+}
+
+// Match returns ratings of both sides after match in which first side got score (second side got 1-score).
+// All players of sides must be rated in same system, returned ratings have one more match.
+func Match(system DR.RatingSystem, sides [2][]DR.Rating, score float64) [2][]DR.Rating {
+	var after [2][]DR.Rating
+	scores := [2]float64{score, 1 - score}
+	opponents := [2]DR.Rating{average(sides[1]), average(sides[0])}
+	for side := 0; side < 2; side++ {
+		for _, player := range sides[side] {
+			switch system {
+			case DR.RS_GLICKO2:
+				player = Glicko2(player, []Result{{Opponent: opponents[side], Score: scores[side]}})
+			default:
+				player.Rating = Elo(player.Rating, opponents[side].Rating, scores[side], kFactor(player.Matches))
+			}
+			player.Matches++
+			after[side] = append(after[side], player)
+		}
+	}
+	return after
+}
+
+// average returns rating with average rating of players and deviation that is root mean square
+// of their deviations, it is opponent against which players of other team are rated
+func average(players []DR.Rating) DR.Rating {
+	avg := DR.Rating{Rating: DEFAULT_RATING, Deviation: DEFAULT_DEVIATION, Volatility: DEFAULT_VOLATILITY}
+	if len(players) == 0 {
+		return avg
+	}
+	var rating, variance float64
+	for _, p := range players {
+		rating += p.Rating
+		variance += p.Deviation * p.Deviation
+	}
+	avg.Rating = rating / float64(len(players))
+	avg.Deviation = math.Sqrt(variance / float64(len(players)))
+	return avg
+}
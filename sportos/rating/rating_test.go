@@ -0,0 +1,84 @@
+package rating_test
+
+import (
+	"backend/sportos/rating"
+	DR "backend/sportos/repo/dto"
+	"math"
+	"testing"
+)
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+// TestGlicko2PaperExample checks calculation from "Example of the Glicko-2 system" by Mark E. Glickman
+func TestGlicko2PaperExample(t *testing.T) {
+	player := DR.Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	after := rating.Glicko2(player, []rating.Result{
+		{Opponent: DR.Rating{Rating: 1400, Deviation: 30}, Score: rating.WIN},
+		{Opponent: DR.Rating{Rating: 1550, Deviation: 100}, Score: rating.LOSS},
+		{Opponent: DR.Rating{Rating: 1700, Deviation: 300}, Score: rating.LOSS},
+	})
+	if !near(after.Rating, 1464.06, 0.01) || !near(after.Deviation, 151.52, 0.01) || !near(after.Volatility, 0.05999, 0.00001) {
+		t.Errorf("expected 1464.06, 151.52, 0.05999, got %.2f, %.2f, %.5f", after.Rating, after.Deviation, after.Volatility)
+	}
+}
+
+func TestEloMatch(t *testing.T) {
+	tennis, _ := DR.GetSportByName("Tennis")
+	if rating.SystemOf(tennis) != DR.RS_ELO {
+		t.Fatalf("expected elo for tennis")
+	}
+	strong := rating.New("a", tennis)
+	strong.Rating = 1700
+	strong.Matches = rating.PROVISIONAL_MATCHES
+	weak := rating.New("b", tennis)
+
+	after := rating.Match(DR.RS_ELO, [2][]DR.Rating{{strong}, {weak}}, rating.WIN)
+	won := after[0][0].Rating - strong.Rating
+	if won <= 0 || won >= 10 || after[0][0].Matches != strong.Matches+1 || after[1][0].Matches != 1 {
+		t.Errorf("expected small gain of favourite, got %+v", after)
+	}
+	upset := rating.Match(DR.RS_ELO, [2][]DR.Rating{{strong}, {weak}}, rating.LOSS)
+	if lost := strong.Rating - upset[0][0].Rating; lost <= won {
+		t.Errorf("expected favourite to lose more by upset (%.2f) than it gains by win (%.2f)", lost, won)
+	}
+	draw := rating.Match(DR.RS_ELO, [2][]DR.Rating{{weak}, {weak}}, rating.DRAW)
+	if draw[0][0].Rating != weak.Rating || draw[1][0].Rating != weak.Rating {
+		t.Errorf("expected draw of equal players not to change rating, got %+v", draw)
+	}
+}
+
+func TestGlicko2TeamMatch(t *testing.T) {
+	basketball, _ := DR.GetSportByName("Basketball")
+	if rating.SystemOf(basketball) != DR.RS_GLICKO2 {
+		t.Fatalf("expected glicko-2 for basketball")
+	}
+	veteran := rating.New("veteran", basketball)
+	veteran.Deviation = 60
+	rookie := rating.New("rookie", basketball)
+	opponents := []DR.Rating{rating.New("c", basketball), rating.New("d", basketball)}
+
+	after := rating.Match(DR.RS_GLICKO2, [2][]DR.Rating{{veteran, rookie}, opponents}, rating.WIN)
+	v, r := after[0][0], after[0][1]
+	if v.Rating <= veteran.Rating || r.Rating <= v.Rating {
+		t.Errorf("expected both winners to gain and uncertain rookie to gain more, got %+v %+v", v, r)
+	}
+	if r.Deviation >= rookie.Deviation {
+		t.Errorf("expected deviation of rookie to shrink, got %.2f", r.Deviation)
+	}
+	for _, loser := range after[1] {
+		if loser.Rating >= rating.DEFAULT_RATING || loser.Matches != 1 {
+			t.Errorf("expected loser to drop, got %+v", loser)
+		}
+	}
+}
+
+func TestScores(t *testing.T) {
+	if a, b := rating.Scores(3, 1); a != rating.WIN || b != rating.LOSS {
+		t.Errorf("expected win of first side, got %v %v", a, b)
+	}
+	if a, b := rating.Scores(2, 2); a != rating.DRAW || b != rating.DRAW {
+		t.Errorf("expected draw, got %v %v", a, b)
+	}
+}
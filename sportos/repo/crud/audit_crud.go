@@ -47,6 +47,19 @@ func (r *AuditCrud) CreateSnapshot(ctx context.Context, old, new DR.CommonEntity
 	if !r.Enabled {
 		return DR.Audit{}, nil
 	}
+	auditVal, err := NewAuditSnapshot(ctx, old, new)
+	if err != nil {
+		return DR.Audit{}, err
+	}
+	ret := DR.Audit{}
+	if len(auditVal.Old) > 0 || len(auditVal.New) > 0 {
+		ret, err = r.Create(ctx, auditVal, qa, by)
+	}
+	return ret, err
+}
+
+// NewAuditSnapshot returns audit with changed columns of entity, audit has neither old nor new values if nothing changed
+func NewAuditSnapshot(ctx context.Context, old, new DR.CommonEntity) (DR.Audit, error) {
 	var auditNew = make(map[string]interface{})
 	var auditOld = make(map[string]interface{})
 	var id string
@@ -99,19 +112,14 @@ func (r *AuditCrud) CreateSnapshot(ctx context.Context, old, new DR.CommonEntity
 	if val, ok := ctx.Value(sportos.CONTEXT_API_JOURNAL_ID_KEY).(string); ok {
 		apiJournalId = &val
 	}
-	auditVal := DR.Audit{
+	return DR.Audit{
 		Entity:       name,
 		EntityId:     id,
 		CrudAction:   &op,
 		ApiJournalId: apiJournalId,
 		Old:          auditOld,
 		New:          auditNew,
-	}
-	ret, err := DR.Audit{}, error(nil)
-	if len(auditVal.Old) > 0 || len(auditVal.New) > 0 {
-		ret, err = r.Create(ctx, auditVal, qa, by)
-	}
-	return ret, err
+	}, nil
 }
 
 // Creates an audit
@@ -0,0 +1,251 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrBookingOverlap is returned when confirmed booking would overlap other confirmed booking of same owner
+var ErrBookingOverlap = errors.New("booking overlaps other booking")
+
+// pq code of exclusion_violation, it is raised by booking_no_overlap constraint
+const pqExclusionViolation = "23P01"
+
+type BookingCrud struct {
+	Crud
+}
+
+func InitBookingCrud(db *sql.DB) *BookingCrud {
+	return &BookingCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	booking_select = `
+		select bo.booking_id, bo.owner_id, bo.kind, bo.reference_id, lower(bo.during), upper(bo.during), bo.status, bo.created_at, bo.created_by, bo.updated_at, bo.updated_by, bo.deleted_at, bo.deleted_by
+		from booking bo
+	`
+	booking_count = `select count(*) from booking bo `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanBooking(row interface{ Scan(...interface{}) error }, bo *DR.Booking) error {
+	return row.Scan(&bo.BookingId, &bo.OwnerId, &bo.Kind, &bo.ReferenceId, &bo.StartTime, &bo.EndTime, &bo.Status, &bo.CreatedAt, &bo.CreatedBy, &bo.UpdatedAt, &bo.UpdatedBy, &bo.DeletedAt, &bo.DeletedBy)
+}
+
+// overlapError logs err and replaces exclusion violation with ErrBookingOverlap
+func overlapError(ctx context.Context, err error) error {
+	if code, _ := util.LogPqError(ctx, err); code == pqExclusionViolation {
+		return ErrBookingOverlap
+	}
+	return err
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a Booking, ErrBookingOverlap is returned if confirmed booking overlaps other confirmed booking of owner
+func (r *BookingCrud) Create(ctx context.Context, en DR.Booking, qa QueryAble, by *string) (DR.Booking, error) {
+	L.L.WithRequestID(ctx).Info("BookingCrud.Create", L.Any("booking", en))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	query := `insert into booking (owner_id, kind, reference_id, during, status, created_at, created_by)
+	values ($1, $2, $3, tstzrange($4, $5, '[)'), $6, $7, $8) RETURNING booking_id;`
+	params := []interface{}{en.OwnerId, en.Kind, en.ReferenceId, en.StartTime, en.EndTime, en.Status, en.CreatedAt, en.CreatedBy}
+
+	L.L.Debug("BookingCrud.Create insert", L.String("query", query), L.Any("params", params))
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.BookingId)
+	if err != nil {
+		return en, overlapError(ctx, err)
+	}
+	pen, err := r.GetById(ctx, en.BookingId, qa)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, nil, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns booking by id
+func (r *BookingCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Booking, error) {
+	L.L.WithRequestID(ctx).Info("BookingCrud.GetById", L.String("id", id))
+
+	db := r.GetTx(qa)
+
+	bo := DR.Booking{}
+	query := ""
+	if qa != nil {
+		query = booking_select +
+			`where bo.booking_id=$1 for update`
+	} else {
+		query = booking_select +
+			`where bo.booking_id=$1`
+	}
+	row := db.QueryRowContext(ctx, query,
+		id)
+
+	err := scanBooking(row, &bo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("booking does not exist for id: %v", id)
+		}
+	}
+
+	return bo, err
+}
+
+func (r *BookingCrud) GetCount(ctx context.Context, sp DR.BookingSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("BookingCrud.GetCount", L.Any("booking", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := booking_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("BookingCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	defer rows.Close()
+
+	cnt := 0
+	for rows.Next() {
+		err := rows.Scan(&cnt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return cnt, nil
+}
+
+// Search returns bookings ordered by start time
+func (r *BookingCrud) Search(ctx context.Context, sp DR.BookingSearchParams, qa QueryAble) ([]DR.Booking, error) {
+	L.L.WithRequestID(ctx).Info("BookingCrud.Search", L.Any("booking", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.Booking{}
+	var params []interface{}
+
+	query := booking_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("BookingCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		bo := DR.Booking{}
+		err := scanBooking(rows, &bo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, bo)
+	}
+
+	if len(results) == 0 {
+		L.L.WithRequestID(ctx).Warn("BookingCrud.Search No rows returned ")
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a booking, ErrBookingOverlap is returned if booking is confirmed while other confirmed booking overlaps it
+func (r *BookingCrud) Update(ctx context.Context, up DR.BookingUpdateParams, qa QueryAble, by *string) (DR.Booking, error) {
+	L.L.WithRequestID(ctx).Info("BookingCrud.Update", L.Any("booking", up))
+
+	up.PopulateUpdateFields(by)
+
+	old, _ := r.GetById(ctx, up.Id, qa)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("BookingCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		return DR.Booking{}, overlapError(ctx, err)
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return DR.Booking{}, fmt.Errorf("no rows affected")
+	}
+	pen, err := r.GetById(ctx, up.Id, qa)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a booking, deleted bookings don't occupy time of owner
+func (r *BookingCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("BookingCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `booking`, "booking_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
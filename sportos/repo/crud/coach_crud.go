@@ -23,7 +23,7 @@ func InitCoachCrud(db *sql.DB) *CoachCrud {
 
 const (
 	coach_select = `
-		select co.user_id, co.name, co.city, co.sport, co.reviews, co.booking, co.created_at, co.created_by, co.updated_at, co.updated_by, co.deleted_at, co.deleted_by
+		select co.user_id, co.name, co.city, co.sport, co.reviews, co.schedule, co.time_zone, co.created_at, co.created_by, co.updated_at, co.updated_by, co.deleted_at, co.deleted_by
 		from coach co
 	`
 	coach_count = `select count(*) from coach co `
@@ -63,9 +63,13 @@ func (r *CoachCrud) Create(ctx context.Context, en DR.Coach, qa QueryAble, by *s
 		return en, fmt.Errorf("user with username %s already exists", en.Username)
 	}
 
-	query := `insert into coach (user_id, name, city, sport, created_at, created_by)
-	values ($1, $2, $3, $4, $5, $6) RETURNING user_id;`
-	params := []interface{}{en.Username, en.Name, en.City, en.Sport, en.CreatedAt, en.CreatedBy}
+	if en.TimeZone == "" {
+		en.TimeZone = DR.DEFAULT_TIME_ZONE
+	}
+
+	query := `insert into coach (user_id, name, city, sport, time_zone, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7) RETURNING user_id;`
+	params := []interface{}{en.Username, en.Name, en.City, en.Sport, en.TimeZone, en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("CoachCrud.Create insert", L.String("query", query), L.Any("params", params))
 
@@ -108,7 +112,7 @@ func (r *CoachCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Co
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Booking, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
+	err := row.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Schedule, &co.TimeZone, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("coach does not exist for username: %v", id)
@@ -136,7 +140,7 @@ func (r *CoachCrud) GetByEmail(ctx context.Context, email string, qa QueryAble)
 	row := db.QueryRowContext(ctx, query,
 		email)
 
-	err := row.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Booking, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
+	err := row.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Schedule, &co.TimeZone, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("coach does not exist for email: %v", email)
@@ -205,7 +209,7 @@ func (r *CoachCrud) Search(ctx context.Context, sp DR.CoachSearchParams, qa Quer
 
 	for rows.Next() {
 		co := DR.Coach{}
-		err := rows.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Booking, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
+		err := rows.Scan(&co.Username, &co.Name, &co.City, &co.Sport, &co.Reviews, &co.Schedule, &co.TimeZone, &co.CreatedAt, &co.CreatedBy, &co.UpdatedAt, &co.UpdatedBy, &co.DeletedAt, &co.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -259,3 +263,23 @@ func (r *CoachCrud) Update(ctx context.Context, up DR.CoachUpdateParams, qa Quer
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a coach
+func (r *CoachCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("CoachCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `coach`, "user_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -2,8 +2,13 @@
 package crud
 
 import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 )
 
 type Crud struct {
@@ -22,7 +27,7 @@ func isQaNil(qa QueryAble) bool {
 	case (*sql.DB):
 		return v == nil
 	}
-	return true
+	return qa == nil
 }
 
 func (c *Crud) GetTx(qa QueryAble) QueryAble {
@@ -32,6 +37,31 @@ func (c *Crud) GetTx(qa QueryAble) QueryAble {
 	return c.db
 }
 
+// softDelete sets deleted_at and deleted_by of row that isn't deleted yet, rows stay in table
+// so they can still be read by id and audited
+func (c *Crud) softDelete(ctx context.Context, table, idColumn, id string, qa QueryAble, by *string) error {
+	ei := DR.EditInfoD{}
+	ei.PopulateDeleteFields(by)
+
+	db := c.GetTx(qa)
+	query := fmt.Sprintf(`update %s set deleted_at = $1, deleted_by = $2 where %s = $3 and deleted_at is null`, table, idColumn)
+	params := []interface{}{ei.DeletedAt, ei.DeletedBy, id}
+
+	L.L.Debug("Crud.softDelete update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
+
 type Creator interface {
 	Create(ctx context.Context, entity interface{}, qa QueryAble, by *string) (interface{}, error)
 }
@@ -41,7 +71,7 @@ type Updater interface {
 }
 
 type Deleter interface {
-	Update(ctx context.Context, entity interface{}, qa QueryAble, by *string) error
+	Delete(ctx context.Context, id string, qa QueryAble, by *string) error
 }
 
 type Searcher interface {
@@ -71,3 +101,56 @@ type QueryAble interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
+
+// memoryTx is transaction of in memory repo, it isn't backed by database so every statement fails,
+// memory stores ignore it. Commit and rollback are done by functions of in memory store, see NewMemoryTx
+type memoryTx struct {
+	commit   func() error
+	rollback func() error
+}
+
+var errMemoryTx = errors.New("in memory transaction can't execute sql")
+
+// NewMemoryTx returns transaction of in memory repo that calls commit or rollback when it ends
+func NewMemoryTx(commit, rollback func() error) Tx {
+	return memoryTx{commit: commit, rollback: rollback}
+}
+
+func (tx memoryTx) Commit() error {
+	if tx.commit == nil {
+		return nil
+	}
+	return tx.commit()
+}
+
+func (tx memoryTx) Rollback() error {
+	if tx.rollback == nil {
+		return nil
+	}
+	return tx.rollback()
+}
+
+func (memoryTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) Prepare(query string) (*sql.Stmt, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errMemoryTx
+}
+func (memoryTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return nil
+}
+func (memoryTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
@@ -0,0 +1,229 @@
+package crud
+
+import (
+	DR "backend/sportos/repo/dto"
+	"context"
+)
+
+// Store interfaces are implemented by postgres cruds in this package and by in memory store (repo/memory),
+// handlers use only these interfaces through Repo
+
+type PlayerStore interface {
+	Create(ctx context.Context, en DR.Player, qa QueryAble, by *string) (DR.Player, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Player, error)
+	GetByEmail(ctx context.Context, email string, qa QueryAble) (DR.Player, error)
+	GetCount(ctx context.Context, sp DR.PlayerSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.PlayerSearchParams, qa QueryAble) ([]DR.Player, error)
+	Update(ctx context.Context, up DR.PlayerUpdateParams, qa QueryAble, by *string) (DR.Player, error)
+	Deleter
+}
+
+type CoachStore interface {
+	Create(ctx context.Context, en DR.Coach, qa QueryAble, by *string) (DR.Coach, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Coach, error)
+	GetByEmail(ctx context.Context, email string, qa QueryAble) (DR.Coach, error)
+	GetCount(ctx context.Context, sp DR.CoachSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.CoachSearchParams, qa QueryAble) ([]DR.Coach, error)
+	Update(ctx context.Context, up DR.CoachUpdateParams, qa QueryAble, by *string) (DR.Coach, error)
+	Deleter
+}
+
+type PlaceStore interface {
+	Create(ctx context.Context, en DR.Place, qa QueryAble, by *string) (DR.Place, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Place, error)
+	GetByEmail(ctx context.Context, email string, qa QueryAble) (DR.Place, error)
+	GetCount(ctx context.Context, sp DR.PlaceSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.PlaceSearchParams, qa QueryAble) ([]DR.Place, error)
+	Update(ctx context.Context, up DR.PlaceUpdateParams, qa QueryAble, by *string) (DR.Place, error)
+	Deleter
+}
+
+type UserStore interface {
+	Create(ctx context.Context, en DR.User, qa QueryAble, by *string) (DR.User, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.User, error)
+	GetByEmail(ctx context.Context, email string, qa QueryAble) (DR.User, error)
+	GetCount(ctx context.Context, sp DR.UserSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.UserSearchParams, qa QueryAble) ([]DR.User, error)
+	Update(ctx context.Context, up DR.UserUpdateParams, qa QueryAble, by *string) (DR.User, error)
+	Deleter
+}
+
+type EventStore interface {
+	Create(ctx context.Context, en DR.Event, qa QueryAble, by *string) (DR.Event, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Event, error)
+	GetCount(ctx context.Context, sp DR.EventSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.EventSearchParams, qa QueryAble) ([]DR.Event, error)
+	Update(ctx context.Context, up DR.EventUpdateParams, qa QueryAble, by *string) (DR.Event, error)
+	Deleter
+}
+
+type MatchStore interface {
+	Create(ctx context.Context, en DR.Match, qa QueryAble, by *string) (DR.Match, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Match, error)
+	GetCount(ctx context.Context, sp DR.MatchSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.MatchSearchParams, qa QueryAble) ([]DR.Match, error)
+	Update(ctx context.Context, up DR.MatchUpdateParams, qa QueryAble, by *string) (DR.Match, error)
+	Deleter
+}
+
+type PracticeStore interface {
+	Create(ctx context.Context, en DR.Practice, qa QueryAble, by *string) (DR.Practice, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Practice, error)
+	GetCount(ctx context.Context, sp DR.PracticeSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.PracticeSearchParams, qa QueryAble) ([]DR.Practice, error)
+	Update(ctx context.Context, up DR.PracticeUpdateParams, qa QueryAble, by *string) (DR.Practice, error)
+	Deleter
+}
+
+// BookingStore returns ErrBookingOverlap when confirmed bookings of owner would overlap
+type BookingStore interface {
+	Create(ctx context.Context, en DR.Booking, qa QueryAble, by *string) (DR.Booking, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Booking, error)
+	GetCount(ctx context.Context, sp DR.BookingSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.BookingSearchParams, qa QueryAble) ([]DR.Booking, error)
+	Update(ctx context.Context, up DR.BookingUpdateParams, qa QueryAble, by *string) (DR.Booking, error)
+	Deleter
+}
+
+type TeamStore interface {
+	CheckConstraints(ctx context.Context, te DR.Team, qa QueryAble) bool
+	Create(ctx context.Context, en DR.Team, qa QueryAble, by *string) (DR.Team, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Team, error)
+	GetCount(ctx context.Context, sp DR.TeamSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.TeamSearchParams, qa QueryAble) ([]DR.Team, error)
+	Update(ctx context.Context, up DR.TeamUpdateParams, qa QueryAble, by *string) (DR.Team, error)
+	Deleter
+}
+
+type MatchPlayerStore interface {
+	Create(ctx context.Context, en DR.MatchPlayer, qa QueryAble, by *string) (DR.MatchPlayer, error)
+	GetById(ctx context.Context, matchId, playerId string, qa QueryAble) (DR.MatchPlayer, error)
+	Search(ctx context.Context, sp DR.MatchPlayerSearchParams, qa QueryAble) ([]DR.MatchPlayer, error)
+	Update(ctx context.Context, up DR.MatchPlayerUpdateParams, qa QueryAble, by *string) (DR.MatchPlayer, error)
+}
+
+type TeamMemberStore interface {
+	Create(ctx context.Context, en DR.TeamMember, qa QueryAble, by *string) (DR.TeamMember, error)
+	GetById(ctx context.Context, teamId, playerId string, qa QueryAble) (DR.TeamMember, error)
+	Search(ctx context.Context, sp DR.TeamMemberSearchParams, qa QueryAble) ([]DR.TeamMember, error)
+}
+
+type UserPostStore interface {
+	Create(ctx context.Context, en DR.UserPost, qa QueryAble, by *string) (DR.UserPost, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.UserPost, error)
+	GetCount(ctx context.Context, sp DR.UserPostSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.UserPostSearchParams, qa QueryAble) ([]DR.UserPost, error)
+	Deleter
+}
+
+type ApiJournalStore interface {
+	Create(ctx context.Context, en DR.ApiJournal, qa QueryAble, by *string) (DR.ApiJournal, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.ApiJournal, error)
+	GetCount(ctx context.Context, sp DR.ApiJournalSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.ApiJournalSearchParams, qa QueryAble) ([]DR.ApiJournal, error)
+	Update(ctx context.Context, up DR.ApiJournalUpdateParams, qa QueryAble, by *string) (DR.ApiJournal, error)
+}
+
+type AuditStore interface {
+	Start()
+	Stop()
+	CreateSnapshot(ctx context.Context, old, new DR.CommonEntity, qa QueryAble, by *string) (DR.Audit, error)
+	Create(ctx context.Context, en DR.Audit, qa QueryAble, by *string) (DR.Audit, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Audit, error)
+	GetCount(ctx context.Context, sp DR.AuditSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.AuditSearchParams, qa QueryAble) ([]DR.Audit, error)
+}
+
+type VerificationTokenStore interface {
+	Create(ctx context.Context, en DR.VerificationToken, qa QueryAble, by *string) (DR.VerificationToken, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.VerificationToken, error)
+	GetValid(ctx context.Context, id string, purpose DR.TokenPurpose, qa QueryAble) (DR.VerificationToken, error)
+	GetCount(ctx context.Context, sp DR.VerificationTokenSearchParams, qa QueryAble) (int, error)
+	Consume(ctx context.Context, id string, purpose DR.TokenPurpose, qa QueryAble) (DR.VerificationToken, error)
+	Invalidate(ctx context.Context, userId string, purpose DR.TokenPurpose, qa QueryAble) error
+}
+
+type MailOutboxStore interface {
+	Create(ctx context.Context, en DR.MailOutbox, qa QueryAble, by *string) (DR.MailOutbox, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.MailOutbox, error)
+	GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.MailOutbox, error)
+	Update(ctx context.Context, up DR.MailOutboxUpdateParams, qa QueryAble, by *string) error
+}
+
+type OutboxEventStore interface {
+	Create(ctx context.Context, en DR.OutboxEvent, qa QueryAble, by *string) (DR.OutboxEvent, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.OutboxEvent, error)
+	GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.OutboxEvent, error)
+	Update(ctx context.Context, up DR.OutboxEventUpdateParams, qa QueryAble, by *string) error
+}
+
+type WebhookStore interface {
+	Create(ctx context.Context, en DR.Webhook, qa QueryAble, by *string) (DR.Webhook, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Webhook, error)
+	GetCount(ctx context.Context, sp DR.WebhookSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.WebhookSearchParams, qa QueryAble) ([]DR.Webhook, error)
+	Update(ctx context.Context, up DR.WebhookUpdateParams, qa QueryAble, by *string) (DR.Webhook, error)
+	Delete(ctx context.Context, id string, qa QueryAble, by *string) error
+}
+
+type WebhookDeliveryStore interface {
+	Create(ctx context.Context, en DR.WebhookDelivery, qa QueryAble, by *string) (DR.WebhookDelivery, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.WebhookDelivery, error)
+	GetCount(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa QueryAble) ([]DR.WebhookDelivery, error)
+	GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.WebhookDelivery, error)
+	Update(ctx context.Context, up DR.WebhookDeliveryUpdateParams, qa QueryAble, by *string) error
+}
+
+type MatchResultStore interface {
+	Create(ctx context.Context, en DR.MatchResult, qa QueryAble, by *string) (DR.MatchResult, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.MatchResult, error)
+	GetCount(ctx context.Context, sp DR.MatchResultSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.MatchResultSearchParams, qa QueryAble) ([]DR.MatchResult, error)
+	GetExpired(ctx context.Context, limit int, qa QueryAble) ([]DR.MatchResult, error)
+	Update(ctx context.Context, up DR.MatchResultUpdateParams, qa QueryAble, by *string) error
+}
+
+type RatingStore interface {
+	GetOrCreate(ctx context.Context, en DR.Rating, qa QueryAble, by *string) (DR.Rating, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Rating, error)
+	GetCount(ctx context.Context, sp DR.RatingSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.RatingSearchParams, qa QueryAble) ([]DR.Rating, error)
+	Update(ctx context.Context, up DR.RatingUpdateParams, qa QueryAble, by *string) (DR.Rating, error)
+}
+
+type RatingHistoryStore interface {
+	Create(ctx context.Context, en DR.RatingHistory, qa QueryAble, by *string) (DR.RatingHistory, bool, error)
+	GetCount(ctx context.Context, sp DR.RatingHistorySearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.RatingHistorySearchParams, qa QueryAble) ([]DR.RatingHistory, error)
+}
+
+type PlayerMatchStore interface {
+	Create(ctx context.Context, en DR.PlayerMatch, qa QueryAble, by *string) error
+	GetCount(ctx context.Context, sp DR.PlayerMatchSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.PlayerMatchSearchParams, qa QueryAble) ([]DR.PlayerMatch, error)
+	Summary(ctx context.Context, playerId, sport string, qa QueryAble) (DR.PlayerMatchSummary, error)
+	Monthly(ctx context.Context, playerId, sport string, qa QueryAble) ([]DR.PlayerMatchMonth, error)
+	Group(ctx context.Context, sp DR.PlayerMatchSearchParams, by DR.PlayerMatchGroupBy, qa QueryAble) ([]DR.PlayerMatchGroup, error)
+}
+
+type PlayerTournamentStore interface {
+	Create(ctx context.Context, en DR.PlayerTournament, qa QueryAble, by *string) error
+	GetCount(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa QueryAble) ([]DR.PlayerTournament, error)
+}
+
+type LeaderboardStore interface {
+	Upsert(ctx context.Context, en DR.Leaderboard, qa QueryAble) error
+	GetCount(ctx context.Context, sp DR.LeaderboardSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.LeaderboardSearchParams, qa QueryAble) ([]DR.Leaderboard, error)
+}
+
+type NotificationStore interface {
+	Create(ctx context.Context, en DR.Notification, qa QueryAble, by *string) (DR.Notification, error)
+	GetById(ctx context.Context, id string, qa QueryAble) (DR.Notification, error)
+	GetCount(ctx context.Context, sp DR.NotificationSearchParams, qa QueryAble) (int, error)
+	Search(ctx context.Context, sp DR.NotificationSearchParams, qa QueryAble) ([]DR.Notification, error)
+	Update(ctx context.Context, up DR.NotificationUpdateParams, qa QueryAble, by *string) (DR.Notification, error)
+	MarkAllRead(ctx context.Context, userId string, qa QueryAble, by *string) (int, error)
+}
@@ -63,9 +63,9 @@ func (r *EventCrud) Create(ctx context.Context, en DR.Event, qa QueryAble, by *s
 		return en, fmt.Errorf("event with name %s already exists for place %s", en.Name, en.Owner)
 	}
 
-	query := `insert into event (name, owner_id, sport, status, time, created_at, created_by)
-	values ($1, $2, $3, $4, $5, $6, $7) RETURNING event_id;`
-	params := []interface{}{en.Name, en.Owner, en.Sport, en.Status, en.Time, en.CreatedAt, en.CreatedBy}
+	query := `insert into event (name, owner_id, sport, status, time, tournament, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING event_id;`
+	params := []interface{}{en.Name, en.Owner, en.Sport, en.Status, en.Time, en.Tournament, en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("EventCrud.Create insert", L.String("query", query), L.Any("params", params))
 
@@ -231,3 +231,23 @@ func (r *EventCrud) Update(ctx context.Context, up DR.EventUpdateParams, qa Quer
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a event
+func (r *EventCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("EventCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `event`, "event_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,129 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type LeaderboardCrud struct {
+	Crud
+}
+
+func InitLeaderboardCrud(db *sql.DB) *LeaderboardCrud {
+	return &LeaderboardCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	leaderboard_select = `
+		select lb.player_id, pl.name, pl.city, lb.sport, lb.win_ratio, lb.matches, lb.tournaments, lb.tournament_points, lb.rating, lb.updated_at
+		from leaderboard lb
+	`
+	leaderboard_count = `select count(*) from leaderboard lb `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanLeaderboard(row interface{ Scan(...interface{}) error }, lb *DR.Leaderboard) error {
+	var name, city sql.NullString
+	err := row.Scan(&lb.PlayerId, &name, &city, &lb.Sport, &lb.WinRatio, &lb.Matches, &lb.Tournaments, &lb.TournamentPoints, &lb.Rating, &lb.UpdatedAt)
+	lb.Name, lb.City = name.String, city.String
+	return err
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Upsert stores leaderboard row of player in sport, existing row is replaced
+func (r *LeaderboardCrud) Upsert(ctx context.Context, en DR.Leaderboard, qa QueryAble) error {
+	L.L.WithRequestID(ctx).Info("LeaderboardCrud.Upsert", L.String("playerId", en.PlayerId), L.String("sport", en.Sport))
+
+	db := r.GetTx(qa)
+
+	if en.UpdatedAt.IsZero() {
+		en.UpdatedAt = time.Now().UTC()
+	}
+
+	query := `insert into leaderboard (player_id, sport, win_ratio, matches, tournaments, tournament_points, rating, updated_at)
+	values ($1, $2, $3, $4, $5, $6, $7, $8)
+	on conflict (player_id, sport) do update set win_ratio = excluded.win_ratio, matches = excluded.matches, tournaments = excluded.tournaments,
+		tournament_points = excluded.tournament_points, rating = excluded.rating, updated_at = excluded.updated_at;`
+	params := []interface{}{en.PlayerId, en.Sport, en.WinRatio, en.Matches, en.Tournaments, en.TournamentPoints, en.Rating, en.UpdatedAt}
+
+	L.L.Debug("LeaderboardCrud.Upsert insert", L.String("query", query), L.Any("params", params))
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return err
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+func (r *LeaderboardCrud) GetCount(ctx context.Context, sp DR.LeaderboardSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("LeaderboardCrud.GetCount", L.Any("leaderboard", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := leaderboard_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("LeaderboardCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns leaderboard from best player by metric of search params
+func (r *LeaderboardCrud) Search(ctx context.Context, sp DR.LeaderboardSearchParams, qa QueryAble) ([]DR.Leaderboard, error) {
+	L.L.WithRequestID(ctx).Info("LeaderboardCrud.Search", L.Any("leaderboard", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.Leaderboard{}
+	var params []interface{}
+
+	query := leaderboard_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("LeaderboardCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		lb := DR.Leaderboard{}
+		err := scanLeaderboard(rows, &lb)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, lb)
+	}
+	return results, nil
+}
@@ -0,0 +1,157 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type MailOutboxCrud struct {
+	Crud
+}
+
+func InitMailOutboxCrud(db *sql.DB) *MailOutboxCrud {
+	return &MailOutboxCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	mail_outbox_select = `
+		select mo.mail_id, mo.recipients, mo.subject, mo.text_body, mo.html_body, mo.status, mo.attempts, mo.next_attempt_at, mo.last_error, mo.sent_at,
+		mo.created_at, mo.created_by, mo.updated_at, mo.updated_by
+		from mail_outbox mo
+	`
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanMailOutbox(row interface{ Scan(...interface{}) error }, mo *DR.MailOutbox) error {
+	return row.Scan(&mo.MailId, pq.Array(&mo.Recipients), &mo.Subject, &mo.TextBody, &mo.HtmlBody, &mo.Status, &mo.Attempts, &mo.NextAttemptAt, &mo.LastError, &mo.SentAt,
+		&mo.CreatedAt, &mo.CreatedBy, &mo.UpdatedAt, &mo.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a mail in outbox, it is sent by outbox worker after transaction commits
+func (r *MailOutboxCrud) Create(ctx context.Context, en DR.MailOutbox, qa QueryAble, by *string) (DR.MailOutbox, error) {
+	L.L.WithRequestID(ctx).Info("MailOutboxCrud.Create", L.Any("recipients", en.Recipients), L.String("subject", en.Subject))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.MOS_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+
+	query := `insert into mail_outbox (recipients, subject, text_body, html_body, status, attempts, next_attempt_at, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING mail_id;`
+	params := []interface{}{pq.Array(en.Recipients), en.Subject, en.TextBody, en.HtmlBody, en.Status, en.Attempts, en.NextAttemptAt, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.MailId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns mail from outbox by id
+func (r *MailOutboxCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.MailOutbox, error) {
+	L.L.WithRequestID(ctx).Info("MailOutboxCrud.GetById", L.String("mailId", id))
+
+	db := r.GetTx(qa)
+
+	mo := DR.MailOutbox{}
+	query := ""
+	if qa != nil {
+		query = mail_outbox_select +
+			`where mo.mail_id=$1 for update`
+	} else {
+		query = mail_outbox_select +
+			`where mo.mail_id=$1`
+	}
+
+	err := scanMailOutbox(db.QueryRowContext(ctx, query, id), &mo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("mail does not exist for id: %v", id)
+		}
+	}
+	return mo, err
+}
+
+// GetPending returns pending mails that are due for sending and locks them,
+// rows locked by other workers are skipped
+func (r *MailOutboxCrud) GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.MailOutbox, error) {
+	db := r.GetTx(qa)
+
+	query := mail_outbox_select +
+		`where mo.status=$1 and mo.next_attempt_at<=$2 order by mo.next_attempt_at limit $3`
+	if qa != nil {
+		query += ` for update skip locked`
+	}
+
+	rows, err := db.QueryContext(ctx, query, DR.MOS_PENDING, time.Now().UTC(), limit)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.MailOutbox{}
+	for rows.Next() {
+		mo := DR.MailOutbox{}
+		err := scanMailOutbox(rows, &mo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mo)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a mail in outbox
+func (r *MailOutboxCrud) Update(ctx context.Context, up DR.MailOutboxUpdateParams, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("MailOutboxCrud.Update", L.Any("mail", up))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("MailOutboxCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
@@ -23,7 +23,7 @@ func InitMatchCrud(db *sql.DB) *MatchCrud {
 
 const (
 	match_select = `
-		select ma.match_id, ma.status, ma.start_time, ma.players, ma.result, ma.place_id, ma.sport, ma.teams, ma.created_at, ma.created_by, ma.updated_at, ma.updated_by, ma.deleted_at, ma.deleted_by
+		select ma.match_id, ma.status, ma.start_time, ma.result, ma.place_id, ma.sport, ma.balancing, ma.created_at, ma.created_by, ma.updated_at, ma.updated_by, ma.deleted_at, ma.deleted_by
 		from match ma
 	`
 	match_count = `select count(*) from match ma `
@@ -31,6 +31,29 @@ const (
 
 ////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
 
+// loadRosters sets players and teams of matches from match_player
+func (r *MatchCrud) loadRosters(ctx context.Context, matches []DR.Match, qa QueryAble) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	ids := []string{}
+	for _, ma := range matches {
+		ids = append(ids, ma.MatchId)
+	}
+	members, err := r.crudRepo.MatchPlayerCrud.Search(ctx, DR.MatchPlayerSearchParams{MatchIds: ids}, qa)
+	if err != nil {
+		return err
+	}
+	byMatch := map[string][]DR.MatchPlayer{}
+	for _, mp := range members {
+		byMatch[mp.MatchId] = append(byMatch[mp.MatchId], mp)
+	}
+	for i := range matches {
+		matches[i].SetRoster(byMatch[matches[i].MatchId])
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
 
 // Creates a Match
@@ -43,9 +66,13 @@ func (r *MatchCrud) Create(ctx context.Context, en DR.Match, qa QueryAble, by *s
 		en.EditInfoC = DR.CreateEditInfoC(by)
 	}
 
-	query := `insert into match (start_time, place_id, status, players, sport, created_at, created_by)
+	if en.Balancing == "" {
+		en.Balancing = DR.MB_RANDOM
+	}
+
+	query := `insert into match (start_time, place_id, status, sport, balancing, created_at, created_by)
 	values ($1, $2, $3, $4, $5, $6, $7) RETURNING match_id;`
-	params := []interface{}{en.StartTime, en.PlaceId, en.Status, en.Players, en.Sport, en.CreatedAt, en.CreatedBy}
+	params := []interface{}{en.StartTime, en.PlaceId, en.Status, en.Sport, en.Balancing, en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("MatchCrud.Create insert", L.String("query", query), L.Any("params", params))
 
@@ -54,6 +81,17 @@ func (r *MatchCrud) Create(ctx context.Context, en DR.Match, qa QueryAble, by *s
 		util.LogPqError(ctx, err)
 		return en, err
 	}
+	// first player is creator of match
+	for i, playerId := range en.Players {
+		role := DR.MR_PLAYER
+		if i == 0 {
+			role = DR.MR_CREATOR
+		}
+		_, err = r.crudRepo.MatchPlayerCrud.Create(ctx, DR.MatchPlayer{MatchId: en.MatchId, PlayerId: playerId, Role: role}, qa, by)
+		if err != nil {
+			return en, err
+		}
+	}
 	pen, err := r.GetById(ctx, en.MatchId, qa)
 	if err != nil {
 		util.LogPqError(ctx, err)
@@ -88,14 +126,17 @@ func (r *MatchCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Ma
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&ma.MatchId, &ma.Status, &ma.StartTime, &ma.Players, &ma.Result, &ma.PlaceId, &ma.Sport, &ma.Teams, &ma.CreatedAt, &ma.CreatedBy, &ma.UpdatedAt, &ma.UpdatedBy, &ma.DeletedAt, &ma.DeletedBy)
+	err := row.Scan(&ma.MatchId, &ma.Status, &ma.StartTime, &ma.Result, &ma.PlaceId, &ma.Sport, &ma.Balancing, &ma.CreatedAt, &ma.CreatedBy, &ma.UpdatedAt, &ma.UpdatedBy, &ma.DeletedAt, &ma.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("match does not exist for username: %v", id)
 		}
+		return ma, err
 	}
+	matches := []DR.Match{ma}
+	err = r.loadRosters(ctx, matches, qa)
 
-	return ma, err
+	return matches[0], err
 }
 
 func (r *MatchCrud) GetCount(ctx context.Context, sp DR.MatchSearchParams, qa QueryAble) (int, error) {
@@ -157,7 +198,7 @@ func (r *MatchCrud) Search(ctx context.Context, sp DR.MatchSearchParams, qa Quer
 
 	for rows.Next() {
 		ma := DR.Match{}
-		err := rows.Scan(&ma.MatchId, &ma.Status, &ma.StartTime, &ma.Players, &ma.Result, &ma.PlaceId, &ma.Sport, &ma.Teams, &ma.CreatedAt, &ma.CreatedBy, &ma.UpdatedAt, &ma.UpdatedBy, &ma.DeletedAt, &ma.DeletedBy)
+		err := rows.Scan(&ma.MatchId, &ma.Status, &ma.StartTime, &ma.Result, &ma.PlaceId, &ma.Sport, &ma.Balancing, &ma.CreatedAt, &ma.CreatedBy, &ma.UpdatedAt, &ma.UpdatedBy, &ma.DeletedAt, &ma.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -167,6 +208,10 @@ func (r *MatchCrud) Search(ctx context.Context, sp DR.MatchSearchParams, qa Quer
 	if len(results) == 0 {
 		L.L.WithRequestID(ctx).Warn("MatchCrud.Search No rows returned ")
 	}
+	err = r.loadRosters(ctx, results, qa)
+	if err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
@@ -211,3 +256,23 @@ func (r *MatchCrud) Update(ctx context.Context, up DR.MatchUpdateParams, qa Quer
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a match
+func (r *MatchCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("MatchCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `match`, "match_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
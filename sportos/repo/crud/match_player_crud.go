@@ -0,0 +1,178 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type MatchPlayerCrud struct {
+	Crud
+}
+
+func InitMatchPlayerCrud(db *sql.DB) *MatchPlayerCrud {
+	return &MatchPlayerCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	match_player_select = `
+		select mp.match_id, mp.player_id, mp.role, mp.position, mp.side, mp.created_at, mp.created_by, mp.updated_at, mp.updated_by
+		from match_player mp
+	`
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanMatchPlayer(row interface{ Scan(...interface{}) error }, mp *DR.MatchPlayer) error {
+	return row.Scan(&mp.MatchId, &mp.PlayerId, &mp.Role, &mp.Position, &mp.Side, &mp.CreatedAt, &mp.CreatedBy, &mp.UpdatedAt, &mp.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Adds player to match, player gets next position in match
+func (r *MatchPlayerCrud) Create(ctx context.Context, en DR.MatchPlayer, qa QueryAble, by *string) (DR.MatchPlayer, error) {
+	L.L.WithRequestID(ctx).Info("MatchPlayerCrud.Create", L.Any("matchPlayer", en))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+
+	if _, err := r.GetById(ctx, en.MatchId, en.PlayerId, qa); err == nil {
+		return en, fmt.Errorf("player %s is already in match %s", en.PlayerId, en.MatchId)
+	}
+
+	query := `insert into match_player (match_id, player_id, role, position, side, created_at, created_by)
+	select $1, $2, $3, coalesce(max(position) + 1, 0), $4, $5, $6 from match_player where match_id = $1;`
+	params := []interface{}{en.MatchId, en.PlayerId, en.Role, en.Side, en.CreatedAt, en.CreatedBy}
+
+	L.L.Debug("MatchPlayerCrud.Create insert", L.String("query", query), L.Any("params", params))
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+	pen, err := r.GetById(ctx, en.MatchId, en.PlayerId, qa)
+	if err != nil {
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, nil, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns membership of player in match
+func (r *MatchPlayerCrud) GetById(ctx context.Context, matchId, playerId string, qa QueryAble) (DR.MatchPlayer, error) {
+	L.L.WithRequestID(ctx).Info("MatchPlayerCrud.GetById", L.String("matchId", matchId), L.String("playerId", playerId))
+
+	db := r.GetTx(qa)
+
+	query := ""
+	if qa != nil {
+		query = match_player_select +
+			`where mp.match_id=$1 and mp.player_id=$2 for update`
+	} else {
+		query = match_player_select +
+			`where mp.match_id=$1 and mp.player_id=$2`
+	}
+	mp := DR.MatchPlayer{}
+	err := scanMatchPlayer(db.QueryRowContext(ctx, query, matchId, playerId), &mp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("player %v is not in match %v", playerId, matchId)
+		}
+	}
+
+	return mp, err
+}
+
+func (r *MatchPlayerCrud) Search(ctx context.Context, sp DR.MatchPlayerSearchParams, qa QueryAble) ([]DR.MatchPlayer, error) {
+	L.L.WithRequestID(ctx).Info("MatchPlayerCrud.Search", L.Any("matchPlayer", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.MatchPlayer{}
+	var params []interface{}
+
+	query := match_player_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("MatchPlayerCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mp := DR.MatchPlayer{}
+		err := scanMatchPlayer(rows, &mp)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mp)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates membership of player in match
+func (r *MatchPlayerCrud) Update(ctx context.Context, up DR.MatchPlayerUpdateParams, qa QueryAble, by *string) (DR.MatchPlayer, error) {
+	L.L.WithRequestID(ctx).Info("MatchPlayerCrud.Update", L.Any("matchPlayer", up))
+
+	up.PopulateUpdateFields(by)
+
+	old, _ := r.GetById(ctx, up.MatchId, up.PlayerId, qa)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("MatchPlayerCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return DR.MatchPlayer{}, err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return DR.MatchPlayer{}, fmt.Errorf("no rows affected")
+	}
+	pen, err := r.GetById(ctx, up.MatchId, up.PlayerId, qa)
+	if err != nil {
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
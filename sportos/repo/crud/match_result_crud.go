@@ -0,0 +1,214 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type MatchResultCrud struct {
+	Crud
+}
+
+func InitMatchResultCrud(db *sql.DB) *MatchResultCrud {
+	return &MatchResultCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	match_result_select = `
+		select mr.result_id, mr.match_id, mr.result, mr.side, mr.status, mr.deadline, mr.disputed_by, mr.dispute_reason, mr.review_note,
+		mr.created_at, mr.created_by, mr.updated_at, mr.updated_by
+		from match_result mr
+	`
+	match_result_count = `select count(*) from match_result mr `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanMatchResult(row interface{ Scan(...interface{}) error }, mr *DR.MatchResult) error {
+	return row.Scan(&mr.ResultId, &mr.MatchId, &mr.Result, &mr.Side, &mr.Status, &mr.Deadline, &mr.DisputedBy, &mr.DisputeReason, &mr.ReviewNote,
+		&mr.CreatedAt, &mr.CreatedBy, &mr.UpdatedAt, &mr.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates result submitted by player, it waits for confirmation of other side until deadline
+func (r *MatchResultCrud) Create(ctx context.Context, en DR.MatchResult, qa QueryAble, by *string) (DR.MatchResult, error) {
+	L.L.WithRequestID(ctx).Info("MatchResultCrud.Create", L.String("matchId", en.MatchId), L.String("result", en.Result))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.MRS_PENDING
+	}
+
+	query := `insert into match_result (match_id, result, side, status, deadline, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7) RETURNING result_id;`
+	params := []interface{}{en.MatchId, en.Result, en.Side, en.Status, en.Deadline, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.ResultId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns match result by id, it is locked when read in transaction
+func (r *MatchResultCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.MatchResult, error) {
+	L.L.WithRequestID(ctx).Info("MatchResultCrud.GetById", L.String("resultId", id))
+
+	db := r.GetTx(qa)
+
+	mr := DR.MatchResult{}
+	query := ""
+	if qa != nil {
+		query = match_result_select +
+			`where mr.result_id=$1 for update`
+	} else {
+		query = match_result_select +
+			`where mr.result_id=$1`
+	}
+
+	err := scanMatchResult(db.QueryRowContext(ctx, query, id), &mr)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("match result does not exist for id: %v", id)
+		}
+	}
+	return mr, err
+}
+
+func (r *MatchResultCrud) GetCount(ctx context.Context, sp DR.MatchResultSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("MatchResultCrud.GetCount", L.Any("matchResult", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := match_result_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns match results, oldest first
+func (r *MatchResultCrud) Search(ctx context.Context, sp DR.MatchResultSearchParams, qa QueryAble) ([]DR.MatchResult, error) {
+	L.L.WithRequestID(ctx).Info("MatchResultCrud.Search", L.Any("matchResult", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.MatchResult{}
+	var params []interface{}
+
+	query := match_result_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("MatchResultCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mr := DR.MatchResult{}
+		err := scanMatchResult(rows, &mr)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mr)
+	}
+	return results, nil
+}
+
+// GetExpired returns pending results whose deadline passed and locks them together with their matches,
+// results locked by other workers and results of matches locked by requests are skipped, so worker never
+// waits for match that request locked before its result
+func (r *MatchResultCrud) GetExpired(ctx context.Context, limit int, qa QueryAble) ([]DR.MatchResult, error) {
+	db := r.GetTx(qa)
+
+	query := match_result_select +
+		`inner join match ma on ma.match_id = mr.match_id
+		where mr.status=$1 and mr.deadline<=$2 order by mr.deadline, mr.result_id limit $3`
+	if qa != nil {
+		query += ` for update of mr, ma skip locked`
+	}
+
+	rows, err := db.QueryContext(ctx, query, DR.MRS_PENDING, time.Now().UTC(), limit)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.MatchResult{}
+	for rows.Next() {
+		mr := DR.MatchResult{}
+		err := scanMatchResult(rows, &mr)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, mr)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a match result
+func (r *MatchResultCrud) Update(ctx context.Context, up DR.MatchResultUpdateParams, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("MatchResultCrud.Update", L.String("resultId", up.Id))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("MatchResultCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type NotificationCrud struct {
+	Crud
+}
+
+func InitNotificationCrud(db *sql.DB) *NotificationCrud {
+	return &NotificationCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	notification_select = `
+		select nt.notification_id, nt.user_id, nt.type, nt.reference_id, nt.message, nt.read_at, nt.created_at, nt.created_by, nt.updated_at, nt.updated_by
+		from notification nt
+	`
+	notification_count = `select count(*) from notification nt `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanNotification(row interface{ Scan(...interface{}) error }, nt *DR.Notification) error {
+	return row.Scan(&nt.NotificationId, &nt.UserId, &nt.Type, &nt.ReferenceId, &nt.Message, &nt.ReadAt, &nt.CreatedAt, &nt.CreatedBy, &nt.UpdatedAt, &nt.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a notification in inbox of user, notifications aren't audited like mails in outbox
+func (r *NotificationCrud) Create(ctx context.Context, en DR.Notification, qa QueryAble, by *string) (DR.Notification, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.Create", L.String("userId", en.UserId), L.String("type", string(en.Type)))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+
+	query := `insert into notification (user_id, type, reference_id, message, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6) RETURNING notification_id;`
+	params := []interface{}{en.UserId, en.Type, en.ReferenceId, en.Message, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.NotificationId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns notification by id
+func (r *NotificationCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Notification, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.GetById", L.String("id", id))
+
+	db := r.GetTx(qa)
+
+	nt := DR.Notification{}
+	query := ""
+	if qa != nil {
+		query = notification_select +
+			`where nt.notification_id=$1 for update`
+	} else {
+		query = notification_select +
+			`where nt.notification_id=$1`
+	}
+
+	err := scanNotification(db.QueryRowContext(ctx, query, id), &nt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("notification does not exist for id: %v", id)
+		}
+	}
+	return nt, err
+}
+
+func (r *NotificationCrud) GetCount(ctx context.Context, sp DR.NotificationSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.GetCount", L.Any("notification", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := notification_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("NotificationCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns notifications, newest first
+func (r *NotificationCrud) Search(ctx context.Context, sp DR.NotificationSearchParams, qa QueryAble) ([]DR.Notification, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.Search", L.Any("notification", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.Notification{}
+	var params []interface{}
+
+	query := notification_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("NotificationCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		nt := DR.Notification{}
+		err := scanNotification(rows, &nt)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, nt)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a notification
+func (r *NotificationCrud) Update(ctx context.Context, up DR.NotificationUpdateParams, qa QueryAble, by *string) (DR.Notification, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.Update", L.Any("notification", up))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("NotificationCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return DR.Notification{}, err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return DR.Notification{}, fmt.Errorf("no rows affected")
+	}
+	return r.GetById(ctx, up.Id, qa)
+}
+
+// MarkAllRead marks all unread notifications of user as read, it returns number of marked notifications
+func (r *NotificationCrud) MarkAllRead(ctx context.Context, userId string, qa QueryAble, by *string) (int, error) {
+	L.L.WithRequestID(ctx).Info("NotificationCrud.MarkAllRead", L.String("userId", userId))
+
+	db := r.GetTx(qa)
+
+	ei := DR.EditInfoU{}
+	ei.PopulateUpdateFields(by)
+	result, err := db.ExecContext(ctx, `update notification set read_at = $1, updated_at = $1, updated_by = $2 where user_id = $3 and read_at is null;`,
+		ei.UpdatedAt, ei.UpdatedBy, userId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	ra, _ := result.RowsAffected()
+	return int(ra), nil
+}
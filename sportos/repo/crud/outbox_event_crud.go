@@ -0,0 +1,154 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type OutboxEventCrud struct {
+	Crud
+}
+
+func InitOutboxEventCrud(db *sql.DB) *OutboxEventCrud {
+	return &OutboxEventCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	outbox_event_select = `
+		select oe.outbox_event_id, oe.type, oe.aggregate_id, oe.payload, oe.status, oe.attempts, oe.next_attempt_at, oe.last_error, oe.dispatched_at,
+		oe.created_at, oe.created_by, oe.updated_at, oe.updated_by
+		from outbox_event oe
+	`
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanOutboxEvent(row interface{ Scan(...interface{}) error }, oe *DR.OutboxEvent) error {
+	return row.Scan(&oe.OutboxEventId, &oe.Type, &oe.AggregateId, &oe.Payload, &oe.Status, &oe.Attempts, &oe.NextAttemptAt, &oe.LastError, &oe.DispatchedAt,
+		&oe.CreatedAt, &oe.CreatedBy, &oe.UpdatedAt, &oe.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a domain event in outbox, it is dispatched to subscribers after transaction commits
+func (r *OutboxEventCrud) Create(ctx context.Context, en DR.OutboxEvent, qa QueryAble, by *string) (DR.OutboxEvent, error) {
+	L.L.WithRequestID(ctx).Info("OutboxEventCrud.Create", L.String("type", string(en.Type)), L.String("aggregateId", en.AggregateId))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.OES_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+
+	query := `insert into outbox_event (type, aggregate_id, payload, status, attempts, next_attempt_at, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING outbox_event_id;`
+	params := []interface{}{en.Type, en.AggregateId, en.Payload, en.Status, en.Attempts, en.NextAttemptAt, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.OutboxEventId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns domain event from outbox by id, event is locked if it is read in transaction
+func (r *OutboxEventCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.OutboxEvent, error) {
+	L.L.WithRequestID(ctx).Info("OutboxEventCrud.GetById", L.String("outboxEventId", id))
+
+	db := r.GetTx(qa)
+
+	oe := DR.OutboxEvent{}
+	query := ""
+	if qa != nil {
+		query = outbox_event_select +
+			`where oe.outbox_event_id=$1 for update`
+	} else {
+		query = outbox_event_select +
+			`where oe.outbox_event_id=$1`
+	}
+
+	err := scanOutboxEvent(db.QueryRowContext(ctx, query, id), &oe)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("outbox event does not exist for id: %v", id)
+		}
+	}
+	return oe, err
+}
+
+// GetPending returns pending domain events that are due for dispatching in order in which they were created
+func (r *OutboxEventCrud) GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.OutboxEvent, error) {
+	db := r.GetTx(qa)
+
+	query := outbox_event_select +
+		`where oe.status=$1 and oe.next_attempt_at<=$2 order by oe.next_attempt_at, oe.outbox_event_id limit $3`
+	if qa != nil {
+		query += ` for update skip locked`
+	}
+
+	rows, err := db.QueryContext(ctx, query, DR.OES_PENDING, time.Now().UTC(), limit)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.OutboxEvent{}
+	for rows.Next() {
+		oe := DR.OutboxEvent{}
+		err := scanOutboxEvent(rows, &oe)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, oe)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a domain event in outbox
+func (r *OutboxEventCrud) Update(ctx context.Context, up DR.OutboxEventUpdateParams, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("OutboxEventCrud.Update", L.Any("outboxEvent", up))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("OutboxEventCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
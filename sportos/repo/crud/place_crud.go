@@ -23,7 +23,7 @@ func InitPlaceCrud(db *sql.DB) *PlaceCrud {
 
 const (
 	place_select = `
-		select pla.user_id, pla.name, pla.city, pla.sport, pla.reviews, pla.booking, pla.created_at, pla.created_by, pla.updated_at, pla.updated_by, pla.deleted_at, pla.deleted_by
+		select pla.user_id, pla.name, pla.city, pla.sport, pla.reviews, pla.schedule, pla.time_zone, pla.created_at, pla.created_by, pla.updated_at, pla.updated_by, pla.deleted_at, pla.deleted_by
 		from place pla
 	`
 	place_count = `select count(*) from place pla `
@@ -63,9 +63,13 @@ func (r *PlaceCrud) Create(ctx context.Context, en DR.Place, qa QueryAble, by *s
 		return en, fmt.Errorf("user with username %s already exists", en.Username)
 	}
 
-	query := `insert into place (user_id, name, city, sport, created_at, created_by)
-	values ($1, $2, $3, $4, $5, $6) RETURNING user_id;`
-	params := []interface{}{en.Username, en.Name, en.City, en.Sport, en.CreatedAt, en.CreatedBy}
+	if en.TimeZone == "" {
+		en.TimeZone = DR.DEFAULT_TIME_ZONE
+	}
+
+	query := `insert into place (user_id, name, city, sport, time_zone, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7) RETURNING user_id;`
+	params := []interface{}{en.Username, en.Name, en.City, en.Sport, en.TimeZone, en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("PlaceCrud.Create insert", L.String("query", query), L.Any("params", params))
 
@@ -108,7 +112,7 @@ func (r *PlaceCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Pl
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Booking, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
+	err := row.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Schedule, &pla.TimeZone, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("place does not exist for username: %v", id)
@@ -136,7 +140,7 @@ func (r *PlaceCrud) GetByEmail(ctx context.Context, email string, qa QueryAble)
 	row := db.QueryRowContext(ctx, query,
 		email)
 
-	err := row.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Booking, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
+	err := row.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Schedule, &pla.TimeZone, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("place does not exist for email: %v", email)
@@ -205,7 +209,7 @@ func (r *PlaceCrud) Search(ctx context.Context, sp DR.PlaceSearchParams, qa Quer
 
 	for rows.Next() {
 		pla := DR.Place{}
-		err := rows.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Booking, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
+		err := rows.Scan(&pla.Username, &pla.Name, &pla.City, &pla.Sport, &pla.Reviews, &pla.Schedule, &pla.TimeZone, &pla.CreatedAt, &pla.CreatedBy, &pla.UpdatedAt, &pla.UpdatedBy, &pla.DeletedAt, &pla.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -259,3 +263,23 @@ func (r *PlaceCrud) Update(ctx context.Context, up DR.PlaceUpdateParams, qa Quer
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a place
+func (r *PlaceCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("PlaceCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `place`, "user_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
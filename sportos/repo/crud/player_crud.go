@@ -23,7 +23,7 @@ func InitPlayerCrud(db *sql.DB) *PlayerCrud {
 
 const (
 	player_select = `
-		select pl.user_id, pl.name, pl.city, pl.preferences, pl.statistics, pl.created_at, pl.created_by, pl.updated_at, pl.updated_by, pl.deleted_at, pl.deleted_by
+		select pl.user_id, pl.name, pl.city, pl.preferences, pl.created_at, pl.created_by, pl.updated_at, pl.updated_by, pl.deleted_at, pl.deleted_by
 		from player pl
 	`
 	player_count = `select count(*) from player pl `
@@ -108,7 +108,7 @@ func (r *PlayerCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.P
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.Statistics, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
+	err := row.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("player does not exist for username: %v", id)
@@ -136,7 +136,7 @@ func (r *PlayerCrud) GetByEmail(ctx context.Context, email string, qa QueryAble)
 	row := db.QueryRowContext(ctx, query,
 		email)
 
-	err := row.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.Statistics, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
+	err := row.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("player does not exist for email: %v", email)
@@ -205,7 +205,7 @@ func (r *PlayerCrud) Search(ctx context.Context, sp DR.PlayerSearchParams, qa Qu
 
 	for rows.Next() {
 		pl := DR.Player{}
-		err := rows.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.Statistics, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
+		err := rows.Scan(&pl.Username, &pl.Name, &pl.City, &pl.Preferences, &pl.CreatedAt, &pl.CreatedBy, &pl.UpdatedAt, &pl.UpdatedBy, &pl.DeletedAt, &pl.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -259,3 +259,23 @@ func (r *PlayerCrud) Update(ctx context.Context, up DR.PlayerUpdateParams, qa Qu
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a player
+func (r *PlayerCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("PlayerCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `player`, "user_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
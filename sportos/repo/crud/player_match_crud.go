@@ -0,0 +1,274 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type PlayerMatchCrud struct {
+	Crud
+}
+
+func InitPlayerMatchCrud(db *sql.DB) *PlayerMatchCrud {
+	return &PlayerMatchCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	// names of both teams are resolved in one lateral join of match_player and player
+	player_match_select = `
+		select pm.match_id, pm.player_id, pm.sport, pm.played_at, pm.side, pm.points_for, pm.points_against, pm.outcome, tm.my_team, tm.opp_team, pm.created_at, pm.created_by
+		from player_match pm
+		left join lateral (
+			select array_agg(coalesce(pl.name, mp.player_id) order by mp.position) filter (where mp.side = pm.side) as my_team,
+				array_agg(coalesce(pl.name, mp.player_id) order by mp.position) filter (where mp.side <> pm.side) as opp_team
+			from match_player mp
+			left join player pl on pl.user_id = mp.player_id
+			where mp.match_id = pm.match_id
+		) tm on true
+	`
+	player_match_count = `select count(*) from player_match pm `
+
+	// streaks are islands of consecutive matches with same outcome, rn - orn is same for all matches of island
+	player_match_summary = `
+		with ordered as (
+			select pm.outcome,
+				row_number() over (order by pm.played_at desc, pm.match_id desc) as rn,
+				row_number() over (partition by pm.outcome order by pm.played_at desc, pm.match_id desc) as orn
+			from player_match pm
+			where pm.player_id = $1 and pm.sport = $2
+		), streaks as (
+			select outcome, count(*) as length, min(rn) as first_rn
+			from ordered
+			group by outcome, rn - orn
+		)
+		select (select count(*) from ordered),
+			(select count(*) from ordered where outcome = 'WIN'),
+			(select count(*) from ordered where outcome = 'DRAW'),
+			(select count(*) from ordered where outcome = 'LOSS'),
+			coalesce((select length from streaks where first_rn = 1), 0),
+			coalesce((select outcome from streaks where first_rn = 1), ''),
+			coalesce((select max(length) from streaks where outcome = 'WIN'), 0)
+	`
+	player_match_monthly = `
+		select date_trunc('month', pm.played_at at time zone 'UTC') as month, count(*),
+			count(*) filter (where pm.outcome = 'WIN'),
+			count(*) filter (where pm.outcome = 'DRAW'),
+			count(*) filter (where pm.outcome = 'LOSS')
+		from player_match pm
+		where pm.player_id = $1 and pm.sport = $2
+		group by 1
+		order by 1 desc
+	`
+	// player_match_group is completed with join of grouped dimension and where of search params
+	player_match_group = `
+		select %s as key, %s as name, count(*),
+			count(*) filter (where pm.outcome = 'WIN'),
+			count(*) filter (where pm.outcome = 'DRAW'),
+			count(*) filter (where pm.outcome = 'LOSS')
+		from player_match pm
+	`
+	// weekday and hour are on wall clock of place
+	player_match_place_join = `
+		inner join match ma on ma.match_id = pm.match_id
+		inner join place pla on pla.user_id = ma.place_id
+	`
+	player_match_player_join = `
+		inner join match_player mp on mp.match_id = pm.match_id and %s
+		left join player pl on pl.user_id = mp.player_id
+	`
+)
+
+// groupQuery returns select of key and name of group and join of tables they come from
+func groupQuery(by DR.PlayerMatchGroupBy) string {
+	switch by {
+	case DR.PMG_OPPONENT:
+		return fmt.Sprintf(player_match_group, "mp.player_id", "coalesce(pl.name, mp.player_id)") +
+			fmt.Sprintf(player_match_player_join, "mp.side <> pm.side")
+	case DR.PMG_TEAMMATE:
+		return fmt.Sprintf(player_match_group, "mp.player_id", "coalesce(pl.name, mp.player_id)") +
+			fmt.Sprintf(player_match_player_join, "mp.side = pm.side and mp.player_id <> pm.player_id")
+	case DR.PMG_PLACE:
+		return fmt.Sprintf(player_match_group, "ma.place_id", "pla.name") + player_match_place_join
+	case DR.PMG_WEEKDAY:
+		return fmt.Sprintf(player_match_group, "extract(dow from pm.played_at at time zone pla.time_zone)::int::text", "''") + player_match_place_join
+	}
+	return fmt.Sprintf(player_match_group, "extract(hour from pm.played_at at time zone pla.time_zone)::int::text", "''") + player_match_place_join
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Create stores result of match for player, result that is already stored isn't changed so repeated
+// delivery of match finished event doesn't count match twice
+func (r *PlayerMatchCrud) Create(ctx context.Context, en DR.PlayerMatch, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.Create", L.String("matchId", en.MatchId), L.String("playerId", en.PlayerId))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	query := `insert into player_match (match_id, player_id, sport, played_at, side, points_for, points_against, outcome, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) on conflict (match_id, player_id) do nothing;`
+	params := []interface{}{en.MatchId, en.PlayerId, en.Sport, en.PlayedAt, en.Side, en.PointsFor, en.PointsAgainst, en.Outcome, en.CreatedAt, en.CreatedBy}
+
+	L.L.Debug("PlayerMatchCrud.Create insert", L.String("query", query), L.Any("params", params))
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return err
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+func (r *PlayerMatchCrud) GetCount(ctx context.Context, sp DR.PlayerMatchSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.GetCount", L.Any("playerMatch", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := player_match_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("PlayerMatchCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns results of matches from newest with names of players of both teams
+func (r *PlayerMatchCrud) Search(ctx context.Context, sp DR.PlayerMatchSearchParams, qa QueryAble) ([]DR.PlayerMatch, error) {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.Search", L.Any("playerMatch", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.PlayerMatch{}
+	var params []interface{}
+
+	query := player_match_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("PlayerMatchCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pm := DR.PlayerMatch{}
+		err := rows.Scan(&pm.MatchId, &pm.PlayerId, &pm.Sport, &pm.PlayedAt, &pm.Side, &pm.PointsFor, &pm.PointsAgainst, &pm.Outcome, &pm.MyTeam, &pm.OppTeam, &pm.CreatedAt, &pm.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pm)
+	}
+	return results, nil
+}
+
+// Summary aggregates all matches of player in sport
+func (r *PlayerMatchCrud) Summary(ctx context.Context, playerId, sport string, qa QueryAble) (DR.PlayerMatchSummary, error) {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.Summary", L.String("playerId", playerId), L.String("sport", sport))
+
+	db := r.GetTx(qa)
+
+	s := DR.PlayerMatchSummary{}
+	err := db.QueryRowContext(ctx, player_match_summary, playerId, sport).Scan(&s.Matches, &s.Wins, &s.Draws, &s.Losses, &s.CurrentStreak, &s.StreakOutcome, &s.LongestWinStreak)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return s, err
+}
+
+// Monthly aggregates matches of player in sport by month, newest month first
+func (r *PlayerMatchCrud) Monthly(ctx context.Context, playerId, sport string, qa QueryAble) ([]DR.PlayerMatchMonth, error) {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.Monthly", L.String("playerId", playerId), L.String("sport", sport))
+
+	db := r.GetTx(qa)
+
+	rows, err := db.QueryContext(ctx, player_match_monthly, playerId, sport)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.PlayerMatchMonth{}
+	for rows.Next() {
+		m := DR.PlayerMatchMonth{}
+		if err := rows.Scan(&m.Month, &m.Matches, &m.Wins, &m.Draws, &m.Losses); err != nil {
+			return nil, err
+		}
+		m.Month = time.Date(m.Month.Year(), m.Month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		results = append(results, m)
+	}
+	return results, nil
+}
+
+// Group aggregates matches found by search params by opponent, teammate, place, weekday or hour,
+// groups with most matches are first
+func (r *PlayerMatchCrud) Group(ctx context.Context, sp DR.PlayerMatchSearchParams, by DR.PlayerMatchGroupBy, qa QueryAble) ([]DR.PlayerMatchGroup, error) {
+	L.L.WithRequestID(ctx).Info("PlayerMatchCrud.Group", L.Any("playerMatch", sp), L.String("by", string(by)))
+
+	if !by.IsValid() {
+		return nil, fmt.Errorf("matches can't be grouped by %s", by)
+	}
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := groupQuery(by)
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+	query += ` group by 1, 2 order by 3 desc, 1`
+
+	L.L.WithRequestID(ctx).Debug("PlayerMatchCrud.Group query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.PlayerMatchGroup{}
+	for rows.Next() {
+		g := DR.PlayerMatchGroup{}
+		if err := rows.Scan(&g.Key, &g.Name, &g.Matches, &g.Wins, &g.Draws, &g.Losses); err != nil {
+			return nil, err
+		}
+		results = append(results, g)
+	}
+	return results, nil
+}
@@ -0,0 +1,125 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+)
+
+type PlayerTournamentCrud struct {
+	Crud
+}
+
+func InitPlayerTournamentCrud(db *sql.DB) *PlayerTournamentCrud {
+	return &PlayerTournamentCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	// name of tournament is joined from event and members of team from team_member and player
+	player_tournament_select = `
+		select pt.event_id, pt.player_id, pt.sport, pt.team_id, pt.ranking, ev.name, tm.my_team, pt.created_at, pt.created_by
+		from player_tournament pt
+		inner join event ev on ev.event_id = pt.event_id
+		left join lateral (
+			select array_agg(coalesce(pl.name, te.player_id) order by te.position) as my_team
+			from team_member te
+			left join player pl on pl.user_id = te.player_id
+			where te.team_id = pt.team_id
+		) tm on true
+	`
+	player_tournament_count = `select count(*) from player_tournament pt `
+)
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Create stores ranking of player in tournament, ranking that is already stored isn't changed
+func (r *PlayerTournamentCrud) Create(ctx context.Context, en DR.PlayerTournament, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("PlayerTournamentCrud.Create", L.String("eventId", en.EventId), L.String("playerId", en.PlayerId))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	query := `insert into player_tournament (event_id, player_id, sport, team_id, ranking, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7) on conflict (event_id, player_id) do nothing;`
+	params := []interface{}{en.EventId, en.PlayerId, en.Sport, en.TeamId, en.Ranking, en.CreatedAt, en.CreatedBy}
+
+	L.L.Debug("PlayerTournamentCrud.Create insert", L.String("query", query), L.Any("params", params))
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return err
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+func (r *PlayerTournamentCrud) GetCount(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("PlayerTournamentCrud.GetCount", L.Any("playerTournament", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := player_tournament_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("PlayerTournamentCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns tournament rankings from last finished tournament
+func (r *PlayerTournamentCrud) Search(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa QueryAble) ([]DR.PlayerTournament, error) {
+	L.L.WithRequestID(ctx).Info("PlayerTournamentCrud.Search", L.Any("playerTournament", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.PlayerTournament{}
+	var params []interface{}
+
+	query := player_tournament_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("PlayerTournamentCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pt := DR.PlayerTournament{}
+		err := rows.Scan(&pt.EventId, &pt.PlayerId, &pt.Sport, &pt.TeamId, &pt.Ranking, &pt.Tournament, &pt.MyTeam, &pt.CreatedAt, &pt.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pt)
+	}
+	return results, nil
+}
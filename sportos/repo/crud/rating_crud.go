@@ -0,0 +1,182 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type RatingCrud struct {
+	Crud
+}
+
+func InitRatingCrud(db *sql.DB) *RatingCrud {
+	return &RatingCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	rating_select = `
+		select ra.rating_id, ra.player_id, ra.sport, ra.system, ra.rating, ra.deviation, ra.volatility, ra.matches, ra.created_at, ra.created_by, ra.updated_at, ra.updated_by
+		from rating ra
+	`
+	rating_count = `select count(*) from rating ra `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanRating(row interface{ Scan(...interface{}) error }, ra *DR.Rating) error {
+	return row.Scan(&ra.RatingId, &ra.PlayerId, &ra.Sport, &ra.System, &ra.Rating, &ra.Deviation, &ra.Volatility, &ra.Matches, &ra.CreatedAt, &ra.CreatedBy, &ra.UpdatedAt, &ra.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// GetOrCreate returns rating of player in sport of en, rating en is created if player doesn't have one yet.
+// In transaction returned rating is locked until end of transaction
+func (r *RatingCrud) GetOrCreate(ctx context.Context, en DR.Rating, qa QueryAble, by *string) (DR.Rating, error) {
+	L.L.WithRequestID(ctx).Info("RatingCrud.GetOrCreate", L.String("playerId", en.PlayerId), L.String("sport", en.Sport))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+
+	query := `insert into rating (player_id, sport, system, rating, deviation, volatility, matches, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9) on conflict (player_id, sport) do nothing;`
+	params := []interface{}{en.PlayerId, en.Sport, en.System, en.Rating, en.Deviation, en.Volatility, en.Matches, en.CreatedAt, en.CreatedBy}
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	query = rating_select + `where ra.player_id=$1 and ra.sport=$2`
+	if qa != nil {
+		query += ` for update`
+	}
+	ra := DR.Rating{}
+	err = scanRating(db.QueryRowContext(ctx, query, en.PlayerId, en.Sport), &ra)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return ra, err
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns rating by id
+func (r *RatingCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Rating, error) {
+	L.L.WithRequestID(ctx).Info("RatingCrud.GetById", L.String("id", id))
+
+	db := r.GetTx(qa)
+
+	ra := DR.Rating{}
+	query := rating_select +
+		`where ra.rating_id=$1`
+
+	err := scanRating(db.QueryRowContext(ctx, query, id), &ra)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("rating does not exist for id: %v", id)
+		}
+	}
+	return ra, err
+}
+
+func (r *RatingCrud) GetCount(ctx context.Context, sp DR.RatingSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("RatingCrud.GetCount", L.Any("rating", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := rating_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("RatingCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns ratings from best to worst
+func (r *RatingCrud) Search(ctx context.Context, sp DR.RatingSearchParams, qa QueryAble) ([]DR.Rating, error) {
+	L.L.WithRequestID(ctx).Info("RatingCrud.Search", L.Any("rating", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.Rating{}
+	var params []interface{}
+
+	query := rating_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("RatingCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ra := DR.Rating{}
+		err := scanRating(rows, &ra)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, ra)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a rating
+func (r *RatingCrud) Update(ctx context.Context, up DR.RatingUpdateParams, qa QueryAble, by *string) (DR.Rating, error) {
+	L.L.WithRequestID(ctx).Info("RatingCrud.Update", L.Any("rating", up))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("RatingCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return DR.Rating{}, err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return DR.Rating{}, fmt.Errorf("no rows affected")
+	}
+	return r.GetById(ctx, up.Id, qa)
+}
@@ -0,0 +1,127 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+)
+
+type RatingHistoryCrud struct {
+	Crud
+}
+
+func InitRatingHistoryCrud(db *sql.DB) *RatingHistoryCrud {
+	return &RatingHistoryCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	rating_history_select = `
+		select rh.history_id, rh.rating_id, rh.player_id, rh.sport, rh.match_id, rh.score, rh.rating_before, rh.rating_after, rh.deviation_before, rh.deviation_after, rh.created_at, rh.created_by
+		from rating_history rh
+	`
+	rating_history_count = `select count(*) from rating_history rh `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanRatingHistory(row interface{ Scan(...interface{}) error }, rh *DR.RatingHistory) error {
+	return row.Scan(&rh.HistoryId, &rh.RatingId, &rh.PlayerId, &rh.Sport, &rh.MatchId, &rh.Score, &rh.RatingBefore, &rh.RatingAfter, &rh.DeviationBefore, &rh.DeviationAfter, &rh.CreatedAt, &rh.CreatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a rating history entry, entries are never changed. Entry of player and match that is already
+// stored isn't changed and created is false, so repeated delivery of match finished event doesn't rate match twice
+func (r *RatingHistoryCrud) Create(ctx context.Context, en DR.RatingHistory, qa QueryAble, by *string) (DR.RatingHistory, bool, error) {
+	L.L.WithRequestID(ctx).Info("RatingHistoryCrud.Create", L.String("playerId", en.PlayerId), L.String("matchId", en.MatchId))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	query := `insert into rating_history (rating_id, player_id, sport, match_id, score, rating_before, rating_after, deviation_before, deviation_after, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) on conflict (player_id, match_id) do nothing RETURNING history_id;`
+	params := []interface{}{en.RatingId, en.PlayerId, en.Sport, en.MatchId, en.Score, en.RatingBefore, en.RatingAfter, en.DeviationBefore, en.DeviationAfter, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.HistoryId)
+	if err == sql.ErrNoRows {
+		return en, false, nil
+	}
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, false, err
+	}
+
+	return en, true, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+func (r *RatingHistoryCrud) GetCount(ctx context.Context, sp DR.RatingHistorySearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("RatingHistoryCrud.GetCount", L.Any("ratingHistory", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := rating_history_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("RatingHistoryCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns rating history, newest first
+func (r *RatingHistoryCrud) Search(ctx context.Context, sp DR.RatingHistorySearchParams, qa QueryAble) ([]DR.RatingHistory, error) {
+	L.L.WithRequestID(ctx).Info("RatingHistoryCrud.Search", L.Any("ratingHistory", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.RatingHistory{}
+	var params []interface{}
+
+	query := rating_history_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("RatingHistoryCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rh := DR.RatingHistory{}
+		err := scanRatingHistory(rows, &rh)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, rh)
+	}
+	return results, nil
+}
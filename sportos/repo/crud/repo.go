@@ -3,11 +3,14 @@ package crud
 import (
 	"backend/internal/cache"
 	L "backend/internal/logging"
+	"backend/sportos/repo/migrations"
 	"context"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/base32"
 	"fmt"
+	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,72 +21,195 @@ type DBConnection struct {
 	Port     string
 	User     string
 	Password string
+	// apply pending schema migrations when repo is initialized
+	Migrate bool
 }
 
 type Repo struct {
-	DB             *sql.DB
-	PlayerCrud     *PlayerCrud
-	CoachCrud      *CoachCrud
-	PlaceCrud      *PlaceCrud
-	EventCrud      *EventCrud
-	ApiJournalCrud *ApiJournalCrud
-	AuditCrud      *AuditCrud
-	UserCrud       *UserCrud
-	MatchCrud      *MatchCrud
-	PracticeCrud   *PracticeCrud
-	TeamCrud       *TeamCrud
-	UserPostsCrud  *UserPostCrud
-	NameCache      *cache.Cache[string, string]
+	// nil when repo is in memory (see repo/memory)
+	DB                    *sql.DB
+	PlayerCrud            PlayerStore
+	CoachCrud             CoachStore
+	PlaceCrud             PlaceStore
+	EventCrud             EventStore
+	ApiJournalCrud        ApiJournalStore
+	AuditCrud             AuditStore
+	UserCrud              UserStore
+	MatchCrud             MatchStore
+	MatchPlayerCrud       MatchPlayerStore
+	PracticeCrud          PracticeStore
+	BookingCrud           BookingStore
+	TeamCrud              TeamStore
+	TeamMemberCrud        TeamMemberStore
+	UserPostsCrud         UserPostStore
+	VerificationTokenCrud VerificationTokenStore
+	MailOutboxCrud        MailOutboxStore
+	NotificationCrud      NotificationStore
+	OutboxEventCrud       OutboxEventStore
+	WebhookCrud           WebhookStore
+	WebhookDeliveryCrud   WebhookDeliveryStore
+	RatingCrud            RatingStore
+	RatingHistoryCrud     RatingHistoryStore
+	LeaderboardCrud       LeaderboardStore
+	PlayerMatchCrud       PlayerMatchStore
+	PlayerTournamentCrud  PlayerTournamentStore
+	MatchResultCrud       MatchResultStore
+	NameCache             *cache.Cache[string, string]
+	// BeginMemoryTx begins transaction when repo is in memory, nil if changes can't be rolled back
+	BeginMemoryTx func() Tx
 }
 
-func (dbCon *DBConnection) InitRepo() *Repo {
+// Tx is transaction passed to cruds as QueryAble, *sql.Tx for postgres
+type Tx interface {
+	QueryAble
+	Commit() error
+	Rollback() error
+}
+
+// Open opens and pings database connection
+func (dbCon *DBConnection) Open() (*sql.DB, error) {
 	connectionString := fmt.Sprintf("dbname=%s host=%s port=%s user=%s password=%s sslmode=disable", dbCon.DBName, dbCon.Host, dbCon.Port, dbCon.User, dbCon.Password)
 
 	postgreDb, err := sql.Open("postgres", connectionString)
 	if err != nil {
-		L.L.Fatal("Could not instantiate database")
+		return nil, err
 	}
 	err = postgreDb.Ping()
 	if err != nil {
-		L.L.Fatal("Could not connect to database")
+		return nil, err
 	}
 	postgreDb.SetMaxOpenConns(100)
 	postgreDb.SetMaxIdleConns(100)
 	postgreDb.SetConnMaxLifetime(5 * time.Minute)
+	return postgreDb, nil
+}
+
+func (dbCon *DBConnection) InitRepo() *Repo {
+	postgreDb, err := dbCon.Open()
+	if err != nil {
+		L.L.Fatal("Could not connect to database", L.Error(err))
+	}
+
+	if dbCon.Migrate {
+		migrator, err := migrations.NewMigrator(postgreDb)
+		if err != nil {
+			L.L.Fatal("Could not load migrations", L.Error(err))
+		}
+		count, err := migrator.Up(context.Background())
+		if err != nil {
+			L.L.Fatal("Could not migrate database", L.Error(err))
+		}
+		L.L.Info("Database is migrated", L.Int("applied", count))
+	}
+
+	playerCrud := InitPlayerCrud(postgreDb)
+	coachCrud := InitCoachCrud(postgreDb)
+	placeCrud := InitPlaceCrud(postgreDb)
+	eventCrud := InitEventCrud(postgreDb)
+	apiJournalCrud := InitApiJournalCrud(postgreDb)
+	auditCrud := InitAuditCrud(postgreDb)
+	userCrud := InitUserCrud(postgreDb)
+	matchCrud := InitMatchCrud(postgreDb)
+	matchPlayerCrud := InitMatchPlayerCrud(postgreDb)
+	practiceCrud := InitPracticeCrud(postgreDb)
+	bookingCrud := InitBookingCrud(postgreDb)
+	teamCrud := InitTeamCrud(postgreDb)
+	teamMemberCrud := InitTeamMemberCrud(postgreDb)
+	userPostCrud := InitUserPostCrud(postgreDb)
+	verificationTokenCrud := InitVerificationTokenCrud(postgreDb)
+	mailOutboxCrud := InitMailOutboxCrud(postgreDb)
+	notificationCrud := InitNotificationCrud(postgreDb)
+	outboxEventCrud := InitOutboxEventCrud(postgreDb)
+	webhookCrud := InitWebhookCrud(postgreDb)
+	webhookDeliveryCrud := InitWebhookDeliveryCrud(postgreDb)
+	ratingCrud := InitRatingCrud(postgreDb)
+	ratingHistoryCrud := InitRatingHistoryCrud(postgreDb)
+	leaderboardCrud := InitLeaderboardCrud(postgreDb)
+	playerMatchCrud := InitPlayerMatchCrud(postgreDb)
+	playerTournamentCrud := InitPlayerTournamentCrud(postgreDb)
+	matchResultCrud := InitMatchResultCrud(postgreDb)
 
 	r := &Repo{
-		DB:             postgreDb,
-		PlayerCrud:     InitPlayerCrud(postgreDb),
-		CoachCrud:      InitCoachCrud(postgreDb),
-		PlaceCrud:      InitPlaceCrud(postgreDb),
-		EventCrud:      InitEventCrud(postgreDb),
-		ApiJournalCrud: InitApiJournalCrud(postgreDb),
-		AuditCrud:      InitAuditCrud(postgreDb),
-		UserCrud:       InitUserCrud(postgreDb),
-		TeamCrud:       InitTeamCrud(postgreDb),
-		MatchCrud:      InitMatchCrud(postgreDb),
-		PracticeCrud:   InitPracticeCrud(postgreDb),
-		UserPostsCrud:  InitUserPostCrud(postgreDb),
+		DB:                    postgreDb,
+		PlayerCrud:            playerCrud,
+		CoachCrud:             coachCrud,
+		PlaceCrud:             placeCrud,
+		EventCrud:             eventCrud,
+		ApiJournalCrud:        apiJournalCrud,
+		AuditCrud:             auditCrud,
+		UserCrud:              userCrud,
+		TeamCrud:              teamCrud,
+		TeamMemberCrud:        teamMemberCrud,
+		MatchCrud:             matchCrud,
+		MatchPlayerCrud:       matchPlayerCrud,
+		PracticeCrud:          practiceCrud,
+		BookingCrud:           bookingCrud,
+		UserPostsCrud:         userPostCrud,
+		VerificationTokenCrud: verificationTokenCrud,
+		MailOutboxCrud:        mailOutboxCrud,
+		NotificationCrud:      notificationCrud,
+		OutboxEventCrud:       outboxEventCrud,
+		WebhookCrud:           webhookCrud,
+		WebhookDeliveryCrud:   webhookDeliveryCrud,
+		RatingCrud:            ratingCrud,
+		RatingHistoryCrud:     ratingHistoryCrud,
+		LeaderboardCrud:       leaderboardCrud,
+		PlayerMatchCrud:       playerMatchCrud,
+		PlayerTournamentCrud:  playerTournamentCrud,
+		MatchResultCrud:       matchResultCrud,
 	}
-	r.PlayerCrud.SetCrudRepo(r)
-	r.CoachCrud.SetCrudRepo(r)
-	r.PlaceCrud.SetCrudRepo(r)
-	r.EventCrud.SetCrudRepo(r)
-	r.ApiJournalCrud.SetCrudRepo(r)
-	r.AuditCrud.SetCrudRepo(r)
-	r.UserCrud.SetCrudRepo(r)
-	r.MatchCrud.SetCrudRepo(r)
-	r.PracticeCrud.SetCrudRepo(r)
-	r.TeamCrud.SetCrudRepo(r)
-	r.UserPostsCrud.SetCrudRepo(r)
+	playerCrud.SetCrudRepo(r)
+	coachCrud.SetCrudRepo(r)
+	placeCrud.SetCrudRepo(r)
+	eventCrud.SetCrudRepo(r)
+	apiJournalCrud.SetCrudRepo(r)
+	auditCrud.SetCrudRepo(r)
+	userCrud.SetCrudRepo(r)
+	matchCrud.SetCrudRepo(r)
+	matchPlayerCrud.SetCrudRepo(r)
+	practiceCrud.SetCrudRepo(r)
+	bookingCrud.SetCrudRepo(r)
+	teamCrud.SetCrudRepo(r)
+	teamMemberCrud.SetCrudRepo(r)
+	userPostCrud.SetCrudRepo(r)
+	verificationTokenCrud.SetCrudRepo(r)
+	mailOutboxCrud.SetCrudRepo(r)
+	notificationCrud.SetCrudRepo(r)
+	outboxEventCrud.SetCrudRepo(r)
+	webhookCrud.SetCrudRepo(r)
+	webhookDeliveryCrud.SetCrudRepo(r)
+	ratingCrud.SetCrudRepo(r)
+	ratingHistoryCrud.SetCrudRepo(r)
+	leaderboardCrud.SetCrudRepo(r)
+	playerMatchCrud.SetCrudRepo(r)
+	playerTournamentCrud.SetCrudRepo(r)
+	matchResultCrud.SetCrudRepo(r)
 
 	r.NameCache = cache.NewCache[string, string]()
 	return r
 }
 
+// BeginTx starts transaction, in memory repo without BeginMemoryTx returns transaction that only groups calls
+func (r *Repo) BeginTx(ctx context.Context) (Tx, error) {
+	if r.DB == nil {
+		if r.BeginMemoryTx != nil {
+			return r.BeginMemoryTx(), nil
+		}
+		return memoryTx{}, nil
+	}
+	return r.DB.BeginTx(ctx, nil)
+}
+
+var memoryImageSeq int64
+
 func (r *Repo) GetImageName(ctx context.Context) (string, error) {
 	var seq string
-	err := r.DB.QueryRowContext(ctx, "select nextval('image_seq');").Scan(&seq)
+	var err error
+	if r.DB == nil {
+		seq = strconv.FormatInt(atomic.AddInt64(&memoryImageSeq, 1), 10)
+	} else {
+		err = r.DB.QueryRowContext(ctx, "select nextval('image_seq');").Scan(&seq)
+	}
 	hasher := sha256.New()
 	hasher.Write([]byte(seq))
 	return base32.StdEncoding.EncodeToString(hasher.Sum(nil)), err
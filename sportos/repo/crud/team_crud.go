@@ -23,7 +23,7 @@ func InitTeamCrud(db *sql.DB) *TeamCrud {
 
 const (
 	team_select = `
-		select te.team_id, te.name, te.sport, te.status, te.players, te.created_at, te.created_by, te.updated_at, te.updated_by, te.deleted_at, te.deleted_by
+		select te.team_id, te.name, te.sport, te.status, te.created_at, te.created_by, te.updated_at, te.updated_by, te.deleted_at, te.deleted_by
 		from team te
 	`
 	team_count = `select count(*) from team te `
@@ -31,6 +31,29 @@ const (
 
 ////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
 
+// loadMembers sets players of teams from team_member
+func (r *TeamCrud) loadMembers(ctx context.Context, teams []DR.Team, qa QueryAble) error {
+	if len(teams) == 0 {
+		return nil
+	}
+	ids := []string{}
+	for _, te := range teams {
+		ids = append(ids, te.TeamId)
+	}
+	members, err := r.crudRepo.TeamMemberCrud.Search(ctx, DR.TeamMemberSearchParams{TeamIds: ids}, qa)
+	if err != nil {
+		return err
+	}
+	byTeam := map[string][]DR.TeamMember{}
+	for _, tm := range members {
+		byTeam[tm.TeamId] = append(byTeam[tm.TeamId], tm)
+	}
+	for i := range teams {
+		teams[i].SetMembers(byTeam[teams[i].TeamId])
+	}
+	return nil
+}
+
 func (r *TeamCrud) CheckConstraints(ctx context.Context, te DR.Team, qa QueryAble) bool {
 	L.L.WithRequestID(ctx).Info("TeamCrud.checkConstraints", L.Any("id", te))
 
@@ -63,9 +86,9 @@ func (r *TeamCrud) Create(ctx context.Context, en DR.Team, qa QueryAble, by *str
 		return en, fmt.Errorf("team with name %s already exists for sport %s", en.Name, en.Sport)
 	}
 
-	query := `insert into team (name, sport, status, players, created_at, created_by)
-	values ($1, $2, $3, $4, $5, $6) RETURNING team_id;`
-	params := []interface{}{en.Name, en.Sport, en.Status, en.Players, en.CreatedAt, en.CreatedBy}
+	query := `insert into team (name, sport, status, created_at, created_by)
+	values ($1, $2, $3, $4, $5) RETURNING team_id;`
+	params := []interface{}{en.Name, en.Sport, en.Status, en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("TeamCrud.Create insert", L.String("query", query), L.Any("params", params))
 
@@ -74,6 +97,17 @@ func (r *TeamCrud) Create(ctx context.Context, en DR.Team, qa QueryAble, by *str
 		util.LogPqError(ctx, err)
 		return en, err
 	}
+	// first player is owner of team
+	for i, playerId := range en.Players {
+		role := DR.TR_MEMBER
+		if i == 0 {
+			role = DR.TR_OWNER
+		}
+		_, err = r.crudRepo.TeamMemberCrud.Create(ctx, DR.TeamMember{TeamId: en.TeamId, PlayerId: playerId, Role: role}, qa, by)
+		if err != nil {
+			return en, err
+		}
+	}
 	pen, err := r.GetById(ctx, en.TeamId, qa)
 	if err != nil {
 		util.LogPqError(ctx, err)
@@ -108,14 +142,17 @@ func (r *TeamCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Tea
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&te.TeamId, &te.Name, &te.Sport, &te.Status, &te.Players, &te.CreatedAt, &te.CreatedBy, &te.UpdatedAt, &te.UpdatedBy, &te.DeletedAt, &te.DeletedBy)
+	err := row.Scan(&te.TeamId, &te.Name, &te.Sport, &te.Status, &te.CreatedAt, &te.CreatedBy, &te.UpdatedAt, &te.UpdatedBy, &te.DeletedAt, &te.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("team does not exist for username: %v", id)
 		}
+		return te, err
 	}
+	teams := []DR.Team{te}
+	err = r.loadMembers(ctx, teams, qa)
 
-	return te, err
+	return teams[0], err
 }
 
 func (r *TeamCrud) GetCount(ctx context.Context, sp DR.TeamSearchParams, qa QueryAble) (int, error) {
@@ -177,7 +214,7 @@ func (r *TeamCrud) Search(ctx context.Context, sp DR.TeamSearchParams, qa QueryA
 
 	for rows.Next() {
 		te := DR.Team{}
-		err := rows.Scan(&te.TeamId, &te.Name, &te.Sport, &te.Status, &te.Players, &te.CreatedAt, &te.CreatedBy, &te.UpdatedAt, &te.UpdatedBy, &te.DeletedAt, &te.DeletedBy)
+		err := rows.Scan(&te.TeamId, &te.Name, &te.Sport, &te.Status, &te.CreatedAt, &te.CreatedBy, &te.UpdatedAt, &te.UpdatedBy, &te.DeletedAt, &te.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -187,6 +224,10 @@ func (r *TeamCrud) Search(ctx context.Context, sp DR.TeamSearchParams, qa QueryA
 	if len(results) == 0 {
 		L.L.WithRequestID(ctx).Warn("TeamCrud.Search No rows returned ")
 	}
+	err = r.loadMembers(ctx, results, qa)
+	if err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
@@ -231,3 +272,23 @@ func (r *TeamCrud) Update(ctx context.Context, up DR.TeamUpdateParams, qa QueryA
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a team
+func (r *TeamCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("TeamCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `team`, "team_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,137 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type TeamMemberCrud struct {
+	Crud
+}
+
+func InitTeamMemberCrud(db *sql.DB) *TeamMemberCrud {
+	return &TeamMemberCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	team_member_select = `
+		select tm.team_id, tm.player_id, tm.role, tm.position, tm.created_at, tm.created_by
+		from team_member tm
+	`
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanTeamMember(row interface{ Scan(...interface{}) error }, tm *DR.TeamMember) error {
+	return row.Scan(&tm.TeamId, &tm.PlayerId, &tm.Role, &tm.Position, &tm.CreatedAt, &tm.CreatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Adds player to team, player gets next position in team
+func (r *TeamMemberCrud) Create(ctx context.Context, en DR.TeamMember, qa QueryAble, by *string) (DR.TeamMember, error) {
+	L.L.WithRequestID(ctx).Info("TeamMemberCrud.Create", L.Any("teamMember", en))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	if _, err := r.GetById(ctx, en.TeamId, en.PlayerId, qa); err == nil {
+		return en, fmt.Errorf("player %s is already in team %s", en.PlayerId, en.TeamId)
+	}
+
+	query := `insert into team_member (team_id, player_id, role, position, created_at, created_by)
+	select $1, $2, $3, coalesce(max(position) + 1, 0), $4, $5 from team_member where team_id = $1;`
+	params := []interface{}{en.TeamId, en.PlayerId, en.Role, en.CreatedAt, en.CreatedBy}
+
+	L.L.Debug("TeamMemberCrud.Create insert", L.String("query", query), L.Any("params", params))
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+	pen, err := r.GetById(ctx, en.TeamId, en.PlayerId, qa)
+	if err != nil {
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, nil, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns membership of player in team
+func (r *TeamMemberCrud) GetById(ctx context.Context, teamId, playerId string, qa QueryAble) (DR.TeamMember, error) {
+	L.L.WithRequestID(ctx).Info("TeamMemberCrud.GetById", L.String("teamId", teamId), L.String("playerId", playerId))
+
+	db := r.GetTx(qa)
+
+	query := ""
+	if qa != nil {
+		query = team_member_select +
+			`where tm.team_id=$1 and tm.player_id=$2 for update`
+	} else {
+		query = team_member_select +
+			`where tm.team_id=$1 and tm.player_id=$2`
+	}
+	tm := DR.TeamMember{}
+	err := scanTeamMember(db.QueryRowContext(ctx, query, teamId, playerId), &tm)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("player %v is not in team %v", playerId, teamId)
+		}
+	}
+
+	return tm, err
+}
+
+func (r *TeamMemberCrud) Search(ctx context.Context, sp DR.TeamMemberSearchParams, qa QueryAble) ([]DR.TeamMember, error) {
+	L.L.WithRequestID(ctx).Info("TeamMemberCrud.Search", L.Any("teamMember", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.TeamMember{}
+	var params []interface{}
+
+	query := team_member_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("TeamMemberCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tm := DR.TeamMember{}
+		err := scanTeamMember(rows, &tm)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tm)
+	}
+	return results, nil
+}
@@ -23,7 +23,7 @@ func InitUserCrud(db *sql.DB) *UserCrud {
 
 const (
 	user_select = `
-		select usr.user_id, usr.email, usr.email_verified, usr.user_type, usr.password_hash, usr.token, usr.token_valid_until, usr.token_refresh_until, usr.created_at, usr.created_by, usr.updated_at, usr.updated_by, usr.deleted_at, usr.deleted_by
+		select usr.user_id, usr.email, usr.email_verified, usr.user_type, usr.password_hash, usr.token, usr.token_valid_until, usr.token_refresh_until, usr.token_version, usr.created_at, usr.created_by, usr.updated_at, usr.updated_by, usr.deleted_at, usr.deleted_by
 		from "user" usr
 	`
 	user_count = `select count(*) from "user" usr `
@@ -47,7 +47,8 @@ func (r *UserCrud) exists(ctx context.Context, id string, qa QueryAble) bool {
 	return count > 0
 }
 
-func trimMail(s string) string {
+// TrimMail removes social login prefix from stored email
+func TrimMail(s string) string {
 	if len(s) > 7 && s[0:7] == "google_" {
 		return s[7:]
 	}
@@ -118,13 +119,13 @@ func (r *UserCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Use
 	row := db.QueryRowContext(ctx, query,
 		id)
 
-	err := row.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
+	err := row.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.TokenVersion, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("user does not exist for username: %v", id)
 		}
 	}
-	usr.Email = trimMail(usr.Email)
+	usr.Email = TrimMail(usr.Email)
 
 	return usr, err
 }
@@ -147,13 +148,13 @@ func (r *UserCrud) GetByEmail(ctx context.Context, email string, qa QueryAble) (
 	row := db.QueryRowContext(ctx, query,
 		email)
 
-	err := row.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
+	err := row.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.TokenVersion, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = fmt.Errorf("user does not exist for email: %v", email)
 		}
 	}
-	usr.Email = trimMail(usr.Email)
+	usr.Email = TrimMail(usr.Email)
 
 	return usr, err
 }
@@ -217,11 +218,11 @@ func (r *UserCrud) Search(ctx context.Context, sp DR.UserSearchParams, qa QueryA
 
 	for rows.Next() {
 		usr := DR.User{}
-		err := rows.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
+		err := rows.Scan(&usr.Username, &usr.Email, &usr.EmailVerified, &usr.UserType, &usr.PasswordHash, &usr.Token, &usr.TokenValidUntil, &usr.TokenRefreshUntil, &usr.TokenVersion, &usr.CreatedAt, &usr.CreatedBy, &usr.UpdatedAt, &usr.UpdatedBy, &usr.DeletedAt, &usr.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
-		usr.Email = trimMail(usr.Email)
+		usr.Email = TrimMail(usr.Email)
 		results = append(results, usr)
 	}
 
@@ -272,3 +273,23 @@ func (r *UserCrud) Update(ctx context.Context, up DR.UserUpdateParams, qa QueryA
 
 	return pen, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a user
+func (r *UserCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("UserCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `"user"`, "user_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"backend/sportos/repo/util"
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/lib/pq"
 )
@@ -24,7 +25,7 @@ func InitUserPostCrud(db *sql.DB) *UserPostCrud {
 
 const (
 	userpost_select = `
-		select up.user_id, up.user_text, up.image_names, up.created_at
+		select up.post_id, up.user_id, up.user_text, up.image_names, up.created_at, up.created_by, up.deleted_at, up.deleted_by
 		from userpost up
 	`
 	userpost_count = `select count(*) from userpost up `
@@ -39,26 +40,64 @@ func (r *UserPostCrud) Create(ctx context.Context, en DR.UserPost, qa QueryAble,
 	db := r.GetTx(qa)
 
 	if en.CreatedAt.IsZero() {
-		en.EditInfoC = DR.CreateEditInfoC(by)
+		en.EditInfoCD = DR.CreateEditInfoCD(by)
 	}
 
-	query := `insert into userpost (user_id, user_text, image_names, created_at)
-	values ($1, $2, $3, $4);`
-	params := []interface{}{en.UserId, en.UserText, pq.Array(en.ImageNames), en.CreatedAt}
+	query := `insert into userpost (user_id, user_text, image_names, created_at, created_by)
+	values ($1, $2, $3, $4, $5) RETURNING post_id;`
+	params := []interface{}{en.UserId, en.UserText, pq.Array(en.ImageNames), en.CreatedAt, en.CreatedBy}
 
 	L.L.Debug("UserPostCrud.Create insert", L.String("query", query), L.Any("params", params))
 
-	_, err := db.ExecContext(ctx, query, params...)
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.PostId)
 	if err != nil {
 		util.LogPqError(ctx, err)
 		return en, err
 	}
+	pen, err := r.GetById(ctx, en.PostId, qa)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return pen, err
+	}
 
-	return en, nil
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, nil, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
 }
 
 ////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
 
+// GetById returns user post by id
+func (r *UserPostCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.UserPost, error) {
+	L.L.WithRequestID(ctx).Info("UserPostCrud.GetById", L.String("id", id))
+
+	db := r.GetTx(qa)
+
+	up := DR.UserPost{}
+	query := ""
+	if qa != nil {
+		query = userpost_select +
+			`where up.post_id=$1 for update`
+	} else {
+		query = userpost_select +
+			`where up.post_id=$1`
+	}
+	row := db.QueryRowContext(ctx, query,
+		id)
+
+	err := row.Scan(&up.PostId, &up.UserId, &up.UserText, pq.Array(&up.ImageNames), &up.CreatedAt, &up.CreatedBy, &up.DeletedAt, &up.DeletedBy)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("user post does not exist for id: %v", id)
+		}
+	}
+
+	return up, err
+}
+
 func (r *UserPostCrud) GetCount(ctx context.Context, sp DR.UserPostSearchParams, qa QueryAble) (int, error) {
 	L.L.WithRequestID(ctx).Info("UserPostCrud.GetCount", L.Any("userpost", sp))
 
@@ -118,7 +157,7 @@ func (r *UserPostCrud) Search(ctx context.Context, sp DR.UserPostSearchParams, q
 
 	for rows.Next() {
 		up := DR.UserPost{}
-		err := rows.Scan(&up.UserId, &up.UserText, pq.Array(&up.ImageNames), &up.CreatedAt)
+		err := rows.Scan(&up.PostId, &up.UserId, &up.UserText, pq.Array(&up.ImageNames), &up.CreatedAt, &up.CreatedBy, &up.DeletedAt, &up.DeletedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -130,3 +169,23 @@ func (r *UserPostCrud) Search(ctx context.Context, sp DR.UserPostSearchParams, q
 	}
 	return results, nil
 }
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a user post
+func (r *UserPostCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("UserPostCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `userpost`, "post_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,159 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type VerificationTokenCrud struct {
+	Crud
+}
+
+func InitVerificationTokenCrud(db *sql.DB) *VerificationTokenCrud {
+	return &VerificationTokenCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	verification_token_select = `
+		select vt.token_hash, vt.user_id, vt.purpose, vt.valid_until, vt.consumed_at, vt.created_at, vt.created_by
+		from verification_token vt
+	`
+	verification_token_count = `select count(*) from verification_token vt `
+)
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a verification token
+func (r *VerificationTokenCrud) Create(ctx context.Context, en DR.VerificationToken, qa QueryAble, by *string) (DR.VerificationToken, error) {
+	L.L.WithRequestID(ctx).Info("VerificationTokenCrud.Create", L.String("userId", en.UserId), L.Any("purpose", en.Purpose))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	query := `insert into verification_token (token_hash, user_id, purpose, valid_until, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6);`
+	params := []interface{}{en.TokenHash, en.UserId, en.Purpose, en.ValidUntil, en.CreatedAt, en.CreatedBy}
+
+	_, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns verification token by its hash
+func (r *VerificationTokenCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.VerificationToken, error) {
+	L.L.WithRequestID(ctx).Info("VerificationTokenCrud.GetById")
+
+	db := r.GetTx(qa)
+
+	vt := DR.VerificationToken{}
+	query := ""
+	if qa != nil {
+		query = verification_token_select +
+			`where vt.token_hash=$1 for update`
+	} else {
+		query = verification_token_select +
+			`where vt.token_hash=$1`
+	}
+	row := db.QueryRowContext(ctx, query, id)
+
+	err := row.Scan(&vt.TokenHash, &vt.UserId, &vt.Purpose, &vt.ValidUntil, &vt.ConsumedAt, &vt.CreatedAt, &vt.CreatedBy)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("verification token does not exist")
+		}
+	}
+	return vt, err
+}
+
+// GetValid returns token by hash if it has given purpose, isn't consumed and isn't expired
+func (r *VerificationTokenCrud) GetValid(ctx context.Context, id string, purpose DR.TokenPurpose, qa QueryAble) (DR.VerificationToken, error) {
+	vt, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return vt, err
+	}
+	if vt.Purpose != purpose || vt.ConsumedAt != nil || vt.ValidUntil.Before(time.Now()) {
+		return vt, fmt.Errorf("verification token is not valid")
+	}
+	return vt, nil
+}
+
+func (r *VerificationTokenCrud) GetCount(ctx context.Context, sp DR.VerificationTokenSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("VerificationTokenCrud.GetCount", L.Any("verificationToken", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := verification_token_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("VerificationTokenCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// Consume marks token as used, token can be consumed only once and only before it expires
+func (r *VerificationTokenCrud) Consume(ctx context.Context, id string, purpose DR.TokenPurpose, qa QueryAble) (DR.VerificationToken, error) {
+	L.L.WithRequestID(ctx).Info("VerificationTokenCrud.Consume", L.Any("purpose", purpose))
+
+	db := r.GetTx(qa)
+
+	query := `update verification_token set consumed_at = $1
+	where token_hash = $2 and purpose = $3 and consumed_at is null and valid_until > $1
+	returning user_id;`
+	vt := DR.VerificationToken{TokenHash: id, Purpose: purpose}
+	err := db.QueryRowContext(ctx, query, time.Now().UTC(), id, purpose).Scan(&vt.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return vt, fmt.Errorf("verification token is not valid")
+		}
+		util.LogPqError(ctx, err)
+		return vt, err
+	}
+	return vt, nil
+}
+
+// Invalidate consumes all unused tokens of user for given purpose
+func (r *VerificationTokenCrud) Invalidate(ctx context.Context, userId string, purpose DR.TokenPurpose, qa QueryAble) error {
+	L.L.WithRequestID(ctx).Info("VerificationTokenCrud.Invalidate", L.String("userId", userId), L.Any("purpose", purpose))
+
+	db := r.GetTx(qa)
+
+	query := `update verification_token set consumed_at = $1
+	where user_id = $2 and purpose = $3 and consumed_at is null;`
+	_, err := db.ExecContext(ctx, query, time.Now().UTC(), userId, purpose)
+	if err != nil {
+		util.LogPqError(ctx, err)
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type WebhookCrud struct {
+	Crud
+}
+
+func InitWebhookCrud(db *sql.DB) *WebhookCrud {
+	return &WebhookCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	webhook_select = `
+		select wh.webhook_id, wh.place_id, wh.url, wh.secret, wh.events, wh.active, wh.created_at, wh.created_by, wh.updated_at, wh.updated_by, wh.deleted_at, wh.deleted_by
+		from webhook wh
+	`
+	webhook_count = `select count(*) from webhook wh `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanWebhook(row interface{ Scan(...interface{}) error }, wh *DR.Webhook) error {
+	return row.Scan(&wh.WebhookId, &wh.PlaceId, &wh.Url, &wh.Secret, pq.Array(&wh.Events), &wh.Active, &wh.CreatedAt, &wh.CreatedBy, &wh.UpdatedAt, &wh.UpdatedBy, &wh.DeletedAt, &wh.DeletedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a Webhook
+func (r *WebhookCrud) Create(ctx context.Context, en DR.Webhook, qa QueryAble, by *string) (DR.Webhook, error) {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.Create", L.String("placeId", en.PlaceId), L.String("url", en.Url))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	if en.Events == nil {
+		en.Events = []string{}
+	}
+
+	query := `insert into webhook (place_id, url, secret, events, active, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7) RETURNING webhook_id;`
+	params := []interface{}{en.PlaceId, en.Url, en.Secret, pq.Array(en.Events), en.Active, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.WebhookId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+	pen, err := r.GetById(ctx, en.WebhookId, qa)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, nil, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns webhook by id
+func (r *WebhookCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.Webhook, error) {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.GetById", L.String("id", id))
+
+	db := r.GetTx(qa)
+
+	wh := DR.Webhook{}
+	query := ""
+	if qa != nil {
+		query = webhook_select +
+			`where wh.webhook_id=$1 for update`
+	} else {
+		query = webhook_select +
+			`where wh.webhook_id=$1`
+	}
+
+	err := scanWebhook(db.QueryRowContext(ctx, query, id), &wh)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("webhook does not exist for id: %v", id)
+		}
+	}
+	return wh, err
+}
+
+func (r *WebhookCrud) GetCount(ctx context.Context, sp DR.WebhookSearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.GetCount", L.Any("webhook", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := webhook_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("WebhookCrud.GetCount query", L.Any("query", L.String("query", query)))
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns webhooks in order in which they were created
+func (r *WebhookCrud) Search(ctx context.Context, sp DR.WebhookSearchParams, qa QueryAble) ([]DR.Webhook, error) {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.Search", L.Any("webhook", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.Webhook{}
+	var params []interface{}
+
+	query := webhook_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("WebhookCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		wh := DR.Webhook{}
+		err := scanWebhook(rows, &wh)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, wh)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a webhook
+func (r *WebhookCrud) Update(ctx context.Context, up DR.WebhookUpdateParams, qa QueryAble, by *string) (DR.Webhook, error) {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.Update", L.String("id", up.Id))
+
+	up.PopulateUpdateFields(by)
+
+	old, _ := r.GetById(ctx, up.Id, qa)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return DR.Webhook{}, err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return DR.Webhook{}, fmt.Errorf("no rows affected")
+	}
+	pen, err := r.GetById(ctx, up.Id, qa)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return pen, err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, &pen, qa, by)
+	if err != nil {
+		return pen, err
+	}
+
+	return pen, nil
+}
+
+////////////////////////////////////////////////DELETE///////////////////////////////////////////////////////////////////////////////////
+
+// soft deletes a webhook, deleted webhooks don't get new deliveries
+func (r *WebhookCrud) Delete(ctx context.Context, id string, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("WebhookCrud.Delete", L.String("id", id))
+
+	old, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return err
+	}
+
+	err = r.softDelete(ctx, `webhook`, "webhook_id", id, qa, by)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.crudRepo.AuditCrud.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,215 @@
+package crud
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type WebhookDeliveryCrud struct {
+	Crud
+}
+
+func InitWebhookDeliveryCrud(db *sql.DB) *WebhookDeliveryCrud {
+	return &WebhookDeliveryCrud{
+		Crud{
+			db: db,
+		},
+	}
+}
+
+const (
+	webhook_delivery_select = `
+		select wd.delivery_id, wd.webhook_id, wd.outbox_event_id, wd.event_type, wd.request, wd.response, wd.response_code, wd.status, wd.attempts, wd.next_attempt_at,
+		wd.last_error, wd.delivered_at, wd.created_at, wd.created_by, wd.updated_at, wd.updated_by
+		from webhook_delivery wd
+	`
+	webhook_delivery_count = `select count(*) from webhook_delivery wd `
+)
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }, wd *DR.WebhookDelivery) error {
+	return row.Scan(&wd.DeliveryId, &wd.WebhookId, &wd.OutboxEventId, &wd.EventType, &wd.Request, &wd.Response, &wd.ResponseCode, &wd.Status, &wd.Attempts, &wd.NextAttemptAt,
+		&wd.LastError, &wd.DeliveredAt, &wd.CreatedAt, &wd.CreatedBy, &wd.UpdatedAt, &wd.UpdatedBy)
+}
+
+////////////////////////////////////////////////CREATE///////////////////////////////////////////////////////////////////////////////////
+
+// Creates a delivery of domain event to webhook, it is sent by webhook worker after transaction commits
+func (r *WebhookDeliveryCrud) Create(ctx context.Context, en DR.WebhookDelivery, qa QueryAble, by *string) (DR.WebhookDelivery, error) {
+	L.L.WithRequestID(ctx).Info("WebhookDeliveryCrud.Create", L.String("webhookId", en.WebhookId), L.String("outboxEventId", en.OutboxEventId))
+
+	db := r.GetTx(qa)
+
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.WDS_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+
+	query := `insert into webhook_delivery (webhook_id, outbox_event_id, event_type, request, status, attempts, next_attempt_at, created_at, created_by)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING delivery_id;`
+	params := []interface{}{en.WebhookId, en.OutboxEventId, en.EventType, en.Request, en.Status, en.Attempts, en.NextAttemptAt, en.CreatedAt, en.CreatedBy}
+
+	err := db.QueryRowContext(ctx, query, params...).Scan(&en.DeliveryId)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return en, err
+	}
+
+	return en, nil
+}
+
+////////////////////////////////////////////////READ/////////////////////////////////////////////////////////////////////////////////////
+
+// GetById returns webhook delivery by id
+func (r *WebhookDeliveryCrud) GetById(ctx context.Context, id string, qa QueryAble) (DR.WebhookDelivery, error) {
+	L.L.WithRequestID(ctx).Info("WebhookDeliveryCrud.GetById", L.String("deliveryId", id))
+
+	db := r.GetTx(qa)
+
+	wd := DR.WebhookDelivery{}
+	query := ""
+	if qa != nil {
+		query = webhook_delivery_select +
+			`where wd.delivery_id=$1 for update`
+	} else {
+		query = webhook_delivery_select +
+			`where wd.delivery_id=$1`
+	}
+
+	err := scanWebhookDelivery(db.QueryRowContext(ctx, query, id), &wd)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = fmt.Errorf("webhook delivery does not exist for id: %v", id)
+		}
+	}
+	return wd, err
+}
+
+func (r *WebhookDeliveryCrud) GetCount(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa QueryAble) (int, error) {
+	L.L.WithRequestID(ctx).Info("WebhookDeliveryCrud.GetCount", L.Any("webhookDelivery", sp))
+
+	db := r.GetTx(qa)
+
+	var params []interface{}
+
+	query := webhook_delivery_count
+
+	err := DR.AppendCountQuery(&sp, &query, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	cnt := 0
+	err = db.QueryRowContext(ctx, query, params...).Scan(&cnt)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return 0, err
+	}
+	return cnt, nil
+}
+
+// Search returns webhook deliveries, newest first
+func (r *WebhookDeliveryCrud) Search(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa QueryAble) ([]DR.WebhookDelivery, error) {
+	L.L.WithRequestID(ctx).Info("WebhookDeliveryCrud.Search", L.Any("webhookDelivery", sp))
+
+	db := r.GetTx(qa)
+
+	results := []DR.WebhookDelivery{}
+	var params []interface{}
+
+	query := webhook_delivery_select
+
+	err := DR.AppendQuery(&sp, &query, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	L.L.WithRequestID(ctx).Debug("WebhookDeliveryCrud.Search query", L.Any("query", L.String("query", query)))
+
+	rows, err := db.QueryContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		wd := DR.WebhookDelivery{}
+		err := scanWebhookDelivery(rows, &wd)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, wd)
+	}
+	return results, nil
+}
+
+// GetPending returns pending deliveries that are due for sending and locks them,
+// rows locked by other workers are skipped
+func (r *WebhookDeliveryCrud) GetPending(ctx context.Context, limit int, qa QueryAble) ([]DR.WebhookDelivery, error) {
+	db := r.GetTx(qa)
+
+	query := webhook_delivery_select +
+		`where wd.status=$1 and wd.next_attempt_at<=$2 order by wd.next_attempt_at, wd.delivery_id limit $3`
+	if qa != nil {
+		query += ` for update skip locked`
+	}
+
+	rows, err := db.QueryContext(ctx, query, DR.WDS_PENDING, time.Now().UTC(), limit)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []DR.WebhookDelivery{}
+	for rows.Next() {
+		wd := DR.WebhookDelivery{}
+		err := scanWebhookDelivery(rows, &wd)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, wd)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////UPDATE///////////////////////////////////////////////////////////////////////////////////
+
+// updates a webhook delivery
+func (r *WebhookDeliveryCrud) Update(ctx context.Context, up DR.WebhookDeliveryUpdateParams, qa QueryAble, by *string) error {
+	L.L.WithRequestID(ctx).Info("WebhookDeliveryCrud.Update", L.String("deliveryId", up.Id))
+
+	up.PopulateUpdateFields(by)
+
+	db := r.GetTx(qa)
+	var query string
+	params := []interface{}{}
+
+	DR.AppendUpdateQuery(up, &query, &params)
+
+	L.L.Debug("WebhookDeliveryCrud.Update update", L.String("query", query), L.Any("params", params))
+
+	result, err := db.ExecContext(ctx, query, params...)
+	if err != nil {
+		util.LogPqError(ctx, err)
+		return err
+	}
+
+	ra, _ := result.RowsAffected()
+	if ra == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BookingKind string
+
+const (
+	BK_MATCH      BookingKind = "MATCH"
+	BK_PRACTICE   BookingKind = "PRACTICE"
+	BK_TOURNAMENT BookingKind = "TOURNAMENT"
+)
+
+type BookingStatus string
+
+const (
+	BS_PENDING   BookingStatus = "PENDING"
+	BS_CONFIRMED BookingStatus = "CONFIRMED"
+)
+
+// Booking is appointment at place or coach (owner) for match, practice or tournament (reference).
+// Confirmed bookings of same owner can't overlap, pending bookings (practices coach didn't accept yet)
+// can overlap each other and confirmed bookings.
+type Booking struct {
+	BookingId   string        `json:"bookingId" column:"booking_id"`
+	OwnerId     string        `json:"ownerId" column:"owner_id"`
+	Kind        BookingKind   `json:"kind" column:"kind"`
+	ReferenceId string        `json:"referenceId" column:"reference_id"`
+	StartTime   time.Time     `json:"startTime" column:"start_time"`
+	EndTime     time.Time     `json:"endTime" column:"end_time"`
+	Status      BookingStatus `json:"status" column:"status"`
+	EditInfoCUD
+}
+
+func (s *Booking) GetTableName() SportosEntity {
+	return "booking"
+}
+
+func (s *Booking) GetId() string {
+	return s.BookingId
+}
+
+// Overlaps returns true if booking and half open interval [start, end) have common time
+func (s *Booking) Overlaps(start, end time.Time) bool {
+	return s.StartTime.Before(end) && start.Before(s.EndTime)
+}
+
+type BookingSearchParams struct {
+	OwnerId     *string        `json:"ownerId,omitempty"`
+	Kind        *BookingKind   `json:"kind,omitempty"`
+	ReferenceId *string        `json:"referenceId,omitempty"`
+	Status      *BookingStatus `json:"status,omitempty"`
+	// From and To return bookings that overlap [From, To)
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	EditInfoCUDSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *BookingSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "bo"
+}
+
+func (sp *BookingSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *BookingSearchParams) validate() error {
+	if (sp.From == nil) != (sp.To == nil) {
+		return fmt.Errorf("from and to must be set together")
+	}
+	if sp.From != nil && !sp.From.Before(*sp.To) {
+		return fmt.Errorf("from must be before to")
+	}
+	err := sp.EditInfoCUDSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *BookingSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *BookingSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.OwnerId != nil {
+		*params = append(*params, *sp.OwnerId)
+		*query += fmt.Sprintf(" and %v.owner_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Kind != nil {
+		*params = append(*params, *sp.Kind)
+		*query += fmt.Sprintf(" and %v.kind=$%d", tablePrefix, len(*params))
+	}
+	if sp.ReferenceId != nil {
+		*params = append(*params, *sp.ReferenceId)
+		*query += fmt.Sprintf(" and %v.reference_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Status != nil {
+		*params = append(*params, *sp.Status)
+		*query += fmt.Sprintf(" and %v.status=$%d", tablePrefix, len(*params))
+	}
+	if sp.From != nil && sp.To != nil {
+		*params = append(*params, *sp.From, *sp.To)
+		*query += fmt.Sprintf(" and %v.during && tstzrange($%d, $%d, '[)')", tablePrefix, len(*params)-1, len(*params))
+	}
+	if !sp.EditInfoCUDSearchParams.IsEmpty() {
+		sp.EditInfoCUDSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *BookingSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("lower(%[1]s.during), %[1]s.booking_id", sp.GetTablePrefix())
+}
+
+func (sp *BookingSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *BookingSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type BookingUpdateParams struct {
+	Id     string
+	Status *BookingStatus
+	EditInfoUDUpdateParams
+}
+
+func (up BookingUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update booking bo set `
+
+	if up.Status != nil {
+		*params = append(*params, *up.Status)
+		*query += fmt.Sprintf("status = $%d, ", len(*params))
+	}
+
+	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where bo.booking_id = $%d;", len(*params))
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "strings"
+
+// DEFAULT_TIME_ZONE is time zone of places and coaches that registered before time zones were stored
+const DEFAULT_TIME_ZONE = "Europe/Belgrade"
+
+// cityTimeZones maps lower case names of cities to their IANA time zone
+var cityTimeZones = map[string]string{
+	"beograd":    "Europe/Belgrade",
+	"belgrade":   "Europe/Belgrade",
+	"novi sad":   "Europe/Belgrade",
+	"nis":        "Europe/Belgrade",
+	"niš":        "Europe/Belgrade",
+	"kragujevac": "Europe/Belgrade",
+	"subotica":   "Europe/Belgrade",
+	"zagreb":     "Europe/Zagreb",
+	"split":      "Europe/Zagreb",
+	"ljubljana":  "Europe/Ljubljana",
+	"sarajevo":   "Europe/Sarajevo",
+	"banja luka": "Europe/Sarajevo",
+	"podgorica":  "Europe/Podgorica",
+	"skopje":     "Europe/Skopje",
+	"budapest":   "Europe/Budapest",
+	"vienna":     "Europe/Vienna",
+	"berlin":     "Europe/Berlin",
+	"london":     "Europe/London",
+	"new york":   "America/New_York",
+}
+
+// TimeZoneOfCity returns IANA time zone of known city, city is matched case insensitive
+func TimeZoneOfCity(city string) (string, bool) {
+	tz, ok := cityTimeZones[strings.ToLower(strings.TrimSpace(city))]
+	return tz, ok
+}
@@ -41,19 +41,19 @@ type UpdateParams interface {
 }
 
 func (ei EditInfoCUD) IsDeleted() bool {
-	return ei.DeletedAt == nil
+	return ei.DeletedAt != nil
 }
 
 func (ei EditInfoCD) IsDeleted() bool {
-	return ei.DeletedAt == nil
+	return ei.DeletedAt != nil
 }
 
 func (ei EditInfoUD) IsDeleted() bool {
-	return ei.DeletedAt == nil
+	return ei.DeletedAt != nil
 }
 
 func (ei EditInfoD) IsDeleted() bool {
-	return ei.DeletedAt == nil
+	return ei.DeletedAt != nil
 }
 
 func CreateEditInfoCUD(createdBy *string) EditInfoCUD {
@@ -314,14 +314,23 @@ func (sp EditInfoUSearchParams) appendSearchQuery(tablePrefix string, query *str
 	}
 }
 
+// EditInfoDSearchParams filters rows by deletion, deleted rows are excluded unless IncludeDeleted is set
+// or one of deleted params is given
 type EditInfoDSearchParams struct {
 	DeletedAtFrom   *time.Time `json:"deletedAtFrom"`
 	DeletedAtBefore *time.Time `json:"deletedAtToBefore"`
 	DeletedBy       *string    `json:"deletedBy"`
+	IncludeDeleted  bool       `json:"includeDeleted"`
 }
 
+// IsEmpty is false by default because deleted rows have to be filtered out
 func (sp *EditInfoDSearchParams) IsEmpty() bool {
-	return sp.DeletedAtFrom == nil && sp.DeletedAtBefore == nil && sp.DeletedBy == nil
+	return sp.DeletedAtFrom == nil && sp.DeletedAtBefore == nil && sp.DeletedBy == nil && sp.IncludeDeleted
+}
+
+// OnlyDeleted returns true if params search for deleted rows
+func (sp *EditInfoDSearchParams) OnlyDeleted() bool {
+	return sp.DeletedAtFrom != nil || sp.DeletedAtBefore != nil || (sp.DeletedBy != nil && len(*sp.DeletedBy) != 0)
 }
 
 func (sp EditInfoDSearchParams) validate() error {
@@ -341,6 +350,9 @@ func (sp EditInfoDSearchParams) appendSearchQuery(tablePrefix string, query *str
 		*params = append(*params, *sp.DeletedBy)
 		*query += fmt.Sprintf(" and %v.deleted_by=$%d", tablePrefix, len(*params))
 	}
+	if !sp.IncludeDeleted && !sp.OnlyDeleted() {
+		*query += fmt.Sprintf(" and %v.deleted_at is null", tablePrefix)
+	}
 }
 
 type PagingSearchParams struct {
@@ -724,30 +736,3 @@ func (r *Reviews) Scan(value interface{}) error {
 
 	return json.Unmarshal(b, &r)
 }
-
-type Apointment struct {
-	StartTime  time.Time `json:"startTime,omitempty"`
-	EndTime    time.Time `json:"endTime,omitempty"`
-	Accepted   bool      `json:"accepted,omitempty"`
-	PracticeId string    `json:"id,omitempty"`
-}
-
-type Booking []Apointment
-
-// Value is implementation of data Valuer interface.
-func (bo Booking) Value() (driver.Value, error) {
-	return json.Marshal(bo)
-}
-
-// Scan is implementation of database/sql scanner interface.
-func (bo *Booking) Scan(value interface{}) error {
-	if value == nil {
-		return nil
-	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-
-	return json.Unmarshal(b, &bo)
-}
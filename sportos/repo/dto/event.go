@@ -13,22 +13,79 @@ import (
 )
 
 type Standing struct {
-	TeamName string `json:"teamName,omitempty"`
-	Points   *int   `json:"points,omitempty"`
-	Ranking  *int   `json:"ranking,omitempty"`
+	TeamName     string `json:"teamName,omitempty"`
+	Group        string `json:"group,omitempty"`
+	Points       *int   `json:"points,omitempty"`
+	Ranking      *int   `json:"ranking,omitempty"`
+	GroupRanking *int   `json:"groupRanking,omitempty"`
+	Played       int    `json:"played"`
+	Won          int    `json:"won"`
+	Drawn        int    `json:"drawn"`
+	Lost         int    `json:"lost"`
+	ScoreFor     int    `json:"scoreFor"`
+	ScoreAgainst int    `json:"scoreAgainst"`
 }
 
+// BracketStage is part of tournament that pairing belongs to, pairings of round robin have empty stage
+type BracketStage string
+
+const (
+	BS_GROUP    BracketStage = "GROUP"
+	BS_KNOCKOUT BracketStage = "KNOCKOUT"
+	BS_WINNERS  BracketStage = "WINNERS"
+	BS_LOSERS   BracketStage = "LOSERS"
+	BS_FINAL    BracketStage = "FINAL"
+)
+
+// Pairing is one game of round, team with bye has no opponent and advances without score.
+// Score is text of DR.Score, like 3:1 or 2:1 (6:4 3:6 7:5)
 type Pairing struct {
-	TeamOne string `json:"teamOne,omitempty"`
-	TeamTwo string `json:"teamTwo,omitempty"`
-	Score   string `json:"score"`
+	TeamOne string       `json:"teamOne,omitempty"`
+	TeamTwo string       `json:"teamTwo,omitempty"`
+	Score   string       `json:"score"`
+	Bye     bool         `json:"bye,omitempty"`
+	Group   string       `json:"group,omitempty"`
+	Stage   BracketStage `json:"stage,omitempty"`
 }
 
 type Round struct {
 	Pairing []Pairing `json:"pairing,omitempty"`
 }
 
+type TournamentFormat string
+
+const (
+	TF_ROUND_ROBIN        TournamentFormat = "ROUND_ROBIN"
+	TF_SINGLE_ELIMINATION TournamentFormat = "SINGLE_ELIMINATION"
+	TF_DOUBLE_ELIMINATION TournamentFormat = "DOUBLE_ELIMINATION"
+	TF_GROUPS_KNOCKOUT    TournamentFormat = "GROUPS_KNOCKOUT"
+)
+
+// TieBreaker orders teams with equal points
+type TieBreaker string
+
+const (
+	TB_HEAD_TO_HEAD     TieBreaker = "HEAD_TO_HEAD"
+	TB_SCORE_DIFFERENCE TieBreaker = "SCORE_DIFFERENCE"
+	TB_SCORES_FOR       TieBreaker = "SCORES_FOR"
+)
+
+// PointsRule is number of points team gets for win, draw and loss
+type PointsRule struct {
+	Win  int `json:"win"`
+	Draw int `json:"draw"`
+	Loss int `json:"loss"`
+}
+
+// Tournament holds rules of tournament together with its rounds and standings, rounds and standings
+// are maintained by tournament package
 type Tournament struct {
+	Format      TournamentFormat `json:"format,omitempty"`
+	Points      *PointsRule      `json:"points,omitempty"`
+	TieBreakers []TieBreaker     `json:"tieBreakers,omitempty"`
+	// Groups is number of groups and Advancing number of teams from every group that play knockout (GROUPS_KNOCKOUT only)
+	Groups    int        `json:"groups,omitempty"`
+	Advancing int        `json:"advancing,omitempty"`
 	Standings []Standing `json:"standings,omitempty"`
 	Rounds    []Round    `json:"rounds,omitempty"`
 }
@@ -54,6 +111,7 @@ type Event struct {
 	Sport      string      `json:"sport,omitempty" column:"sport"`
 	Status     EventStatus `json:"eventStatus,omitempty" column:"status"`
 	Time       *time.Time  `json:"time,omitempty" column:"time"`
+	TimeZone   *string     `json:"timeZone,omitempty"`
 	Teams      Teams       `json:"teams,omitempty" column:"teams"`
 	Tournament *Tournament `json:"tournament,omitempty" column:"tournament"`
 	EditInfoCUD
@@ -105,6 +163,15 @@ func (t *Teams) Scan(value interface{}) error {
 	return json.Unmarshal(b, &t)
 }
 
+// Names returns names of teams in order in which they applied, it is seeding order of tournament
+func (t Teams) Names() []string {
+	names := make([]string, 0, len(t))
+	for _, team := range t {
+		names = append(names, team.Name)
+	}
+	return names
+}
+
 type EventSearchParams struct {
 	Name   *string  `json:"name,omitempty"`
 	Owner  *string  `json:"owner,omitempty"`
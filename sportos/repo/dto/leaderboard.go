@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LeaderboardMetric is value by which players on leaderboard are ranked
+type LeaderboardMetric string
+
+const (
+	LM_WIN_RATIO   LeaderboardMetric = "winRatio"
+	LM_MATCHES     LeaderboardMetric = "matches"
+	LM_TOURNAMENTS LeaderboardMetric = "tournaments"
+	LM_RATING      LeaderboardMetric = "rating"
+)
+
+func (lm LeaderboardMetric) IsValid() bool {
+	return lm == LM_WIN_RATIO || lm == LM_MATCHES || lm == LM_TOURNAMENTS || lm == LM_RATING
+}
+
+// Leaderboard is materialized row of leaderboard of sport, it is computed from statistics and rating
+// of player when they change so leaderboards don't read statistics of all players.
+// Name and City are joined from player
+type Leaderboard struct {
+	PlayerId string          `json:"playerId" column:"player_id"`
+	Name     string          `json:"name"`
+	City     string          `json:"city"`
+	Sport    string          `json:"sport" column:"sport"`
+	WinRatio decimal.Decimal `json:"winRatio" column:"win_ratio"`
+	Matches  int             `json:"matches" column:"matches"`
+	// Tournaments is number of tournaments in which team of player got ranking
+	Tournaments int `json:"tournaments" column:"tournaments"`
+	// TournamentPoints are 3 points for every won tournament, 2 for second and 1 for third place
+	TournamentPoints int `json:"tournamentPoints" column:"tournament_points"`
+	// Rating is nil until first rated match of player
+	Rating    *float64  `json:"rating" column:"rating"`
+	UpdatedAt time.Time `json:"updatedAt" column:"updated_at"`
+}
+
+// TournamentPoints returns points of tournament finish on leaderboard
+func TournamentPoints(ranking int) int {
+	if ranking < 1 || ranking > 3 {
+		return 0
+	}
+	return 4 - ranking
+}
+
+type LeaderboardSearchParams struct {
+	PlayerId *string `json:"playerId,omitempty"`
+	Sport    *string `json:"sport,omitempty"`
+	// City is city of player
+	City *string `json:"city,omitempty"`
+	// MinMatches leaves out players with less matches, their win ratio isn't reliable yet
+	MinMatches *int `json:"minMatches,omitempty"`
+	// By is metric players are ranked by, default is LM_WIN_RATIO. Players without rating are left out of LM_RATING
+	By LeaderboardMetric `json:"by,omitempty"`
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *LeaderboardSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "lb"
+}
+
+func (sp *LeaderboardSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *LeaderboardSearchParams) validate() error {
+	if sp.By != "" && !sp.By.IsValid() {
+		return fmt.Errorf("leaderboard can't be ranked by %s", sp.By)
+	}
+	return sp.PagingSearchParams.validate()
+}
+
+// joinTables joins player for name and city, leaderboard of deleted players isn't returned
+func (sp *LeaderboardSearchParams) joinTables(query *string) {
+	*query += fmt.Sprintf(`inner join player pl on pl.user_id = %s.player_id `, sp.GetTablePrefix())
+}
+
+func (sp *LeaderboardSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where pl.deleted_at is null `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Sport != nil {
+		*params = append(*params, *sp.Sport)
+		*query += fmt.Sprintf(" and %v.sport=$%d", tablePrefix, len(*params))
+	}
+	if sp.City != nil && *sp.City != "" {
+		*params = append(*params, *sp.City)
+		*query += fmt.Sprintf(" and pl.city=$%d", len(*params))
+	}
+	if sp.MinMatches != nil {
+		*params = append(*params, *sp.MinMatches)
+		*query += fmt.Sprintf(" and %v.matches>=$%d", tablePrefix, len(*params))
+	}
+	if sp.By == LM_RATING {
+		*query += fmt.Sprintf(" and %v.rating is not null", tablePrefix)
+	}
+}
+
+// appendSortQuery sorts players from best by metric of leaderboard
+func (sp *LeaderboardSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	switch sp.By {
+	case LM_MATCHES:
+		*query += fmt.Sprintf("%[1]s.matches desc, %[1]s.win_ratio desc, %[1]s.player_id", sp.GetTablePrefix())
+	case LM_TOURNAMENTS:
+		*query += fmt.Sprintf("%[1]s.tournament_points desc, %[1]s.tournaments desc, %[1]s.player_id", sp.GetTablePrefix())
+	case LM_RATING:
+		*query += fmt.Sprintf("%[1]s.rating desc, %[1]s.matches desc, %[1]s.player_id", sp.GetTablePrefix())
+	default:
+		*query += fmt.Sprintf("%[1]s.win_ratio desc, %[1]s.matches desc, %[1]s.player_id", sp.GetTablePrefix())
+	}
+}
+
+func (sp *LeaderboardSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *LeaderboardSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+)
+
+type MailStatus string
+
+const (
+	MOS_PENDING MailStatus = "PENDING"
+	MOS_SENT    MailStatus = "SENT"
+	MOS_FAILED  MailStatus = "FAILED"
+)
+
+// MailOutbox is email waiting to be sent by outbox worker
+type MailOutbox struct {
+	MailId        string     `json:"mailId" column:"mail_id"`
+	Recipients    []string   `json:"recipients" column:"recipients"`
+	Subject       string     `json:"subject" column:"subject"`
+	TextBody      string     `json:"textBody" column:"text_body"`
+	HtmlBody      *string    `json:"htmlBody" column:"html_body"`
+	Status        MailStatus `json:"status" column:"status"`
+	Attempts      int        `json:"attempts" column:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt" column:"next_attempt_at"`
+	LastError     *string    `json:"lastError" column:"last_error"`
+	SentAt        *time.Time `json:"sentAt" column:"sent_at"`
+	EditInfoCU
+}
+
+func (m *MailOutbox) GetTableName() SportosEntity {
+	return "mail_outbox"
+}
+
+func (m *MailOutbox) GetId() string {
+	return m.MailId
+}
+
+type MailOutboxUpdateParams struct {
+	Id            string
+	Status        *MailStatus
+	Attempts      *int
+	NextAttemptAt *time.Time
+	LastError     *string
+	SentAt        *time.Time
+	EditInfoUUpdateParams
+}
+
+func (up MailOutboxUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update mail_outbox mo set `
+
+	if up.Status != nil {
+		*params = append(*params, *up.Status)
+		*query += fmt.Sprintf("status = $%d, ", len(*params))
+	}
+
+	if up.Attempts != nil {
+		*params = append(*params, *up.Attempts)
+		*query += fmt.Sprintf("attempts = $%d, ", len(*params))
+	}
+
+	if up.NextAttemptAt != nil {
+		*params = append(*params, *up.NextAttemptAt)
+		*query += fmt.Sprintf("next_attempt_at = $%d, ", len(*params))
+	}
+
+	if up.LastError != nil {
+		*params = append(*params, *up.LastError)
+		*query += fmt.Sprintf("last_error = $%d, ", len(*params))
+	}
+
+	if up.SentAt != nil {
+		*params = append(*params, *up.SentAt)
+		*query += fmt.Sprintf("sent_at = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where mo.mail_id = $%d;", len(*params))
+}
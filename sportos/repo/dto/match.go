@@ -15,21 +15,44 @@ import (
 type MatchStatus string
 
 const (
-	MS_CREATED  MatchStatus = "CREATED"
-	MS_FULL     MatchStatus = "FULL"
+	MS_CREATED MatchStatus = "CREATED"
+	MS_FULL    MatchStatus = "FULL"
+	// MS_RESULT_PENDING match has result that waits for confirmation of other side, see MatchResult
+	MS_RESULT_PENDING MatchStatus = "RESULT_PENDING"
+	// MS_DISPUTED match has disputed result that waits for review in backoffice
+	MS_DISPUTED MatchStatus = "DISPUTED"
 	MS_FINISHED MatchStatus = "FINISHED"
 )
 
+// MatchBalancing is algorithm that splits players of match into teams when match is full
+type MatchBalancing string
+
+const (
+	// MB_RANDOM splits players randomly
+	MB_RANDOM MatchBalancing = "RANDOM"
+	// MB_RATING splits players so sums of skill of both teams are as close as possible
+	MB_RATING MatchBalancing = "RATING"
+	// MB_POSITIONS balances skill like MB_RATING and tries to cover every position of sport in both teams
+	MB_POSITIONS MatchBalancing = "POSITIONS"
+)
+
+func (mb MatchBalancing) IsValid() bool {
+	return mb == MB_RANDOM || mb == MB_RATING || mb == MB_POSITIONS
+}
+
 type Match struct {
-	MatchId     string      `json:"matchId,omitempty" column:"match_id"`
-	Status      MatchStatus `json:"status,omitempty" column:"status"`
-	Players     *string     `json:"players,omitempty" column:"players"`
-	PlayerNames *string     `json:"playerNames,omitempty"`
-	PlaceId     string      `json:"placeId,omitempty" column:"place_id"`
-	Sport       string      `json:"sport,omitempty" column:"sport"`
-	StartTime   *time.Time  `json:"startTime,omitempty" column:"start_time"`
-	Teams       StrArr      `json:"teams,omitempty" column:"teams"`
-	Result      *string     `json:"result" column:"result"`
+	MatchId     string         `json:"matchId,omitempty" column:"match_id"`
+	Status      MatchStatus    `json:"status,omitempty" column:"status"`
+	PlayerNames *string        `json:"playerNames,omitempty"`
+	TimeZone    *string        `json:"timeZone,omitempty"`
+	PlaceId     string         `json:"placeId,omitempty" column:"place_id"`
+	Sport       string         `json:"sport,omitempty" column:"sport"`
+	StartTime   *time.Time     `json:"startTime,omitempty" column:"start_time"`
+	Result      *string        `json:"result" column:"result"`
+	Balancing   MatchBalancing `json:"balancing,omitempty" column:"balancing"`
+	// Players (ordered by position, creator first) and Teams (players by side) are loaded from match_player
+	Players StrArr   `json:"players,omitempty"`
+	Teams   []StrArr `json:"teams,omitempty"`
 	EditInfoCUD
 }
 
@@ -61,9 +84,55 @@ func (s *Match) GetId() string {
 	return s.MatchId
 }
 
+// SetRoster sets players and teams of match from its members, members must be ordered by position
+func (s *Match) SetRoster(members []MatchPlayer) {
+	s.Players = StrArr{}
+	s.Teams = nil
+	for _, mp := range members {
+		s.Players = append(s.Players, mp.PlayerId)
+		if mp.Side != nil && *mp.Side >= 0 && *mp.Side < 2 {
+			if s.Teams == nil {
+				s.Teams = []StrArr{{}, {}}
+			}
+			s.Teams[*mp.Side] = append(s.Teams[*mp.Side], mp.PlayerId)
+		}
+	}
+}
+
+func (s *Match) HasPlayer(playerId string) bool {
+	for _, id := range s.Players {
+		if id == playerId {
+			return true
+		}
+	}
+	return false
+}
+
+// SideOf returns side (0 or 1) of player in match or -1 if player isn't in any team
+func (s *Match) SideOf(playerId string) int {
+	for side, team := range s.Teams {
+		for _, id := range team {
+			if id == playerId {
+				return side
+			}
+		}
+	}
+	return -1
+}
+
+// Creator returns id of player who created match
+func (s *Match) Creator() string {
+	if len(s.Players) == 0 {
+		return ""
+	}
+	return s.Players[0]
+}
+
 type MatchSearchParams struct {
 	Status *string  `json:"status,omitempty"`
 	Sports []string `json:"sports,omitempty"`
+	// PlayerId returns only matches player is member of
+	PlayerId *string `json:"playerId,omitempty"`
 	EditInfoCUDSearchParams
 	MatchSortParams
 	PlaceSearchParams *PlaceSearchParams
@@ -116,6 +185,10 @@ func (sp *MatchSearchParams) appendSearchQuery(query *string, params *[]interfac
 		*params = append(*params, pq.Array(sp.Sports))
 		*query += fmt.Sprintf(" and %v.sport=any($%d)", tablePrefix, len(*params))
 	}
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and exists (select 1 from match_player mp where mp.match_id = %v.match_id and mp.player_id=$%d)", tablePrefix, len(*params))
+	}
 	if !sp.EditInfoCUDSearchParams.IsEmpty() {
 		sp.EditInfoCUDSearchParams.appendSearchQuery(tablePrefix, query, params)
 	}
@@ -200,11 +273,9 @@ func (sp MatchSortParams) OrderBy() string {
 }
 
 type MatchUpdateParams struct {
-	Id      string
-	Players *string
-	Status  *MatchStatus
-	Result  *string
-	Teams   *StrArr
+	Id     string
+	Status *MatchStatus
+	Result *string
 	EditInfoUDUpdateParams
 }
 
@@ -216,21 +287,11 @@ func (up MatchUpdateParams) appendUpdateQuery(query *string, params *[]interface
 		*query += fmt.Sprintf("status = $%d, ", len(*params))
 	}
 
-	if up.Players != nil {
-		*params = append(*params, up.Players)
-		*query += fmt.Sprintf("players = $%d, ", len(*params))
-	}
-
 	if up.Result != nil {
 		*params = append(*params, up.Result)
 		*query += fmt.Sprintf("result = $%d, ", len(*params))
 	}
 
-	if up.Teams != nil {
-		*params = append(*params, *up.Teams)
-		*query += fmt.Sprintf("teams = $%d, ", len(*params))
-	}
-
 	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
 
 	*params = append(*params, up.Id)
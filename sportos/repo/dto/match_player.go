@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type MatchRole string
+
+const (
+	MR_CREATOR MatchRole = "CREATOR"
+	MR_PLAYER  MatchRole = "PLAYER"
+)
+
+// MatchPlayer is membership of player in match. Position is order in which players joined (creator is first)
+// and Side is index of team (0 or 1) player plays for, it is set when match is full and teams are generated
+type MatchPlayer struct {
+	MatchId  string    `json:"matchId" column:"match_id"`
+	PlayerId string    `json:"playerId" column:"player_id"`
+	Role     MatchRole `json:"role" column:"role"`
+	Position int       `json:"position" column:"position"`
+	Side     *int      `json:"side,omitempty" column:"side"`
+	EditInfoCU
+}
+
+func (s *MatchPlayer) GetTableName() SportosEntity {
+	return "match_player"
+}
+
+func (s *MatchPlayer) GetId() string {
+	return s.MatchId + ":" + s.PlayerId
+}
+
+type MatchPlayerSearchParams struct {
+	MatchIds []string   `json:"matchIds,omitempty"`
+	PlayerId *string    `json:"playerId,omitempty"`
+	Role     *MatchRole `json:"role,omitempty"`
+	EditInfoCUSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *MatchPlayerSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "mp"
+}
+
+func (sp *MatchPlayerSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *MatchPlayerSearchParams) validate() error {
+	err := sp.EditInfoCUSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *MatchPlayerSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *MatchPlayerSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if len(sp.MatchIds) != 0 {
+		*params = append(*params, pq.Array(sp.MatchIds))
+		*query += fmt.Sprintf(" and %v.match_id=any($%d)", tablePrefix, len(*params))
+	}
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Role != nil {
+		*params = append(*params, *sp.Role)
+		*query += fmt.Sprintf(" and %v.role=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCUSearchParams.IsEmpty() {
+		sp.EditInfoCUSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *MatchPlayerSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.match_id, %[1]s.position", sp.GetTablePrefix())
+}
+
+func (sp *MatchPlayerSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *MatchPlayerSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type MatchPlayerUpdateParams struct {
+	MatchId  string
+	PlayerId string
+	Side     *int
+	EditInfoUUpdateParams
+}
+
+func (up MatchPlayerUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update match_player mp set `
+
+	if up.Side != nil {
+		*params = append(*params, *up.Side)
+		*query += fmt.Sprintf("side = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.MatchId)
+	*query += fmt.Sprintf("where mp.match_id = $%d ", len(*params))
+	*params = append(*params, up.PlayerId)
+	*query += fmt.Sprintf("and mp.player_id = $%d;", len(*params))
+}
@@ -0,0 +1,173 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type MatchResultStatus string
+
+const (
+	// MRS_PENDING result waits for players of other side until deadline
+	MRS_PENDING MatchResultStatus = "PENDING"
+	// MRS_CONFIRMED result is confirmed by player of other side
+	MRS_CONFIRMED MatchResultStatus = "CONFIRMED"
+	// MRS_AUTO_CONFIRMED result isn't confirmed nor disputed until deadline
+	MRS_AUTO_CONFIRMED MatchResultStatus = "AUTO_CONFIRMED"
+	// MRS_DISPUTED result is disputed by player of other side and waits for review in backoffice
+	MRS_DISPUTED MatchResultStatus = "DISPUTED"
+	// MRS_RESOLVED disputed result is accepted in backoffice, possibly corrected
+	MRS_RESOLVED MatchResultStatus = "RESOLVED"
+	// MRS_REJECTED disputed result is rejected in backoffice, players can submit result again
+	MRS_REJECTED MatchResultStatus = "REJECTED"
+)
+
+func (s MatchResultStatus) IsValid() bool {
+	switch s {
+	case MRS_PENDING, MRS_CONFIRMED, MRS_AUTO_CONFIRMED, MRS_DISPUTED, MRS_RESOLVED, MRS_REJECTED:
+		return true
+	}
+	return false
+}
+
+// MatchResult is result of match submitted by player of one side, it finishes match only when players
+// of other side confirm it, it isn't answered until Deadline or backoffice resolves dispute about it
+type MatchResult struct {
+	ResultId string `json:"resultId" column:"result_id"`
+	MatchId  string `json:"matchId" column:"match_id"`
+	// Result is text of Score, like 3:1 or 2:1 (6:4 3:6 7:5)
+	Result string `json:"result" column:"result"`
+	// Side (0 or 1) of player that submitted result, created_by is that player
+	Side          int               `json:"side" column:"side"`
+	Status        MatchResultStatus `json:"status" column:"status"`
+	Deadline      time.Time         `json:"deadline" column:"deadline"`
+	DisputedBy    *string           `json:"disputedBy" column:"disputed_by"`
+	DisputeReason *string           `json:"disputeReason" column:"dispute_reason"`
+	// ReviewNote is explanation of backoffice decision about disputed result, updated_by is reviewer
+	ReviewNote *string `json:"reviewNote" column:"review_note"`
+	EditInfoCU
+}
+
+func (s *MatchResult) GetTableName() SportosEntity {
+	return "match_result"
+}
+
+func (s *MatchResult) GetId() string {
+	return s.ResultId
+}
+
+type MatchResultSearchParams struct {
+	MatchId *string            `json:"matchId,omitempty"`
+	Status  *MatchResultStatus `json:"status,omitempty"`
+	EditInfoCUSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *MatchResultSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "mr"
+}
+
+func (sp *MatchResultSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *MatchResultSearchParams) validate() error {
+	err := sp.EditInfoCUSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *MatchResultSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *MatchResultSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.MatchId != nil {
+		*params = append(*params, *sp.MatchId)
+		*query += fmt.Sprintf(" and %v.match_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Status != nil {
+		*params = append(*params, *sp.Status)
+		*query += fmt.Sprintf(" and %v.status=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCUSearchParams.IsEmpty() {
+		sp.EditInfoCUSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+// appendSortQuery sorts results from oldest so review queue is handled in order of submission
+func (sp *MatchResultSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.created_at, %[1]s.result_id", sp.GetTablePrefix())
+}
+
+func (sp *MatchResultSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *MatchResultSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type MatchResultUpdateParams struct {
+	Id            string
+	Result        *string
+	Status        *MatchResultStatus
+	DisputedBy    *string
+	DisputeReason *string
+	ReviewNote    *string
+	EditInfoUUpdateParams
+}
+
+func (up MatchResultUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update match_result mr set `
+
+	if up.Result != nil {
+		*params = append(*params, *up.Result)
+		*query += fmt.Sprintf("result = $%d, ", len(*params))
+	}
+
+	if up.Status != nil {
+		*params = append(*params, *up.Status)
+		*query += fmt.Sprintf("status = $%d, ", len(*params))
+	}
+
+	if up.DisputedBy != nil {
+		*params = append(*params, *up.DisputedBy)
+		*query += fmt.Sprintf("disputed_by = $%d, ", len(*params))
+	}
+
+	if up.DisputeReason != nil {
+		*params = append(*params, *up.DisputeReason)
+		*query += fmt.Sprintf("dispute_reason = $%d, ", len(*params))
+	}
+
+	if up.ReviewNote != nil {
+		*params = append(*params, *up.ReviewNote)
+		*query += fmt.Sprintf("review_note = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where mr.result_id = $%d;", len(*params))
+}
@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NT_MATCH_FULL           NotificationType = "MATCH_FULL"
+	NT_MATCH_FINISHED       NotificationType = "MATCH_FINISHED"
+	NT_MATCH_RESULT         NotificationType = "MATCH_RESULT"
+	NT_MATCH_DISPUTED       NotificationType = "MATCH_DISPUTED"
+	NT_MATCH_REJECTED       NotificationType = "MATCH_REJECTED"
+	NT_PRACTICE_REQUESTED   NotificationType = "PRACTICE_REQUESTED"
+	NT_PRACTICE_ACCEPTED    NotificationType = "PRACTICE_ACCEPTED"
+	NT_PRACTICE_DENIED      NotificationType = "PRACTICE_DENIED"
+	NT_TOURNAMENT_ROUND     NotificationType = "TOURNAMENT_ROUND"
+	NT_TOURNAMENT_FINISHED  NotificationType = "TOURNAMENT_FINISHED"
+	NT_TOURNAMENT_CANCELLED NotificationType = "TOURNAMENT_CANCELLED"
+	NT_TEAM_PLAYER_JOINED   NotificationType = "TEAM_PLAYER_JOINED"
+	NT_TEAM_FULL            NotificationType = "TEAM_FULL"
+)
+
+// Notification is message in inbox of user about change of match, practice, tournament or team with id ReferenceId
+type Notification struct {
+	NotificationId string           `json:"notificationId" column:"notification_id"`
+	UserId         string           `json:"userId" column:"user_id"`
+	Type           NotificationType `json:"type" column:"type"`
+	ReferenceId    string           `json:"referenceId" column:"reference_id"`
+	Message        string           `json:"message" column:"message"`
+	ReadAt         *time.Time       `json:"readAt" column:"read_at"`
+	EditInfoCU
+}
+
+func (s *Notification) GetTableName() SportosEntity {
+	return "notification"
+}
+
+func (s *Notification) GetId() string {
+	return s.NotificationId
+}
+
+type NotificationSearchParams struct {
+	UserId *string `json:"userId,omitempty"`
+	// Unread returns only notifications that aren't read if it is true
+	Unread *bool `json:"unread,omitempty"`
+	EditInfoCUSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *NotificationSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "nt"
+}
+
+func (sp *NotificationSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *NotificationSearchParams) validate() error {
+	err := sp.EditInfoCUSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *NotificationSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *NotificationSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.UserId != nil {
+		*params = append(*params, *sp.UserId)
+		*query += fmt.Sprintf(" and %v.user_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Unread != nil && *sp.Unread {
+		*query += fmt.Sprintf(" and %v.read_at is null", tablePrefix)
+	}
+	if !sp.EditInfoCUSearchParams.IsEmpty() {
+		sp.EditInfoCUSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *NotificationSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.created_at desc, %[1]s.notification_id desc", sp.GetTablePrefix())
+}
+
+func (sp *NotificationSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *NotificationSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type NotificationUpdateParams struct {
+	Id     string
+	ReadAt *time.Time
+	EditInfoUUpdateParams
+}
+
+func (up NotificationUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update notification nt set `
+
+	if up.ReadAt != nil {
+		*params = append(*params, *up.ReadAt)
+		*query += fmt.Sprintf("read_at = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where nt.notification_id = $%d;", len(*params))
+}
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OutboxEventType string
+
+const (
+	OE_MATCH_BOOKED              OutboxEventType = "MATCH_BOOKED"
+	OE_MATCH_CANCELLED           OutboxEventType = "MATCH_CANCELLED"
+	OE_MATCH_FINISHED            OutboxEventType = "MATCH_FINISHED"
+	OE_TOURNAMENT_STATUS_CHANGED OutboxEventType = "TOURNAMENT_STATUS_CHANGED"
+	OE_TOURNAMENT_FINISHED       OutboxEventType = "TOURNAMENT_FINISHED"
+	OE_PRACTICE_ACCEPTED         OutboxEventType = "PRACTICE_ACCEPTED"
+	OE_USER_REGISTERED           OutboxEventType = "USER_REGISTERED"
+)
+
+type OutboxEventStatus string
+
+const (
+	OES_PENDING    OutboxEventStatus = "PENDING"
+	OES_DISPATCHED OutboxEventStatus = "DISPATCHED"
+	OES_FAILED     OutboxEventStatus = "FAILED"
+)
+
+// OutboxEvent is domain event written in same transaction as change that caused it,
+// dispatcher delivers it to subscribers after transaction commits
+type OutboxEvent struct {
+	OutboxEventId string            `json:"outboxEventId" column:"outbox_event_id"`
+	Type          OutboxEventType   `json:"type" column:"type"`
+	AggregateId   string            `json:"aggregateId" column:"aggregate_id"`
+	Payload       OutboxPayload     `json:"payload" column:"payload"`
+	Status        OutboxEventStatus `json:"status" column:"status"`
+	Attempts      int               `json:"attempts" column:"attempts"`
+	NextAttemptAt time.Time         `json:"nextAttemptAt" column:"next_attempt_at"`
+	LastError     *string           `json:"lastError" column:"last_error"`
+	DispatchedAt  *time.Time        `json:"dispatchedAt" column:"dispatched_at"`
+	EditInfoCU
+}
+
+func (oe *OutboxEvent) GetTableName() SportosEntity {
+	return "outbox_event"
+}
+
+func (oe *OutboxEvent) GetId() string {
+	return oe.OutboxEventId
+}
+
+// OutboxPayload is json encoded event, it is stored as jsonb
+type OutboxPayload []byte
+
+func (p OutboxPayload) Value() (driver.Value, error) {
+	if p == nil {
+		return "{}", nil
+	}
+	return string(p), nil
+}
+
+func (p *OutboxPayload) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	*p = append(OutboxPayload{}, b...)
+	return nil
+}
+
+func (p *OutboxPayload) UnmarshalJSON(b []byte) error {
+	*p = append(OutboxPayload{}, b...)
+	return nil
+}
+
+func (p OutboxPayload) MarshalJSON() ([]byte, error) {
+	if p == nil {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+type OutboxEventUpdateParams struct {
+	Id            string
+	Status        *OutboxEventStatus
+	Attempts      *int
+	NextAttemptAt *time.Time
+	LastError     *string
+	DispatchedAt  *time.Time
+	EditInfoUUpdateParams
+}
+
+func (up OutboxEventUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update outbox_event oe set `
+
+	if up.Status != nil {
+		*params = append(*params, *up.Status)
+		*query += fmt.Sprintf("status = $%d, ", len(*params))
+	}
+
+	if up.Attempts != nil {
+		*params = append(*params, *up.Attempts)
+		*query += fmt.Sprintf("attempts = $%d, ", len(*params))
+	}
+
+	if up.NextAttemptAt != nil {
+		*params = append(*params, *up.NextAttemptAt)
+		*query += fmt.Sprintf("next_attempt_at = $%d, ", len(*params))
+	}
+
+	if up.LastError != nil {
+		*params = append(*params, *up.LastError)
+		*query += fmt.Sprintf("last_error = $%d, ", len(*params))
+	}
+
+	if up.DispatchedAt != nil {
+		*params = append(*params, *up.DispatchedAt)
+		*query += fmt.Sprintf("dispatched_at = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where oe.outbox_event_id = $%d;", len(*params))
+}
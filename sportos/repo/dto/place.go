@@ -6,13 +6,15 @@ import (
 	"strings"
 )
 
+// Place has IANA TimeZone, its schedule and days are on wall clock of that zone
 type Place struct {
-	Username string   `json:"username" column:"user_id"`
-	Name     string   `json:"name" column:"name"`
-	City     string   `json:"city" column:"city"`
-	Sport    string   `json:"sport" column:"sport"`
-	Booking  *Booking `json:"booking" column:"booking"`
-	Reviews  *Reviews `json:"reviews" column:"reviews"`
+	Username string    `json:"username" column:"user_id"`
+	Name     string    `json:"name" column:"name"`
+	City     string    `json:"city" column:"city"`
+	Sport    string    `json:"sport" column:"sport"`
+	Reviews  *Reviews  `json:"reviews" column:"reviews"`
+	Schedule *Schedule `json:"schedule" column:"schedule"`
+	TimeZone string    `json:"timeZone" column:"time_zone"`
 	EditInfoCUD
 }
 
@@ -173,11 +175,12 @@ func (sp PlaceSortParams) OrderBy() string {
 }
 
 type PlaceUpdateParams struct {
-	Id      string
-	Name    *string
-	City    *string
-	Booking *Booking
-	Reviews *Reviews
+	Id       string
+	Name     *string
+	City     *string
+	Reviews  *Reviews
+	Schedule *Schedule
+	TimeZone *string
 	EditInfoUDUpdateParams
 }
 
@@ -194,16 +197,21 @@ func (up PlaceUpdateParams) appendUpdateQuery(query *string, params *[]interface
 		*query += fmt.Sprintf("city = $%d, ", len(*params))
 	}
 
-	if up.Booking != nil {
-		*params = append(*params, up.Booking)
-		*query += fmt.Sprintf("booking = $%d, ", len(*params))
-	}
-
 	if up.Reviews != nil {
 		*params = append(*params, up.Reviews)
 		*query += fmt.Sprintf("reviews = $%d, ", len(*params))
 	}
 
+	if up.Schedule != nil {
+		*params = append(*params, up.Schedule)
+		*query += fmt.Sprintf("schedule = $%d, ", len(*params))
+	}
+
+	if up.TimeZone != nil {
+		*params = append(*params, *up.TimeZone)
+		*query += fmt.Sprintf("time_zone = $%d, ", len(*params))
+	}
+
 	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
 
 	*params = append(*params, up.Id)
@@ -7,9 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
-
-	"github.com/shopspring/decimal"
 )
 
 type Player struct {
@@ -17,7 +14,6 @@ type Player struct {
 	Name        string      `json:"name" column:"name"`
 	City        string      `json:"city" column:"city"`
 	Preferences *Prefernces `json:"preferences" column:"preferences"`
-	Statistics  StatMap     `json:"statistics" column:"statistics"`
 	EditInfoCUD
 }
 
@@ -177,7 +173,6 @@ type PlayerUpdateParams struct {
 	Name        *string
 	City        *string
 	Preferences *Prefernces
-	Statistics  *StatMap
 	EditInfoUDUpdateParams
 }
 
@@ -199,11 +194,6 @@ func (up PlayerUpdateParams) appendUpdateQuery(query *string, params *[]interfac
 		*query += fmt.Sprintf("preferences = $%d, ", len(*params))
 	}
 
-	if up.Statistics != nil {
-		*params = append(*params, *up.Statistics)
-		*query += fmt.Sprintf("statistics = $%d, ", len(*params))
-	}
-
 	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
 
 	*params = append(*params, up.Id)
@@ -242,60 +232,3 @@ func (p *Prefernces) Scan(value interface{}) error {
 
 	return json.Unmarshal(b, &p)
 }
-
-type Statistic struct {
-	MyTeam  []string  `json:"myTeam,omitempty"`
-	OppTeam []string  `json:"oppTeam,omitempty"`
-	Date    time.Time `json:"date,omitempty"`
-	Score   string    `json:"score,omitempty"`
-}
-
-type TournamentFinish struct {
-	MyTeam     []string `json:"myTeam,omitempty"`
-	Ranking    int      `json:"ranking"`
-	Tournament string   `json:"tournament"`
-}
-
-type Statistics struct {
-	Matches     []Statistic        `json:"matches,omitempty"`
-	WinRatio    decimal.Decimal    `json:"winRatio,omitempty"`
-	Tournaments []TournamentFinish `json:"tournaments,omitempty"`
-}
-
-type StatMap map[string]Statistics
-
-// Value is implementation of data Valuer interface.
-func (s StatMap) Value() (driver.Value, error) {
-	return json.Marshal(s)
-}
-
-// Scan is implementation of database/sql scanner interface.
-func (s *StatMap) Scan(value interface{}) error {
-	if value == nil {
-		return nil
-	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-
-	return json.Unmarshal(b, &s)
-}
-
-// Value is implementation of data Valuer interface.
-func (s Statistics) Value() (driver.Value, error) {
-	return json.Marshal(s)
-}
-
-// Scan is implementation of database/sql scanner interface.
-func (s *Statistics) Scan(value interface{}) error {
-	if value == nil {
-		return nil
-	}
-	b, ok := value.([]byte)
-	if !ok {
-		return errors.New("type assertion to []byte failed")
-	}
-
-	return json.Unmarshal(b, &s)
-}
@@ -0,0 +1,180 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+type MatchOutcome string
+
+const (
+	MO_WIN  MatchOutcome = "WIN"
+	MO_DRAW MatchOutcome = "DRAW"
+	MO_LOSS MatchOutcome = "LOSS"
+)
+
+// Outcome returns outcome of side that scored pointsFor
+func Outcome(pointsFor, pointsAgainst int) MatchOutcome {
+	switch {
+	case pointsFor > pointsAgainst:
+		return MO_WIN
+	case pointsFor < pointsAgainst:
+		return MO_LOSS
+	}
+	return MO_DRAW
+}
+
+// PlayerMatch is result of finished match for one of its players, statistics of player are aggregated
+// from these rows. MyTeam and OppTeam are names of players of both sides joined from match_player
+type PlayerMatch struct {
+	MatchId       string         `json:"matchId" column:"match_id"`
+	PlayerId      string         `json:"playerId" column:"player_id"`
+	Sport         string         `json:"sport" column:"sport"`
+	PlayedAt      time.Time      `json:"playedAt" column:"played_at"`
+	Side          int            `json:"side" column:"side"`
+	PointsFor     int            `json:"pointsFor" column:"points_for"`
+	PointsAgainst int            `json:"pointsAgainst" column:"points_against"`
+	Outcome       MatchOutcome   `json:"outcome" column:"outcome"`
+	MyTeam        pq.StringArray `json:"myTeam,omitempty"`
+	OppTeam       pq.StringArray `json:"oppTeam,omitempty"`
+	EditInfoC
+}
+
+// Score returns result of match from view of player, like 3:1
+func (s *PlayerMatch) Score() string {
+	return fmt.Sprintf("%d:%d", s.PointsFor, s.PointsAgainst)
+}
+
+type PlayerMatchSearchParams struct {
+	PlayerId *string `json:"playerId,omitempty"`
+	Sport    *string `json:"sport,omitempty"`
+	MatchId  *string `json:"matchId,omitempty"`
+	// From and To limit time when match was played, To is exclusive
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *PlayerMatchSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "pm"
+}
+
+func (sp *PlayerMatchSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *PlayerMatchSearchParams) validate() error {
+	return sp.PagingSearchParams.validate()
+}
+
+func (sp *PlayerMatchSearchParams) joinTables(query *string) {
+
+}
+
+// appendSearchQuery always starts where clause, select of player match contains where of lateral join
+func (sp *PlayerMatchSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	*query += `where 1 = 1 `
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Sport != nil {
+		*params = append(*params, *sp.Sport)
+		*query += fmt.Sprintf(" and %v.sport=$%d", tablePrefix, len(*params))
+	}
+	if sp.MatchId != nil {
+		*params = append(*params, *sp.MatchId)
+		*query += fmt.Sprintf(" and %v.match_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.From != nil {
+		*params = append(*params, *sp.From)
+		*query += fmt.Sprintf(" and %v.played_at>=$%d", tablePrefix, len(*params))
+	}
+	if sp.To != nil {
+		*params = append(*params, *sp.To)
+		*query += fmt.Sprintf(" and %v.played_at<$%d", tablePrefix, len(*params))
+	}
+}
+
+// appendSortQuery sorts matches from newest
+func (sp *PlayerMatchSearchParams) appendSortQuery(query *string) {
+	*query += fmt.Sprintf(" order by %[1]s.played_at desc, %[1]s.match_id desc", sp.GetTablePrefix())
+}
+
+func (sp *PlayerMatchSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *PlayerMatchSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+// PlayerMatchGroupBy is dimension by which matches of player are grouped
+type PlayerMatchGroupBy string
+
+const (
+	// PMG_OPPONENT groups matches by players of other team
+	PMG_OPPONENT PlayerMatchGroupBy = "OPPONENT"
+	// PMG_TEAMMATE groups matches by other players of same team
+	PMG_TEAMMATE PlayerMatchGroupBy = "TEAMMATE"
+	// PMG_PLACE groups matches by place where they were played
+	PMG_PLACE PlayerMatchGroupBy = "PLACE"
+	// PMG_WEEKDAY groups matches by day of week in time zone of place, 0 is Sunday
+	PMG_WEEKDAY PlayerMatchGroupBy = "WEEKDAY"
+	// PMG_HOUR groups matches by hour of start in time zone of place
+	PMG_HOUR PlayerMatchGroupBy = "HOUR"
+)
+
+func (g PlayerMatchGroupBy) IsValid() bool {
+	return g == PMG_OPPONENT || g == PMG_TEAMMATE || g == PMG_PLACE || g == PMG_WEEKDAY || g == PMG_HOUR
+}
+
+// PlayerMatchGroup is record of player in matches of one group. Key is id of opponent, teammate or place,
+// day of week or hour, Name is name of player or place
+type PlayerMatchGroup struct {
+	Key     string `json:"key"`
+	Name    string `json:"name,omitempty"`
+	Matches int    `json:"matches"`
+	Wins    int    `json:"wins"`
+	Draws   int    `json:"draws"`
+	Losses  int    `json:"losses"`
+}
+
+// PlayerMatchSummary is aggregate of all matches of player in sport
+type PlayerMatchSummary struct {
+	Matches int `json:"matches"`
+	Wins    int `json:"wins"`
+	Draws   int `json:"draws"`
+	Losses  int `json:"losses"`
+	// CurrentStreak is number of last matches that ended with StreakOutcome
+	CurrentStreak    int          `json:"currentStreak"`
+	StreakOutcome    MatchOutcome `json:"streakOutcome,omitempty"`
+	LongestWinStreak int          `json:"longestWinStreak"`
+}
+
+// WinRatio returns share of won matches, draw isn't win
+func (s PlayerMatchSummary) WinRatio() decimal.Decimal {
+	if s.Matches == 0 {
+		return decimal.Zero
+	}
+	return decimal.NewFromInt(int64(s.Wins)).Div(decimal.NewFromInt(int64(s.Matches)))
+}
+
+// PlayerMatchMonth is aggregate of matches of player in sport played in one month (UTC)
+type PlayerMatchMonth struct {
+	Month   time.Time `json:"month"`
+	Matches int       `json:"matches"`
+	Wins    int       `json:"wins"`
+	Draws   int       `json:"draws"`
+	Losses  int       `json:"losses"`
+}
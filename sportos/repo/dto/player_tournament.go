@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// PlayerTournament is ranking that team of player got in finished tournament. Tournament is name of event
+// and MyTeam names of members of team, both are joined
+type PlayerTournament struct {
+	EventId    string         `json:"eventId" column:"event_id"`
+	PlayerId   string         `json:"playerId" column:"player_id"`
+	Sport      string         `json:"sport" column:"sport"`
+	TeamId     string         `json:"teamId" column:"team_id"`
+	Ranking    int            `json:"ranking" column:"ranking"`
+	Tournament string         `json:"tournament"`
+	MyTeam     pq.StringArray `json:"myTeam,omitempty"`
+	EditInfoC
+}
+
+type PlayerTournamentSearchParams struct {
+	PlayerId *string `json:"playerId,omitempty"`
+	Sport    *string `json:"sport,omitempty"`
+	EventId  *string `json:"eventId,omitempty"`
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *PlayerTournamentSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "pt"
+}
+
+func (sp *PlayerTournamentSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *PlayerTournamentSearchParams) validate() error {
+	return sp.PagingSearchParams.validate()
+}
+
+func (sp *PlayerTournamentSearchParams) joinTables(query *string) {
+
+}
+
+// appendSearchQuery always starts where clause, select of player tournament contains where of lateral join
+func (sp *PlayerTournamentSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	*query += `where 1 = 1 `
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Sport != nil {
+		*params = append(*params, *sp.Sport)
+		*query += fmt.Sprintf(" and %v.sport=$%d", tablePrefix, len(*params))
+	}
+	if sp.EventId != nil {
+		*params = append(*params, *sp.EventId)
+		*query += fmt.Sprintf(" and %v.event_id=$%d", tablePrefix, len(*params))
+	}
+}
+
+// appendSortQuery sorts tournaments from last finished
+func (sp *PlayerTournamentSearchParams) appendSortQuery(query *string) {
+	*query += fmt.Sprintf(" order by %[1]s.created_at desc, %[1]s.event_id desc", sp.GetTablePrefix())
+}
+
+func (sp *PlayerTournamentSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *PlayerTournamentSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
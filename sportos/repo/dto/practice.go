@@ -24,6 +24,7 @@ type Practice struct {
 	CoachId    string         `json:"coachId,omitempty" column:"coach_id"`
 	Sport      string         `json:"sport,omitempty" column:"sport"`
 	StartTime  *time.Time     `json:"startTime,omitempty" column:"start_time"`
+	TimeZone   *string        `json:"timeZone,omitempty"`
 	EditInfoCUD
 }
 
@@ -0,0 +1,249 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+type RatingSystem string
+
+const (
+	// RS_ELO is used for sports in which one player plays against one player
+	RS_ELO RatingSystem = "ELO"
+	// RS_GLICKO2 is used for team sports, every player is rated against average of other team
+	RS_GLICKO2 RatingSystem = "GLICKO2"
+)
+
+// Rating is skill of player in one sport, Deviation and Volatility are used only by glicko-2
+type Rating struct {
+	RatingId   string       `json:"ratingId" column:"rating_id"`
+	PlayerId   string       `json:"playerId" column:"player_id"`
+	Sport      string       `json:"sport" column:"sport"`
+	System     RatingSystem `json:"system" column:"system"`
+	Rating     float64      `json:"rating" column:"rating"`
+	Deviation  float64      `json:"deviation" column:"deviation"`
+	Volatility float64      `json:"volatility" column:"volatility"`
+	Matches    int          `json:"matches" column:"matches"`
+	EditInfoCU
+}
+
+func (s *Rating) GetTableName() SportosEntity {
+	return "rating"
+}
+
+func (s *Rating) GetId() string {
+	return s.RatingId
+}
+
+type RatingSearchParams struct {
+	PlayerId *string `json:"playerId,omitempty"`
+	Sport    *string `json:"sport,omitempty"`
+	// MinMatches leaves out players with less rated matches, their rating isn't reliable yet
+	MinMatches *int `json:"minMatches,omitempty"`
+	EditInfoCUSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *RatingSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "ra"
+}
+
+func (sp *RatingSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *RatingSearchParams) validate() error {
+	err := sp.EditInfoCUSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *RatingSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *RatingSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Sport != nil {
+		*params = append(*params, *sp.Sport)
+		*query += fmt.Sprintf(" and %v.sport=$%d", tablePrefix, len(*params))
+	}
+	if sp.MinMatches != nil {
+		*params = append(*params, *sp.MinMatches)
+		*query += fmt.Sprintf(" and %v.matches>=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCUSearchParams.IsEmpty() {
+		sp.EditInfoCUSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+// appendSortQuery sorts ratings from best, it is order of leaderboard
+func (sp *RatingSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.rating desc, %[1]s.matches desc, %[1]s.player_id", sp.GetTablePrefix())
+}
+
+func (sp *RatingSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *RatingSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type RatingUpdateParams struct {
+	Id         string
+	Rating     *float64
+	Deviation  *float64
+	Volatility *float64
+	Matches    *int
+	EditInfoUUpdateParams
+}
+
+func (up RatingUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update rating ra set `
+
+	if up.Rating != nil {
+		*params = append(*params, *up.Rating)
+		*query += fmt.Sprintf("rating = $%d, ", len(*params))
+	}
+
+	if up.Deviation != nil {
+		*params = append(*params, *up.Deviation)
+		*query += fmt.Sprintf("deviation = $%d, ", len(*params))
+	}
+
+	if up.Volatility != nil {
+		*params = append(*params, *up.Volatility)
+		*query += fmt.Sprintf("volatility = $%d, ", len(*params))
+	}
+
+	if up.Matches != nil {
+		*params = append(*params, *up.Matches)
+		*query += fmt.Sprintf("matches = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where ra.rating_id = $%d;", len(*params))
+}
+
+// RatingHistory is change of rating of player after match, Score is 1 for win, 0.5 for draw and 0 for loss
+type RatingHistory struct {
+	HistoryId       string  `json:"historyId" column:"history_id"`
+	RatingId        string  `json:"ratingId" column:"rating_id"`
+	PlayerId        string  `json:"playerId" column:"player_id"`
+	Sport           string  `json:"sport" column:"sport"`
+	MatchId         string  `json:"matchId" column:"match_id"`
+	Score           float64 `json:"score" column:"score"`
+	RatingBefore    float64 `json:"ratingBefore" column:"rating_before"`
+	RatingAfter     float64 `json:"ratingAfter" column:"rating_after"`
+	DeviationBefore float64 `json:"deviationBefore" column:"deviation_before"`
+	DeviationAfter  float64 `json:"deviationAfter" column:"deviation_after"`
+	EditInfoC
+}
+
+func (s *RatingHistory) GetTableName() SportosEntity {
+	return "rating_history"
+}
+
+func (s *RatingHistory) GetId() string {
+	return s.HistoryId
+}
+
+type RatingHistorySearchParams struct {
+	PlayerId *string `json:"playerId,omitempty"`
+	Sport    *string `json:"sport,omitempty"`
+	MatchId  *string `json:"matchId,omitempty"`
+	EditInfoCSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *RatingHistorySearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "rh"
+}
+
+func (sp *RatingHistorySearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *RatingHistorySearchParams) validate() error {
+	err := sp.EditInfoCSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *RatingHistorySearchParams) joinTables(query *string) {
+
+}
+
+func (sp *RatingHistorySearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Sport != nil {
+		*params = append(*params, *sp.Sport)
+		*query += fmt.Sprintf(" and %v.sport=$%d", tablePrefix, len(*params))
+	}
+	if sp.MatchId != nil {
+		*params = append(*params, *sp.MatchId)
+		*query += fmt.Sprintf(" and %v.match_id=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCSearchParams.IsEmpty() {
+		sp.EditInfoCSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *RatingHistorySearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.created_at desc, %[1]s.history_id desc", sp.GetTablePrefix())
+}
+
+func (sp *RatingHistorySearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *RatingHistorySearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
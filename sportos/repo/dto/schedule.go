@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// OpeningHours is time range in which owner is open on day of week, Open and Close are
+// wall clock times in format 15:04 and Close can be 24:00 for midnight at the end of day
+type OpeningHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Open    string       `json:"open"`
+	Close   string       `json:"close"`
+}
+
+// ScheduleException replaces weekly opening hours on date (2006-01-02), exception without
+// Open and Close closes owner for whole day (holidays, closures)
+type ScheduleException struct {
+	Date   string `json:"date"`
+	Open   string `json:"open,omitempty"`
+	Close  string `json:"close,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (e ScheduleException) Closed() bool {
+	return e.Open == "" && e.Close == ""
+}
+
+// Schedule is weekly availability of place or coach. Opening hours are split into slots of
+// SlotMinutes that are separated by BufferMinutes, owner without schedule is open whole day
+// in slots of one hour.
+type Schedule struct {
+	Hours         []OpeningHours      `json:"hours"`
+	Exceptions    []ScheduleException `json:"exceptions,omitempty"`
+	SlotMinutes   int                 `json:"slotMinutes"`
+	BufferMinutes int                 `json:"bufferMinutes"`
+}
+
+// Value is implementation of data Valuer interface.
+func (s Schedule) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan is implementation of database/sql scanner interface.
+func (s *Schedule) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &s)
+}
@@ -0,0 +1,277 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrScoreMissing = errors.New("score is missing")
+	ErrScoreFormat  = errors.New("score must be in format points:points, optionally followed by sets or periods in brackets, like 2:1 (6:4 3:6 7:5)")
+	ErrScoreDraw    = errors.New("match of this sport can't end in a draw")
+)
+
+// ScoringKind is how result of match is counted in sport
+type ScoringKind string
+
+const (
+	// SK_POINTS sports count points (goals, baskets) of whole match, periods only split them
+	SK_POINTS ScoringKind = "POINTS"
+	// SK_SETS sports count won sets, every set is won by side with more points or games in it
+	SK_SETS ScoringKind = "SETS"
+)
+
+// Scoring is schema of result of match in sport
+type Scoring struct {
+	Kind ScoringKind `json:"kind"`
+	// Sets is maximal number of sets (best of), SK_SETS only
+	Sets int `json:"sets,omitempty"`
+	// Periods is number of periods of regular time (halves, quarters), SK_POINTS only
+	Periods  int  `json:"periods,omitempty"`
+	Overtime bool `json:"overtime,omitempty"`
+	Draws    bool `json:"draws,omitempty"`
+	// ForfeitPoints is score of winner of forfeited match of SK_POINTS sport, like 3:0 in football.
+	// Winner of forfeited SK_SETS match always gets sets needed to win
+	ForfeitPoints int `json:"forfeitPoints,omitempty"`
+}
+
+// SetsToWin returns number of sets that wins match of SK_SETS sport
+func (sc Scoring) SetsToWin() int {
+	return sc.Sets/2 + 1
+}
+
+// ScoreForfeit marks match that wasn't played to the end
+type ScoreForfeit string
+
+const (
+	// SF_FORFEIT is match that loser gave up during play or was disqualified from
+	SF_FORFEIT ScoreForfeit = "FF"
+	// SF_WALKOVER is match that wasn't played because loser didn't show up
+	SF_WALKOVER ScoreForfeit = "WO"
+)
+
+// PeriodScore is score of both sides in one set or period
+type PeriodScore struct {
+	One int `json:"one"`
+	Two int `json:"two"`
+}
+
+// Score is result of match between side one and two. One and Two are headline score, sets won in SK_SETS
+// sports and total points in SK_POINTS sports. Periods are sets or periods of regular time and Overtime
+// periods played after it, both are optional.
+//
+// Score is stored as text, like 2:1 (6:4 3:6 7:5), 98:95 (20:18 25:22 19:24 21:23 OT 13:8) or 3:0 WO,
+// so results in old format points:points are scores without breakdown
+type Score struct {
+	One      int           `json:"one"`
+	Two      int           `json:"two"`
+	Periods  []PeriodScore `json:"periods,omitempty"`
+	Overtime []PeriodScore `json:"overtime,omitempty"`
+	Forfeit  ScoreForfeit  `json:"forfeit,omitempty"`
+}
+
+// ParseScore parses text of score, see Score
+func ParseScore(text string) (Score, error) {
+	s := Score{}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return s, ErrScoreMissing
+	}
+	for _, f := range []ScoreForfeit{SF_FORFEIT, SF_WALKOVER} {
+		if strings.HasSuffix(strings.ToUpper(text), " "+string(f)) {
+			s.Forfeit = f
+			text = strings.TrimSpace(text[:len(text)-len(f)])
+			break
+		}
+	}
+	head, breakdown, hasBreakdown := strings.Cut(text, "(")
+	var err error
+	if s.One, s.Two, err = parsePair(head); err != nil {
+		return Score{}, err
+	}
+	if !hasBreakdown {
+		return s, nil
+	}
+	if !strings.HasSuffix(breakdown, ")") {
+		return Score{}, ErrScoreFormat
+	}
+	breakdown = strings.TrimSuffix(breakdown, ")")
+	regular, overtime, hasOvertime := strings.Cut(strings.ToUpper(breakdown), "OT")
+	if s.Periods, err = parsePeriods(regular); err != nil || len(s.Periods) == 0 {
+		return Score{}, ErrScoreFormat
+	}
+	if hasOvertime {
+		if s.Overtime, err = parsePeriods(overtime); err != nil || len(s.Overtime) == 0 {
+			return Score{}, ErrScoreFormat
+		}
+	}
+	return s, nil
+}
+
+// parsePeriods parses scores of periods separated by spaces or commas
+func parsePeriods(text string) ([]PeriodScore, error) {
+	periods := []PeriodScore{}
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) {
+		one, two, err := parsePair(field)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, PeriodScore{One: one, Two: two})
+	}
+	return periods, nil
+}
+
+// parsePair parses non negative points of both sides, like 3:1
+func parsePair(text string) (int, int, error) {
+	parts := strings.Split(text, ":")
+	if len(parts) != 2 {
+		return 0, 0, ErrScoreFormat
+	}
+	var points [2]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n < 0 {
+			return 0, 0, ErrScoreFormat
+		}
+		points[i] = n
+	}
+	return points[0], points[1], nil
+}
+
+// String returns text of score that ParseScore parses
+func (s Score) String() string {
+	text := fmt.Sprintf("%d:%d", s.One, s.Two)
+	if len(s.Periods) > 0 {
+		text += " (" + joinPeriods(s.Periods)
+		if len(s.Overtime) > 0 {
+			text += " OT " + joinPeriods(s.Overtime)
+		}
+		text += ")"
+	}
+	if s.Forfeit != "" {
+		text += " " + string(s.Forfeit)
+	}
+	return text
+}
+
+func joinPeriods(periods []PeriodScore) string {
+	parts := make([]string, len(periods))
+	for i, p := range periods {
+		parts[i] = fmt.Sprintf("%d:%d", p.One, p.Two)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks that score fits scoring of sport: breakdown matches headline score, number of sets or
+// periods, overtime and forfeit. Set sports are played until one side wins needed sets, so they can't end
+// in a draw. Draws of other sports aren't checked, tournament games can be drawn in any sport, see Winner,
+// but overtime of sport without draws must decide match
+func (sc Scoring) Validate(s Score) error {
+	if s.Forfeit != "" {
+		return sc.validateForfeit(s)
+	}
+	if sc.Kind == SK_SETS {
+		return sc.validateSets(s)
+	}
+	return sc.validatePoints(s)
+}
+
+func (sc Scoring) validateForfeit(s Score) error {
+	if s.One == s.Two {
+		return errors.New("forfeited match must have winner")
+	}
+	if s.Forfeit == SF_WALKOVER && len(s.Periods) > 0 {
+		return errors.New("walkover match wasn't played, it can't have sets or periods")
+	}
+	winner := s.One
+	if s.Two > winner {
+		winner = s.Two
+	}
+	switch {
+	case sc.Kind == SK_SETS && (winner != sc.SetsToWin() || s.One+s.Two-winner != 0):
+		return fmt.Errorf("winner of forfeited match gets %d:0 in sets", sc.SetsToWin())
+	case sc.Kind != SK_SETS && sc.ForfeitPoints > 0 && (winner != sc.ForfeitPoints || s.One+s.Two-winner != 0):
+		return fmt.Errorf("winner of forfeited match gets %d:0", sc.ForfeitPoints)
+	}
+	return nil
+}
+
+func (sc Scoring) validateSets(s Score) error {
+	if len(s.Overtime) > 0 {
+		return errors.New("sets can't have overtime")
+	}
+	if sc.Sets > 0 && (s.One > sc.SetsToWin() || s.Two > sc.SetsToWin() || s.One+s.Two > sc.Sets) {
+		return fmt.Errorf("match is played to at most %d sets", sc.Sets)
+	}
+	if sc.Sets > 0 && s.One != sc.SetsToWin() && s.Two != sc.SetsToWin() {
+		return fmt.Errorf("match is over when one side wins %d sets", sc.SetsToWin())
+	}
+	if len(s.Periods) == 0 {
+		return nil
+	}
+	var won [2]int
+	for _, set := range s.Periods {
+		switch {
+		case set.One > set.Two:
+			won[0]++
+		case set.Two > set.One:
+			won[1]++
+		default:
+			return fmt.Errorf("set %d:%d has no winner", set.One, set.Two)
+		}
+	}
+	if won[0] != s.One || won[1] != s.Two {
+		return fmt.Errorf("sets are won %d:%d, not %d:%d", won[0], won[1], s.One, s.Two)
+	}
+	return nil
+}
+
+func (sc Scoring) validatePoints(s Score) error {
+	if len(s.Periods) == 0 {
+		return nil
+	}
+	if sc.Periods > 0 && len(s.Periods) != sc.Periods {
+		return fmt.Errorf("regular time has %d periods", sc.Periods)
+	}
+	var total [2]int
+	for _, p := range s.Periods {
+		total[0] += p.One
+		total[1] += p.Two
+	}
+	if len(s.Overtime) > 0 {
+		if !sc.Overtime {
+			return errors.New("sport doesn't have overtime")
+		}
+		if total[0] != total[1] {
+			return errors.New("overtime is played only after draw in regular time")
+		}
+		for _, p := range s.Overtime {
+			total[0] += p.One
+			total[1] += p.Two
+		}
+	}
+	if total[0] != s.One || total[1] != s.Two {
+		return fmt.Errorf("periods add up to %d:%d, not %d:%d", total[0], total[1], s.One, s.Two)
+	}
+	if len(s.Overtime) > 0 && !sc.Draws && s.One == s.Two {
+		return errors.New("overtime is played until one side wins")
+	}
+	return nil
+}
+
+// Winner returns side (0 or 1) that won match or -1 for draw, ErrScoreDraw is returned for draw
+// in sport without draws
+func (sc Scoring) Winner(s Score) (int, error) {
+	switch {
+	case s.One > s.Two:
+		return 0, nil
+	case s.Two > s.One:
+		return 1, nil
+	case !sc.Draws:
+		return -1, ErrScoreDraw
+	}
+	return -1, nil
+}
@@ -0,0 +1,130 @@
+package dto_test
+
+import (
+	DR "backend/sportos/repo/dto"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseScore(t *testing.T) {
+	tests := []struct {
+		text string
+		want DR.Score
+		err  error
+	}{
+		// legacy scores are headline without breakdown
+		{"3:1", DR.Score{One: 3, Two: 1}, nil},
+		{" 0 : 0 ", DR.Score{}, nil},
+		{"2:1 (6:4 3:6 7:5)", DR.Score{One: 2, Two: 1, Periods: []DR.PeriodScore{{6, 4}, {3, 6}, {7, 5}}}, nil},
+		{"2:1 (6:4,3:6, 7:5)", DR.Score{One: 2, Two: 1, Periods: []DR.PeriodScore{{6, 4}, {3, 6}, {7, 5}}}, nil},
+		{"98:95 (20:18 25:22 19:24 21:21 OT 13:10)", DR.Score{One: 98, Two: 95,
+			Periods: []DR.PeriodScore{{20, 18}, {25, 22}, {19, 24}, {21, 21}}, Overtime: []DR.PeriodScore{{13, 10}}}, nil},
+		{"2:2 (1:0 1:2 ot 0:0)", DR.Score{One: 2, Two: 2, Periods: []DR.PeriodScore{{1, 0}, {1, 2}}, Overtime: []DR.PeriodScore{{0, 0}}}, nil},
+		{"3:0 WO", DR.Score{One: 3, Two: 0, Forfeit: DR.SF_WALKOVER}, nil},
+		{"3:0 wo", DR.Score{One: 3, Two: 0, Forfeit: DR.SF_WALKOVER}, nil},
+		{"1:2 (6:4 2:3) ff", DR.Score{One: 1, Two: 2, Periods: []DR.PeriodScore{{6, 4}, {2, 3}}, Forfeit: DR.SF_FORFEIT}, nil},
+		{"", DR.Score{}, DR.ErrScoreMissing},
+		{"  ", DR.Score{}, DR.ErrScoreMissing},
+		{"3", DR.Score{}, DR.ErrScoreFormat},
+		{"1:2:3", DR.Score{}, DR.ErrScoreFormat},
+		{"-1:2", DR.Score{}, DR.ErrScoreFormat},
+		{"a:b", DR.Score{}, DR.ErrScoreFormat},
+		{"WO", DR.Score{}, DR.ErrScoreFormat},
+		// malformed brackets
+		{"2:1 (6:4 3:6", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 6:4 3:6)", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 ()", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 (6:4 (3:6))", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 (6:4 x:6)", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 (OT 3:2)", DR.Score{}, DR.ErrScoreFormat},
+		{"2:1 (1:1 OT)", DR.Score{}, DR.ErrScoreFormat},
+	}
+	for _, tt := range tests {
+		got, err := DR.ParseScore(tt.text)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseScore(%q) error = %v, want %v", tt.text, err, tt.err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseScore(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScoreStringRoundTrips(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"3:1", "3:1"},
+		{"2:1 (6:4,3:6, 7:5)", "2:1 (6:4 3:6 7:5)"},
+		{"98:95 (20:18 25:22 19:24 21:21 ot 13:10)", "98:95 (20:18 25:22 19:24 21:21 OT 13:10)"},
+		{"3:0 wo", "3:0 WO"},
+		{"1:2 (6:4 2:3) FF", "1:2 (6:4 2:3) FF"},
+	}
+	for _, tt := range tests {
+		score, err := DR.ParseScore(tt.text)
+		if err != nil {
+			t.Fatalf("ParseScore(%q): %v", tt.text, err)
+		}
+		if got := score.String(); got != tt.want {
+			t.Errorf("ParseScore(%q).String() = %q, want %q", tt.text, got, tt.want)
+		}
+		again, err := DR.ParseScore(score.String())
+		if err != nil || !reflect.DeepEqual(again, score) {
+			t.Errorf("ParseScore(%q) = %+v, %v, want %+v", score.String(), again, err, score)
+		}
+	}
+}
+
+func TestScoringValidate(t *testing.T) {
+	tests := []struct {
+		sport string
+		text  string
+		ok    bool
+	}{
+		{"Tennis", "2:1", true},
+		{"Tennis", "2:1 (6:4 3:6 7:5)", true},
+		{"Tennis", "1:1", false},
+		{"Tennis", "3:0", false},
+		// sets with mismatched headline score
+		{"Tennis", "2:0 (6:4 3:6 7:5)", false},
+		{"Tennis", "2:1 (6:4 6:6 7:5)", false},
+		{"Tennis", "2:0 (6:4 6:3 OT 7:5)", false},
+		{"Basketball", "98:95", true},
+		{"Basketball", "98:95 (20:18 25:22 19:24 21:21 OT 13:10)", true},
+		{"Basketball", "90:85 (20:20 20:20 20:20 20:25)", false},
+		{"Basketball", "40:38 (20:18 20:20)", false},
+		// overtime after non-draw
+		{"Basketball", "98:95 (20:18 25:22 19:24 21:23 OT 13:8)", false},
+		// overtime that ends in draw in sport without draws
+		{"Basketball", "90:90 (20:18 25:22 19:24 21:21 OT 5:5)", false},
+		{"Football", "2:2 (1:0 0:1 OT 1:1)", true},
+		{"Swimming", "2:1 (1:1 OT 1:0)", false},
+		// forfeits
+		{"Tennis", "2:0 WO", true},
+		{"Tennis", "0:2 wo", true},
+		{"Tennis", "2:1 (6:4 2:6 3:1) ff", false},
+		{"Tennis", "2:0 (6:4 2:1) FF", true},
+		{"Tennis", "3:0 WO", false},
+		{"Tennis", "2:0 (6:0 6:0) WO", false},
+		{"Basketball", "20:0 ff", true},
+		{"Basketball", "21:0 FF", false},
+		{"Basketball", "0:0 WO", false},
+		{"Football", "0:3 wo", true},
+	}
+	for _, tt := range tests {
+		sport, err := DR.GetSportByName(tt.sport)
+		if err != nil {
+			t.Fatalf("sport %s: %v", tt.sport, err)
+		}
+		score, err := DR.ParseScore(tt.text)
+		if err != nil {
+			t.Fatalf("ParseScore(%q): %v", tt.text, err)
+		}
+		if err := sport.Scoring.Validate(score); (err == nil) != tt.ok {
+			t.Errorf("%s Validate(%q) = %v, want ok %v", tt.sport, tt.text, err, tt.ok)
+		}
+	}
+}
@@ -6,20 +6,24 @@ type Sport struct {
 	Name      string   `json:"name,omitempty"`
 	TeamSize  int      `json:"teamSize,omitempty"`
 	Positions []string `json:"positions,omitempty"`
+	Scoring   Scoring  `json:"scoring"`
 }
 
 var Sports []Sport = []Sport{
 	{
 		Name:     "Tennis",
 		TeamSize: 1,
+		Scoring:  Scoring{Kind: SK_SETS, Sets: 3},
 	},
 	{
 		Name:     "Table tennis",
 		TeamSize: 1,
+		Scoring:  Scoring{Kind: SK_SETS, Sets: 5},
 	},
 	{
 		Name:     "Basketball",
 		TeamSize: 5,
+		Scoring:  Scoring{Kind: SK_POINTS, Periods: 4, Overtime: true, ForfeitPoints: 20},
 		Positions: []string{
 			"Point guard",
 			"Shooting guard",
@@ -31,6 +35,7 @@ var Sports []Sport = []Sport{
 	{
 		Name:     "Volleyball",
 		TeamSize: 6,
+		Scoring:  Scoring{Kind: SK_SETS, Sets: 5},
 		Positions: []string{
 			"Outside hitter",
 			"Opposite",
@@ -42,6 +47,7 @@ var Sports []Sport = []Sport{
 	{
 		Name:     "Handball",
 		TeamSize: 7,
+		Scoring:  Scoring{Kind: SK_POINTS, Periods: 2, Overtime: true, Draws: true, ForfeitPoints: 10},
 		Positions: []string{
 			"Goalkeeper",
 			"Left wing",
@@ -55,6 +61,7 @@ var Sports []Sport = []Sport{
 	{
 		Name:     "Football",
 		TeamSize: 11,
+		Scoring:  Scoring{Kind: SK_POINTS, Periods: 2, Overtime: true, Draws: true, ForfeitPoints: 3},
 		Positions: []string{
 			"Attack",
 			"Middle field",
@@ -65,14 +72,17 @@ var Sports []Sport = []Sport{
 	{
 		Name:     "Swimming",
 		TeamSize: 1,
+		Scoring:  Scoring{Kind: SK_POINTS, Draws: true},
 	},
 	{
 		Name:     "Fitness",
 		TeamSize: 1,
+		Scoring:  Scoring{Kind: SK_POINTS, Draws: true},
 	},
 	{
 		Name:     "Bodybuilding",
 		TeamSize: 1,
+		Scoring:  Scoring{Kind: SK_POINTS, Draws: true},
 	},
 }
 
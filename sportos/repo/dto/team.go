@@ -17,11 +17,12 @@ const (
 )
 
 type Team struct {
-	TeamId  string     `json:"teamId" column:"team_id"`
-	Name    string     `json:"name" column:"name"`
-	Sport   string     `json:"sport" column:"sport"`
-	Status  TeamStatus `json:"status" column:"status"`
-	Players string     `json:"players" column:"players"`
+	TeamId string     `json:"teamId" column:"team_id"`
+	Name   string     `json:"name" column:"name"`
+	Sport  string     `json:"sport" column:"sport"`
+	Status TeamStatus `json:"status" column:"status"`
+	// Players are loaded from team_member ordered by position, owner is first
+	Players StrArr `json:"players"`
 	EditInfoCUD
 }
 
@@ -33,6 +34,31 @@ func (s *Team) GetId() string {
 	return s.TeamId
 }
 
+// SetMembers sets players of team from its members, members must be ordered by position
+func (s *Team) SetMembers(members []TeamMember) {
+	s.Players = StrArr{}
+	for _, tm := range members {
+		s.Players = append(s.Players, tm.PlayerId)
+	}
+}
+
+func (s *Team) HasPlayer(playerId string) bool {
+	for _, id := range s.Players {
+		if id == playerId {
+			return true
+		}
+	}
+	return false
+}
+
+// Owner returns id of player who created team
+func (s *Team) Owner() string {
+	if len(s.Players) == 0 {
+		return ""
+	}
+	return s.Players[0]
+}
+
 type TeamSearchParams struct {
 	Name          *string  `json:"name,omitempty"`
 	Sports        []string `json:"sport,omitempty"`
@@ -92,11 +118,11 @@ func (sp *TeamSearchParams) appendSearchQuery(query *string, params *[]interface
 	}
 	if sp.UserNotInTeam != nil {
 		*params = append(*params, *sp.UserNotInTeam)
-		*query += fmt.Sprintf(" and %v.players not like '%%'||$%d||'%%'", tablePrefix, len(*params))
+		*query += fmt.Sprintf(" and not exists (select 1 from team_member tm where tm.team_id = %v.team_id and tm.player_id=$%d)", tablePrefix, len(*params))
 	}
 	if sp.Owner != nil {
-		*params = append(*params, *sp.Owner)
-		*query += fmt.Sprintf(" and %v.players like $%d||'%%'", tablePrefix, len(*params))
+		*params = append(*params, *sp.Owner, TR_OWNER)
+		*query += fmt.Sprintf(" and exists (select 1 from team_member tm where tm.team_id = %v.team_id and tm.player_id=$%d and tm.role=$%d)", tablePrefix, len(*params)-1, len(*params))
 	}
 	if !sp.EditInfoCUDSearchParams.IsEmpty() {
 		sp.EditInfoCUDSearchParams.appendSearchQuery(tablePrefix, query, params)
@@ -163,9 +189,8 @@ func (sp TeamSortParams) OrderBy() string {
 }
 
 type TeamUpdateParams struct {
-	Id      string
-	Players *string
-	Status  *TeamStatus
+	Id     string
+	Status *TeamStatus
 	EditInfoUDUpdateParams
 }
 
@@ -177,11 +202,6 @@ func (up TeamUpdateParams) appendUpdateQuery(query *string, params *[]interface{
 		*query += fmt.Sprintf("status = $%d, ", len(*params))
 	}
 
-	if up.Players != nil {
-		*params = append(*params, up.Players)
-		*query += fmt.Sprintf("players = $%d, ", len(*params))
-	}
-
 	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
 
 	*params = append(*params, up.Id)
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type TeamRole string
+
+const (
+	TR_OWNER  TeamRole = "OWNER"
+	TR_MEMBER TeamRole = "MEMBER"
+)
+
+// TeamMember is membership of player in team, Position is order in which players joined (owner is first)
+type TeamMember struct {
+	TeamId   string   `json:"teamId" column:"team_id"`
+	PlayerId string   `json:"playerId" column:"player_id"`
+	Role     TeamRole `json:"role" column:"role"`
+	Position int      `json:"position" column:"position"`
+	EditInfoC
+}
+
+func (s *TeamMember) GetTableName() SportosEntity {
+	return "team_member"
+}
+
+func (s *TeamMember) GetId() string {
+	return s.TeamId + ":" + s.PlayerId
+}
+
+type TeamMemberSearchParams struct {
+	TeamIds  []string  `json:"teamIds,omitempty"`
+	PlayerId *string   `json:"playerId,omitempty"`
+	Role     *TeamRole `json:"role,omitempty"`
+	EditInfoCSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *TeamMemberSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "tm"
+}
+
+func (sp *TeamMemberSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *TeamMemberSearchParams) validate() error {
+	err := sp.EditInfoCSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *TeamMemberSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *TeamMemberSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if len(sp.TeamIds) != 0 {
+		*params = append(*params, pq.Array(sp.TeamIds))
+		*query += fmt.Sprintf(" and %v.team_id=any($%d)", tablePrefix, len(*params))
+	}
+	if sp.PlayerId != nil {
+		*params = append(*params, *sp.PlayerId)
+		*query += fmt.Sprintf(" and %v.player_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Role != nil {
+		*params = append(*params, *sp.Role)
+		*query += fmt.Sprintf(" and %v.role=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCSearchParams.IsEmpty() {
+		sp.EditInfoCSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *TeamMemberSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.team_id, %[1]s.position", sp.GetTablePrefix())
+}
+
+func (sp *TeamMemberSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *TeamMemberSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
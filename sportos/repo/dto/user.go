@@ -16,6 +16,12 @@ const (
 	UT_ADMIN  UserType = "admin"
 )
 
+// values of User.EmailVerified, negative values are unverified
+const (
+	EMAIL_NOT_VERIFIED = -1
+	EMAIL_VERIFIED     = 1
+)
+
 func (ut UserType) IsValid() bool {
 	return ut == UT_ADMIN || ut == UT_COACH || ut == UT_PLACE || ut == UT_PLAYER
 }
@@ -29,6 +35,7 @@ type User struct {
 	Token             *string    `json:"token" column:"token"`
 	TokenValidUntil   *time.Time `json:"tokenValidUntil" column:"token_valid_until"`
 	TokenRefreshUntil *time.Time `json:"tokenRefreshUntil" column:"token_refresh_until"`
+	TokenVersion      int        `json:"tokenVersion" column:"token_version"`
 	EditInfoCUD
 }
 
@@ -178,6 +185,8 @@ type UserUpdateParams struct {
 	Token             *string
 	TokenValidUntil   *time.Time
 	TokenRefreshUntil *time.Time
+	// RevokeTokens increments token version, tokens issued before can't be refreshed
+	RevokeTokens bool
 	EditInfoUDUpdateParams
 }
 
@@ -209,6 +218,10 @@ func (up UserUpdateParams) appendUpdateQuery(query *string, params *[]interface{
 		*query += fmt.Sprintf("token_refresh_until = $%d, ", len(*params))
 	}
 
+	if up.RevokeTokens {
+		*query += "token_version = token_version + 1, "
+	}
+
 	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
 
 	*params = append(*params, up.Id)
@@ -6,10 +6,19 @@ import (
 )
 
 type UserPost struct {
-	UserId     string   `json:"userId,omitempty"`
-	UserText   string   `json:"userText,omitempty"`
-	ImageNames []string `json:"imageNames,omitempty"`
-	EditInfoC
+	PostId     string   `json:"postId,omitempty" column:"post_id"`
+	UserId     string   `json:"userId,omitempty" column:"user_id"`
+	UserText   string   `json:"userText,omitempty" column:"user_text"`
+	ImageNames []string `json:"imageNames,omitempty" column:"image_names"`
+	EditInfoCD
+}
+
+func (s *UserPost) GetTableName() SportosEntity {
+	return "userpost"
+}
+
+func (s *UserPost) GetId() string {
+	return s.PostId
 }
 
 type UserPostSearchParams struct {
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TokenPurpose string
+
+const (
+	TP_VERIFY_EMAIL   TokenPurpose = "VERIFY_EMAIL"
+	TP_RESET_PASSWORD TokenPurpose = "RESET_PASSWORD"
+)
+
+// VerificationToken is single use token sent by email, only hash of token is stored
+type VerificationToken struct {
+	TokenHash  string       `json:"tokenHash" column:"token_hash"`
+	UserId     string       `json:"userId" column:"user_id"`
+	Purpose    TokenPurpose `json:"purpose" column:"purpose"`
+	ValidUntil time.Time    `json:"validUntil" column:"valid_until"`
+	ConsumedAt *time.Time   `json:"consumedAt" column:"consumed_at"`
+	EditInfoC
+}
+
+func (vt *VerificationToken) GetTableName() SportosEntity {
+	return "verification_token"
+}
+
+func (vt *VerificationToken) GetId() string {
+	return vt.TokenHash
+}
+
+type VerificationTokenSearchParams struct {
+	UserId  *string       `json:"userId,omitempty"`
+	Purpose *TokenPurpose `json:"purpose,omitempty"`
+	EditInfoCSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *VerificationTokenSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "vt"
+}
+
+func (sp *VerificationTokenSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *VerificationTokenSearchParams) validate() error {
+	err := sp.EditInfoCSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *VerificationTokenSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *VerificationTokenSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.UserId != nil && len(*sp.UserId) != 0 {
+		*params = append(*params, *sp.UserId)
+		*query += fmt.Sprintf(" and %v.user_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Purpose != nil && len(*sp.Purpose) != 0 {
+		*params = append(*params, *sp.Purpose)
+		*query += fmt.Sprintf(" and %v.purpose=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCSearchParams.IsEmpty() {
+		sp.EditInfoCSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *VerificationTokenSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%s.created_at desc", sp.GetTablePrefix())
+}
+
+func (sp *VerificationTokenSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *VerificationTokenSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
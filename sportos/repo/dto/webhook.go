@@ -0,0 +1,322 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookEvents are domain events that can be delivered to webhooks, all of them have placeId in payload
+var WebhookEvents = []OutboxEventType{OE_MATCH_BOOKED, OE_MATCH_CANCELLED, OE_TOURNAMENT_STATUS_CHANGED}
+
+// Webhook is url of place or integrator that receives domain events of place, Events filters types
+// of delivered events (all webhook events if it is empty). Deliveries are signed with Secret
+type Webhook struct {
+	WebhookId string   `json:"webhookId" column:"webhook_id"`
+	PlaceId   string   `json:"placeId" column:"place_id"`
+	Url       string   `json:"url" column:"url"`
+	Secret    string   `json:"secret" column:"secret"`
+	Events    []string `json:"events" column:"events"`
+	Active    bool     `json:"active" column:"active"`
+	EditInfoCUD
+}
+
+func (s *Webhook) GetTableName() SportosEntity {
+	return "webhook"
+}
+
+func (s *Webhook) GetId() string {
+	return s.WebhookId
+}
+
+// Accepts returns true if event of type should be delivered to webhook
+func (s *Webhook) Accepts(eventType OutboxEventType) bool {
+	if !s.Active || s.IsDeleted() {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookSearchParams struct {
+	PlaceId *string `json:"placeId,omitempty"`
+	Active  *bool   `json:"active,omitempty"`
+	// EventType returns webhooks whose filter accepts event type
+	EventType *OutboxEventType `json:"eventType,omitempty"`
+	EditInfoCUDSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *WebhookSearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "wh"
+}
+
+func (sp *WebhookSearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *WebhookSearchParams) validate() error {
+	err := sp.EditInfoCUDSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *WebhookSearchParams) joinTables(query *string) {
+
+}
+
+func (sp *WebhookSearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.PlaceId != nil {
+		*params = append(*params, *sp.PlaceId)
+		*query += fmt.Sprintf(" and %v.place_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Active != nil {
+		*params = append(*params, *sp.Active)
+		*query += fmt.Sprintf(" and %v.active=$%d", tablePrefix, len(*params))
+	}
+	if sp.EventType != nil {
+		*params = append(*params, *sp.EventType)
+		*query += fmt.Sprintf(" and (cardinality(%[1]v.events) = 0 or $%[2]d = any(%[1]v.events))", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCUDSearchParams.IsEmpty() {
+		sp.EditInfoCUDSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *WebhookSearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.created_at, %[1]s.webhook_id", sp.GetTablePrefix())
+}
+
+func (sp *WebhookSearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *WebhookSearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type WebhookUpdateParams struct {
+	Id     string
+	Url    *string
+	Secret *string
+	Events *[]string
+	Active *bool
+	EditInfoUDUpdateParams
+}
+
+func (up WebhookUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update webhook wh set `
+
+	if up.Url != nil {
+		*params = append(*params, *up.Url)
+		*query += fmt.Sprintf("url = $%d, ", len(*params))
+	}
+
+	if up.Secret != nil {
+		*params = append(*params, *up.Secret)
+		*query += fmt.Sprintf("secret = $%d, ", len(*params))
+	}
+
+	if up.Events != nil {
+		*params = append(*params, pq.Array(*up.Events))
+		*query += fmt.Sprintf("events = $%d, ", len(*params))
+	}
+
+	if up.Active != nil {
+		*params = append(*params, *up.Active)
+		*query += fmt.Sprintf("active = $%d, ", len(*params))
+	}
+
+	up.EditInfoUDUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where wh.webhook_id = $%d;", len(*params))
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WDS_PENDING   WebhookDeliveryStatus = "PENDING"
+	WDS_DELIVERED WebhookDeliveryStatus = "DELIVERED"
+	WDS_FAILED    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is one domain event sent to webhook, Request is signed body and Response is body
+// returned by webhook on last attempt, like in api journal
+type WebhookDelivery struct {
+	DeliveryId    string                `json:"deliveryId" column:"delivery_id"`
+	WebhookId     string                `json:"webhookId" column:"webhook_id"`
+	OutboxEventId string                `json:"outboxEventId" column:"outbox_event_id"`
+	EventType     OutboxEventType       `json:"eventType" column:"event_type"`
+	Request       string                `json:"request" column:"request"`
+	Response      *string               `json:"response" column:"response"`
+	ResponseCode  *int                  `json:"responseCode" column:"response_code"`
+	Status        WebhookDeliveryStatus `json:"status" column:"status"`
+	Attempts      int                   `json:"attempts" column:"attempts"`
+	NextAttemptAt time.Time             `json:"nextAttemptAt" column:"next_attempt_at"`
+	LastError     *string               `json:"lastError" column:"last_error"`
+	DeliveredAt   *time.Time            `json:"deliveredAt" column:"delivered_at"`
+	EditInfoCU
+}
+
+func (s *WebhookDelivery) GetTableName() SportosEntity {
+	return "webhook_delivery"
+}
+
+func (s *WebhookDelivery) GetId() string {
+	return s.DeliveryId
+}
+
+type WebhookDeliverySearchParams struct {
+	WebhookId *string                `json:"webhookId,omitempty"`
+	Status    *WebhookDeliveryStatus `json:"status,omitempty"`
+	EditInfoCUSearchParams
+	PagingSearchParams
+	prefix string
+}
+
+func (sp *WebhookDeliverySearchParams) GetTablePrefix() string {
+	if sp.prefix != "" {
+		return sp.prefix
+	}
+	return "wd"
+}
+
+func (sp *WebhookDeliverySearchParams) SetTablePrefix(prefix string) {
+	sp.prefix = prefix
+}
+
+func (sp *WebhookDeliverySearchParams) validate() error {
+	err := sp.EditInfoCUSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	err = sp.PagingSearchParams.validate()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sp *WebhookDeliverySearchParams) joinTables(query *string) {
+
+}
+
+func (sp *WebhookDeliverySearchParams) appendSearchQuery(query *string, params *[]interface{}) {
+	if !strings.Contains(*query, "where") {
+		*query += `where 1 = 1 `
+	}
+	tablePrefix := sp.GetTablePrefix()
+	if sp.WebhookId != nil {
+		*params = append(*params, *sp.WebhookId)
+		*query += fmt.Sprintf(" and %v.webhook_id=$%d", tablePrefix, len(*params))
+	}
+	if sp.Status != nil {
+		*params = append(*params, *sp.Status)
+		*query += fmt.Sprintf(" and %v.status=$%d", tablePrefix, len(*params))
+	}
+	if !sp.EditInfoCUSearchParams.IsEmpty() {
+		sp.EditInfoCUSearchParams.appendSearchQuery(tablePrefix, query, params)
+	}
+}
+
+func (sp *WebhookDeliverySearchParams) appendSortQuery(query *string) {
+	if !strings.Contains(*query, "order by") {
+		*query += ` order by `
+	}
+	*query += fmt.Sprintf("%[1]s.created_at desc, %[1]s.delivery_id desc", sp.GetTablePrefix())
+}
+
+func (sp *WebhookDeliverySearchParams) appendGroupByQuery(query *string) {
+
+}
+
+func (sp *WebhookDeliverySearchParams) appendPagingQuery(query *string, params *[]interface{}) {
+	if !sp.PagingSearchParams.IsEmpty() {
+		sp.PagingSearchParams.appendSearchQuery(query, params)
+	}
+}
+
+type WebhookDeliveryUpdateParams struct {
+	Id            string
+	Response      *string
+	ResponseCode  *int
+	Status        *WebhookDeliveryStatus
+	Attempts      *int
+	NextAttemptAt *time.Time
+	LastError     *string
+	DeliveredAt   *time.Time
+	EditInfoUUpdateParams
+}
+
+func (up WebhookDeliveryUpdateParams) appendUpdateQuery(query *string, params *[]interface{}) {
+	*query = `update webhook_delivery wd set `
+
+	if up.Response != nil {
+		*params = append(*params, *up.Response)
+		*query += fmt.Sprintf("response = $%d, ", len(*params))
+	}
+
+	if up.ResponseCode != nil {
+		*params = append(*params, *up.ResponseCode)
+		*query += fmt.Sprintf("response_code = $%d, ", len(*params))
+	}
+
+	if up.Status != nil {
+		*params = append(*params, *up.Status)
+		*query += fmt.Sprintf("status = $%d, ", len(*params))
+	}
+
+	if up.Attempts != nil {
+		*params = append(*params, *up.Attempts)
+		*query += fmt.Sprintf("attempts = $%d, ", len(*params))
+	}
+
+	if up.NextAttemptAt != nil {
+		*params = append(*params, *up.NextAttemptAt)
+		*query += fmt.Sprintf("next_attempt_at = $%d, ", len(*params))
+	}
+
+	if up.LastError != nil {
+		*params = append(*params, *up.LastError)
+		*query += fmt.Sprintf("last_error = $%d, ", len(*params))
+	}
+
+	if up.DeliveredAt != nil {
+		*params = append(*params, *up.DeliveredAt)
+		*query += fmt.Sprintf("delivered_at = $%d, ", len(*params))
+	}
+
+	up.EditInfoUUpdateParams.appendUpdateQuery(query, params)
+
+	*params = append(*params, up.Id)
+	*query += fmt.Sprintf("where wd.delivery_id = $%d;", len(*params))
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/util"
+	"context"
+	"fmt"
+)
+
+type apiJournalStore struct {
+	s *store
+}
+
+func (r *apiJournalStore) Create(ctx context.Context, en DR.ApiJournal, qa crud.QueryAble, by *string) (DR.ApiJournal, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	var reqJson *string
+	if en.RequestBodyString != nil && util.IsJSON(*en.RequestBodyString) {
+		reqJson = en.RequestBodyString
+	}
+	row := DR.ApiJournal{
+		Request:     en.Request,
+		RequestJson: reqJson,
+		SourceIP:    en.SourceIP,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.apiJournals.insertNext(row, func(aj *DR.ApiJournal, id string) {
+		aj.ApiJournalId = id
+	})
+	return pen, nil
+}
+
+func (r *apiJournalStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.ApiJournal, error) {
+	aj, ok := r.s.apiJournals.get(id)
+	if !ok {
+		return DR.ApiJournal{}, fmt.Errorf("api journal does not exist for id: %v", id)
+	}
+	return aj, nil
+}
+
+func (r *apiJournalStore) GetCount(ctx context.Context, sp DR.ApiJournalSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *apiJournalStore) Search(ctx context.Context, sp DR.ApiJournalSearchParams, qa crud.QueryAble) ([]DR.ApiJournal, error) {
+	journals := r.find(sp)
+	sortRows(journals, sp.ApiJournalSortParams.SortColumns())
+	return page(journals, sp.PagingSearchParams), nil
+}
+
+func (r *apiJournalStore) find(sp DR.ApiJournalSearchParams) []DR.ApiJournal {
+	journals := r.s.apiJournals.find(func(aj DR.ApiJournal) bool {
+		return matchesNonEmpty(sp.ApiJournalId, aj.ApiJournalId) &&
+			(sp.SourceIP == nil || *sp.SourceIP == "" || (aj.SourceIP != nil && *aj.SourceIP == *sp.SourceIP)) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, aj.EditInfoC)
+	})
+	if sp.UserSearchParams == nil {
+		return journals
+	}
+	return filter(journals, func(aj DR.ApiJournal) bool {
+		return aj.UserId != nil && r.s.joinsUser(sp.UserSearchParams, *aj.UserId)
+	})
+}
+
+func (r *apiJournalStore) Update(ctx context.Context, up DR.ApiJournalUpdateParams, qa crud.QueryAble, by *string) (DR.ApiJournal, error) {
+	up.PopulateUpdateFields(by)
+
+	_, pen, ok := r.s.apiJournals.update(up.Id, func(aj *DR.ApiJournal) {
+		if up.Response != nil {
+			aj.Response = up.Response
+		}
+		if up.ResponseJson != nil {
+			aj.ResponseJson = up.ResponseJson
+		}
+		if up.UserId != nil {
+			aj.UserId = up.UserId
+		}
+		applyEditInfoU(&aj.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return DR.ApiJournal{}, fmt.Errorf("no rows affected")
+	}
+	return pen, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type auditStore struct {
+	s       *store
+	Enabled bool
+}
+
+func (r *auditStore) Start() {
+	r.Enabled = true
+}
+
+func (r *auditStore) Stop() {
+	r.Enabled = false
+}
+
+// CreateSnapshot creates audit from old and new value of entity the same way as postgres audit crud
+func (r *auditStore) CreateSnapshot(ctx context.Context, old, new DR.CommonEntity, qa crud.QueryAble, by *string) (DR.Audit, error) {
+	if !r.Enabled {
+		return DR.Audit{}, nil
+	}
+	auditVal, err := crud.NewAuditSnapshot(ctx, old, new)
+	if err != nil {
+		return DR.Audit{}, err
+	}
+	ret := DR.Audit{}
+	if len(auditVal.Old) > 0 || len(auditVal.New) > 0 {
+		ret, err = r.Create(ctx, auditVal, qa, by)
+	}
+	return ret, err
+}
+
+func (r *auditStore) Create(ctx context.Context, en DR.Audit, qa crud.QueryAble, by *string) (DR.Audit, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	pen := r.s.audits.insertNext(en, func(aud *DR.Audit, id string) {
+		aud.AuditId = id
+	})
+	return pen, nil
+}
+
+func (r *auditStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Audit, error) {
+	aud, ok := r.s.audits.get(id)
+	if !ok {
+		return DR.Audit{}, fmt.Errorf("audit does not exist for id: %v", id)
+	}
+	return aud, nil
+}
+
+func (r *auditStore) GetCount(ctx context.Context, sp DR.AuditSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *auditStore) Search(ctx context.Context, sp DR.AuditSearchParams, qa crud.QueryAble) ([]DR.Audit, error) {
+	audits := r.find(sp)
+	sortRows(audits, sp.AuditSortParams.SortColumns())
+	return page(audits, sp.PagingSearchParams), nil
+}
+
+func (r *auditStore) find(sp DR.AuditSearchParams) []DR.Audit {
+	var entity *string
+	if sp.Entity != nil {
+		e := string(*sp.Entity)
+		entity = &e
+	}
+	return r.s.audits.find(func(aud DR.Audit) bool {
+		crudAction := ""
+		if aud.CrudAction != nil {
+			crudAction = string(*aud.CrudAction)
+		}
+		return matchesNonEmpty(entity, string(aud.Entity)) &&
+			matchesNonEmpty(sp.EntityId, aud.EntityId) &&
+			matchesNonEmpty(sp.CrudAction, crudAction) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, aud.EditInfoC)
+	})
+}
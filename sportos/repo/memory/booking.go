@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type bookingStore struct {
+	s *store
+}
+
+// overlaps returns true if other confirmed booking of owner overlaps bo, like booking_no_overlap constraint
+func (r *bookingStore) overlaps(bo DR.Booking) bool {
+	if bo.Status != DR.BS_CONFIRMED || bo.IsDeleted() {
+		return false
+	}
+	return r.s.bookings.exists(func(other DR.Booking) bool {
+		return other.BookingId != bo.BookingId && other.OwnerId == bo.OwnerId && other.Status == DR.BS_CONFIRMED &&
+			!other.IsDeleted() && other.Overlaps(bo.StartTime, bo.EndTime)
+	})
+}
+
+func (r *bookingStore) Create(ctx context.Context, en DR.Booking, qa crud.QueryAble, by *string) (DR.Booking, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.Booking{
+		OwnerId:     en.OwnerId,
+		Kind:        en.Kind,
+		ReferenceId: en.ReferenceId,
+		StartTime:   en.StartTime,
+		EndTime:     en.EndTime,
+		Status:      en.Status,
+	}
+	row.EditInfoC = en.EditInfoC
+
+	// check and insert have to be atomic so concurrent requests can't book same time
+	r.s.bookingLock.Lock()
+	if r.overlaps(row) {
+		r.s.bookingLock.Unlock()
+		return en, crud.ErrBookingOverlap
+	}
+	pen := r.s.bookings.insertNext(row, func(bo *DR.Booking, id string) {
+		bo.BookingId = id
+	})
+	r.s.bookingLock.Unlock()
+
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *bookingStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Booking, error) {
+	bo, ok := r.s.bookings.get(id)
+	if !ok {
+		return DR.Booking{}, fmt.Errorf("booking does not exist for id: %v", id)
+	}
+	return bo, nil
+}
+
+func (r *bookingStore) GetCount(ctx context.Context, sp DR.BookingSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *bookingStore) Search(ctx context.Context, sp DR.BookingSearchParams, qa crud.QueryAble) ([]DR.Booking, error) {
+	bookings := r.find(sp)
+	sort.SliceStable(bookings, func(i, j int) bool {
+		return bookings[i].StartTime.Before(bookings[j].StartTime)
+	})
+	return page(bookings, sp.PagingSearchParams), nil
+}
+
+func (r *bookingStore) find(sp DR.BookingSearchParams) []DR.Booking {
+	return r.s.bookings.find(func(bo DR.Booking) bool {
+		return matches(sp.OwnerId, bo.OwnerId) &&
+			(sp.Kind == nil || *sp.Kind == bo.Kind) &&
+			matches(sp.ReferenceId, bo.ReferenceId) &&
+			(sp.Status == nil || *sp.Status == bo.Status) &&
+			(sp.From == nil || sp.To == nil || bo.Overlaps(*sp.From, *sp.To)) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, bo.EditInfoCUD)
+	})
+}
+
+func (r *bookingStore) Update(ctx context.Context, up DR.BookingUpdateParams, qa crud.QueryAble, by *string) (DR.Booking, error) {
+	up.PopulateUpdateFields(by)
+
+	r.s.bookingLock.Lock()
+	bo, ok := r.s.bookings.get(up.Id)
+	if ok && up.Status != nil {
+		bo.Status = *up.Status
+		if r.overlaps(bo) {
+			r.s.bookingLock.Unlock()
+			return DR.Booking{}, crud.ErrBookingOverlap
+		}
+	}
+	old, pen, ok := r.s.bookings.update(up.Id, func(bo *DR.Booking) {
+		if up.Status != nil {
+			bo.Status = *up.Status
+		}
+		applyEditInfoUD(&bo.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	r.s.bookingLock.Unlock()
+	if !ok {
+		return DR.Booking{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *bookingStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.bookings, id, func(bo *DR.Booking) *DR.EditInfoD {
+		return &bo.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type coachStore struct {
+	s *store
+}
+
+func (r *coachStore) Create(ctx context.Context, en DR.Coach, qa crud.QueryAble, by *string) (DR.Coach, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.Coach{
+		Username: en.Username,
+		Name:     en.Name,
+		City:     en.City,
+		Sport:    en.Sport,
+		TimeZone: en.TimeZone,
+	}
+	if row.TimeZone == "" {
+		row.TimeZone = DR.DEFAULT_TIME_ZONE
+	}
+	row.EditInfoC = en.EditInfoC
+	if !r.s.coaches.insert(en.Username, row) {
+		return en, fmt.Errorf("user with username %s already exists", en.Username)
+	}
+	pen, err := r.GetById(ctx, en.Username, qa)
+	if err != nil {
+		return pen, err
+	}
+	_, err = r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *coachStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Coach, error) {
+	en, ok := r.s.coaches.get(id)
+	if !ok {
+		return DR.Coach{}, fmt.Errorf("coach does not exist for username: %v", id)
+	}
+	return en, nil
+}
+
+// GetByEmail returns coach of user with email
+func (r *coachStore) GetByEmail(ctx context.Context, email string, qa crud.QueryAble) (DR.Coach, error) {
+	if usr, ok := r.s.userByEmail(email); ok {
+		if en, ok := r.s.coaches.get(usr.Username); ok {
+			return en, nil
+		}
+	}
+	return DR.Coach{}, fmt.Errorf("coach does not exist for email: %v", email)
+}
+
+func (r *coachStore) GetCount(ctx context.Context, sp DR.CoachSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *coachStore) Search(ctx context.Context, sp DR.CoachSearchParams, qa crud.QueryAble) ([]DR.Coach, error) {
+	rows := r.find(sp)
+	sortRows(rows, sp.CoachSortParams.SortColumns())
+	return page(rows, sp.PagingSearchParams), nil
+}
+
+func (r *coachStore) find(sp DR.CoachSearchParams) []DR.Coach {
+	rows := r.s.coaches.find(func(en DR.Coach) bool {
+		return matchesNonEmpty(sp.Username, en.Username) &&
+			matchesNonEmpty(sp.Name, en.Name) &&
+			matchesNonEmpty(sp.City, en.City) &&
+			matchesNonEmpty(sp.Sport, en.Sport) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, en.EditInfoCUD)
+	})
+	return filter(rows, func(en DR.Coach) bool {
+		return r.s.joinsUser(sp.UserSearchParams, en.Username)
+	})
+}
+
+func (r *coachStore) Update(ctx context.Context, up DR.CoachUpdateParams, qa crud.QueryAble, by *string) (DR.Coach, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.coaches.update(up.Id, func(en *DR.Coach) {
+		if up.Name != nil {
+			en.Name = *up.Name
+		}
+		if up.City != nil {
+			en.City = *up.City
+		}
+		if up.Reviews != nil {
+			en.Reviews = up.Reviews
+		}
+		if up.Schedule != nil {
+			en.Schedule = up.Schedule
+		}
+		if up.TimeZone != nil {
+			en.TimeZone = *up.TimeZone
+		}
+		applyEditInfoUD(&en.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Coach{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *coachStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.coaches, id, func(co *DR.Coach) *DR.EditInfoD {
+		return &co.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
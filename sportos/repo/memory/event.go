@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type eventStore struct {
+	s *store
+}
+
+func (r *eventStore) Create(ctx context.Context, en DR.Event, qa crud.QueryAble, by *string) (DR.Event, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	exists := r.s.events.exists(func(ev DR.Event) bool {
+		return ev.Owner == en.Owner && ev.Name == en.Name
+	})
+	if exists {
+		return en, fmt.Errorf("event with name %s already exists for place %s", en.Name, en.Owner)
+	}
+
+	row := DR.Event{
+		Name:       en.Name,
+		Owner:      en.Owner,
+		Sport:      en.Sport,
+		Status:     en.Status,
+		Time:       en.Time,
+		Tournament: en.Tournament,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.events.insertNext(row, func(ev *DR.Event, id string) {
+		ev.EventId = id
+	})
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *eventStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Event, error) {
+	ev, ok := r.s.events.get(id)
+	if !ok {
+		return DR.Event{}, fmt.Errorf("event does not exist for username: %v", id)
+	}
+	return ev, nil
+}
+
+func (r *eventStore) GetCount(ctx context.Context, sp DR.EventSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *eventStore) Search(ctx context.Context, sp DR.EventSearchParams, qa crud.QueryAble) ([]DR.Event, error) {
+	events := r.find(sp)
+	sortRows(events, sp.EventSortParams.SortColumns())
+	return page(events, sp.PagingSearchParams), nil
+}
+
+func (r *eventStore) find(sp DR.EventSearchParams) []DR.Event {
+	return r.s.events.find(func(ev DR.Event) bool {
+		return matchesNonEmpty(sp.Name, ev.Name) &&
+			matchesNonEmpty(sp.Owner, ev.Owner) &&
+			matchesAny(sp.Sports, ev.Sport) &&
+			matches(sp.Status, string(ev.Status)) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, ev.EditInfoCUD)
+	})
+}
+
+func (r *eventStore) Update(ctx context.Context, up DR.EventUpdateParams, qa crud.QueryAble, by *string) (DR.Event, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.events.update(up.Id, func(ev *DR.Event) {
+		if up.Teams != nil {
+			ev.Teams = *up.Teams
+		}
+		if up.Tournament != nil {
+			ev.Tournament = up.Tournament
+		}
+		if up.Status != nil {
+			ev.Status = *up.Status
+		}
+		applyEditInfoUD(&ev.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Event{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *eventStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.events, id, func(ev *DR.Event) *DR.EditInfoD {
+		return &ev.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type leaderboardStore struct {
+	s *store
+}
+
+func (r *leaderboardStore) Upsert(ctx context.Context, en DR.Leaderboard, qa crud.QueryAble) error {
+	if en.UpdatedAt.IsZero() {
+		en.UpdatedAt = time.Now().UTC()
+	}
+	// name and city are joined from player on read
+	en.Name, en.City = "", ""
+	id := en.PlayerId + "|" + en.Sport
+	if !r.s.leaderboards.insert(id, en) {
+		r.s.leaderboards.update(id, func(lb *DR.Leaderboard) {
+			*lb = en
+		})
+	}
+	return nil
+}
+
+func (r *leaderboardStore) GetCount(ctx context.Context, sp DR.LeaderboardSearchParams, qa crud.QueryAble) (int, error) {
+	rows, err := r.find(sp)
+	return len(rows), err
+}
+
+// Search returns leaderboard from best player by metric of search params like postgres crud
+func (r *leaderboardStore) Search(ctx context.Context, sp DR.LeaderboardSearchParams, qa crud.QueryAble) ([]DR.Leaderboard, error) {
+	rows, err := r.find(sp)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch sp.By {
+		case DR.LM_MATCHES:
+			if a.Matches != b.Matches {
+				return a.Matches > b.Matches
+			}
+			if !a.WinRatio.Equal(b.WinRatio) {
+				return a.WinRatio.GreaterThan(b.WinRatio)
+			}
+		case DR.LM_TOURNAMENTS:
+			if a.TournamentPoints != b.TournamentPoints {
+				return a.TournamentPoints > b.TournamentPoints
+			}
+			if a.Tournaments != b.Tournaments {
+				return a.Tournaments > b.Tournaments
+			}
+		case DR.LM_RATING:
+			if *a.Rating != *b.Rating {
+				return *a.Rating > *b.Rating
+			}
+			if a.Matches != b.Matches {
+				return a.Matches > b.Matches
+			}
+		default:
+			if !a.WinRatio.Equal(b.WinRatio) {
+				return a.WinRatio.GreaterThan(b.WinRatio)
+			}
+			if a.Matches != b.Matches {
+				return a.Matches > b.Matches
+			}
+		}
+		return a.PlayerId < b.PlayerId
+	})
+	return page(rows, sp.PagingSearchParams), nil
+}
+
+func (r *leaderboardStore) find(sp DR.LeaderboardSearchParams) ([]DR.Leaderboard, error) {
+	if sp.By != "" && !sp.By.IsValid() {
+		return nil, fmt.Errorf("leaderboard can't be ranked by %s", sp.By)
+	}
+	rows := r.s.leaderboards.find(func(lb DR.Leaderboard) bool {
+		return matches(sp.PlayerId, lb.PlayerId) &&
+			matches(sp.Sport, lb.Sport) &&
+			(sp.MinMatches == nil || lb.Matches >= *sp.MinMatches) &&
+			(sp.By != DR.LM_RATING || lb.Rating != nil)
+	})
+	results := []DR.Leaderboard{}
+	for _, lb := range rows {
+		player, ok := r.s.players.get(lb.PlayerId)
+		if !ok || player.DeletedAt != nil || !matchesNonEmpty(sp.City, player.City) {
+			continue
+		}
+		lb.Name, lb.City = player.Name, player.City
+		results = append(results, lb)
+	}
+	return results, nil
+}
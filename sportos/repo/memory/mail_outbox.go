@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type mailOutboxStore struct {
+	s *store
+}
+
+func (r *mailOutboxStore) Create(ctx context.Context, en DR.MailOutbox, qa crud.QueryAble, by *string) (DR.MailOutbox, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.MOS_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+	return r.s.mails.insertNext(en, func(mo *DR.MailOutbox, id string) {
+		mo.MailId = id
+	}), nil
+}
+
+func (r *mailOutboxStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.MailOutbox, error) {
+	mo, ok := r.s.mails.get(id)
+	if !ok {
+		return DR.MailOutbox{}, fmt.Errorf("mail does not exist for id: %v", id)
+	}
+	return mo, nil
+}
+
+// GetPending returns pending mails that are due for sending
+func (r *mailOutboxStore) GetPending(ctx context.Context, limit int, qa crud.QueryAble) ([]DR.MailOutbox, error) {
+	now := time.Now().UTC()
+	mails := r.s.mails.find(func(mo DR.MailOutbox) bool {
+		return mo.Status == DR.MOS_PENDING && !mo.NextAttemptAt.After(now)
+	})
+	sort.SliceStable(mails, func(i, j int) bool {
+		return mails[i].NextAttemptAt.Before(mails[j].NextAttemptAt)
+	})
+	if len(mails) > limit {
+		mails = mails[:limit]
+	}
+	return mails, nil
+}
+
+func (r *mailOutboxStore) Update(ctx context.Context, up DR.MailOutboxUpdateParams, qa crud.QueryAble, by *string) error {
+	up.PopulateUpdateFields(by)
+
+	_, _, ok := r.s.mails.update(up.Id, func(mo *DR.MailOutbox) {
+		if up.Status != nil {
+			mo.Status = *up.Status
+		}
+		if up.Attempts != nil {
+			mo.Attempts = *up.Attempts
+		}
+		if up.NextAttemptAt != nil {
+			mo.NextAttemptAt = *up.NextAttemptAt
+		}
+		if up.LastError != nil {
+			mo.LastError = up.LastError
+		}
+		if up.SentAt != nil {
+			mo.SentAt = up.SentAt
+		}
+		applyEditInfoU(&mo.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
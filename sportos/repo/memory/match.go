@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type matchStore struct {
+	s *store
+}
+
+func (r *matchStore) Create(ctx context.Context, en DR.Match, qa crud.QueryAble, by *string) (DR.Match, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	if en.Balancing == "" {
+		en.Balancing = DR.MB_RANDOM
+	}
+	row := DR.Match{
+		StartTime: en.StartTime,
+		PlaceId:   en.PlaceId,
+		Status:    en.Status,
+		Sport:     en.Sport,
+		Balancing: en.Balancing,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.matches.insertNext(row, func(ma *DR.Match, id string) {
+		ma.MatchId = id
+	})
+	// first player is creator of match
+	for i, playerId := range en.Players {
+		role := DR.MR_PLAYER
+		if i == 0 {
+			role = DR.MR_CREATOR
+		}
+		if _, err := (&matchPlayerStore{r.s}).Create(ctx, DR.MatchPlayer{MatchId: pen.MatchId, PlayerId: playerId, Role: role}, qa, by); err != nil {
+			return pen, err
+		}
+	}
+	pen.SetRoster(r.s.roster(pen.MatchId))
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *matchStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Match, error) {
+	ma, ok := r.s.matches.get(id)
+	if !ok {
+		return DR.Match{}, fmt.Errorf("match does not exist for username: %v", id)
+	}
+	ma.SetRoster(r.s.roster(ma.MatchId))
+	return ma, nil
+}
+
+func (r *matchStore) GetCount(ctx context.Context, sp DR.MatchSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *matchStore) Search(ctx context.Context, sp DR.MatchSearchParams, qa crud.QueryAble) ([]DR.Match, error) {
+	matches := r.find(sp)
+	sortRows(matches, sp.MatchSortParams.SortColumns())
+	return page(matches, sp.PagingSearchParams), nil
+}
+
+func (r *matchStore) find(sp DR.MatchSearchParams) []DR.Match {
+	matches := r.s.matches.find(func(ma DR.Match) bool {
+		return matchesNonEmpty(sp.Status, string(ma.Status)) &&
+			matchesAny(sp.Sports, ma.Sport) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, ma.EditInfoCUD)
+	})
+	for i := range matches {
+		matches[i].SetRoster(r.s.roster(matches[i].MatchId))
+	}
+	if sp.PlayerId != nil {
+		matches = filter(matches, func(ma DR.Match) bool {
+			return ma.HasPlayer(*sp.PlayerId)
+		})
+	}
+	if sp.PlaceSearchParams == nil {
+		return matches
+	}
+	places := (&placeStore{r.s}).find(*sp.PlaceSearchParams)
+	return filter(matches, func(ma DR.Match) bool {
+		for _, pla := range places {
+			if pla.Username == ma.PlaceId {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (r *matchStore) Update(ctx context.Context, up DR.MatchUpdateParams, qa crud.QueryAble, by *string) (DR.Match, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.matches.update(up.Id, func(ma *DR.Match) {
+		if up.Status != nil {
+			ma.Status = *up.Status
+		}
+		if up.Result != nil {
+			ma.Result = up.Result
+		}
+		applyEditInfoUD(&ma.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Match{}, fmt.Errorf("no rows affected")
+	}
+	old.SetRoster(r.s.roster(up.Id))
+	pen.SetRoster(r.s.roster(up.Id))
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *matchStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.matches, id, func(ma *DR.Match) *DR.EditInfoD {
+		return &ma.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	old.SetRoster(r.s.roster(id))
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type matchPlayerStore struct {
+	s *store
+}
+
+func (r *matchPlayerStore) Create(ctx context.Context, en DR.MatchPlayer, qa crud.QueryAble, by *string) (DR.MatchPlayer, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	en.Position = len(r.find(DR.MatchPlayerSearchParams{MatchIds: []string{en.MatchId}}))
+	if !r.s.matchPlayers.insert(en.GetId(), en) {
+		return en, fmt.Errorf("player %s is already in match %s", en.PlayerId, en.MatchId)
+	}
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &en, qa, by)
+	return en, err
+}
+
+func (r *matchPlayerStore) GetById(ctx context.Context, matchId, playerId string, qa crud.QueryAble) (DR.MatchPlayer, error) {
+	mp, ok := r.s.matchPlayers.get((&DR.MatchPlayer{MatchId: matchId, PlayerId: playerId}).GetId())
+	if !ok {
+		return DR.MatchPlayer{}, fmt.Errorf("player %v is not in match %v", playerId, matchId)
+	}
+	return mp, nil
+}
+
+func (r *matchPlayerStore) Search(ctx context.Context, sp DR.MatchPlayerSearchParams, qa crud.QueryAble) ([]DR.MatchPlayer, error) {
+	return page(r.find(sp), sp.PagingSearchParams), nil
+}
+
+// find returns members in insertion order, so members of every match are ordered by position
+func (r *matchPlayerStore) find(sp DR.MatchPlayerSearchParams) []DR.MatchPlayer {
+	return r.s.matchPlayers.find(func(mp DR.MatchPlayer) bool {
+		return matchesAny(sp.MatchIds, mp.MatchId) &&
+			matches(sp.PlayerId, mp.PlayerId) &&
+			(sp.Role == nil || *sp.Role == mp.Role) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, mp.EditInfoC) &&
+			matchesEditInfoU(sp.EditInfoUSearchParams, mp.EditInfoU)
+	})
+}
+
+func (r *matchPlayerStore) Update(ctx context.Context, up DR.MatchPlayerUpdateParams, qa crud.QueryAble, by *string) (DR.MatchPlayer, error) {
+	up.PopulateUpdateFields(by)
+
+	id := (&DR.MatchPlayer{MatchId: up.MatchId, PlayerId: up.PlayerId}).GetId()
+	old, pen, ok := r.s.matchPlayers.update(id, func(mp *DR.MatchPlayer) {
+		if up.Side != nil {
+			mp.Side = up.Side
+		}
+		mp.EditInfoU = up.EditInfoU
+	})
+	if !ok {
+		return DR.MatchPlayer{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+// roster returns members of match ordered by position
+func (s *store) roster(matchId string) []DR.MatchPlayer {
+	return (&matchPlayerStore{s}).find(DR.MatchPlayerSearchParams{MatchIds: []string{matchId}})
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type matchResultStore struct {
+	s *store
+}
+
+func (r *matchResultStore) Create(ctx context.Context, en DR.MatchResult, qa crud.QueryAble, by *string) (DR.MatchResult, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.MRS_PENDING
+	}
+	return r.s.matchResults.insertNext(en, func(mr *DR.MatchResult, id string) {
+		mr.ResultId = id
+	}), nil
+}
+
+func (r *matchResultStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.MatchResult, error) {
+	mr, ok := r.s.matchResults.get(id)
+	if !ok {
+		return DR.MatchResult{}, fmt.Errorf("match result does not exist for id: %v", id)
+	}
+	return mr, nil
+}
+
+func (r *matchResultStore) GetCount(ctx context.Context, sp DR.MatchResultSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns match results, oldest first
+func (r *matchResultStore) Search(ctx context.Context, sp DR.MatchResultSearchParams, qa crud.QueryAble) ([]DR.MatchResult, error) {
+	results := r.find(sp)
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.Before(results[j].CreatedAt)
+		}
+		return idLess(results[i].ResultId, results[j].ResultId)
+	})
+	return page(results, sp.PagingSearchParams), nil
+}
+
+func (r *matchResultStore) find(sp DR.MatchResultSearchParams) []DR.MatchResult {
+	return r.s.matchResults.find(func(mr DR.MatchResult) bool {
+		return matches(sp.MatchId, mr.MatchId) &&
+			(sp.Status == nil || *sp.Status == mr.Status) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, mr.EditInfoC) &&
+			matchesEditInfoU(sp.EditInfoUSearchParams, mr.EditInfoU)
+	})
+}
+
+// GetExpired returns pending results whose deadline passed
+func (r *matchResultStore) GetExpired(ctx context.Context, limit int, qa crud.QueryAble) ([]DR.MatchResult, error) {
+	now := time.Now().UTC()
+	results := r.s.matchResults.find(func(mr DR.MatchResult) bool {
+		return mr.Status == DR.MRS_PENDING && !mr.Deadline.After(now)
+	})
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].Deadline.Equal(results[j].Deadline) {
+			return results[i].Deadline.Before(results[j].Deadline)
+		}
+		return idLess(results[i].ResultId, results[j].ResultId)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (r *matchResultStore) Update(ctx context.Context, up DR.MatchResultUpdateParams, qa crud.QueryAble, by *string) error {
+	up.PopulateUpdateFields(by)
+
+	_, _, ok := r.s.matchResults.update(up.Id, func(mr *DR.MatchResult) {
+		if up.Result != nil {
+			mr.Result = *up.Result
+		}
+		if up.Status != nil {
+			mr.Status = *up.Status
+		}
+		if up.DisputedBy != nil {
+			mr.DisputedBy = up.DisputedBy
+		}
+		if up.DisputeReason != nil {
+			mr.DisputeReason = up.DisputeReason
+		}
+		if up.ReviewNote != nil {
+			mr.ReviewNote = up.ReviewNote
+		}
+		applyEditInfoU(&mr.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+// Package memory contains in memory implementation of repo stores, it is used for handler tests
+// and for running service without postgres (-db.driver=memory).
+//
+// Stores keep copies of entities in maps guarded by mutex and mirror behaviour of postgres cruds
+// (constraints, error messages, audit snapshots). Transactions are serialized: BeginTx waits for
+// transaction in progress and copies all tables, Rollback restores them, so changes made before failed step
+// of handler are undone. Changes made meanwhile outside of transaction by other goroutines are undone
+// too, api journal is the only table written outside of transactions during requests and isn't restored.
+package memory
+
+import (
+	"backend/internal/cache"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"encoding/json"
+	"strconv"
+	"sync"
+)
+
+// store holds tables of all entities, every entity store works on shared store
+// so joins (user of player, place of match) can be resolved
+type store struct {
+	users              *table[DR.User]
+	players            *table[DR.Player]
+	coaches            *table[DR.Coach]
+	places             *table[DR.Place]
+	events             *table[DR.Event]
+	matches            *table[DR.Match]
+	matchPlayers       *table[DR.MatchPlayer]
+	practices          *table[DR.Practice]
+	bookings           *table[DR.Booking]
+	teams              *table[DR.Team]
+	teamMembers        *table[DR.TeamMember]
+	userPosts          *table[DR.UserPost]
+	apiJournals        *table[DR.ApiJournal]
+	audits             *table[DR.Audit]
+	verificationTokens *table[DR.VerificationToken]
+	mails              *table[DR.MailOutbox]
+	notifications      *table[DR.Notification]
+	outboxEvents       *table[DR.OutboxEvent]
+	webhooks           *table[DR.Webhook]
+	webhookDeliveries  *table[DR.WebhookDelivery]
+	ratings            *table[DR.Rating]
+	ratingHistory      *table[DR.RatingHistory]
+	leaderboards       *table[DR.Leaderboard]
+	playerMatches      *table[DR.PlayerMatch]
+	playerTournaments  *table[DR.PlayerTournament]
+	matchResults       *table[DR.MatchResult]
+
+	audit *auditStore
+	// bookingLock makes overlap check and write of booking atomic
+	bookingLock sync.Mutex
+	// ratingLock makes lookup and insert of rating in GetOrCreate and of rating history atomic
+	ratingLock sync.Mutex
+	// txLock is held from begin until end of transaction
+	txLock sync.Mutex
+}
+
+// InitRepo returns repo backed by empty in memory store, repo.DB is nil
+func InitRepo() *crud.Repo {
+	s := &store{
+		users:              newTable[DR.User](),
+		players:            newTable[DR.Player](),
+		coaches:            newTable[DR.Coach](),
+		places:             newTable[DR.Place](),
+		events:             newTable[DR.Event](),
+		matches:            newTable[DR.Match](),
+		matchPlayers:       newTable[DR.MatchPlayer](),
+		practices:          newTable[DR.Practice](),
+		bookings:           newTable[DR.Booking](),
+		teams:              newTable[DR.Team](),
+		teamMembers:        newTable[DR.TeamMember](),
+		userPosts:          newTable[DR.UserPost](),
+		apiJournals:        newTable[DR.ApiJournal](),
+		audits:             newTable[DR.Audit](),
+		verificationTokens: newTable[DR.VerificationToken](),
+		mails:              newTable[DR.MailOutbox](),
+		notifications:      newTable[DR.Notification](),
+		outboxEvents:       newTable[DR.OutboxEvent](),
+		webhooks:           newTable[DR.Webhook](),
+		webhookDeliveries:  newTable[DR.WebhookDelivery](),
+		ratings:            newTable[DR.Rating](),
+		ratingHistory:      newTable[DR.RatingHistory](),
+		leaderboards:       newTable[DR.Leaderboard](),
+		playerMatches:      newTable[DR.PlayerMatch](),
+		playerTournaments:  newTable[DR.PlayerTournament](),
+		matchResults:       newTable[DR.MatchResult](),
+	}
+	s.audit = &auditStore{s: s}
+
+	return &crud.Repo{
+		BeginMemoryTx:         s.beginTx,
+		PlayerCrud:            &playerStore{s},
+		CoachCrud:             &coachStore{s},
+		PlaceCrud:             &placeStore{s},
+		EventCrud:             &eventStore{s},
+		ApiJournalCrud:        &apiJournalStore{s},
+		AuditCrud:             s.audit,
+		UserCrud:              &userStore{s},
+		MatchCrud:             &matchStore{s},
+		MatchPlayerCrud:       &matchPlayerStore{s},
+		PracticeCrud:          &practiceStore{s},
+		BookingCrud:           &bookingStore{s},
+		TeamCrud:              &teamStore{s},
+		TeamMemberCrud:        &teamMemberStore{s},
+		UserPostsCrud:         &userPostStore{s},
+		VerificationTokenCrud: &verificationTokenStore{s},
+		MailOutboxCrud:        &mailOutboxStore{s},
+		NotificationCrud:      &notificationStore{s},
+		OutboxEventCrud:       &outboxEventStore{s},
+		WebhookCrud:           &webhookStore{s},
+		WebhookDeliveryCrud:   &webhookDeliveryStore{s},
+		RatingCrud:            &ratingStore{s},
+		RatingHistoryCrud:     &ratingHistoryStore{s},
+		LeaderboardCrud:       &leaderboardStore{s},
+		PlayerMatchCrud:       &playerMatchStore{s},
+		PlayerTournamentCrud:  &playerTournamentStore{s},
+		MatchResultCrud:       &matchResultStore{s},
+		NameCache:             cache.NewCache[string, string](),
+	}
+}
+
+// beginTx waits for transaction in progress and copies tables, they are restored on rollback.
+// Commit or rollback after transaction ended does nothing, like defer tx.Rollback() after commit
+func (s *store) beginTx() crud.Tx {
+	s.txLock.Lock()
+	tables := []snapshotter{
+		s.users, s.players, s.coaches, s.places, s.events, s.matches, s.matchPlayers, s.practices, s.bookings,
+		s.teams, s.teamMembers, s.userPosts, s.audits, s.verificationTokens, s.mails, s.notifications,
+		s.outboxEvents, s.webhooks, s.webhookDeliveries, s.ratings, s.ratingHistory, s.leaderboards,
+		s.playerMatches, s.playerTournaments, s.matchResults,
+	}
+	restores := make([]func(), 0, len(tables))
+	for _, t := range tables {
+		restores = append(restores, t.snapshot())
+	}
+	ended := false
+	end := func(rollback bool) error {
+		if ended {
+			return nil
+		}
+		ended = true
+		if rollback {
+			for _, restore := range restores {
+				restore()
+			}
+		}
+		s.txLock.Unlock()
+		return nil
+	}
+	return crud.NewMemoryTx(func() error { return end(false) }, func() error { return end(true) })
+}
+
+type snapshotter interface {
+	snapshot() func()
+}
+
+////////////////////////////////////////////////TABLE//////////////////////////////////////////////////////////////////////////////////////
+
+// table is map of rows by id that keeps insertion order, rows are copied on every read and write
+// so callers can't change stored rows through returned values
+type table[T any] struct {
+	mutex sync.RWMutex
+	rows  map[string]T
+	order []string
+	seq   int64
+}
+
+func newTable[T any]() *table[T] {
+	return &table[T]{
+		rows: map[string]T{},
+	}
+}
+
+// insert stores row under id, it returns false if row with id already exists
+func (t *table[T]) insert(id string, row T) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.rows[id]; ok {
+		return false
+	}
+	t.rows[id] = clone(row)
+	t.order = append(t.order, id)
+	return true
+}
+
+// insertNext stores row under next value of table sequence, like columns with nextval default
+func (t *table[T]) insertNext(row T, setId func(*T, string)) T {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.seq++
+	id := strconv.FormatInt(t.seq, 10)
+	setId(&row, id)
+	t.rows[id] = clone(row)
+	t.order = append(t.order, id)
+	return clone(row)
+}
+
+// snapshot copies rows of table and returns function that restores them, sequence isn't restored
+// so ids aren't reused, the same as with postgres sequences
+func (t *table[T]) snapshot() func() {
+	t.mutex.RLock()
+	rows := make(map[string]T, len(t.rows))
+	for id, row := range t.rows {
+		rows[id] = clone(row)
+	}
+	order := append([]string(nil), t.order...)
+	t.mutex.RUnlock()
+	return func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.rows, t.order = rows, order
+	}
+}
+
+func (t *table[T]) get(id string) (T, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	row, ok := t.rows[id]
+	return clone(row), ok
+}
+
+// find returns rows accepted by match in insertion order
+func (t *table[T]) find(match func(T) bool) []T {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	results := []T{}
+	for _, id := range t.order {
+		row := t.rows[id]
+		if match(row) {
+			results = append(results, clone(row))
+		}
+	}
+	return results
+}
+
+func (t *table[T]) exists(match func(T) bool) bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, row := range t.rows {
+		if match(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// update applies change to row with id and returns row before and after change
+func (t *table[T]) update(id string, change func(*T)) (T, T, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	row, ok := t.rows[id]
+	if !ok {
+		return row, row, false
+	}
+	old := clone(row)
+	change(&row)
+	t.rows[id] = clone(row)
+	return old, clone(row), true
+}
+
+// updateAll applies change to every row accepted by match
+func (t *table[T]) updateAll(match func(T) bool, change func(*T)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for id, row := range t.rows {
+		if match(row) {
+			change(&row)
+			t.rows[id] = clone(row)
+		}
+	}
+}
+
+// softDelete sets deleted fields of row that isn't deleted yet, it returns row before change
+// and false if row doesn't exist or is already deleted
+func softDelete[T any](t *table[T], id string, editInfo func(*T) *DR.EditInfoD, by *string) (T, bool) {
+	deleted := false
+	old, _, ok := t.update(id, func(row *T) {
+		ei := editInfo(row)
+		if ei.DeletedAt == nil {
+			ei.PopulateDeleteFields(by)
+			deleted = true
+		}
+	})
+	return old, ok && deleted
+}
+
+// clone deep copies row through json, the same way rows are copied when they are read from database
+func clone[T any](row T) T {
+	var c T
+	b, err := json.Marshal(row)
+	if err != nil {
+		return row
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return row
+	}
+	return c
+}
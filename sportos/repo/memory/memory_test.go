@@ -0,0 +1,281 @@
+package memory_test
+
+import (
+	L "backend/internal/logging"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestCreateAndSearch(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	for _, name := range []string{"b", "a", "c"} {
+		if _, err := repo.TeamCrud.Create(ctx, DR.Team{Name: name, Sport: "Football", Status: DR.TS_CREATED, Players: DR.StrArr{"owner", name}}, nil, nil); err != nil {
+			t.Fatalf("create team %s: %v", name, err)
+		}
+	}
+	if _, err := repo.TeamCrud.Create(ctx, DR.Team{Name: "a", Sport: "Football"}, nil, nil); err == nil {
+		t.Errorf("expected error for team with existing name")
+	}
+
+	limit := int64(2)
+	sp := DR.TeamSearchParams{Sports: []string{"Football"}}
+	sp.TeamSortParams.Name = &DR.SortColumn{Direction: -1}
+	sp.Limit = &limit
+	teams, err := repo.TeamCrud.Search(ctx, sp, nil)
+	if err != nil {
+		t.Fatalf("search teams: %v", err)
+	}
+	if len(teams) != 2 || teams[0].Name != "c" || teams[1].Name != "b" {
+		t.Errorf("expected teams c, b, got %+v", teams)
+	}
+
+	notIn := "b"
+	count, err := repo.TeamCrud.GetCount(ctx, DR.TeamSearchParams{UserNotInTeam: &notIn}, nil)
+	if err != nil {
+		t.Fatalf("count teams: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 teams without player b, got %d", count)
+	}
+}
+
+func TestMembershipIsExact(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	if _, err := repo.TeamCrud.Create(ctx, DR.Team{Name: "team", Sport: "Football", Players: DR.StrArr{"ana2", "bob"}}, nil, nil); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if _, err := repo.MatchCrud.Create(ctx, DR.Match{Sport: "Football", Players: DR.StrArr{"ana2"}}, nil, nil); err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+
+	player := "ana"
+	count, err := repo.TeamCrud.GetCount(ctx, DR.TeamSearchParams{UserNotInTeam: &player}, nil)
+	if err != nil || count != 1 {
+		t.Errorf("expected team without player ana, got %d, %v", count, err)
+	}
+	count, err = repo.MatchCrud.GetCount(ctx, DR.MatchSearchParams{PlayerId: &player}, nil)
+	if err != nil || count != 0 {
+		t.Errorf("expected no matches of player ana, got %d, %v", count, err)
+	}
+	player = "ana2"
+	count, err = repo.MatchCrud.GetCount(ctx, DR.MatchSearchParams{PlayerId: &player}, nil)
+	if err != nil || count != 1 {
+		t.Errorf("expected match of player ana2, got %d, %v", count, err)
+	}
+}
+
+func TestUpdateCreatesAudit(t *testing.T) {
+	repo := memory.InitRepo()
+	repo.AuditCrud.Start()
+	ctx := context.Background()
+
+	pl, err := repo.PlayerCrud.Create(ctx, DR.Player{Username: "player", Name: "Old"}, nil, nil)
+	if err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	name := "New"
+	if _, err := repo.PlayerCrud.Update(ctx, DR.PlayerUpdateParams{Id: pl.Username, Name: &name}, nil, nil); err != nil {
+		t.Fatalf("update player: %v", err)
+	}
+	if _, err := repo.PlayerCrud.Update(ctx, DR.PlayerUpdateParams{Id: "missing", Name: &name}, nil, nil); err == nil {
+		t.Errorf("expected error for update of missing player")
+	}
+
+	pl, err = repo.PlayerCrud.GetById(ctx, pl.Username, nil)
+	if err != nil {
+		t.Fatalf("get player: %v", err)
+	}
+	if pl.Name != name || pl.UpdatedAt == nil {
+		t.Errorf("expected updated player, got %+v", pl)
+	}
+
+	entity := pl.GetTableName()
+	audits, err := repo.AuditCrud.Search(ctx, DR.AuditSearchParams{Entity: &entity, EntityId: &pl.Username}, nil)
+	if err != nil {
+		t.Fatalf("search audits: %v", err)
+	}
+	if len(audits) != 2 || *audits[1].CrudAction != DR.AUDIT_UPDATE || audits[1].New["name"] != name {
+		t.Errorf("expected create and update audit, got %+v", audits)
+	}
+}
+
+func TestReturnedEntitiesAreCopies(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	ev, err := repo.EventCrud.Create(ctx, DR.Event{Name: "cup", Owner: "place", Sport: "Football"}, nil, nil)
+	if err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	teams := DR.Teams{{Name: "first", TeamId: "1"}}
+	if _, err := repo.EventCrud.Update(ctx, DR.EventUpdateParams{Id: ev.EventId, Teams: &teams}, nil, nil); err != nil {
+		t.Fatalf("update event: %v", err)
+	}
+	teams[0].Name = "changed"
+
+	ev, _ = repo.EventCrud.GetById(ctx, ev.EventId, nil)
+	ev.Teams[0].Name = "changed"
+	ev, _ = repo.EventCrud.GetById(ctx, ev.EventId, nil)
+	if ev.Teams[0].Name != "first" {
+		t.Errorf("stored event was changed through returned value: %+v", ev.Teams)
+	}
+}
+
+func TestRollbackRestoresTables(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	if _, err := repo.PlayerCrud.Create(ctx, DR.Player{Username: "kept", Name: "Old"}, nil, nil); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	tx, err := repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := repo.PlayerCrud.Create(ctx, DR.Player{Username: "rolled_back"}, tx, nil); err != nil {
+		t.Fatalf("create player in transaction: %v", err)
+	}
+	name := "New"
+	if _, err := repo.PlayerCrud.Update(ctx, DR.PlayerUpdateParams{Id: "kept", Name: &name}, tx, nil); err != nil {
+		t.Fatalf("update player in transaction: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if _, err := repo.PlayerCrud.GetById(ctx, "rolled_back", nil); err == nil {
+		t.Errorf("expected player created in transaction to be removed by rollback")
+	}
+	if pl, err := repo.PlayerCrud.GetById(ctx, "kept", nil); err != nil || pl.Name != "Old" {
+		t.Errorf("expected update to be undone by rollback, got %+v, %v", pl, err)
+	}
+
+	tx, err = repo.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := repo.PlayerCrud.Update(ctx, DR.PlayerUpdateParams{Id: "kept", Name: &name}, tx, nil); err != nil {
+		t.Fatalf("update player in transaction: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	// deferred rollback after commit does nothing
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("rollback after commit: %v", err)
+	}
+	if pl, err := repo.PlayerCrud.GetById(ctx, "kept", nil); err != nil || pl.Name != name {
+		t.Errorf("expected committed name %s, got %+v, %v", name, pl, err)
+	}
+}
+
+func TestVerificationTokenIsConsumedOnce(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	_, err := repo.VerificationTokenCrud.Create(ctx, DR.VerificationToken{
+		TokenHash:  "hash",
+		UserId:     "player",
+		Purpose:    DR.TP_VERIFY_EMAIL,
+		ValidUntil: time.Now().Add(time.Hour),
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if _, err := repo.VerificationTokenCrud.Consume(ctx, "hash", DR.TP_RESET_PASSWORD, nil); err == nil {
+		t.Errorf("expected error for token with other purpose")
+	}
+	vt, err := repo.VerificationTokenCrud.Consume(ctx, "hash", DR.TP_VERIFY_EMAIL, nil)
+	if err != nil || vt.UserId != "player" {
+		t.Fatalf("consume token: %+v, %v", vt, err)
+	}
+	if _, err := repo.VerificationTokenCrud.Consume(ctx, "hash", DR.TP_VERIFY_EMAIL, nil); err == nil {
+		t.Errorf("expected error for token that is already consumed")
+	}
+}
+
+func TestDeleteHidesRowFromSearch(t *testing.T) {
+	repo := memory.InitRepo()
+	repo.AuditCrud.Start()
+	ctx := context.Background()
+
+	post, err := repo.UserPostsCrud.Create(ctx, DR.UserPost{UserId: "player", UserText: "text"}, nil, nil)
+	if err != nil {
+		t.Fatalf("create post: %v", err)
+	}
+	if err := repo.UserPostsCrud.Delete(ctx, post.PostId, nil, nil); err != nil {
+		t.Fatalf("delete post: %v", err)
+	}
+	if err := repo.UserPostsCrud.Delete(ctx, post.PostId, nil, nil); err == nil {
+		t.Errorf("expected error for post that is already deleted")
+	}
+
+	posts, err := repo.UserPostsCrud.Search(ctx, DR.UserPostSearchParams{}, nil)
+	if err != nil || len(posts) != 0 {
+		t.Errorf("expected no posts, got %+v, %v", posts, err)
+	}
+	sp := DR.UserPostSearchParams{}
+	sp.IncludeDeleted = true
+	posts, err = repo.UserPostsCrud.Search(ctx, sp, nil)
+	if err != nil || len(posts) != 1 || !posts[0].IsDeleted() {
+		t.Errorf("expected deleted post, got %+v, %v", posts, err)
+	}
+
+	entity := post.GetTableName()
+	audits, err := repo.AuditCrud.Search(ctx, DR.AuditSearchParams{Entity: &entity, EntityId: &post.PostId}, nil)
+	if err != nil {
+		t.Fatalf("search audits: %v", err)
+	}
+	if len(audits) != 2 || *audits[1].CrudAction != DR.AUDIT_DELETE {
+		t.Errorf("expected create and delete audit, got %+v", audits)
+	}
+}
+
+func TestPlayerMatchSummaryStreaksAndMonths(t *testing.T) {
+	repo := memory.InitRepo()
+	ctx := context.Background()
+
+	start := time.Date(2026, time.March, 30, 18, 0, 0, 0, time.UTC)
+	// oldest first: two wins, loss, three wins, draw; last match is in April
+	outcomes := []DR.MatchOutcome{DR.MO_WIN, DR.MO_WIN, DR.MO_LOSS, DR.MO_WIN, DR.MO_WIN, DR.MO_WIN, DR.MO_DRAW}
+	for i, outcome := range outcomes {
+		pm := DR.PlayerMatch{MatchId: string(rune('1' + i)), PlayerId: "p", Sport: "Tennis", PlayedAt: start.Add(time.Duration(i) * time.Hour * 8), Outcome: outcome}
+		if err := repo.PlayerMatchCrud.Create(ctx, pm, nil, nil); err != nil {
+			t.Fatalf("create player match: %v", err)
+		}
+		// repeated result isn't counted twice
+		if err := repo.PlayerMatchCrud.Create(ctx, pm, nil, nil); err != nil {
+			t.Fatalf("create player match again: %v", err)
+		}
+	}
+
+	s, err := repo.PlayerMatchCrud.Summary(ctx, "p", "Tennis", nil)
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	expected := DR.PlayerMatchSummary{Matches: 7, Wins: 5, Draws: 1, Losses: 1, CurrentStreak: 1, StreakOutcome: DR.MO_DRAW, LongestWinStreak: 3}
+	if s != expected {
+		t.Errorf("expected summary %+v, got %+v", expected, s)
+	}
+
+	months, err := repo.PlayerMatchCrud.Monthly(ctx, "p", "Tennis", nil)
+	if err != nil {
+		t.Fatalf("monthly: %v", err)
+	}
+	if len(months) != 2 || months[0].Month.Month() != time.April || months[1].Month.Month() != time.March ||
+		months[0].Matches+months[1].Matches != 7 {
+		t.Errorf("expected April and March, got %+v", months)
+	}
+}
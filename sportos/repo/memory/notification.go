@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type notificationStore struct {
+	s *store
+}
+
+func (r *notificationStore) Create(ctx context.Context, en DR.Notification, qa crud.QueryAble, by *string) (DR.Notification, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	en.ReadAt = nil
+	return r.s.notifications.insertNext(en, func(nt *DR.Notification, id string) {
+		nt.NotificationId = id
+	}), nil
+}
+
+func (r *notificationStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Notification, error) {
+	nt, ok := r.s.notifications.get(id)
+	if !ok {
+		return DR.Notification{}, fmt.Errorf("notification does not exist for id: %v", id)
+	}
+	return nt, nil
+}
+
+func (r *notificationStore) GetCount(ctx context.Context, sp DR.NotificationSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns notifications newest first, notifications created at same time are in reverse insertion order
+func (r *notificationStore) Search(ctx context.Context, sp DR.NotificationSearchParams, qa crud.QueryAble) ([]DR.Notification, error) {
+	notifications := r.find(sp)
+	for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+		notifications[i], notifications[j] = notifications[j], notifications[i]
+	}
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	return page(notifications, sp.PagingSearchParams), nil
+}
+
+func (r *notificationStore) find(sp DR.NotificationSearchParams) []DR.Notification {
+	return r.s.notifications.find(func(nt DR.Notification) bool {
+		return matches(sp.UserId, nt.UserId) &&
+			(sp.Unread == nil || !*sp.Unread || nt.ReadAt == nil) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, nt.EditInfoC) &&
+			matchesEditInfoU(sp.EditInfoUSearchParams, nt.EditInfoU)
+	})
+}
+
+func (r *notificationStore) Update(ctx context.Context, up DR.NotificationUpdateParams, qa crud.QueryAble, by *string) (DR.Notification, error) {
+	up.PopulateUpdateFields(by)
+
+	_, pen, ok := r.s.notifications.update(up.Id, func(nt *DR.Notification) {
+		if up.ReadAt != nil {
+			nt.ReadAt = up.ReadAt
+		}
+		applyEditInfoU(&nt.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return DR.Notification{}, fmt.Errorf("no rows affected")
+	}
+	return pen, nil
+}
+
+func (r *notificationStore) MarkAllRead(ctx context.Context, userId string, qa crud.QueryAble, by *string) (int, error) {
+	ei := DR.EditInfoU{}
+	ei.PopulateUpdateFields(by)
+	marked := 0
+	r.s.notifications.updateAll(func(nt DR.Notification) bool {
+		return nt.UserId == userId && nt.ReadAt == nil
+	}, func(nt *DR.Notification) {
+		nt.ReadAt = ei.UpdatedAt
+		applyEditInfoU(&nt.EditInfoU, ei)
+		marked++
+	})
+	return marked, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type outboxEventStore struct {
+	s *store
+}
+
+func (r *outboxEventStore) Create(ctx context.Context, en DR.OutboxEvent, qa crud.QueryAble, by *string) (DR.OutboxEvent, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.OES_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+	en.Payload = append(DR.OutboxPayload{}, en.Payload...)
+	return r.s.outboxEvents.insertNext(en, func(oe *DR.OutboxEvent, id string) {
+		oe.OutboxEventId = id
+	}), nil
+}
+
+func (r *outboxEventStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.OutboxEvent, error) {
+	oe, ok := r.s.outboxEvents.get(id)
+	if !ok {
+		return DR.OutboxEvent{}, fmt.Errorf("outbox event does not exist for id: %v", id)
+	}
+	return oe, nil
+}
+
+// GetPending returns pending domain events that are due for dispatching in order in which they were created
+func (r *outboxEventStore) GetPending(ctx context.Context, limit int, qa crud.QueryAble) ([]DR.OutboxEvent, error) {
+	now := time.Now().UTC()
+	events := r.s.outboxEvents.find(func(oe DR.OutboxEvent) bool {
+		return oe.Status == DR.OES_PENDING && !oe.NextAttemptAt.After(now)
+	})
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].NextAttemptAt.Equal(events[j].NextAttemptAt) {
+			return events[i].NextAttemptAt.Before(events[j].NextAttemptAt)
+		}
+		return idLess(events[i].OutboxEventId, events[j].OutboxEventId)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *outboxEventStore) Update(ctx context.Context, up DR.OutboxEventUpdateParams, qa crud.QueryAble, by *string) error {
+	up.PopulateUpdateFields(by)
+
+	_, _, ok := r.s.outboxEvents.update(up.Id, func(oe *DR.OutboxEvent) {
+		if up.Status != nil {
+			oe.Status = *up.Status
+		}
+		if up.Attempts != nil {
+			oe.Attempts = *up.Attempts
+		}
+		if up.NextAttemptAt != nil {
+			oe.NextAttemptAt = *up.NextAttemptAt
+		}
+		if up.LastError != nil {
+			oe.LastError = up.LastError
+		}
+		if up.DispatchedAt != nil {
+			oe.DispatchedAt = up.DispatchedAt
+		}
+		applyEditInfoU(&oe.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
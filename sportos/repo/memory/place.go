@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type placeStore struct {
+	s *store
+}
+
+func (r *placeStore) Create(ctx context.Context, en DR.Place, qa crud.QueryAble, by *string) (DR.Place, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.Place{
+		Username: en.Username,
+		Name:     en.Name,
+		City:     en.City,
+		Sport:    en.Sport,
+		TimeZone: en.TimeZone,
+	}
+	if row.TimeZone == "" {
+		row.TimeZone = DR.DEFAULT_TIME_ZONE
+	}
+	row.EditInfoC = en.EditInfoC
+	if !r.s.places.insert(en.Username, row) {
+		return en, fmt.Errorf("user with username %s already exists", en.Username)
+	}
+	pen, err := r.GetById(ctx, en.Username, qa)
+	if err != nil {
+		return pen, err
+	}
+	_, err = r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *placeStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Place, error) {
+	en, ok := r.s.places.get(id)
+	if !ok {
+		return DR.Place{}, fmt.Errorf("place does not exist for username: %v", id)
+	}
+	return en, nil
+}
+
+// GetByEmail returns place of user with email
+func (r *placeStore) GetByEmail(ctx context.Context, email string, qa crud.QueryAble) (DR.Place, error) {
+	if usr, ok := r.s.userByEmail(email); ok {
+		if en, ok := r.s.places.get(usr.Username); ok {
+			return en, nil
+		}
+	}
+	return DR.Place{}, fmt.Errorf("place does not exist for email: %v", email)
+}
+
+func (r *placeStore) GetCount(ctx context.Context, sp DR.PlaceSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *placeStore) Search(ctx context.Context, sp DR.PlaceSearchParams, qa crud.QueryAble) ([]DR.Place, error) {
+	rows := r.find(sp)
+	sortRows(rows, sp.PlaceSortParams.SortColumns())
+	return page(rows, sp.PagingSearchParams), nil
+}
+
+func (r *placeStore) find(sp DR.PlaceSearchParams) []DR.Place {
+	rows := r.s.places.find(func(en DR.Place) bool {
+		return matchesNonEmpty(sp.Username, en.Username) &&
+			matchesNonEmpty(sp.Name, en.Name) &&
+			matchesNonEmpty(sp.City, en.City) &&
+			matchesNonEmpty(sp.Sport, en.Sport) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, en.EditInfoCUD)
+	})
+	return filter(rows, func(en DR.Place) bool {
+		return r.s.joinsUser(sp.UserSearchParams, en.Username)
+	})
+}
+
+func (r *placeStore) Update(ctx context.Context, up DR.PlaceUpdateParams, qa crud.QueryAble, by *string) (DR.Place, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.places.update(up.Id, func(en *DR.Place) {
+		if up.Name != nil {
+			en.Name = *up.Name
+		}
+		if up.City != nil {
+			en.City = *up.City
+		}
+		if up.Reviews != nil {
+			en.Reviews = up.Reviews
+		}
+		if up.Schedule != nil {
+			en.Schedule = up.Schedule
+		}
+		if up.TimeZone != nil {
+			en.TimeZone = *up.TimeZone
+		}
+		applyEditInfoUD(&en.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Place{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *placeStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.places, id, func(pla *DR.Place) *DR.EditInfoD {
+		return &pla.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type playerStore struct {
+	s *store
+}
+
+func (r *playerStore) Create(ctx context.Context, en DR.Player, qa crud.QueryAble, by *string) (DR.Player, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.Player{
+		Username: en.Username,
+		Name:     en.Name,
+		City:     en.City,
+	}
+	row.EditInfoC = en.EditInfoC
+	if !r.s.players.insert(en.Username, row) {
+		return en, fmt.Errorf("user with username %s already exists", en.Username)
+	}
+	pen, err := r.GetById(ctx, en.Username, qa)
+	if err != nil {
+		return pen, err
+	}
+	_, err = r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *playerStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Player, error) {
+	pl, ok := r.s.players.get(id)
+	if !ok {
+		return DR.Player{}, fmt.Errorf("player does not exist for username: %v", id)
+	}
+	return pl, nil
+}
+
+// GetByEmail returns player of user with email
+func (r *playerStore) GetByEmail(ctx context.Context, email string, qa crud.QueryAble) (DR.Player, error) {
+	if usr, ok := r.s.userByEmail(email); ok {
+		if pl, ok := r.s.players.get(usr.Username); ok {
+			return pl, nil
+		}
+	}
+	return DR.Player{}, fmt.Errorf("player does not exist for email: %v", email)
+}
+
+func (r *playerStore) GetCount(ctx context.Context, sp DR.PlayerSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *playerStore) Search(ctx context.Context, sp DR.PlayerSearchParams, qa crud.QueryAble) ([]DR.Player, error) {
+	players := r.find(sp)
+	sortRows(players, sp.PlayerSortParams.SortColumns())
+	return page(players, sp.PagingSearchParams), nil
+}
+
+func (r *playerStore) find(sp DR.PlayerSearchParams) []DR.Player {
+	players := r.s.players.find(func(pl DR.Player) bool {
+		return matchesNonEmpty(sp.Username, pl.Username) &&
+			matchesNonEmpty(sp.Name, pl.Name) &&
+			matchesNonEmpty(sp.City, pl.City) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, pl.EditInfoCUD)
+	})
+	return filter(players, func(pl DR.Player) bool {
+		return r.s.joinsUser(sp.UserSearchParams, pl.Username)
+	})
+}
+
+func (r *playerStore) Update(ctx context.Context, up DR.PlayerUpdateParams, qa crud.QueryAble, by *string) (DR.Player, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.players.update(up.Id, func(pl *DR.Player) {
+		if up.Name != nil {
+			pl.Name = *up.Name
+		}
+		if up.City != nil {
+			pl.City = *up.City
+		}
+		if up.Preferences != nil {
+			pl.Preferences = up.Preferences
+		}
+		applyEditInfoUD(&pl.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Player{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *playerStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.players, id, func(pl *DR.Player) *DR.EditInfoD {
+		return &pl.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
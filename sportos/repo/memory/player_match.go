@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type playerMatchStore struct {
+	s *store
+}
+
+// Create mirrors on conflict do nothing of postgres crud
+func (r *playerMatchStore) Create(ctx context.Context, en DR.PlayerMatch, qa crud.QueryAble, by *string) error {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	en.MyTeam, en.OppTeam = nil, nil
+	r.s.playerMatches.insert(en.MatchId+"|"+en.PlayerId, en)
+	return nil
+}
+
+func (r *playerMatchStore) GetCount(ctx context.Context, sp DR.PlayerMatchSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns results of matches from newest with names of players of both teams like postgres crud
+func (r *playerMatchStore) Search(ctx context.Context, sp DR.PlayerMatchSearchParams, qa crud.QueryAble) ([]DR.PlayerMatch, error) {
+	results := page(r.find(sp), sp.PagingSearchParams)
+	for i := range results {
+		pm := &results[i]
+		for _, mp := range r.s.roster(pm.MatchId) {
+			if mp.Side == nil {
+				continue
+			}
+			name := r.s.playerName(mp.PlayerId)
+			if *mp.Side == pm.Side {
+				pm.MyTeam = append(pm.MyTeam, name)
+			} else {
+				pm.OppTeam = append(pm.OppTeam, name)
+			}
+		}
+	}
+	return results, nil
+}
+
+// find returns matches from newest
+func (r *playerMatchStore) find(sp DR.PlayerMatchSearchParams) []DR.PlayerMatch {
+	results := r.s.playerMatches.find(func(pm DR.PlayerMatch) bool {
+		return matches(sp.PlayerId, pm.PlayerId) &&
+			matches(sp.Sport, pm.Sport) &&
+			matches(sp.MatchId, pm.MatchId) &&
+			(sp.From == nil || !pm.PlayedAt.Before(*sp.From)) &&
+			(sp.To == nil || pm.PlayedAt.Before(*sp.To))
+	})
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].PlayedAt.Equal(results[j].PlayedAt) {
+			return results[i].PlayedAt.After(results[j].PlayedAt)
+		}
+		return idLess(results[j].MatchId, results[i].MatchId)
+	})
+	return results
+}
+
+func (r *playerMatchStore) Summary(ctx context.Context, playerId, sport string, qa crud.QueryAble) (DR.PlayerMatchSummary, error) {
+	s := DR.PlayerMatchSummary{}
+	streak := 0
+	// matches are from newest, first streak is current
+	for i, pm := range r.find(DR.PlayerMatchSearchParams{PlayerId: &playerId, Sport: &sport}) {
+		s.Matches++
+		switch pm.Outcome {
+		case DR.MO_WIN:
+			s.Wins++
+		case DR.MO_DRAW:
+			s.Draws++
+		case DR.MO_LOSS:
+			s.Losses++
+		}
+		if i == 0 {
+			s.StreakOutcome = pm.Outcome
+		}
+		if i == s.CurrentStreak && pm.Outcome == s.StreakOutcome {
+			s.CurrentStreak++
+		}
+		if pm.Outcome == DR.MO_WIN {
+			streak++
+			if streak > s.LongestWinStreak {
+				s.LongestWinStreak = streak
+			}
+		} else {
+			streak = 0
+		}
+	}
+	return s, nil
+}
+
+func (r *playerMatchStore) Monthly(ctx context.Context, playerId, sport string, qa crud.QueryAble) ([]DR.PlayerMatchMonth, error) {
+	results := []DR.PlayerMatchMonth{}
+	for _, pm := range r.find(DR.PlayerMatchSearchParams{PlayerId: &playerId, Sport: &sport}) {
+		played := pm.PlayedAt.UTC()
+		month := time.Date(played.Year(), played.Month(), 1, 0, 0, 0, 0, time.UTC)
+		// matches are from newest so months are too
+		if len(results) == 0 || !results[len(results)-1].Month.Equal(month) {
+			results = append(results, DR.PlayerMatchMonth{Month: month})
+		}
+		m := &results[len(results)-1]
+		m.Matches++
+		switch pm.Outcome {
+		case DR.MO_WIN:
+			m.Wins++
+		case DR.MO_DRAW:
+			m.Draws++
+		case DR.MO_LOSS:
+			m.Losses++
+		}
+	}
+	return results, nil
+}
+
+// Group mirrors grouping of postgres crud, weekday and hour are on wall clock of place of match
+func (r *playerMatchStore) Group(ctx context.Context, sp DR.PlayerMatchSearchParams, by DR.PlayerMatchGroupBy, qa crud.QueryAble) ([]DR.PlayerMatchGroup, error) {
+	if !by.IsValid() {
+		return nil, fmt.Errorf("matches can't be grouped by %s", by)
+	}
+	groups := map[string]*DR.PlayerMatchGroup{}
+	add := func(key, name string, outcome DR.MatchOutcome) {
+		g, ok := groups[key]
+		if !ok {
+			g = &DR.PlayerMatchGroup{Key: key, Name: name}
+			groups[key] = g
+		}
+		g.Matches++
+		switch outcome {
+		case DR.MO_WIN:
+			g.Wins++
+		case DR.MO_DRAW:
+			g.Draws++
+		case DR.MO_LOSS:
+			g.Losses++
+		}
+	}
+	for _, pm := range r.find(sp) {
+		switch by {
+		case DR.PMG_OPPONENT, DR.PMG_TEAMMATE:
+			for _, mp := range r.s.roster(pm.MatchId) {
+				if mp.Side == nil {
+					continue
+				}
+				opponent := *mp.Side != pm.Side
+				teammate := *mp.Side == pm.Side && mp.PlayerId != pm.PlayerId
+				if (by == DR.PMG_OPPONENT && opponent) || (by == DR.PMG_TEAMMATE && teammate) {
+					add(mp.PlayerId, r.s.playerName(mp.PlayerId), pm.Outcome)
+				}
+			}
+		default:
+			match, ok := r.s.matches.get(pm.MatchId)
+			if !ok {
+				continue
+			}
+			place, ok := r.s.places.get(match.PlaceId)
+			if !ok {
+				continue
+			}
+			if by == DR.PMG_PLACE {
+				add(place.Username, place.Name, pm.Outcome)
+				continue
+			}
+			loc, err := time.LoadLocation(place.TimeZone)
+			if err != nil {
+				return nil, err
+			}
+			played := pm.PlayedAt.In(loc)
+			if by == DR.PMG_WEEKDAY {
+				add(strconv.Itoa(int(played.Weekday())), "", pm.Outcome)
+			} else {
+				add(strconv.Itoa(played.Hour()), "", pm.Outcome)
+			}
+		}
+	}
+	results := []DR.PlayerMatchGroup{}
+	for _, g := range groups {
+		results = append(results, *g)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Matches != results[j].Matches {
+			return results[i].Matches > results[j].Matches
+		}
+		return results[i].Key < results[j].Key
+	})
+	return results, nil
+}
+
+type playerTournamentStore struct {
+	s *store
+}
+
+// Create mirrors on conflict do nothing of postgres crud
+func (r *playerTournamentStore) Create(ctx context.Context, en DR.PlayerTournament, qa crud.QueryAble, by *string) error {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	en.Tournament, en.MyTeam = "", nil
+	r.s.playerTournaments.insert(en.EventId+"|"+en.PlayerId, en)
+	return nil
+}
+
+func (r *playerTournamentStore) GetCount(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns tournament rankings from last finished with name of event and members of team like postgres crud
+func (r *playerTournamentStore) Search(ctx context.Context, sp DR.PlayerTournamentSearchParams, qa crud.QueryAble) ([]DR.PlayerTournament, error) {
+	results := page(r.find(sp), sp.PagingSearchParams)
+	for i := range results {
+		pt := &results[i]
+		for _, tm := range r.s.members(pt.TeamId) {
+			pt.MyTeam = append(pt.MyTeam, r.s.playerName(tm.PlayerId))
+		}
+	}
+	return results, nil
+}
+
+// find returns rankings of existing events from last finished
+func (r *playerTournamentStore) find(sp DR.PlayerTournamentSearchParams) []DR.PlayerTournament {
+	rows := r.s.playerTournaments.find(func(pt DR.PlayerTournament) bool {
+		return matches(sp.PlayerId, pt.PlayerId) &&
+			matches(sp.Sport, pt.Sport) &&
+			matches(sp.EventId, pt.EventId)
+	})
+	results := []DR.PlayerTournament{}
+	for _, pt := range rows {
+		event, ok := r.s.events.get(pt.EventId)
+		if !ok {
+			continue
+		}
+		pt.Tournament = event.Name
+		results = append(results, pt)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].CreatedAt.Equal(results[j].CreatedAt) {
+			return results[i].CreatedAt.After(results[j].CreatedAt)
+		}
+		return idLess(results[j].EventId, results[i].EventId)
+	})
+	return results
+}
+
+// playerName returns name of player or id if player doesn't exist, like coalesce in postgres cruds
+func (s *store) playerName(id string) string {
+	if player, ok := s.players.get(id); ok {
+		return player.Name
+	}
+	return id
+}
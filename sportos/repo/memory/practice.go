@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type practiceStore struct {
+	s *store
+}
+
+func (r *practiceStore) Create(ctx context.Context, en DR.Practice, qa crud.QueryAble, by *string) (DR.Practice, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.Practice{
+		PlayerId:  en.PlayerId,
+		CoachId:   en.CoachId,
+		StartTime: en.StartTime,
+		Status:    en.Status,
+		Sport:     en.Sport,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.practices.insertNext(row, func(pr *DR.Practice, id string) {
+		pr.PracticeId = id
+	})
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *practiceStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Practice, error) {
+	pr, ok := r.s.practices.get(id)
+	if !ok {
+		return DR.Practice{}, fmt.Errorf("practice does not exist for username: %v", id)
+	}
+	return pr, nil
+}
+
+func (r *practiceStore) GetCount(ctx context.Context, sp DR.PracticeSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *practiceStore) Search(ctx context.Context, sp DR.PracticeSearchParams, qa crud.QueryAble) ([]DR.Practice, error) {
+	practices := r.find(sp)
+	sortRows(practices, sp.PracticeSortParams.SortColumns())
+	return page(practices, sp.PagingSearchParams), nil
+}
+
+func (r *practiceStore) find(sp DR.PracticeSearchParams) []DR.Practice {
+	return r.s.practices.find(func(pr DR.Practice) bool {
+		return matchesNonEmpty(sp.Status, string(pr.Status)) &&
+			matches(sp.PlayerId, pr.PlayerId) &&
+			matches(sp.CoachId, pr.CoachId) &&
+			matchesAny(sp.Sports, pr.Sport) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, pr.EditInfoCUD)
+	})
+}
+
+func (r *practiceStore) Update(ctx context.Context, up DR.PracticeUpdateParams, qa crud.QueryAble, by *string) (DR.Practice, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.practices.update(up.Id, func(pr *DR.Practice) {
+		if up.Status != nil {
+			pr.Status = *up.Status
+		}
+		applyEditInfoUD(&pr.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Practice{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *practiceStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.practices, id, func(pr *DR.Practice) *DR.EditInfoD {
+		return &pr.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
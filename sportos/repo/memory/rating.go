@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type ratingStore struct {
+	s *store
+}
+
+func (r *ratingStore) GetOrCreate(ctx context.Context, en DR.Rating, qa crud.QueryAble, by *string) (DR.Rating, error) {
+	r.s.ratingLock.Lock()
+	defer r.s.ratingLock.Unlock()
+
+	existing := r.s.ratings.find(func(ra DR.Rating) bool {
+		return ra.PlayerId == en.PlayerId && ra.Sport == en.Sport
+	})
+	if len(existing) != 0 {
+		return existing[0], nil
+	}
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	return r.s.ratings.insertNext(en, func(ra *DR.Rating, id string) {
+		ra.RatingId = id
+	}), nil
+}
+
+func (r *ratingStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Rating, error) {
+	ra, ok := r.s.ratings.get(id)
+	if !ok {
+		return DR.Rating{}, fmt.Errorf("rating does not exist for id: %v", id)
+	}
+	return ra, nil
+}
+
+func (r *ratingStore) GetCount(ctx context.Context, sp DR.RatingSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns ratings from best to worst like postgres crud
+func (r *ratingStore) Search(ctx context.Context, sp DR.RatingSearchParams, qa crud.QueryAble) ([]DR.Rating, error) {
+	ratings := r.find(sp)
+	sort.SliceStable(ratings, func(i, j int) bool {
+		if ratings[i].Rating != ratings[j].Rating {
+			return ratings[i].Rating > ratings[j].Rating
+		}
+		if ratings[i].Matches != ratings[j].Matches {
+			return ratings[i].Matches > ratings[j].Matches
+		}
+		return ratings[i].PlayerId < ratings[j].PlayerId
+	})
+	return page(ratings, sp.PagingSearchParams), nil
+}
+
+func (r *ratingStore) find(sp DR.RatingSearchParams) []DR.Rating {
+	return r.s.ratings.find(func(ra DR.Rating) bool {
+		return matches(sp.PlayerId, ra.PlayerId) &&
+			matches(sp.Sport, ra.Sport) &&
+			(sp.MinMatches == nil || ra.Matches >= *sp.MinMatches) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, ra.EditInfoC) &&
+			matchesEditInfoU(sp.EditInfoUSearchParams, ra.EditInfoU)
+	})
+}
+
+func (r *ratingStore) Update(ctx context.Context, up DR.RatingUpdateParams, qa crud.QueryAble, by *string) (DR.Rating, error) {
+	up.PopulateUpdateFields(by)
+
+	_, pen, ok := r.s.ratings.update(up.Id, func(ra *DR.Rating) {
+		if up.Rating != nil {
+			ra.Rating = *up.Rating
+		}
+		if up.Deviation != nil {
+			ra.Deviation = *up.Deviation
+		}
+		if up.Volatility != nil {
+			ra.Volatility = *up.Volatility
+		}
+		if up.Matches != nil {
+			ra.Matches = *up.Matches
+		}
+		applyEditInfoU(&ra.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return DR.Rating{}, fmt.Errorf("no rows affected")
+	}
+	return pen, nil
+}
+
+type ratingHistoryStore struct {
+	s *store
+}
+
+// Create mirrors on conflict do nothing of unique player and match of rating history
+func (r *ratingHistoryStore) Create(ctx context.Context, en DR.RatingHistory, qa crud.QueryAble, by *string) (DR.RatingHistory, bool, error) {
+	r.s.ratingLock.Lock()
+	defer r.s.ratingLock.Unlock()
+
+	if r.s.ratingHistory.exists(func(rh DR.RatingHistory) bool {
+		return rh.PlayerId == en.PlayerId && rh.MatchId == en.MatchId
+	}) {
+		return en, false, nil
+	}
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	return r.s.ratingHistory.insertNext(en, func(rh *DR.RatingHistory, id string) {
+		rh.HistoryId = id
+	}), true, nil
+}
+
+func (r *ratingHistoryStore) GetCount(ctx context.Context, sp DR.RatingHistorySearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns rating history newest first
+func (r *ratingHistoryStore) Search(ctx context.Context, sp DR.RatingHistorySearchParams, qa crud.QueryAble) ([]DR.RatingHistory, error) {
+	history := r.find(sp)
+	sort.SliceStable(history, func(i, j int) bool {
+		if !history[i].CreatedAt.Equal(history[j].CreatedAt) {
+			return history[i].CreatedAt.After(history[j].CreatedAt)
+		}
+		return idLess(history[j].HistoryId, history[i].HistoryId)
+	})
+	return page(history, sp.PagingSearchParams), nil
+}
+
+func (r *ratingHistoryStore) find(sp DR.RatingHistorySearchParams) []DR.RatingHistory {
+	return r.s.ratingHistory.find(func(rh DR.RatingHistory) bool {
+		return matches(sp.PlayerId, rh.PlayerId) &&
+			matches(sp.Sport, rh.Sport) &&
+			matches(sp.MatchId, rh.MatchId) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, rh.EditInfoC)
+	})
+}
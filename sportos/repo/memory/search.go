@@ -0,0 +1,205 @@
+package memory
+
+import (
+	DR "backend/sportos/repo/dto"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// filters follow conditions that search params append to sql queries
+
+// matches is condition that is applied whenever parameter is set
+func matches(sp *string, v string) bool {
+	return sp == nil || *sp == v
+}
+
+// matchesNonEmpty is condition that is applied only for non empty parameter
+func matchesNonEmpty(sp *string, v string) bool {
+	return sp == nil || *sp == "" || *sp == v
+}
+
+// matchesAny is condition column=any(sps)
+func matchesAny(sps []string, v string) bool {
+	if len(sps) == 0 {
+		return true
+	}
+	for _, s := range sps {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTimeRange is condition from<=t and t<before, null column never matches
+func matchesTimeRange(from, before *time.Time, t *time.Time) bool {
+	if from != nil && !from.IsZero() && (t == nil || t.Before(*from)) {
+		return false
+	}
+	if before != nil && !before.IsZero() && (t == nil || !t.Before(*before)) {
+		return false
+	}
+	return true
+}
+
+func matchesEditInfoC(sp DR.EditInfoCSearchParams, ei DR.EditInfoC) bool {
+	return matchesTimeRange(sp.CreatedAtFrom, sp.CreatedAtBefore, &ei.CreatedAt) && matchesNonEmpty(sp.CreatedBy, ei.CreatedBy)
+}
+
+func matchesEditInfoU(sp DR.EditInfoUSearchParams, ei DR.EditInfoU) bool {
+	if !matchesTimeRange(sp.UpdatedAtFrom, sp.UpdatedAtBefore, ei.UpdatedAt) {
+		return false
+	}
+	if sp.UpdatedBy != nil && *sp.UpdatedBy != "" {
+		return ei.UpdatedBy != nil && *ei.UpdatedBy == *sp.UpdatedBy
+	}
+	return true
+}
+
+func matchesEditInfoD(sp DR.EditInfoDSearchParams, ei DR.EditInfoD) bool {
+	if !sp.IncludeDeleted && !sp.OnlyDeleted() && ei.DeletedAt != nil {
+		return false
+	}
+	if !matchesTimeRange(sp.DeletedAtFrom, sp.DeletedAtBefore, ei.DeletedAt) {
+		return false
+	}
+	if sp.DeletedBy != nil && *sp.DeletedBy != "" {
+		return ei.DeletedBy != nil && *ei.DeletedBy == *sp.DeletedBy
+	}
+	return true
+}
+
+func matchesEditInfoCUD(sp DR.EditInfoCUDSearchParams, ei DR.EditInfoCUD) bool {
+	return matchesEditInfoC(sp.EditInfoCSearchParams, ei.EditInfoC) &&
+		matchesEditInfoU(sp.EditInfoUSearchParams, ei.EditInfoU) &&
+		matchesEditInfoD(sp.EditInfoDSearchParams, ei.EditInfoD)
+}
+
+// sortRows sorts rows by columns of sort params, column is found by column or json tag of row field.
+// Null values are last in ascending order like in postgres
+func sortRows[T any](rows []T, scs DR.SortColumns) {
+	if len(scs) == 0 {
+		return
+	}
+	sort.Sort(scs)
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, sc := range scs {
+			c := compareValues(columnValue(reflect.ValueOf(rows[i]), sc.Column), columnValue(reflect.ValueOf(rows[j]), sc.Column))
+			if c == 0 {
+				continue
+			}
+			if sc.Direction < 0 {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// page applies limit and offset
+func page[T any](rows []T, sp DR.PagingSearchParams) []T {
+	if sp.Offset != nil && *sp.Offset >= 0 {
+		if *sp.Offset >= int64(len(rows)) {
+			return []T{}
+		}
+		rows = rows[*sp.Offset:]
+	}
+	if sp.Limit != nil && *sp.Limit >= 0 && *sp.Limit < int64(len(rows)) {
+		rows = rows[:*sp.Limit]
+	}
+	return rows
+}
+
+func columnValue(v reflect.Value, column string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			continue
+		}
+		if f.Tag.Get("column") == column || strings.Split(f.Tag.Get("json"), ",")[0] == column {
+			return v.Field(i)
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if fv := columnValue(v.Field(i), column); fv.IsValid() {
+				return fv
+			}
+		}
+	}
+	return reflect.Value{}
+}
+
+func compareValues(a, b reflect.Value) int {
+	if !a.IsValid() || !b.IsValid() {
+		return 0
+	}
+	if a.Kind() == reflect.Ptr {
+		switch {
+		case a.IsNil() && b.IsNil():
+			return 0
+		case a.IsNil():
+			return 1
+		case b.IsNil():
+			return -1
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if at, ok := a.Interface().(time.Time); ok {
+		bt := b.Interface().(time.Time)
+		switch {
+		case at.Before(bt):
+			return -1
+		case at.After(bt):
+			return 1
+		}
+		return 0
+	}
+	switch a.Kind() {
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch {
+		case a.Int() < b.Int():
+			return -1
+		case a.Int() > b.Int():
+			return 1
+		}
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case a.Float() < b.Float():
+			return -1
+		case a.Float() > b.Float():
+			return 1
+		}
+	}
+	return 0
+}
+
+// filter returns rows accepted by match, it is used for conditions on joined tables
+func filter[T any](rows []T, match func(T) bool) []T {
+	results := []T{}
+	for _, row := range rows {
+		if match(row) {
+			results = append(results, row)
+		}
+	}
+	return results
+}
+
+func applyEditInfoU(ei *DR.EditInfoU, up DR.EditInfoU) {
+	ei.UpdatedAt = up.UpdatedAt
+	ei.UpdatedBy = up.UpdatedBy
+}
+
+func applyEditInfoUD(ei *DR.EditInfoCUD, up DR.EditInfoUDUpdateParams) {
+	applyEditInfoU(&ei.EditInfoU, up.EditInfoU)
+	if up.DeletedAt != nil {
+		ei.DeletedAt = up.DeletedAt
+		ei.DeletedBy = up.DeletedBy
+	}
+}
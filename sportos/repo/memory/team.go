@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type teamStore struct {
+	s *store
+}
+
+// CheckConstraints returns true if team with same name already exists for sport
+func (r *teamStore) CheckConstraints(ctx context.Context, te DR.Team, qa crud.QueryAble) bool {
+	return r.s.teams.exists(func(t DR.Team) bool {
+		return t.Sport == te.Sport && t.Name == te.Name
+	})
+}
+
+func (r *teamStore) Create(ctx context.Context, en DR.Team, qa crud.QueryAble, by *string) (DR.Team, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+
+	if r.CheckConstraints(ctx, en, qa) {
+		return en, fmt.Errorf("team with name %s already exists for sport %s", en.Name, en.Sport)
+	}
+
+	row := DR.Team{
+		Name:   en.Name,
+		Sport:  en.Sport,
+		Status: en.Status,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.teams.insertNext(row, func(te *DR.Team, id string) {
+		te.TeamId = id
+	})
+	// first player is owner of team
+	for i, playerId := range en.Players {
+		role := DR.TR_MEMBER
+		if i == 0 {
+			role = DR.TR_OWNER
+		}
+		if _, err := (&teamMemberStore{r.s}).Create(ctx, DR.TeamMember{TeamId: pen.TeamId, PlayerId: playerId, Role: role}, qa, by); err != nil {
+			return pen, err
+		}
+	}
+	pen.SetMembers(r.s.members(pen.TeamId))
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *teamStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Team, error) {
+	te, ok := r.s.teams.get(id)
+	if !ok {
+		return DR.Team{}, fmt.Errorf("team does not exist for username: %v", id)
+	}
+	te.SetMembers(r.s.members(te.TeamId))
+	return te, nil
+}
+
+func (r *teamStore) GetCount(ctx context.Context, sp DR.TeamSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *teamStore) Search(ctx context.Context, sp DR.TeamSearchParams, qa crud.QueryAble) ([]DR.Team, error) {
+	teams := r.find(sp)
+	sortRows(teams, sp.TeamSortParams.SortColumns())
+	return page(teams, sp.PagingSearchParams), nil
+}
+
+func (r *teamStore) find(sp DR.TeamSearchParams) []DR.Team {
+	teams := r.s.teams.find(func(te DR.Team) bool {
+		return matchesNonEmpty(sp.Name, te.Name) &&
+			matchesNonEmpty(sp.Status, string(te.Status)) &&
+			matchesAny(sp.Sports, te.Sport) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, te.EditInfoCUD)
+	})
+	for i := range teams {
+		teams[i].SetMembers(r.s.members(teams[i].TeamId))
+	}
+	return filter(teams, func(te DR.Team) bool {
+		return (sp.UserNotInTeam == nil || !te.HasPlayer(*sp.UserNotInTeam)) &&
+			(sp.Owner == nil || te.Owner() == *sp.Owner)
+	})
+}
+
+func (r *teamStore) Update(ctx context.Context, up DR.TeamUpdateParams, qa crud.QueryAble, by *string) (DR.Team, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.teams.update(up.Id, func(te *DR.Team) {
+		if up.Status != nil {
+			te.Status = *up.Status
+		}
+		applyEditInfoUD(&te.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Team{}, fmt.Errorf("no rows affected")
+	}
+	old.SetMembers(r.s.members(up.Id))
+	pen.SetMembers(r.s.members(up.Id))
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *teamStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.teams, id, func(te *DR.Team) *DR.EditInfoD {
+		return &te.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	old.SetMembers(r.s.members(id))
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type teamMemberStore struct {
+	s *store
+}
+
+func (r *teamMemberStore) Create(ctx context.Context, en DR.TeamMember, qa crud.QueryAble, by *string) (DR.TeamMember, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	en.Position = len(r.find(DR.TeamMemberSearchParams{TeamIds: []string{en.TeamId}}))
+	if !r.s.teamMembers.insert(en.GetId(), en) {
+		return en, fmt.Errorf("player %s is already in team %s", en.PlayerId, en.TeamId)
+	}
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &en, qa, by)
+	return en, err
+}
+
+func (r *teamMemberStore) GetById(ctx context.Context, teamId, playerId string, qa crud.QueryAble) (DR.TeamMember, error) {
+	tm, ok := r.s.teamMembers.get((&DR.TeamMember{TeamId: teamId, PlayerId: playerId}).GetId())
+	if !ok {
+		return DR.TeamMember{}, fmt.Errorf("player %v is not in team %v", playerId, teamId)
+	}
+	return tm, nil
+}
+
+func (r *teamMemberStore) Search(ctx context.Context, sp DR.TeamMemberSearchParams, qa crud.QueryAble) ([]DR.TeamMember, error) {
+	return page(r.find(sp), sp.PagingSearchParams), nil
+}
+
+// find returns members in insertion order, so members of every team are ordered by position
+func (r *teamMemberStore) find(sp DR.TeamMemberSearchParams) []DR.TeamMember {
+	return r.s.teamMembers.find(func(tm DR.TeamMember) bool {
+		return matchesAny(sp.TeamIds, tm.TeamId) &&
+			matches(sp.PlayerId, tm.PlayerId) &&
+			(sp.Role == nil || *sp.Role == tm.Role) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, tm.EditInfoC)
+	})
+}
+
+// members returns members of team ordered by position
+func (s *store) members(teamId string) []DR.TeamMember {
+	return (&teamMemberStore{s}).find(DR.TeamMemberSearchParams{TeamIds: []string{teamId}})
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type userStore struct {
+	s *store
+}
+
+func matchesUser(sp DR.UserSearchParams, usr DR.User) bool {
+	var userType *string
+	if sp.UserType != nil {
+		ut := string(*sp.UserType)
+		userType = &ut
+	}
+	return matchesNonEmpty(sp.Username, usr.Username) &&
+		matchesNonEmpty(sp.Email, usr.Email) &&
+		matchesNonEmpty(userType, string(usr.UserType)) &&
+		(sp.Token == nil || *sp.Token == "" || (usr.Token != nil && *usr.Token == *sp.Token)) &&
+		matchesEditInfoCUD(sp.EditInfoCUDSearchParams, usr.EditInfoCUD)
+}
+
+// joinsUser is condition of inner join on "user" by user_id
+func (s *store) joinsUser(sp *DR.UserSearchParams, username string) bool {
+	if sp == nil {
+		return true
+	}
+	usr, ok := s.users.get(username)
+	return ok && matchesUser(*sp, usr)
+}
+
+func (s *store) userByEmail(email string) (DR.User, bool) {
+	users := s.users.find(func(usr DR.User) bool {
+		return usr.Email == email
+	})
+	if len(users) == 0 {
+		return DR.User{}, false
+	}
+	return users[0], true
+}
+
+func (r *userStore) Create(ctx context.Context, en DR.User, qa crud.QueryAble, by *string) (DR.User, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := DR.User{
+		Username:      en.Username,
+		Email:         en.Email,
+		EmailVerified: en.EmailVerified,
+		UserType:      en.UserType,
+		PasswordHash:  en.PasswordHash,
+	}
+	row.EditInfoC = en.EditInfoC
+	if !r.s.users.insert(en.Username, row) {
+		return en, fmt.Errorf("user with username %s already exists", en.Username)
+	}
+	pen, err := r.GetById(ctx, en.Username, qa)
+	if err != nil {
+		return pen, err
+	}
+	_, err = r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *userStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.User, error) {
+	usr, ok := r.s.users.get(id)
+	if !ok {
+		return DR.User{}, fmt.Errorf("user does not exist for username: %v", id)
+	}
+	usr.Email = crud.TrimMail(usr.Email)
+	return usr, nil
+}
+
+func (r *userStore) GetByEmail(ctx context.Context, email string, qa crud.QueryAble) (DR.User, error) {
+	usr, ok := r.s.userByEmail(email)
+	if !ok {
+		return DR.User{}, fmt.Errorf("user does not exist for email: %v", email)
+	}
+	usr.Email = crud.TrimMail(usr.Email)
+	return usr, nil
+}
+
+func (r *userStore) GetCount(ctx context.Context, sp DR.UserSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *userStore) Search(ctx context.Context, sp DR.UserSearchParams, qa crud.QueryAble) ([]DR.User, error) {
+	users := r.find(sp)
+	sortRows(users, sp.UserSortParams.SortColumns())
+	users = page(users, sp.PagingSearchParams)
+	for i := range users {
+		users[i].Email = crud.TrimMail(users[i].Email)
+	}
+	return users, nil
+}
+
+func (r *userStore) find(sp DR.UserSearchParams) []DR.User {
+	return r.s.users.find(func(usr DR.User) bool {
+		return matchesUser(sp, usr)
+	})
+}
+
+func (r *userStore) Update(ctx context.Context, up DR.UserUpdateParams, qa crud.QueryAble, by *string) (DR.User, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.users.update(up.Id, func(usr *DR.User) {
+		if up.EmailVerified != nil {
+			usr.EmailVerified = *up.EmailVerified
+		}
+		if up.PasswordHash != nil {
+			usr.PasswordHash = *up.PasswordHash
+		}
+		if up.Token != nil {
+			usr.Token = up.Token
+		}
+		if up.TokenValidUntil != nil {
+			usr.TokenValidUntil = up.TokenValidUntil
+		}
+		if up.TokenRefreshUntil != nil {
+			usr.TokenRefreshUntil = up.TokenRefreshUntil
+		}
+		if up.RevokeTokens {
+			usr.TokenVersion++
+		}
+		applyEditInfoUD(&usr.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.User{}, fmt.Errorf("no rows affected")
+	}
+	old.Email = crud.TrimMail(old.Email)
+	pen.Email = crud.TrimMail(pen.Email)
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *userStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.users, id, func(usr *DR.User) *DR.EditInfoD {
+		return &usr.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
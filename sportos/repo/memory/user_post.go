@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+type userPostStore struct {
+	s *store
+}
+
+func (r *userPostStore) Create(ctx context.Context, en DR.UserPost, qa crud.QueryAble, by *string) (DR.UserPost, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCD = DR.CreateEditInfoCD(by)
+	}
+	row := DR.UserPost{
+		UserId:     en.UserId,
+		UserText:   en.UserText,
+		ImageNames: en.ImageNames,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.userPosts.insertNext(row, func(up *DR.UserPost, id string) {
+		up.PostId = id
+	})
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *userPostStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.UserPost, error) {
+	up, ok := r.s.userPosts.get(id)
+	if !ok {
+		return DR.UserPost{}, fmt.Errorf("user post does not exist for id: %v", id)
+	}
+	return up, nil
+}
+
+func (r *userPostStore) GetCount(ctx context.Context, sp DR.UserPostSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+func (r *userPostStore) Search(ctx context.Context, sp DR.UserPostSearchParams, qa crud.QueryAble) ([]DR.UserPost, error) {
+	posts := r.find(sp)
+	sortRows(posts, sp.UserPostSortParams.SortColumns())
+	return page(posts, sp.PagingSearchParams), nil
+}
+
+func (r *userPostStore) find(sp DR.UserPostSearchParams) []DR.UserPost {
+	return r.s.userPosts.find(func(up DR.UserPost) bool {
+		return matches(sp.UserId, up.UserId) &&
+			(sp.NotUserId == nil || *sp.NotUserId != up.UserId) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, up.EditInfoC) &&
+			matchesEditInfoD(sp.EditInfoDSearchParams, up.EditInfoD)
+	})
+}
+
+func (r *userPostStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.userPosts, id, func(up *DR.UserPost) *DR.EditInfoD {
+		return &up.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"time"
+)
+
+type verificationTokenStore struct {
+	s *store
+}
+
+func (r *verificationTokenStore) Create(ctx context.Context, en DR.VerificationToken, qa crud.QueryAble, by *string) (DR.VerificationToken, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	row := en
+	row.ConsumedAt = nil
+	if !r.s.verificationTokens.insert(en.TokenHash, row) {
+		return en, fmt.Errorf("verification token already exists")
+	}
+	return en, nil
+}
+
+func (r *verificationTokenStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.VerificationToken, error) {
+	vt, ok := r.s.verificationTokens.get(id)
+	if !ok {
+		return DR.VerificationToken{}, fmt.Errorf("verification token does not exist")
+	}
+	return vt, nil
+}
+
+// GetValid returns token by hash if it has given purpose, isn't consumed and isn't expired
+func (r *verificationTokenStore) GetValid(ctx context.Context, id string, purpose DR.TokenPurpose, qa crud.QueryAble) (DR.VerificationToken, error) {
+	vt, err := r.GetById(ctx, id, qa)
+	if err != nil {
+		return vt, err
+	}
+	if vt.Purpose != purpose || vt.ConsumedAt != nil || vt.ValidUntil.Before(time.Now()) {
+		return vt, fmt.Errorf("verification token is not valid")
+	}
+	return vt, nil
+}
+
+func (r *verificationTokenStore) GetCount(ctx context.Context, sp DR.VerificationTokenSearchParams, qa crud.QueryAble) (int, error) {
+	var purpose *string
+	if sp.Purpose != nil {
+		p := string(*sp.Purpose)
+		purpose = &p
+	}
+	tokens := r.s.verificationTokens.find(func(vt DR.VerificationToken) bool {
+		return matchesNonEmpty(sp.UserId, vt.UserId) &&
+			matchesNonEmpty(purpose, string(vt.Purpose)) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, vt.EditInfoC)
+	})
+	return len(tokens), nil
+}
+
+// Consume marks token as used, token can be consumed only once and only before it expires
+func (r *verificationTokenStore) Consume(ctx context.Context, id string, purpose DR.TokenPurpose, qa crud.QueryAble) (DR.VerificationToken, error) {
+	now := time.Now().UTC()
+	consumed := false
+	_, pen, _ := r.s.verificationTokens.update(id, func(vt *DR.VerificationToken) {
+		if vt.Purpose == purpose && vt.ConsumedAt == nil && vt.ValidUntil.After(now) {
+			vt.ConsumedAt = &now
+			consumed = true
+		}
+	})
+	vt := DR.VerificationToken{TokenHash: id, Purpose: purpose}
+	if !consumed {
+		return vt, fmt.Errorf("verification token is not valid")
+	}
+	vt.UserId = pen.UserId
+	return vt, nil
+}
+
+// Invalidate consumes all unused tokens of user for given purpose
+func (r *verificationTokenStore) Invalidate(ctx context.Context, userId string, purpose DR.TokenPurpose, qa crud.QueryAble) error {
+	now := time.Now().UTC()
+	r.s.verificationTokens.updateAll(func(vt DR.VerificationToken) bool {
+		return vt.UserId == userId && vt.Purpose == purpose && vt.ConsumedAt == nil
+	}, func(vt *DR.VerificationToken) {
+		vt.ConsumedAt = &now
+	})
+	return nil
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	H "backend/internal/helpers"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type webhookStore struct {
+	s *store
+}
+
+func (r *webhookStore) Create(ctx context.Context, en DR.Webhook, qa crud.QueryAble, by *string) (DR.Webhook, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoC = DR.CreateEditInfoC(by)
+	}
+	if en.Events == nil {
+		en.Events = []string{}
+	}
+	row := DR.Webhook{
+		PlaceId: en.PlaceId,
+		Url:     en.Url,
+		Secret:  en.Secret,
+		Events:  append([]string{}, en.Events...),
+		Active:  en.Active,
+	}
+	row.EditInfoC = en.EditInfoC
+	pen := r.s.webhooks.insertNext(row, func(wh *DR.Webhook, id string) {
+		wh.WebhookId = id
+	})
+
+	_, err := r.s.audit.CreateSnapshot(ctx, nil, &pen, qa, by)
+	return pen, err
+}
+
+func (r *webhookStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.Webhook, error) {
+	wh, ok := r.s.webhooks.get(id)
+	if !ok {
+		return DR.Webhook{}, fmt.Errorf("webhook does not exist for id: %v", id)
+	}
+	return wh, nil
+}
+
+func (r *webhookStore) GetCount(ctx context.Context, sp DR.WebhookSearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns webhooks in order in which they were created
+func (r *webhookStore) Search(ctx context.Context, sp DR.WebhookSearchParams, qa crud.QueryAble) ([]DR.Webhook, error) {
+	webhooks := r.find(sp)
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return page(webhooks, sp.PagingSearchParams), nil
+}
+
+func (r *webhookStore) find(sp DR.WebhookSearchParams) []DR.Webhook {
+	return r.s.webhooks.find(func(wh DR.Webhook) bool {
+		return matches(sp.PlaceId, wh.PlaceId) &&
+			(sp.Active == nil || *sp.Active == wh.Active) &&
+			(sp.EventType == nil || len(wh.Events) == 0 || H.Contains(wh.Events, string(*sp.EventType))) &&
+			matchesEditInfoCUD(sp.EditInfoCUDSearchParams, wh.EditInfoCUD)
+	})
+}
+
+func (r *webhookStore) Update(ctx context.Context, up DR.WebhookUpdateParams, qa crud.QueryAble, by *string) (DR.Webhook, error) {
+	up.PopulateUpdateFields(by)
+
+	old, pen, ok := r.s.webhooks.update(up.Id, func(wh *DR.Webhook) {
+		if up.Url != nil {
+			wh.Url = *up.Url
+		}
+		if up.Secret != nil {
+			wh.Secret = *up.Secret
+		}
+		if up.Events != nil {
+			wh.Events = append([]string{}, *up.Events...)
+		}
+		if up.Active != nil {
+			wh.Active = *up.Active
+		}
+		applyEditInfoUD(&wh.EditInfoCUD, up.EditInfoUDUpdateParams)
+	})
+	if !ok {
+		return DR.Webhook{}, fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, &pen, qa, by)
+	return pen, err
+}
+
+func (r *webhookStore) Delete(ctx context.Context, id string, qa crud.QueryAble, by *string) error {
+	if _, err := r.GetById(ctx, id, qa); err != nil {
+		return err
+	}
+	old, ok := softDelete(r.s.webhooks, id, func(wh *DR.Webhook) *DR.EditInfoD {
+		return &wh.EditInfoD
+	}, by)
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+
+	_, err := r.s.audit.CreateSnapshot(ctx, &old, nil, qa, by)
+	return err
+}
+
+type webhookDeliveryStore struct {
+	s *store
+}
+
+func (r *webhookDeliveryStore) Create(ctx context.Context, en DR.WebhookDelivery, qa crud.QueryAble, by *string) (DR.WebhookDelivery, error) {
+	if en.CreatedAt.IsZero() {
+		en.EditInfoCU = DR.CreateEditInfoCU(by)
+	}
+	if en.Status == "" {
+		en.Status = DR.WDS_PENDING
+	}
+	if en.NextAttemptAt.IsZero() {
+		en.NextAttemptAt = en.CreatedAt
+	}
+	return r.s.webhookDeliveries.insertNext(en, func(wd *DR.WebhookDelivery, id string) {
+		wd.DeliveryId = id
+	}), nil
+}
+
+func (r *webhookDeliveryStore) GetById(ctx context.Context, id string, qa crud.QueryAble) (DR.WebhookDelivery, error) {
+	wd, ok := r.s.webhookDeliveries.get(id)
+	if !ok {
+		return DR.WebhookDelivery{}, fmt.Errorf("webhook delivery does not exist for id: %v", id)
+	}
+	return wd, nil
+}
+
+func (r *webhookDeliveryStore) GetCount(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa crud.QueryAble) (int, error) {
+	return len(r.find(sp)), nil
+}
+
+// Search returns webhook deliveries, newest first
+func (r *webhookDeliveryStore) Search(ctx context.Context, sp DR.WebhookDeliverySearchParams, qa crud.QueryAble) ([]DR.WebhookDelivery, error) {
+	deliveries := r.find(sp)
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+		}
+		return idLess(deliveries[j].DeliveryId, deliveries[i].DeliveryId)
+	})
+	return page(deliveries, sp.PagingSearchParams), nil
+}
+
+func (r *webhookDeliveryStore) find(sp DR.WebhookDeliverySearchParams) []DR.WebhookDelivery {
+	return r.s.webhookDeliveries.find(func(wd DR.WebhookDelivery) bool {
+		return matches(sp.WebhookId, wd.WebhookId) &&
+			(sp.Status == nil || *sp.Status == wd.Status) &&
+			matchesEditInfoC(sp.EditInfoCSearchParams, wd.EditInfoC) &&
+			matchesEditInfoU(sp.EditInfoUSearchParams, wd.EditInfoU)
+	})
+}
+
+// GetPending returns pending deliveries that are due for sending
+func (r *webhookDeliveryStore) GetPending(ctx context.Context, limit int, qa crud.QueryAble) ([]DR.WebhookDelivery, error) {
+	now := time.Now().UTC()
+	deliveries := r.s.webhookDeliveries.find(func(wd DR.WebhookDelivery) bool {
+		return wd.Status == DR.WDS_PENDING && !wd.NextAttemptAt.After(now)
+	})
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return idLess(deliveries[i].DeliveryId, deliveries[j].DeliveryId)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryStore) Update(ctx context.Context, up DR.WebhookDeliveryUpdateParams, qa crud.QueryAble, by *string) error {
+	up.PopulateUpdateFields(by)
+
+	_, _, ok := r.s.webhookDeliveries.update(up.Id, func(wd *DR.WebhookDelivery) {
+		if up.Response != nil {
+			wd.Response = up.Response
+		}
+		if up.ResponseCode != nil {
+			wd.ResponseCode = up.ResponseCode
+		}
+		if up.Status != nil {
+			wd.Status = *up.Status
+		}
+		if up.Attempts != nil {
+			wd.Attempts = *up.Attempts
+		}
+		if up.NextAttemptAt != nil {
+			wd.NextAttemptAt = *up.NextAttemptAt
+		}
+		if up.LastError != nil {
+			wd.LastError = up.LastError
+		}
+		if up.DeliveredAt != nil {
+			wd.DeliveredAt = up.DeliveredAt
+		}
+		applyEditInfoU(&wd.EditInfoU, up.EditInfoU)
+	})
+	if !ok {
+		return fmt.Errorf("no rows affected")
+	}
+	return nil
+}
+
+// idLess compares ids generated by insertNext by their numeric value
+func idLess(a, b string) bool {
+	first, _ := strconv.Atoi(a)
+	second, _ := strconv.Atoi(b)
+	return first < second
+}
@@ -0,0 +1,19 @@
+-- undo of V1.00
+drop table if exists practice;
+drop sequence if exists practice_id_seq;
+drop table if exists userpost;
+drop sequence if exists image_seq;
+drop table if exists audit;
+drop sequence if exists audit_id_seq;
+drop table if exists api_journal;
+drop sequence if exists api_journal_id_seq;
+drop table if exists match;
+drop sequence if exists match_id_seq;
+drop table if exists team;
+drop sequence if exists team_id_seq;
+drop table if exists event;
+drop sequence if exists event_id_seq;
+drop table if exists place;
+drop table if exists coach;
+drop table if exists player;
+drop table if exists "user";
@@ -0,0 +1,2 @@
+-- undo of V1.01
+drop table if exists verification_token;
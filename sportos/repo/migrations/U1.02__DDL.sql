@@ -0,0 +1,3 @@
+-- undo of V1.02
+drop table if exists mail_outbox;
+drop sequence if exists mail_outbox_id_seq;
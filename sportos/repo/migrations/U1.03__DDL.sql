@@ -0,0 +1,7 @@
+-- undo of V1.03
+alter table userpost drop constraint if exists pk_userpost;
+alter table userpost drop column if exists deleted_by;
+alter table userpost drop column if exists deleted_at;
+alter table userpost drop column if exists created_by;
+alter table userpost drop column if exists post_id;
+drop sequence if exists userpost_id_seq;
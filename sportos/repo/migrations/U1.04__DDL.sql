@@ -0,0 +1,21 @@
+-- undo of V1.04
+alter table match add column if not exists players character varying(1000) null;
+alter table match add column if not exists teams jsonb null;
+alter table team add column if not exists players character varying(1000) null;
+
+update match ma set players = (
+    select string_agg(mp.player_id, ',' order by mp.position) from match_player mp where mp.match_id = ma.match_id
+);
+update match ma set teams = (
+    select jsonb_build_array(
+        string_agg(mp.player_id, ',' order by mp.position) filter (where mp.side = 0),
+        string_agg(mp.player_id, ',' order by mp.position) filter (where mp.side = 1))
+    from match_player mp where mp.match_id = ma.match_id and mp.side is not null
+    having count(*) > 0
+);
+update team te set players = (
+    select string_agg(tm.player_id, ',' order by tm.position) from team_member tm where tm.team_id = te.team_id
+);
+
+drop table if exists match_player;
+drop table if exists team_member;
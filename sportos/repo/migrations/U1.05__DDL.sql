@@ -0,0 +1,16 @@
+-- undo of V1.05
+alter table place add column if not exists booking jsonb null;
+alter table coach add column if not exists booking jsonb null;
+
+update place pla set booking = (
+    select jsonb_agg(jsonb_build_object('startTime', lower(bo.during), 'endTime', upper(bo.during)) order by lower(bo.during))
+    from booking bo where bo.owner_id = pla.user_id and bo.deleted_at is null
+);
+update coach co set booking = (
+    select jsonb_agg(jsonb_strip_nulls(jsonb_build_object('startTime', lower(bo.during), 'endTime', upper(bo.during),
+        'accepted', case when bo.status = 'CONFIRMED' then true end, 'id', bo.reference_id)) order by lower(bo.during))
+    from booking bo where bo.owner_id = co.user_id and bo.deleted_at is null
+);
+
+drop table if exists booking;
+drop sequence if exists booking_id_seq;
@@ -0,0 +1,3 @@
+-- undo of V1.06
+alter table place drop column if exists schedule;
+alter table coach drop column if exists schedule;
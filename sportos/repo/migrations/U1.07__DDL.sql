@@ -0,0 +1,3 @@
+-- undo of V1.07
+alter table place drop column if exists time_zone;
+alter table coach drop column if exists time_zone;
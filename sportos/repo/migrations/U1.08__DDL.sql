@@ -0,0 +1,3 @@
+-- undo of V1.08
+drop table if exists notification;
+drop sequence if exists notification_id_seq;
@@ -0,0 +1,3 @@
+-- undo of V1.09
+drop table if exists outbox_event;
+drop sequence if exists outbox_event_id_seq;
@@ -0,0 +1,5 @@
+-- undo of V1.10
+drop table if exists webhook_delivery;
+drop table if exists webhook;
+drop sequence if exists webhook_delivery_id_seq;
+drop sequence if exists webhook_id_seq;
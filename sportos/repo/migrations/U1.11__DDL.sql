@@ -0,0 +1,5 @@
+-- undo of V1.11
+drop table if exists rating_history;
+drop table if exists rating;
+drop sequence if exists rating_history_id_seq;
+drop sequence if exists rating_id_seq;
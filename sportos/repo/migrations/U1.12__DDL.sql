@@ -0,0 +1,2 @@
+-- undo of V1.12
+alter table match drop column if exists balancing;
@@ -0,0 +1,2 @@
+-- undo of V1.13
+drop table if exists leaderboard;
@@ -0,0 +1,31 @@
+-- undo of V1.14, statistics are rebuilt from results of matches and tournament rankings
+alter table player add column if not exists statistics jsonb null;
+
+update player pl set statistics = (
+    select jsonb_object_agg(s.sport, jsonb_strip_nulls(jsonb_build_object(
+        'winRatio', (select (count(*) filter (where pm.outcome = 'WIN'))::numeric / count(*)
+            from player_match pm where pm.player_id = pl.user_id and pm.sport = s.sport having count(*) > 0)::text,
+        'matches', (select jsonb_agg(jsonb_build_object(
+                'date', pm.played_at,
+                'score', pm.points_for || ':' || pm.points_against,
+                'myTeam', (select jsonb_agg(mp.player_id order by mp.position) from match_player mp where mp.match_id = pm.match_id and mp.side = pm.side),
+                'oppTeam', (select jsonb_agg(mp.player_id order by mp.position) from match_player mp where mp.match_id = pm.match_id and mp.side <> pm.side)
+            ) order by pm.played_at)
+            from player_match pm where pm.player_id = pl.user_id and pm.sport = s.sport),
+        'tournaments', (select jsonb_agg(jsonb_build_object(
+                'tournament', ev.name,
+                'ranking', pt.ranking,
+                'myTeam', (select jsonb_agg(tm.player_id order by tm.position) from team_member tm where tm.team_id = pt.team_id)
+            ) order by pt.created_at)
+            from player_tournament pt inner join event ev on ev.event_id = pt.event_id
+            where pt.player_id = pl.user_id and pt.sport = s.sport)
+    )))
+    from (
+        select sport from player_match where player_id = pl.user_id
+        union
+        select sport from player_tournament where player_id = pl.user_id
+    ) s
+);
+
+drop table if exists player_tournament;
+drop table if exists player_match;
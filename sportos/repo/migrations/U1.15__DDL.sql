@@ -0,0 +1,4 @@
+-- undo of V1.15, results with breakdown keep only headline score
+update match set result = split_part(result, ' ', 1) where length(result) > 20;
+alter table match alter column result type character varying(20);
+comment on column match.result is null;
@@ -0,0 +1,4 @@
+-- undo of V1.16, matches waiting for result go back to full
+update match set status = 'FULL' where status in ('RESULT_PENDING', 'DISPUTED');
+drop table if exists match_result;
+drop sequence if exists match_result_id_seq;
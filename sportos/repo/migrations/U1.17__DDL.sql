@@ -0,0 +1,2 @@
+-- undo of V1.17
+alter table "user" drop column if exists token_version;
@@ -0,0 +1,8 @@
+-- undo of V1.18, statistics column is added back with archived statistics, U1.14 rebuilds it from results
+alter table player add column if not exists statistics jsonb null;
+
+update player pl set statistics = ar.statistics
+from player_statistics_archive ar
+where ar.player_id = pl.user_id;
+
+drop table if exists player_statistics_archive;
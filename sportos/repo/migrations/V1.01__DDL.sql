@@ -0,0 +1,20 @@
+-- verification_token ddl
+CREATE TABLE verification_token (
+    token_hash character varying(64) not null,
+    user_id character varying(40) not null,
+    purpose character varying(40) not null,
+    valid_until timestamp(6) with time zone not null,
+    consumed_at timestamp(6) with time zone,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    constraint pk_verification_token PRIMARY KEY (token_hash),
+    constraint fk_verification_token_user_id foreign key (user_id)
+    references "user" (user_id) match simple
+);
+
+comment on table verification_token is 'Single use tokens sent by email (email verification, password reset).';
+comment on column verification_token.token_hash is 'Hex encoded SHA-256 of token, token itself is never stored.';
+comment on column verification_token.purpose is 'What token can be used for, e.g. VERIFY_EMAIL, RESET_PASSWORD.';
+comment on column verification_token.consumed_at is 'When token was used or invalidated by newer token.';
+
+create index verification_token_user_index on verification_token (user_id, purpose, created_at);
@@ -0,0 +1,31 @@
+create sequence mail_outbox_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- mail_outbox ddl
+CREATE TABLE mail_outbox (
+    mail_id character varying(40) not null DEFAULT nextval('mail_outbox_id_seq'::regclass),
+    recipients text[] not null,
+    subject character varying(200) not null,
+    text_body text not null,
+    html_body text,
+    status character varying(20) not null,
+    attempts integer not null default 0,
+    next_attempt_at timestamp(6) with time zone not null,
+    last_error text,
+    sent_at timestamp(6) with time zone,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_mail_outbox PRIMARY KEY (mail_id)
+);
+
+comment on table mail_outbox is 'Mails waiting to be sent, rows are inserted in same transaction as business change.';
+comment on column mail_outbox.status is 'PENDING, SENT or FAILED (after max attempts).';
+comment on column mail_outbox.next_attempt_at is 'Earliest time of next send attempt, grows with exponential backoff.';
+
+create index mail_outbox_pending_index on mail_outbox (status, next_attempt_at);
@@ -0,0 +1,17 @@
+create sequence userpost_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- userpost gets id and soft delete columns like other entities
+alter table userpost add column post_id character varying(40) not null DEFAULT nextval('userpost_id_seq'::regclass);
+alter table userpost add column created_by character varying(40);
+update userpost set created_by = user_id;
+alter table userpost alter column created_by set not null;
+alter table userpost add column deleted_at timestamp(6) with time zone;
+alter table userpost add column deleted_by character varying(40);
+alter table userpost add constraint pk_userpost PRIMARY KEY (post_id);
+
+comment on column userpost.deleted_at is 'Time when post was (soft) deleted, deleted posts are not returned by search.';
@@ -0,0 +1,76 @@
+-- match_player ddl
+CREATE TABLE match_player (
+    match_id character varying(40) not null,
+    player_id character varying(40) not null,
+    role character varying(20) not null,
+    position integer not null,
+    side smallint,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_match_player PRIMARY KEY (match_id, player_id),
+    constraint fk_match_player_match_id foreign key (match_id)
+    references match (match_id) match simple
+);
+
+comment on table match_player is 'Players of match, replaces comma separated match.players and match.teams.';
+comment on column match_player.role is 'CREATOR or PLAYER.';
+comment on column match_player.position is 'Order in which players joined match, creator has position 0.';
+comment on column match_player.side is 'Index of team (0 or 1) player plays for, set when match is full.';
+
+create index match_player_player_index on match_player (player_id);
+
+-- team_member ddl
+CREATE TABLE team_member (
+    team_id character varying(40) not null,
+    player_id character varying(40) not null,
+    role character varying(20) not null,
+    position integer not null,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    constraint pk_team_member PRIMARY KEY (team_id, player_id),
+    constraint fk_team_member_team_id foreign key (team_id)
+    references team (team_id) match simple
+);
+
+comment on table team_member is 'Players of team, replaces comma separated team.players.';
+comment on column team_member.role is 'OWNER or MEMBER.';
+comment on column team_member.position is 'Order in which players joined team, owner has position 0.';
+
+create index team_member_player_index on team_member (player_id);
+
+-- existing rows aren't checked, old rosters may contain players that don't exist anymore
+alter table match_player add constraint fk_match_player_player_id foreign key (player_id)
+    references player (user_id) match simple not valid;
+alter table team_member add constraint fk_team_member_player_id foreign key (player_id)
+    references player (user_id) match simple not valid;
+
+-- split existing rosters, side is index of element of match.teams that contains player
+insert into match_player (match_id, player_id, role, position, side, created_at, created_by)
+select ma.match_id, p.player_id,
+    case when p.ord = 1 then 'CREATOR' else 'PLAYER' end,
+    p.ord - 1,
+    (select t.ord - 1
+        from jsonb_array_elements_text(case when jsonb_typeof(ma.teams) = 'array' then ma.teams else '[]'::jsonb end) with ordinality t(members, ord)
+        where p.player_id = any(string_to_array(t.members, ','))
+        limit 1),
+    ma.created_at, ma.created_by
+from match ma
+cross join lateral unnest(string_to_array(ma.players, ',')) with ordinality p(player_id, ord)
+where p.player_id <> ''
+on conflict do nothing;
+
+insert into team_member (team_id, player_id, role, position, created_at, created_by)
+select te.team_id, p.player_id,
+    case when p.ord = 1 then 'OWNER' else 'MEMBER' end,
+    p.ord - 1,
+    te.created_at, te.created_by
+from team te
+cross join lateral unnest(string_to_array(te.players, ',')) with ordinality p(player_id, ord)
+where p.player_id <> ''
+on conflict do nothing;
+
+alter table match drop column players;
+alter table match drop column teams;
+alter table team drop column players;
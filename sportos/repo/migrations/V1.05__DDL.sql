@@ -0,0 +1,68 @@
+-- btree_gist is needed for equality on owner_id in exclusion constraint
+create extension if not exists btree_gist;
+
+create sequence booking_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- booking ddl
+CREATE TABLE booking (
+    booking_id character varying(40) not null DEFAULT nextval('booking_id_seq'::regclass),
+    owner_id character varying(40) not null,
+    kind character varying(20) not null,
+    reference_id character varying(40) not null,
+    during tstzrange not null,
+    status character varying(20) not null,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    deleted_at timestamp(6) with time zone,
+    deleted_by character varying(40),
+    constraint pk_booking PRIMARY KEY (booking_id),
+    constraint fk_booking_owner_id foreign key (owner_id)
+    references "user" (user_id) match simple,
+    constraint booking_no_overlap exclude using gist (owner_id with =, during with &&)
+    where (status = 'CONFIRMED' and deleted_at is null)
+);
+
+comment on table booking is 'Appointments at places and coaches, replaces place.booking and coach.booking.';
+comment on column booking.owner_id is 'Place or coach that is booked.';
+comment on column booking.kind is 'MATCH, PRACTICE or TOURNAMENT.';
+comment on column booking.reference_id is 'Id of match, practice or event booking is made for.';
+comment on column booking.during is 'Booked time, half open range [start, end).';
+comment on column booking.status is 'PENDING until coach accepts practice, CONFIRMED bookings of owner can not overlap.';
+
+create index booking_reference_index on booking (kind, reference_id);
+
+-- move appointments out of json columns, appointments of deleted or missing matches, events and
+-- practices are dropped, on conflict skips confirmed appointments that overlap already moved ones
+insert into booking (owner_id, kind, reference_id, during, status, created_at, created_by)
+select pla.user_id, 'MATCH', ma.match_id, tstzrange((b->>'startTime')::timestamptz, (b->>'endTime')::timestamptz, '[)'), 'CONFIRMED', ma.created_at, ma.created_by
+from place pla
+cross join lateral jsonb_array_elements(case when jsonb_typeof(pla.booking) = 'array' then pla.booking else '[]'::jsonb end) b
+join match ma on ma.place_id = pla.user_id and ma.start_time = (b->>'startTime')::timestamptz and ma.deleted_at is null
+where (b->>'endTime')::timestamptz - (b->>'startTime')::timestamptz < interval '1 day'
+on conflict do nothing;
+
+insert into booking (owner_id, kind, reference_id, during, status, created_at, created_by)
+select pla.user_id, 'TOURNAMENT', ev.event_id, tstzrange((b->>'startTime')::timestamptz, (b->>'endTime')::timestamptz, '[)'), 'CONFIRMED', ev.created_at, ev.created_by
+from place pla
+cross join lateral jsonb_array_elements(case when jsonb_typeof(pla.booking) = 'array' then pla.booking else '[]'::jsonb end) b
+join event ev on ev.owner_id = pla.user_id and ev.time >= (b->>'startTime')::timestamptz and ev.time < (b->>'endTime')::timestamptz and ev.deleted_at is null
+where (b->>'endTime')::timestamptz - (b->>'startTime')::timestamptz >= interval '1 day'
+on conflict do nothing;
+
+insert into booking (owner_id, kind, reference_id, during, status, created_at, created_by)
+select co.user_id, 'PRACTICE', pr.practice_id, tstzrange((b->>'startTime')::timestamptz, (b->>'endTime')::timestamptz, '[)'),
+    case when coalesce((b->>'accepted')::boolean, false) then 'CONFIRMED' else 'PENDING' end, pr.created_at, pr.created_by
+from coach co
+cross join lateral jsonb_array_elements(case when jsonb_typeof(co.booking) = 'array' then co.booking else '[]'::jsonb end) b
+join practice pr on pr.practice_id = b->>'id' and pr.deleted_at is null
+on conflict do nothing;
+
+alter table place drop column booking;
+alter table coach drop column booking;
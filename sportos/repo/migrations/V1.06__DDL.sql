@@ -0,0 +1,6 @@
+-- weekly opening hours of places and coaches, null means open whole day in slots of one hour
+alter table place add column schedule jsonb null;
+alter table coach add column schedule jsonb null;
+
+comment on column place.schedule is 'Opening hours, exceptions, slot length and buffer of place.';
+comment on column coach.schedule is 'Opening hours, exceptions, slot length and buffer of coach.';
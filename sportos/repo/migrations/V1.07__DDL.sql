@@ -0,0 +1,6 @@
+-- IANA time zone of places and coaches, existing rows were created for Serbian cities
+alter table place add column time_zone varchar(64) not null default 'Europe/Belgrade';
+alter table coach add column time_zone varchar(64) not null default 'Europe/Belgrade';
+
+comment on column place.time_zone is 'IANA time zone of place, opening hours and days are in this zone.';
+comment on column coach.time_zone is 'IANA time zone of coach, opening hours and days are in this zone.';
@@ -0,0 +1,30 @@
+create sequence notification_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- notification ddl
+CREATE TABLE notification (
+    notification_id character varying(40) not null DEFAULT nextval('notification_id_seq'::regclass),
+    user_id character varying(40) not null,
+    type character varying(40) not null,
+    reference_id character varying(40) not null,
+    message character varying(500) not null,
+    read_at timestamp(6) with time zone,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_notification PRIMARY KEY (notification_id),
+    constraint fk_notification_user_id foreign key (user_id)
+    references "user" (user_id) match simple
+);
+
+comment on table notification is 'Inbox of users, rows are inserted in same transaction as change of match, practice, tournament or team.';
+comment on column notification.reference_id is 'Id of match, practice, event or team the notification is about.';
+comment on column notification.read_at is 'Time when user marked notification as read, null for unread notifications.';
+
+create index notification_user_index on notification (user_id, created_at desc);
+create index notification_unread_index on notification (user_id) where read_at is null;
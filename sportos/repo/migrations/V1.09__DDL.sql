@@ -0,0 +1,31 @@
+create sequence outbox_event_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- outbox_event ddl
+CREATE TABLE outbox_event (
+    outbox_event_id character varying(40) not null DEFAULT nextval('outbox_event_id_seq'::regclass),
+    type character varying(40) not null,
+    aggregate_id character varying(40) not null,
+    payload jsonb not null,
+    status character varying(20) not null,
+    attempts integer not null default 0,
+    next_attempt_at timestamp(6) with time zone not null,
+    last_error text,
+    dispatched_at timestamp(6) with time zone,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_outbox_event PRIMARY KEY (outbox_event_id)
+);
+
+comment on table outbox_event is 'Domain events (match finished, tournament finished...) waiting for dispatch to subscribers, rows are inserted in same transaction as business change.';
+comment on column outbox_event.aggregate_id is 'Id of match, event, practice or user the event is about.';
+comment on column outbox_event.status is 'PENDING, DISPATCHED or FAILED (after max attempts).';
+comment on column outbox_event.next_attempt_at is 'Earliest time of next dispatch attempt, grows with exponential backoff.';
+
+create index outbox_event_pending_index on outbox_event (status, next_attempt_at);
@@ -0,0 +1,72 @@
+create sequence webhook_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+create sequence webhook_delivery_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- webhook ddl
+CREATE TABLE webhook (
+    webhook_id character varying(40) not null DEFAULT nextval('webhook_id_seq'::regclass),
+    place_id character varying(40) not null,
+    url character varying(2000) not null,
+    secret character varying(200) not null,
+    events text[] not null default '{}',
+    active boolean not null default true,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    deleted_at timestamp(6) with time zone,
+    deleted_by character varying(40),
+    constraint pk_webhook PRIMARY KEY (webhook_id),
+    constraint fk_webhook_place_id foreign key (place_id)
+    references place (user_id) match simple
+);
+
+comment on table webhook is 'Urls of places and integrators that receive domain events of place, managed in backoffice.';
+comment on column webhook.secret is 'Key of HMAC-SHA256 signature sent in X-Sportos-Signature header of every delivery.';
+comment on column webhook.events is 'Types of delivered domain events, empty array means all.';
+
+create index webhook_place_index on webhook (place_id) where deleted_at is null;
+
+-- webhook_delivery ddl
+CREATE TABLE webhook_delivery (
+    delivery_id character varying(40) not null DEFAULT nextval('webhook_delivery_id_seq'::regclass),
+    webhook_id character varying(40) not null,
+    outbox_event_id character varying(40) not null,
+    event_type character varying(40) not null,
+    request text not null,
+    response text,
+    response_code integer,
+    status character varying(20) not null,
+    attempts integer not null default 0,
+    next_attempt_at timestamp(6) with time zone not null,
+    last_error text,
+    delivered_at timestamp(6) with time zone,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_webhook_delivery PRIMARY KEY (delivery_id),
+    constraint fk_webhook_delivery_webhook_id foreign key (webhook_id)
+    references webhook (webhook_id) match simple,
+    constraint fk_webhook_delivery_outbox_event_id foreign key (outbox_event_id)
+    references outbox_event (outbox_event_id) match simple,
+    constraint uq_webhook_delivery_event unique (webhook_id, outbox_event_id)
+);
+
+comment on table webhook_delivery is 'Log of domain events sent to webhooks, rows are inserted by event dispatcher and sent by webhook worker.';
+comment on column webhook_delivery.request is 'Body that was signed and sent to webhook.';
+comment on column webhook_delivery.response is 'Body returned by webhook on last attempt.';
+comment on column webhook_delivery.status is 'PENDING, DELIVERED or FAILED (after max attempts or if webhook was deleted).';
+
+create index webhook_delivery_pending_index on webhook_delivery (status, next_attempt_at);
+create index webhook_delivery_webhook_index on webhook_delivery (webhook_id, created_at desc);
@@ -0,0 +1,67 @@
+create sequence rating_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+create sequence rating_history_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- rating ddl
+CREATE TABLE rating (
+    rating_id character varying(40) not null DEFAULT nextval('rating_id_seq'::regclass),
+    player_id character varying(40) not null,
+    sport character varying(40) not null,
+    system character varying(20) not null,
+    rating double precision not null,
+    deviation double precision not null,
+    volatility double precision not null,
+    matches integer not null default 0,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_rating PRIMARY KEY (rating_id),
+    constraint fk_rating_player_id foreign key (player_id)
+    references player (user_id) match simple,
+    constraint uq_rating_player_sport unique (player_id, sport)
+);
+
+comment on table rating is 'Rating of player per sport, updated by event dispatcher when result of match is submitted.';
+comment on column rating.system is 'ELO for sports with one player per side, GLICKO2 for team sports.';
+comment on column rating.deviation is 'Glicko-2 rating deviation, uncertainty of rating. Not used by ELO.';
+comment on column rating.volatility is 'Glicko-2 volatility, expected fluctuation of rating. Not used by ELO.';
+
+create index rating_leaderboard_index on rating (sport, rating desc);
+
+-- rating_history ddl
+CREATE TABLE rating_history (
+    history_id character varying(40) not null DEFAULT nextval('rating_history_id_seq'::regclass),
+    rating_id character varying(40) not null,
+    player_id character varying(40) not null,
+    sport character varying(40) not null,
+    match_id character varying(40) not null,
+    score double precision not null,
+    rating_before double precision not null,
+    rating_after double precision not null,
+    deviation_before double precision not null,
+    deviation_after double precision not null,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    constraint pk_rating_history PRIMARY KEY (history_id),
+    constraint fk_rating_history_rating_id foreign key (rating_id)
+    references rating (rating_id) match simple,
+    constraint fk_rating_history_match_id foreign key (match_id)
+    references match (match_id) match simple,
+    constraint uq_rating_history_player_match unique (player_id, match_id)
+);
+
+comment on table rating_history is 'Change of rating of player after every rated match.';
+comment on column rating_history.score is '1 for win, 0.5 for draw and 0 for loss.';
+
+create index rating_history_player_index on rating_history (player_id, sport, created_at desc);
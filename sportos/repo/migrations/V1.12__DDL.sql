@@ -0,0 +1,4 @@
+-- existing matches keep random teams
+alter table match add column balancing character varying(20) not null default 'RANDOM';
+
+comment on column match.balancing is 'Algorithm that splits players into teams when match is full: RANDOM, RATING or POSITIONS.';
@@ -0,0 +1,43 @@
+-- leaderboard ddl
+CREATE TABLE leaderboard (
+    player_id character varying(40) not null,
+    sport character varying(40) not null,
+    win_ratio numeric not null default 0,
+    matches integer not null default 0,
+    tournaments integer not null default 0,
+    tournament_points integer not null default 0,
+    rating double precision,
+    updated_at timestamp(6) with time zone not null,
+    constraint pk_leaderboard PRIMARY KEY (player_id, sport),
+    constraint fk_leaderboard_player_id foreign key (player_id)
+    references player (user_id) match simple
+);
+
+comment on table leaderboard is 'Statistics and rating of player per sport, kept up to date from player.statistics and rating so leaderboards are read without scanning all players.';
+comment on column leaderboard.tournament_points is 'Sum of points of tournament finishes: 3 for first, 2 for second and 1 for third place.';
+comment on column leaderboard.rating is 'Rating of player in sport, null until first rated match.';
+
+create index leaderboard_win_ratio_index on leaderboard (sport, win_ratio desc, matches desc);
+create index leaderboard_matches_index on leaderboard (sport, matches desc);
+create index leaderboard_tournament_points_index on leaderboard (sport, tournament_points desc, tournaments desc);
+create index leaderboard_rating_index on leaderboard (sport, rating desc);
+
+-- fill leaderboard from existing statistics and ratings
+insert into leaderboard (player_id, sport, win_ratio, matches, tournaments, tournament_points, rating, updated_at)
+select pl.user_id, st.key,
+    coalesce((st.value->>'winRatio')::numeric, 0),
+    case when jsonb_typeof(st.value->'matches') = 'array' then jsonb_array_length(st.value->'matches') else 0 end,
+    case when jsonb_typeof(st.value->'tournaments') = 'array' then jsonb_array_length(st.value->'tournaments') else 0 end,
+    coalesce((select sum(greatest(0, 4 - (t->>'ranking')::int))
+        from jsonb_array_elements(case when jsonb_typeof(st.value->'tournaments') = 'array' then st.value->'tournaments' else '[]'::jsonb end) t
+        where (t->>'ranking')::int > 0), 0),
+    ra.rating,
+    now()
+from player pl
+cross join lateral jsonb_each(case when jsonb_typeof(pl.statistics) = 'object' then pl.statistics else '{}'::jsonb end) st
+left join rating ra on ra.player_id = pl.user_id and ra.sport = st.key;
+
+insert into leaderboard (player_id, sport, rating, updated_at)
+select ra.player_id, ra.sport, ra.rating, now()
+from rating ra
+on conflict do nothing;
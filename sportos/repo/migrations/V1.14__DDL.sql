@@ -0,0 +1,86 @@
+-- player_match ddl
+CREATE TABLE player_match (
+    match_id character varying(40) not null,
+    player_id character varying(40) not null,
+    sport character varying(40) not null,
+    played_at timestamp(6) with time zone not null,
+    side smallint not null,
+    points_for integer not null,
+    points_against integer not null,
+    outcome character varying(10) not null,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    constraint pk_player_match PRIMARY KEY (match_id, player_id),
+    constraint fk_player_match_match_id foreign key (match_id)
+    references match (match_id) match simple,
+    constraint fk_player_match_player_id foreign key (player_id)
+    references player (user_id) match simple
+);
+
+comment on table player_match is 'Result of finished match for every its player, replaces matches and win ratio in player.statistics.';
+comment on column player_match.side is 'Index of team (0 or 1) player played for.';
+comment on column player_match.outcome is 'WIN, DRAW or LOSS from view of player.';
+
+create index player_match_player_index on player_match (player_id, sport, played_at desc);
+
+-- player_tournament ddl
+CREATE TABLE player_tournament (
+    event_id character varying(40) not null,
+    player_id character varying(40) not null,
+    sport character varying(40) not null,
+    team_id character varying(40) not null,
+    ranking integer not null,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    constraint pk_player_tournament PRIMARY KEY (event_id, player_id),
+    constraint fk_player_tournament_event_id foreign key (event_id)
+    references event (event_id) match simple,
+    constraint fk_player_tournament_player_id foreign key (player_id)
+    references player (user_id) match simple
+);
+
+comment on table player_tournament is 'Ranking of team of player in finished tournament, replaces tournaments in player.statistics.';
+
+create index player_tournament_player_index on player_tournament (player_id, sport);
+
+-- results are taken from finished matches, sides are in match_player
+insert into player_match (match_id, player_id, sport, played_at, side, points_for, points_against, outcome, created_at, created_by)
+select ma.match_id, mp.player_id, ma.sport, ma.start_time, mp.side,
+    case when mp.side = 0 then r.p1 else r.p2 end,
+    case when mp.side = 0 then r.p2 else r.p1 end,
+    case when r.p1 = r.p2 then 'DRAW' when (r.p1 > r.p2) = (mp.side = 0) then 'WIN' else 'LOSS' end,
+    coalesce(ma.updated_at, ma.created_at), coalesce(ma.updated_by, ma.created_by)
+from match ma
+cross join lateral (
+    select case when ma.result ~ '^[0-9]+:[0-9]+$' then split_part(ma.result, ':', 1)::int end as p1,
+        case when ma.result ~ '^[0-9]+:[0-9]+$' then split_part(ma.result, ':', 2)::int end as p2
+) r
+inner join match_player mp on mp.match_id = ma.match_id and mp.side in (0, 1)
+inner join player pl on pl.user_id = mp.player_id
+where ma.status = 'FINISHED' and ma.start_time is not null and r.p1 is not null
+on conflict do nothing;
+
+-- rankings are taken from standings of finished tournaments, teams of event are matched by name
+insert into player_tournament (event_id, player_id, sport, team_id, ranking, created_at, created_by)
+select ev.event_id, tm.player_id, ev.sport, tr."teamId", st.ranking,
+    coalesce(ev.updated_at, ev.created_at), coalesce(ev.updated_by, ev.created_by)
+from event ev
+cross join lateral jsonb_to_recordset(case when jsonb_typeof(ev.teams) = 'array' then ev.teams else '[]'::jsonb end) tr(name text, "teamId" text)
+cross join lateral jsonb_to_recordset(case when jsonb_typeof(ev.tournament->'standings') = 'array' then ev.tournament->'standings' else '[]'::jsonb end) st("teamName" text, ranking int)
+inner join team_member tm on tm.team_id = tr."teamId"
+inner join player pl on pl.user_id = tm.player_id
+where ev.status = 'FINISHED' and st."teamName" = tr.name and st.ranking is not null
+on conflict do nothing;
+
+-- leaderboard is recomputed from results so it is same as aggregates of player_match, players with matches
+-- that couldn't be backfilled keep statistics from player.statistics
+update leaderboard lb set matches = pm.matches, win_ratio = pm.win_ratio
+from (
+    select player_id, sport, count(*) as matches, (count(*) filter (where outcome = 'WIN'))::numeric / count(*) as win_ratio
+    from player_match
+    group by player_id, sport
+) pm
+where lb.player_id = pm.player_id and lb.sport = pm.sport and pm.matches >= lb.matches;
+
+-- player.statistics is kept until backfill is checked against it, it is dropped in V1.18
+comment on column player.statistics is 'Replaced by player_match and player_tournament, no longer updated.';
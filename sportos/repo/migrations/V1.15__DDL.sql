@@ -0,0 +1,4 @@
+-- results with sets or periods, like 2:1 (6:4 3:6 7:5), don't fit into 20 characters
+alter table match alter column result type character varying(100);
+
+comment on column match.result is 'Score of side one and two, like 3:1, optionally with sets or periods in brackets and FF or WO for forfeited match.';
@@ -0,0 +1,34 @@
+create sequence match_result_id_seq
+    start with 1000000000
+    increment by 1
+    no minvalue
+    no maxvalue
+    cache 1;
+
+-- match_result ddl
+CREATE TABLE match_result (
+    result_id character varying(40) not null DEFAULT nextval('match_result_id_seq'::regclass),
+    match_id character varying(40) not null,
+    result character varying(100) not null,
+    side smallint not null,
+    status character varying(20) not null,
+    deadline timestamp(6) with time zone not null,
+    disputed_by character varying(40),
+    dispute_reason text,
+    review_note text,
+    created_at timestamp(6) with time zone not null,
+    created_by character varying(40) not null,
+    updated_at timestamp(6) with time zone,
+    updated_by character varying(40),
+    constraint pk_match_result PRIMARY KEY (result_id),
+    constraint fk_match_result_match_id foreign key (match_id)
+    references match (match_id) match simple
+);
+
+comment on table match_result is 'Results submitted by players, match is finished only after players of other side confirm result, it is auto confirmed at deadline or dispute is resolved in backoffice.';
+comment on column match_result.side is 'Index of team (0 or 1) of player that submitted result.';
+comment on column match_result.status is 'PENDING, CONFIRMED, AUTO_CONFIRMED, DISPUTED, RESOLVED or REJECTED.';
+comment on column match_result.review_note is 'Explanation of backoffice decision about disputed result.';
+
+create index match_result_pending_index on match_result (status, deadline);
+create index match_result_match_index on match_result (match_id, created_at);
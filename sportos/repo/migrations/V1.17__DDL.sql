@@ -0,0 +1,4 @@
+-- refresh tokens carry token version of user, logout, password reset and account deletion increment it
+alter table "user" add column token_version integer not null default 0;
+
+comment on column "user".token_version is 'Incremented when tokens of user are revoked, refresh tokens with older version are rejected.';
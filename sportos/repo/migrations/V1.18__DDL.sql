@@ -0,0 +1,26 @@
+-- player.statistics is dropped after matches and tournaments backfilled in V1.14 are counted against it,
+-- statistics of players with more matches or tournaments than were backfilled are archived first
+CREATE TABLE player_statistics_archive (
+    player_id character varying(40) not null,
+    statistics jsonb not null,
+    archived_at timestamp(6) with time zone not null default now(),
+    constraint pk_player_statistics_archive PRIMARY KEY (player_id)
+);
+
+comment on table player_statistics_archive is 'Statistics of players that had matches or tournaments which could not be moved to player_match and player_tournament.';
+
+insert into player_statistics_archive (player_id, statistics)
+select pl.user_id, pl.statistics
+from player pl
+where jsonb_typeof(pl.statistics) = 'object' and exists (
+    select 1
+    from jsonb_each(pl.statistics) st
+    where jsonb_typeof(st.value) = 'object' and (
+        (case when jsonb_typeof(st.value->'matches') = 'array' then jsonb_array_length(st.value->'matches') else 0 end) >
+            (select count(*) from player_match pm where pm.player_id = pl.user_id and pm.sport = st.key)
+        or (case when jsonb_typeof(st.value->'tournaments') = 'array' then jsonb_array_length(st.value->'tournaments') else 0 end) >
+            (select count(*) from player_tournament pt where pt.player_id = pl.user_id and pt.sport = st.key)
+    )
+);
+
+alter table player drop column statistics;
@@ -0,0 +1,11 @@
+package migrations
+
+import (
+	"database/sql"
+	"io/fs"
+)
+
+// NewMigratorFS creates migrator of migrations in fsys instead of embedded ones
+func NewMigratorFS(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	return newMigrator(db, fsys)
+}
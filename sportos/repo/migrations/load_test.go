@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadSortsByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"V1.10__later.sql":   {Data: []byte("select 10;")},
+		"V1.9__earlier.sql":  {Data: []byte("select 9;")},
+		"U1.9__earlier.sql":  {Data: []byte("select -9;")},
+		"V2__next_major.sql": {Data: []byte("select 2;")},
+	}
+	migrations, err := load(fsys)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	versions := []string{}
+	for _, mig := range migrations {
+		versions = append(versions, mig.Version)
+	}
+	if strings.Join(versions, ",") != "1.9,1.10,2" {
+		t.Errorf("expected numeric version order, got %v", versions)
+	}
+	if migrations[0].Down != "select -9;" || migrations[1].Down != "" || migrations[0].Description != "earlier" {
+		t.Errorf("expected undo file only for 1.9, got %+v", migrations)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"invalid name":           {"V1.00-DDL.sql": {Data: []byte("select 1;")}},
+		"undo without migration": {"U1.00__DDL.sql": {Data: []byte("select 1;")}},
+	}
+	for name, fsys := range cases {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsHaveUndo(t *testing.T) {
+	migrations, err := load(files)
+	if err != nil {
+		t.Fatalf("load embedded migrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != BASELINE_VERSION {
+		t.Fatalf("expected first migration to be baseline %s, got %+v", BASELINE_VERSION, migrations)
+	}
+	for _, mig := range migrations {
+		if mig.Down == "" {
+			t.Errorf("migration %s doesn't have undo file", mig.Version)
+		}
+	}
+}
+
+func TestVerifyDetectsChangedChecksum(t *testing.T) {
+	m := &Migrator{migrations: []Migration{{Version: "1.00", Checksum: "abc"}}}
+	if err := m.verify(map[string]applied{"1.00": {checksum: "abc"}}); err != nil {
+		t.Errorf("unchanged migration: %v", err)
+	}
+	if err := m.verify(map[string]applied{"1.00": {checksum: "def"}}); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("changed migration: expected checksum error, got %v", err)
+	}
+	if err := m.verify(map[string]applied{"1.01": {checksum: "abc"}}); err == nil {
+		t.Errorf("applied migration missing in binary: expected error")
+	}
+}
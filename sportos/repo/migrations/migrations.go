@@ -0,0 +1,422 @@
+// Package migrations contains versioned SQL schema migrations and runner that applies them.
+//
+// Migration files are embedded into binary and named like Flyway migrations:
+// V<version>__<description>.sql applies migration, U<version>__<description>.sql undoes it.
+// Applied migrations are recorded in schema_migrations table together with checksum of the file,
+// and runner holds postgres advisory lock so only one instance migrates database at a time.
+//
+// Databases created before migrations were introduced already have schema of BASELINE_VERSION, Up records
+// it as applied without running it when it finds such schema, Baseline does the same for any version.
+package migrations
+
+import (
+	L "backend/internal/logging"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// arbitrary key of advisory lock held while migrating
+const advisoryLockKey = 7243160
+
+// BASELINE_VERSION is migration that created schema of databases that existed before migrations
+const BASELINE_VERSION = "1.00"
+
+// baselineTable is table created by BASELINE_VERSION, database that has it but has no applied
+// migrations was created before migrations
+const baselineTable = `"user"`
+
+const schema_migrations_ddl = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version character varying(40) not null,
+		description character varying(200) not null,
+		checksum character varying(64) not null,
+		execution_ms integer not null,
+		applied_at timestamp(6) with time zone not null,
+		constraint pk_schema_migrations PRIMARY KEY (version)
+	);
+`
+
+var fileNameRegex = regexp.MustCompile(`^([VU])(\d+(?:\.\d+)*)__(\w+)\.sql$`)
+
+// Migration is single versioned migration, Down is empty if migration can't be undone
+type Migration struct {
+	Version     string
+	Description string
+	Checksum    string
+	Up          string
+	Down        string
+
+	version []int
+}
+
+// Status is migration together with information whether it is applied
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+	// false if file of applied migration was changed after it was applied
+	ChecksumOk bool
+}
+
+type applied struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies embedded migrations to database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator loads embedded migrations
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	return newMigrator(db, files)
+}
+
+func newMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations in version order and returns number of applied migrations
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			if done, err = m.baselineExisting(ctx, conn); err != nil {
+				return err
+			}
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+		latest := m.latestApplied(done)
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			if latest != nil && compareVersions(mig.version, latest.version) < 0 {
+				return fmt.Errorf("migration %s is older than latest applied migration %s", mig.Version, latest.Version)
+			}
+			L.L.Info("Applying migration", L.String("version", mig.Version), L.String("description", mig.Description))
+			start := time.Now()
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+					return fmt.Errorf("migration %s failed: %w", mig.Version, err)
+				}
+				_, err := tx.ExecContext(ctx, `insert into schema_migrations (version, description, checksum, execution_ms, applied_at) values ($1, $2, $3, $4, $5)`,
+					mig.Version, mig.Description, mig.Checksum, time.Since(start).Milliseconds(), time.Now().UTC())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down undoes latest applied migration and returns its version
+func (m *Migrator) Down(ctx context.Context) (string, error) {
+	version := ""
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(done); err != nil {
+			return err
+		}
+		mig := m.latestApplied(done)
+		if mig == nil {
+			return fmt.Errorf("there are no applied migrations")
+		}
+		if mig.Down == "" {
+			return fmt.Errorf("migration %s doesn't have undo file", mig.Version)
+		}
+		L.L.Info("Undoing migration", L.String("version", mig.Version), L.String("description", mig.Description))
+		err = inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+				return fmt.Errorf("undo of migration %s failed: %w", mig.Version, err)
+			}
+			_, err := tx.ExecContext(ctx, `delete from schema_migrations where version=$1`, mig.Version)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		version = mig.Version
+		return nil
+	})
+	return version, err
+}
+
+// Baseline records all migrations up to version as applied without running them and returns their number.
+// It is used for database whose schema was created (or later changed) by hand, so it can be migrated from
+// version on. It fails if database already has applied migrations
+func (m *Migrator) Baseline(ctx context.Context, version string) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(done) > 0 {
+			return fmt.Errorf("database already has %d applied migrations", len(done))
+		}
+		count, err = m.baseline(ctx, conn, version)
+		return err
+	})
+	return count, err
+}
+
+// Status returns all migrations with information whether they are applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	results := []Status{}
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			st := Status{Migration: mig, ChecksumOk: true}
+			if a, ok := done[mig.Version]; ok {
+				appliedAt := a.appliedAt
+				st.AppliedAt = &appliedAt
+				st.ChecksumOk = a.checksum == mig.Checksum
+			}
+			results = append(results, st)
+		}
+		return nil
+	})
+	return results, err
+}
+
+////////////////////////////////////////////////UTIL/////////////////////////////////////////////////////////////////////////////////////
+
+// withLock runs f on single connection that holds advisory lock, lock is session level so it has to be released on same connection
+func (m *Migrator) withLock(ctx context.Context, f func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `select pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `select pg_advisory_unlock($1)`, advisoryLockKey)
+
+	if _, err := conn.ExecContext(ctx, schema_migrations_ddl); err != nil {
+		return err
+	}
+	return f(conn)
+}
+
+// baselineExisting records BASELINE_VERSION as applied if database has schema that was created before
+// migrations, it returns applied migrations after that
+func (m *Migrator) baselineExisting(ctx context.Context, conn *sql.Conn) (map[string]applied, error) {
+	exists := false
+	if err := conn.QueryRowContext(ctx, `select to_regclass($1) is not null`, baselineTable).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return map[string]applied{}, nil
+	}
+	L.L.Warn("Database was created before migrations, recording baseline", L.String("version", BASELINE_VERSION))
+	if _, err := m.baseline(ctx, conn, BASELINE_VERSION); err != nil {
+		return nil, err
+	}
+	return m.applied(ctx, conn)
+}
+
+// baseline records migrations up to version as applied without running them
+func (m *Migrator) baseline(ctx context.Context, conn *sql.Conn, version string) (int, error) {
+	found := false
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("migration %s doesn't exist", version)
+	}
+	count := 0
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		for _, mig := range m.migrations {
+			if compareVersions(mig.version, parseVersion(version)) > 0 {
+				break
+			}
+			_, err := tx.ExecContext(ctx, `insert into schema_migrations (version, description, checksum, execution_ms, applied_at) values ($1, $2, $3, 0, $4)`,
+				mig.Version, mig.Description, mig.Checksum, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[string]applied, error) {
+	rows, err := conn.QueryContext(ctx, `select version, checksum, applied_at from schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := map[string]applied{}
+	for rows.Next() {
+		var version string
+		a := applied{}
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		results[version] = a
+	}
+	return results, rows.Err()
+}
+
+// verify checks that every applied migration still exists and wasn't changed
+func (m *Migrator) verify(done map[string]applied) error {
+	byVersion := map[string]Migration{}
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+	for version, a := range done {
+		mig, ok := byVersion[version]
+		if !ok {
+			return fmt.Errorf("applied migration %s doesn't exist in binary", version)
+		}
+		if mig.Checksum != a.checksum {
+			return fmt.Errorf("checksum of migration %s doesn't match applied checksum", version)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) latestApplied(done map[string]applied) *Migration {
+	var latest *Migration
+	for i := range m.migrations {
+		if _, ok := done[m.migrations[i].Version]; ok {
+			latest = &m.migrations[i]
+		}
+	}
+	return latest
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, f func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := f(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// load reads migration files and sorts them by version
+func load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[string]*Migration{}
+	downs := map[string]string{}
+	for _, name := range names {
+		match := fileNameRegex.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("migration file name %s isn't valid", name)
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		kind, version, description := match[1], match[2], match[3]
+		if kind == "U" {
+			downs[version] = string(content)
+			continue
+		}
+		if _, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("migration version %s is duplicated", version)
+		}
+		sum := sha256.Sum256(content)
+		byVersion[version] = &Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(description, "_", " "),
+			Checksum:    hex.EncodeToString(sum[:]),
+			Up:          string(content),
+			version:     parseVersion(version),
+		}
+	}
+
+	migrations := []Migration{}
+	for version, down := range downs {
+		mig, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("undo migration %s doesn't have migration", version)
+		}
+		mig.Down = down
+	}
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return compareVersions(migrations[i].version, migrations[j].version) < 0
+	})
+	return migrations, nil
+}
+
+func parseVersion(version string) []int {
+	parts := strings.Split(version, ".")
+	result := make([]int, len(parts))
+	for i, p := range parts {
+		result[i], _ = strconv.Atoi(p)
+	}
+	return result
+}
+
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		x, y := 0, 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
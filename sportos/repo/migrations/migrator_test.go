@@ -0,0 +1,155 @@
+package migrations_test
+
+import (
+	"backend/sportos/api/apitest"
+	"backend/sportos/repo/migrations"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	apitest.Main(m)
+}
+
+func TestUpDownRoundTrip(t *testing.T) {
+	db := apitest.NewDB(t)
+	ctx := context.Background()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if applied != len(statuses) {
+		t.Fatalf("expected all %d migrations to be applied, got %d", len(statuses), applied)
+	}
+	if n, err := migrator.Up(ctx); err != nil || n != 0 {
+		t.Errorf("second up: expected nothing to apply, got %d, %v", n, err)
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		version, err := migrator.Down(ctx)
+		if err != nil {
+			t.Fatalf("down of %s: %v", statuses[i].Version, err)
+		}
+		if version != statuses[i].Version {
+			t.Fatalf("expected down of %s, got %s", statuses[i].Version, version)
+		}
+	}
+	tables := 0
+	if err := db.QueryRow(`select count(*) from information_schema.tables where table_schema = 'public' and table_name <> 'schema_migrations'`).Scan(&tables); err != nil {
+		t.Fatalf("count tables: %v", err)
+	}
+	if tables != 0 {
+		t.Errorf("expected undo files to drop all tables, %d are left", tables)
+	}
+	if n, err := migrator.Up(ctx); err != nil || n != len(statuses) {
+		t.Errorf("up after down: expected %d migrations, got %d, %v", len(statuses), n, err)
+	}
+}
+
+func TestUpRefusesChangedMigration(t *testing.T) {
+	db := apitest.NewDB(t)
+	ctx := context.Background()
+
+	original, _ := migrations.NewMigratorFS(db, fstest.MapFS{"V1.00__DDL.sql": {Data: []byte("create table t1 (id int);")}})
+	if _, err := original.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	changed, _ := migrations.NewMigratorFS(db, fstest.MapFS{
+		"V1.00__DDL.sql": {Data: []byte("create table t1 (id bigint);")},
+		"V1.01__DDL.sql": {Data: []byte("create table t2 (id int);")},
+	})
+	if _, err := changed.Up(ctx); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+	exists := false
+	db.QueryRow(`select to_regclass('t2') is not null`).Scan(&exists)
+	if exists {
+		t.Errorf("expected no migration to be applied after checksum error")
+	}
+}
+
+func TestUpBaselinesExistingDatabase(t *testing.T) {
+	db := apitest.NewDB(t)
+	ctx := context.Background()
+
+	// schema created before migrations
+	if _, err := db.Exec(`create table "user" (user_id varchar(40))`); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+	migrator, _ := migrations.NewMigratorFS(db, fstest.MapFS{
+		"V1.00__DDL.sql": {Data: []byte(`create table "user" (user_id varchar(40));`)},
+		"V1.01__DDL.sql": {Data: []byte("create table t2 (id int);")},
+	})
+	n, err := migrator.Up(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("up: expected only 1.01 to be applied, got %d, %v", n, err)
+	}
+	statuses, _ := migrator.Status(ctx)
+	if len(statuses) != 2 || statuses[0].AppliedAt == nil || statuses[1].AppliedAt == nil {
+		t.Errorf("expected baseline and 1.01 to be applied, got %+v", statuses)
+	}
+	if _, err := migrator.Baseline(ctx, "1.01"); err == nil {
+		t.Errorf("baseline of migrated database: expected error")
+	}
+}
+
+func TestBaselineRecordsMigrationsUpToVersion(t *testing.T) {
+	db := apitest.NewDB(t)
+	ctx := context.Background()
+
+	migrator, _ := migrations.NewMigratorFS(db, fstest.MapFS{
+		"V1.00__DDL.sql": {Data: []byte("create table t1 (id int);")},
+		"V1.01__DDL.sql": {Data: []byte("create table t2 (id int);")},
+		"V1.02__DDL.sql": {Data: []byte("create table t3 (id int);")},
+	})
+	if _, err := migrator.Baseline(ctx, "1.05"); err == nil {
+		t.Errorf("baseline of unknown version: expected error")
+	}
+	if n, err := migrator.Baseline(ctx, "1.01"); err != nil || n != 2 {
+		t.Fatalf("baseline: expected 2 recorded migrations, got %d, %v", n, err)
+	}
+	if n, err := migrator.Up(ctx); err != nil || n != 1 {
+		t.Fatalf("up after baseline: expected only 1.02 to be applied, got %d, %v", n, err)
+	}
+}
+
+func TestUpWaitsForLock(t *testing.T) {
+	db := apitest.NewDB(t)
+	ctx := context.Background()
+
+	// other instance is migrating
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `select pg_advisory_lock(7243160)`); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	migrator, _ := migrations.NewMigratorFS(db, fstest.MapFS{"V1.00__DDL.sql": {Data: []byte("create table t1 (id int);")}})
+	waiting, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	if _, err := migrator.Up(waiting); err == nil {
+		t.Fatalf("expected up to wait for lock until context is done")
+	}
+
+	if _, err := conn.ExecContext(ctx, `select pg_advisory_unlock(7243160)`); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if n, err := migrator.Up(ctx); err != nil || n != 1 {
+		t.Errorf("up after lock is released: expected 1 migration, got %d, %v", n, err)
+	}
+}
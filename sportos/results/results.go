@@ -0,0 +1,84 @@
+// Package results finishes matches with results that both sides agree on.
+//
+// Player of one side submits result of full match and players of other side confirm or dispute it until
+// deadline. Match is finished (and MatchFinished event updates statistics) only when result is confirmed,
+// when Worker auto confirms result that wasn't answered until deadline or when backoffice resolves dispute.
+package results
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/events"
+	"backend/sportos/notify"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"time"
+)
+
+// Config of result confirmation, filled from flags
+type Config struct {
+	// Window is how long players of other side have to confirm or dispute result
+	Window   time.Duration
+	Interval time.Duration
+}
+
+var config = Config{Window: DEFAULT_CONFIRM_WINDOW, Interval: DEFAULT_WORKER_INTERVAL}
+
+// Init sets config used by results submitted and workers created after it
+func Init(c Config) {
+	config = c
+	L.L.Info("results params", L.Duration("results.window", c.Window), L.Duration("results.interval", c.Interval))
+}
+
+// Deadline returns time until which result submitted at now can be confirmed or disputed
+func Deadline(now time.Time) time.Time {
+	window := config.Window
+	if window <= 0 {
+		window = DEFAULT_CONFIRM_WINDOW
+	}
+	return now.Add(window)
+}
+
+// Validate checks that result fits scoring of sport and has winner if sport has no draws,
+// it returns result in canonical format, like 2:1 (6:4 3:6 7:5)
+func Validate(sport, result string) (string, error) {
+	sp, err := DR.GetSportByName(sport)
+	if err != nil {
+		return "", err
+	}
+	score, err := DR.ParseScore(result)
+	if err != nil {
+		return "", err
+	}
+	if err = sp.Scoring.Validate(score); err != nil {
+		return "", err
+	}
+	if _, err = sp.Scoring.Winner(score); err != nil {
+		return "", err
+	}
+	return score.String(), nil
+}
+
+// Finish updates result mr with up (status and optionally corrected result) and finishes its match with
+// that result, MatchFinished event and notifications for players are stored in transaction qa
+func Finish(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, notes *notify.Batch, mr DR.MatchResult, up DR.MatchResultUpdateParams, by *string) (DR.Match, error) {
+	result := mr.Result
+	if up.Result != nil {
+		result = *up.Result
+	}
+	up.Id = mr.ResultId
+	if err := Repo.MatchResultCrud.Update(ctx, up, qa, by); err != nil {
+		return DR.Match{}, err
+	}
+	fin := DR.MS_FINISHED
+	match, err := Repo.MatchCrud.Update(ctx, DR.MatchUpdateParams{Id: mr.MatchId, Status: &fin, Result: &result}, qa, by)
+	if err != nil {
+		return match, err
+	}
+	if err = events.Publish(ctx, Repo, qa, events.MatchFinished{MatchId: mr.MatchId, Result: result}); err != nil {
+		return match, err
+	}
+	place, _ := Repo.GetNameForId(ctx, match.PlaceId)
+	n := DR.Notification{Type: DR.NT_MATCH_FINISHED, ReferenceId: match.MatchId, Message: "Your match at " + place + " finished " + result}
+	return match, notes.Store(ctx, Repo, qa, n, match, match.Players...)
+}
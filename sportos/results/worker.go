@@ -0,0 +1,77 @@
+package results
+
+import (
+	L "backend/internal/logging"
+	"backend/internal/periodic"
+	"backend/sportos/notify"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"time"
+)
+
+const (
+	DEFAULT_CONFIRM_WINDOW  = 48 * time.Hour
+	DEFAULT_WORKER_INTERVAL = time.Minute
+	workerBatchSize         = 20
+)
+
+// Worker periodically auto confirms results that weren't confirmed nor disputed until deadline
+type Worker struct {
+	Repo     *crud.Repo
+	Interval time.Duration
+	// Hub sends notifications about finished matches to connected players
+	Hub *notify.Hub
+
+	runner periodic.Runner
+}
+
+// NewWorker creates worker with interval from config
+func NewWorker(Repo *crud.Repo, hub *notify.Hub) *Worker {
+	interval := config.Interval
+	if interval <= 0 {
+		interval = DEFAULT_WORKER_INTERVAL
+	}
+	return &Worker{
+		Repo:     Repo,
+		Interval: interval,
+		Hub:      hub,
+	}
+}
+
+// Start runs worker in background until Stop is called
+func (w *Worker) Start() {
+	w.runner.Start("Worker.ProcessExpired", w.Interval, w.ProcessExpired)
+}
+
+// Stop waits for batch in progress to finish
+func (w *Worker) Stop() {
+	w.runner.Stop()
+}
+
+// ProcessExpired auto confirms one batch of results whose deadline passed and returns number of finished matches
+func (w *Worker) ProcessExpired(ctx context.Context) (int, error) {
+	tx, err := w.Repo.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	expired, err := w.Repo.MatchResultCrud.GetExpired(ctx, workerBatchSize, tx)
+	if err != nil {
+		return 0, err
+	}
+	var notes notify.Batch
+	status := DR.MRS_AUTO_CONFIRMED
+	for _, mr := range expired {
+		if _, err = Finish(ctx, w.Repo, tx, &notes, mr, DR.MatchResultUpdateParams{Status: &status}, nil); err != nil {
+			return 0, err
+		}
+		L.L.Info("Result auto confirmed", L.String("resultId", mr.ResultId), L.String("matchId", mr.MatchId))
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	notes.Send(notify.WithHub(ctx, w.Hub))
+	return len(expired), nil
+}
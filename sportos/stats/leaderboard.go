@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+)
+
+// Leaderboard returns leaderboard row of player in sport from aggregate of matches and tournament
+// rankings of player, r is nil if player isn't rated in sport yet
+func Leaderboard(playerId, sport string, summary DR.PlayerMatchSummary, tournaments []DR.PlayerTournament, r *DR.Rating) DR.Leaderboard {
+	lb := DR.Leaderboard{
+		PlayerId:    playerId,
+		Sport:       sport,
+		WinRatio:    summary.WinRatio(),
+		Matches:     summary.Matches,
+		Tournaments: len(tournaments),
+	}
+	for _, t := range tournaments {
+		lb.TournamentPoints += DR.TournamentPoints(t.Ranking)
+	}
+	if r != nil {
+		rating := r.Rating
+		lb.Rating = &rating
+	}
+	return lb
+}
+
+// refreshLeaderboard stores leaderboard row of player in sport after matches, tournaments or rating of player changed
+func refreshLeaderboard(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, playerId, sport string) error {
+	summary, err := Repo.PlayerMatchCrud.Summary(ctx, playerId, sport, qa)
+	if err != nil {
+		return err
+	}
+	tournaments, err := Repo.PlayerTournamentCrud.Search(ctx, DR.PlayerTournamentSearchParams{PlayerId: &playerId, Sport: &sport}, qa)
+	if err != nil {
+		return err
+	}
+	ratings, err := Repo.RatingCrud.Search(ctx, DR.RatingSearchParams{PlayerId: &playerId, Sport: &sport}, qa)
+	if err != nil {
+		return err
+	}
+	var r *DR.Rating
+	if len(ratings) > 0 {
+		r = &ratings[0]
+	}
+	return Repo.LeaderboardCrud.Upsert(ctx, Leaderboard(playerId, sport, summary, tournaments, r), qa)
+}
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"backend/sportos/events"
+	"backend/sportos/rating"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+)
+
+// MatchRated updates ratings of all players of finished match in its sport and stores change of every
+// rating in history. Unique history of player and match makes sure match is rated only once, rating of
+// player whose history of match is already stored isn't changed again
+func MatchRated(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+	var ev events.MatchFinished
+	if err := events.Decode(oe, &ev); err != nil {
+		return err
+	}
+	match, points, err := finishedMatch(ctx, Repo, qa, ev)
+	if err != nil {
+		return err
+	}
+	sport, err := DR.GetSportByName(match.Sport)
+	if err != nil {
+		return err
+	}
+	var before [2][]DR.Rating
+	for side := 0; side < 2; side++ {
+		for _, id := range match.Teams[side] {
+			ra, err := Repo.RatingCrud.GetOrCreate(ctx, rating.New(id, sport), qa, nil)
+			if err != nil {
+				return err
+			}
+			before[side] = append(before[side], ra)
+		}
+	}
+	var scores [2]float64
+	scores[0], scores[1] = rating.Scores(points[0], points[1])
+	after := rating.Match(rating.SystemOf(sport), before, scores[0])
+	for side := 0; side < 2; side++ {
+		for i, ra := range after[side] {
+			old := before[side][i]
+			_, created, err := Repo.RatingHistoryCrud.Create(ctx, DR.RatingHistory{
+				RatingId:        ra.RatingId,
+				PlayerId:        ra.PlayerId,
+				Sport:           ra.Sport,
+				MatchId:         match.MatchId,
+				Score:           scores[side],
+				RatingBefore:    old.Rating,
+				RatingAfter:     ra.Rating,
+				DeviationBefore: old.Deviation,
+				DeviationAfter:  ra.Deviation,
+			}, qa, nil)
+			if err != nil {
+				return err
+			}
+			if !created {
+				continue
+			}
+			up := DR.RatingUpdateParams{Id: ra.RatingId, Rating: &ra.Rating, Deviation: &ra.Deviation, Volatility: &ra.Volatility, Matches: &ra.Matches}
+			if _, err = Repo.RatingCrud.Update(ctx, up, qa, nil); err != nil {
+				return err
+			}
+			if err = refreshLeaderboard(ctx, Repo, qa, ra.PlayerId, ra.Sport); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
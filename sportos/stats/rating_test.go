@@ -0,0 +1,60 @@
+package stats_test
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/events"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"backend/sportos/stats"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestMatchIsRatedOnce(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+
+	match, err := Repo.MatchCrud.Create(ctx, DR.Match{Sport: "Tennis", Status: DR.MS_FINISHED}, nil, nil)
+	if err != nil {
+		t.Fatalf("create match: %v", err)
+	}
+	for side, playerId := range []string{"ana", "bob"} {
+		side := side
+		if _, err := Repo.MatchPlayerCrud.Create(ctx, DR.MatchPlayer{MatchId: match.MatchId, PlayerId: playerId, Role: DR.MR_PLAYER, Side: &side}, nil, nil); err != nil {
+			t.Fatalf("add player %s: %v", playerId, err)
+		}
+	}
+	payload, _ := json.Marshal(events.MatchFinished{MatchId: match.MatchId, Result: "2:0 (6:3 6:4)"})
+	oe := DR.OutboxEvent{Type: DR.OE_MATCH_FINISHED, AggregateId: match.MatchId, Payload: payload}
+
+	// repeated delivery of event doesn't change ratings again
+	for i := 0; i < 2; i++ {
+		if err := stats.MatchRated(ctx, Repo, nil, oe); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+	}
+
+	history, err := Repo.RatingHistoryCrud.Search(ctx, DR.RatingHistorySearchParams{}, nil)
+	if err != nil {
+		t.Fatalf("search history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected history of both players, got %+v", history)
+	}
+	for _, rh := range history {
+		ra, err := Repo.RatingCrud.GetById(ctx, rh.RatingId, nil)
+		if err != nil {
+			t.Fatalf("get rating: %v", err)
+		}
+		if ra.Matches != 1 || ra.Rating != rh.RatingAfter {
+			t.Errorf("expected rating of %s to be changed once to %v, got %v after %d matches", rh.PlayerId, rh.RatingAfter, ra.Rating, ra.Matches)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Package stats keeps statistics, ratings and leaderboards of players up to date, it subscribes to domain events
+// of finished matches and tournaments
+package stats
+
+import (
+	"backend/sportos/events"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"fmt"
+)
+
+// Subscribe registers statistics subscribers on dispatcher
+func Subscribe(d *events.Dispatcher) {
+	d.Subscribe(DR.OE_MATCH_FINISHED, MatchFinished)
+	d.Subscribe(DR.OE_MATCH_FINISHED, MatchRated)
+	d.Subscribe(DR.OE_TOURNAMENT_FINISHED, TournamentFinished)
+}
+
+// MatchFinished stores result of match for all its players and updates their leaderboards
+func MatchFinished(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+	var ev events.MatchFinished
+	if err := events.Decode(oe, &ev); err != nil {
+		return err
+	}
+	match, points, err := finishedMatch(ctx, Repo, qa, ev)
+	if err != nil {
+		return err
+	}
+	for side := 0; side < 2; side++ {
+		for _, id := range match.Teams[side] {
+			err = Repo.PlayerMatchCrud.Create(ctx, DR.PlayerMatch{
+				MatchId:       match.MatchId,
+				PlayerId:      id,
+				Sport:         match.Sport,
+				PlayedAt:      *match.StartTime,
+				Side:          side,
+				PointsFor:     points[side],
+				PointsAgainst: points[1-side],
+				Outcome:       DR.Outcome(points[side], points[1-side]),
+			}, qa, nil)
+			if err != nil {
+				return err
+			}
+			if err = refreshLeaderboard(ctx, Repo, qa, id, match.Sport); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finishedMatch returns match of event and headline score of both sides from its result, sets won in sports
+// played in sets
+func finishedMatch(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, ev events.MatchFinished) (DR.Match, [2]int, error) {
+	var points [2]int
+	match, err := Repo.MatchCrud.GetById(ctx, ev.MatchId, qa)
+	if err != nil {
+		return match, points, err
+	}
+	if len(match.Teams) != 2 {
+		return match, points, fmt.Errorf("match %s doesn't have sides of players", ev.MatchId)
+	}
+	score, err := DR.ParseScore(ev.Result)
+	if err != nil {
+		return match, points, fmt.Errorf("result %s of match %s isn't valid: %w", ev.Result, ev.MatchId, err)
+	}
+	points[0], points[1] = score.One, score.Two
+	return match, points, nil
+}
+
+// TournamentFinished stores ranking of team for every player of teams that got standing
+func TournamentFinished(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+	var ev events.TournamentFinished
+	if err := events.Decode(oe, &ev); err != nil {
+		return err
+	}
+	event, err := Repo.EventCrud.GetById(ctx, ev.EventId, qa)
+	if err != nil {
+		return err
+	}
+	if event.Tournament == nil {
+		return nil
+	}
+	for _, teamRef := range event.Teams {
+		for _, standing := range event.Tournament.Standings {
+			if standing.TeamName != teamRef.Name || standing.Ranking == nil {
+				continue
+			}
+			team, err := Repo.TeamCrud.GetById(ctx, teamRef.TeamId, qa)
+			if err != nil {
+				return err
+			}
+			for _, playerId := range team.Players {
+				err = Repo.PlayerTournamentCrud.Create(ctx, DR.PlayerTournament{
+					EventId:  event.EventId,
+					PlayerId: playerId,
+					Sport:    event.Sport,
+					TeamId:   team.TeamId,
+					Ranking:  *standing.Ranking,
+				}, qa, nil)
+				if err != nil {
+					return err
+				}
+				if err = refreshLeaderboard(ctx, Repo, qa, playerId, event.Sport); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package tournament
+
+import (
+	DR "backend/sportos/repo/dto"
+)
+
+// seedOrder returns seeds (starting from 1) in bracket order for bracket of given size (power of 2),
+// so that better seeds meet as late as possible, for 8 it is 1 8 4 5 2 7 3 6
+func seedOrder(size int) []int {
+	order := []int{1}
+	for s := 1; s < size; s *= 2 {
+		next := make([]int, 0, 2*s)
+		for _, seed := range order {
+			next = append(next, seed, 2*s+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// firstBracketRound pairs seeded teams in bracket, when number of teams isn't power of 2 best seeds get byes
+func firstBracketRound(seeds []string, stage DR.BracketStage) []DR.Pairing {
+	size := 1
+	for size < len(seeds) {
+		size *= 2
+	}
+	order := seedOrder(size)
+	pairings := []DR.Pairing{}
+	for i := 0; i < size; i += 2 {
+		one, two := order[i], order[i+1]
+		if two > len(seeds) {
+			pairings = append(pairings, DR.Pairing{TeamOne: seeds[one-1], Bye: true, Stage: stage})
+		} else {
+			pairings = append(pairings, DR.Pairing{TeamOne: seeds[one-1], TeamTwo: seeds[two-1], Stage: stage})
+		}
+	}
+	return pairings
+}
+
+// pairUp pairs neighbouring teams, winners of neighbouring games meet in next round
+func pairUp(teams []string, stage DR.BracketStage) []DR.Pairing {
+	pairings := []DR.Pairing{}
+	for i := 0; i+1 < len(teams); i += 2 {
+		pairings = append(pairings, DR.Pairing{TeamOne: teams[i], TeamTwo: teams[i+1], Stage: stage})
+	}
+	return pairings
+}
+
+// singleElimination returns next knockout round for seeded teams, rounds are knockout rounds played so far
+func singleElimination(seeds []string, rounds []DR.Round) *DR.Round {
+	if len(rounds) == 0 {
+		return &DR.Round{Pairing: firstBracketRound(seeds, DR.BS_KNOCKOUT)}
+	}
+	alive := []string{}
+	for _, p := range rounds[len(rounds)-1].Pairing {
+		w, _ := winner(p)
+		alive = append(alive, w)
+	}
+	if len(alive) < 2 {
+		return nil
+	}
+	return &DR.Round{Pairing: pairUp(alive, DR.BS_KNOCKOUT)}
+}
+
+// doubleBracket is state of double elimination: teams without loss in winners bracket order,
+// teams with one loss in losers bracket order and number of losses of every team
+type doubleBracket struct {
+	winners []string
+	losers  []string
+	losses  map[string]int
+}
+
+// replayDouble replays rounds of double elimination. Losers of winners bracket drop to end of losers bracket,
+// second loss eliminates team. Final is played between winners of both brackets, if team from losers
+// bracket wins final both teams have one loss and final is played again.
+func replayDouble(rounds []DR.Round) doubleBracket {
+	db := doubleBracket{losses: map[string]int{}}
+	for _, round := range rounds {
+		var winners, losersWinners, drops []string
+		playedWinners, final := false, false
+		played := map[string]bool{}
+		for _, p := range round.Pairing {
+			w, l := winner(p)
+			if l != "" {
+				db.losses[l]++
+			}
+			switch p.Stage {
+			case DR.BS_WINNERS:
+				playedWinners = true
+				winners = append(winners, w)
+				if l != "" {
+					drops = append(drops, l)
+				}
+			case DR.BS_LOSERS:
+				played[p.TeamOne], played[p.TeamTwo] = true, true
+				losersWinners = append(losersWinners, w)
+			case DR.BS_FINAL:
+				final = true
+				db.winners = []string{w}
+				db.losers = nil
+				if db.losses[l] < 2 {
+					db.losers = []string{l}
+				}
+			}
+		}
+		if final {
+			continue
+		}
+		if playedWinners {
+			db.winners = winners
+		}
+		// teams that waited this round play first in next one
+		losers := []string{}
+		for _, team := range db.losers {
+			if !played[team] {
+				losers = append(losers, team)
+			}
+		}
+		losers = append(losers, losersWinners...)
+		db.losers = append(losers, drops...)
+	}
+	return db
+}
+
+// doubleElimination returns next round of double elimination, winners and losers bracket games are played in the same round
+func doubleElimination(seeds []string, rounds []DR.Round) *DR.Round {
+	if len(rounds) == 0 {
+		return &DR.Round{Pairing: firstBracketRound(seeds, DR.BS_WINNERS)}
+	}
+	db := replayDouble(rounds)
+	if len(db.winners) <= 1 && len(db.losers) == 0 {
+		return nil
+	}
+	if len(db.winners) == 1 && len(db.losers) == 1 {
+		return &DR.Round{Pairing: []DR.Pairing{{TeamOne: db.winners[0], TeamTwo: db.losers[0], Stage: DR.BS_FINAL}}}
+	}
+	pairings := []DR.Pairing{}
+	if len(db.winners) > 1 {
+		pairings = append(pairings, pairUp(db.winners, DR.BS_WINNERS)...)
+	}
+	// with odd number of teams in losers bracket last team to drop waits
+	pairings = append(pairings, pairUp(db.losers, DR.BS_LOSERS)...)
+	return &DR.Round{Pairing: pairings}
+}
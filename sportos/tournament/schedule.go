@@ -0,0 +1,104 @@
+package tournament
+
+import (
+	DR "backend/sportos/repo/dto"
+)
+
+// roundRobinRounds is number of rounds in which each of n teams plays every other team once
+func roundRobinRounds(n int) int {
+	if n%2 == 1 {
+		return n
+	}
+	return n - 1
+}
+
+// circleRound returns pairings of round k of round robin made by circle method: first team stays
+// in place and other teams rotate by one place every round. With odd number of teams empty place
+// is added and team paired with it has bye.
+func circleRound(teams []string, k int) []DR.Pairing {
+	slots := append([]string{}, teams...)
+	if len(slots)%2 == 1 {
+		slots = append(slots, "")
+	}
+	m := len(slots)
+	circle := make([]string, m)
+	circle[0] = slots[0]
+	for i, team := range slots[1:] {
+		circle[1+(i+k)%(m-1)] = team
+	}
+
+	pairings := []DR.Pairing{}
+	for i := 0; i < m/2; i++ {
+		one, two := circle[i], circle[m-1-i]
+		// fixed team alternates sides
+		if i == 0 && k%2 == 1 {
+			one, two = two, one
+		}
+		switch {
+		case one == "":
+			pairings = append(pairings, DR.Pairing{TeamOne: two, Bye: true})
+		case two == "":
+			pairings = append(pairings, DR.Pairing{TeamOne: one, Bye: true})
+		default:
+			pairings = append(pairings, DR.Pairing{TeamOne: one, TeamTwo: two})
+		}
+	}
+	return pairings
+}
+
+func groupName(i int) string {
+	return string(rune('A' + i))
+}
+
+// groups splits seeded teams into n groups by snake order (A B C C B A ...), so groups are of similar strength
+func groups(teams []string, n int) [][]string {
+	gs := make([][]string, n)
+	for i, team := range teams {
+		g := i % n
+		if (i/n)%2 == 1 {
+			g = n - 1 - g
+		}
+		gs[g] = append(gs[g], team)
+	}
+	return gs
+}
+
+// groupRounds is number of rounds of group stage, groups play round robin at the same time
+func groupRounds(gs [][]string) int {
+	rounds := 0
+	for _, g := range gs {
+		if r := roundRobinRounds(len(g)); r > rounds {
+			rounds = r
+		}
+	}
+	return rounds
+}
+
+// groupRound returns round k of group stage with pairings of all groups that still have games
+func groupRound(gs [][]string, k int) *DR.Round {
+	round := DR.Round{Pairing: []DR.Pairing{}}
+	for i, g := range gs {
+		if k >= roundRobinRounds(len(g)) {
+			continue
+		}
+		for _, p := range circleRound(g, k) {
+			p.Group = groupName(i)
+			p.Stage = DR.BS_GROUP
+			round.Pairing = append(round.Pairing, p)
+		}
+	}
+	return &round
+}
+
+// qualifiers returns teams that advance from groups seeded for knockout: group winners first, then
+// runners-up and so on, so in first knockout round group winners meet teams that finished lower
+func qualifiers(t DR.Tournament, teams []string, gs [][]string) []string {
+	ranked := rankGroups(t, teams, gs)
+	seeds := []string{}
+	for pos := 0; pos < t.Advancing; pos++ {
+		for _, g := range ranked {
+			seeds = append(seeds, g[pos])
+		}
+	}
+	return seeds
+}
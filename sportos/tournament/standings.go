@@ -0,0 +1,271 @@
+package tournament
+
+import (
+	DR "backend/sportos/repo/dto"
+	"sort"
+)
+
+// result is scored game between two teams
+type result struct {
+	one, two           string
+	scoreOne, scoreTwo int
+}
+
+// record is performance of team in games
+type record struct {
+	points, played, won, drawn, lost, scoreFor, scoreAgainst int
+}
+
+// results returns scored games (without byes) of pairings accepted by match
+func results(rounds []DR.Round, match func(DR.Pairing) bool) []result {
+	res := []result{}
+	for _, round := range rounds {
+		for _, p := range round.Pairing {
+			if p.Bye || !match(p) {
+				continue
+			}
+			one, two, err := ParseScore(p.Score)
+			if err != nil {
+				continue
+			}
+			res = append(res, result{one: p.TeamOne, two: p.TeamTwo, scoreOne: one, scoreTwo: two})
+		}
+	}
+	return res
+}
+
+func allPairings(DR.Pairing) bool {
+	return true
+}
+
+func groupPairings(p DR.Pairing) bool {
+	return p.Stage == DR.BS_GROUP
+}
+
+// tally sums results of teams, games against teams that aren't in teams are skipped
+func tally(teams []string, res []result, points DR.PointsRule) map[string]*record {
+	recs := map[string]*record{}
+	for _, team := range teams {
+		recs[team] = &record{}
+	}
+	add := func(rec *record, scored, conceded int) {
+		rec.played++
+		rec.scoreFor += scored
+		rec.scoreAgainst += conceded
+		switch {
+		case scored > conceded:
+			rec.won++
+			rec.points += points.Win
+		case scored == conceded:
+			rec.drawn++
+			rec.points += points.Draw
+		default:
+			rec.lost++
+			rec.points += points.Loss
+		}
+	}
+	for _, r := range res {
+		one, okOne := recs[r.one]
+		two, okTwo := recs[r.two]
+		if !okOne || !okTwo {
+			continue
+		}
+		add(one, r.scoreOne, r.scoreTwo)
+		add(two, r.scoreTwo, r.scoreOne)
+	}
+	return recs
+}
+
+// orderByKey sorts teams by key (greater first) keeping order of teams with equal key and applies
+// breakTie to every block of teams with equal key
+func orderByKey(teams []string, key func(string) int, breakTie func([]string) []string) []string {
+	sorted := append([]string{}, teams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]) > key(sorted[j])
+	})
+	ordered := []string{}
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && key(sorted[j]) == key(sorted[i]) {
+			j++
+		}
+		block := sorted[i:j]
+		if len(block) > 1 {
+			block = breakTie(block)
+		}
+		ordered = append(ordered, block...)
+		i = j
+	}
+	return ordered
+}
+
+// ranker orders teams by points and tie breakers, teams that are still tied keep seeding order
+type ranker struct {
+	res         []result
+	recs        map[string]*record
+	points      DR.PointsRule
+	tieBreakers []DR.TieBreaker
+	seed        map[string]int
+}
+
+func newRanker(t DR.Tournament, teams []string, res []result) ranker {
+	seed := map[string]int{}
+	for i, team := range teams {
+		seed[team] = i
+	}
+	return ranker{
+		res:         res,
+		recs:        tally(teams, res, *t.Points),
+		points:      *t.Points,
+		tieBreakers: t.TieBreakers,
+		seed:        seed,
+	}
+}
+
+func (r ranker) rank(teams []string) []string {
+	sorted := append([]string{}, teams...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return r.seed[sorted[i]] < r.seed[sorted[j]]
+	})
+	return orderByKey(sorted, func(team string) int {
+		return r.recs[team].points
+	}, func(block []string) []string {
+		return r.breakTie(block, 0)
+	})
+}
+
+// breakTie orders teams with equal points by i-th and following tie breakers
+func (r ranker) breakTie(block []string, i int) []string {
+	if i >= len(r.tieBreakers) {
+		return block
+	}
+	var key func(string) int
+	switch r.tieBreakers[i] {
+	case DR.TB_HEAD_TO_HEAD:
+		// points in games between tied teams only
+		between := tally(block, r.res, r.points)
+		key = func(team string) int {
+			return between[team].points
+		}
+	case DR.TB_SCORE_DIFFERENCE:
+		key = func(team string) int {
+			return r.recs[team].scoreFor - r.recs[team].scoreAgainst
+		}
+	case DR.TB_SCORES_FOR:
+		key = func(team string) int {
+			return r.recs[team].scoreFor
+		}
+	default:
+		return r.breakTie(block, i+1)
+	}
+	return orderByKey(block, key, func(b []string) []string {
+		return r.breakTie(b, i+1)
+	})
+}
+
+// rankGroups returns teams of every group ordered by results of group stage
+func rankGroups(t DR.Tournament, teams []string, gs [][]string) [][]string {
+	r := newRanker(t, teams, results(t.Rounds, groupPairings))
+	ranked := make([][]string, len(gs))
+	for i, g := range gs {
+		ranked[i] = r.rank(g)
+	}
+	return ranked
+}
+
+// eliminations returns index of round in which team was eliminated, teams that are still in tournament aren't in map
+func eliminations(t DR.Tournament, teams []string) map[string]int {
+	elim := map[string]int{}
+	if t.Format == DR.TF_ROUND_ROBIN {
+		return elim
+	}
+	maxLosses := 1
+	if t.Format == DR.TF_DOUBLE_ELIMINATION {
+		maxLosses = 2
+	}
+	losses := map[string]int{}
+	for i, round := range t.Rounds {
+		for _, p := range round.Pairing {
+			if !isElimination(p.Stage) {
+				continue
+			}
+			if _, l := winner(p); l != "" {
+				losses[l]++
+				if losses[l] == maxLosses {
+					elim[l] = i
+				}
+			}
+		}
+	}
+	if t.Format == DR.TF_GROUPS_KNOCKOUT {
+		gs := groups(teams, t.Groups)
+		gr := groupRounds(gs)
+		if len(t.Rounds) > gr || (len(t.Rounds) == gr && isScored(t.Rounds[gr-1])) {
+			advancing := map[string]bool{}
+			for _, team := range qualifiers(t, teams, gs) {
+				advancing[team] = true
+			}
+			for _, team := range teams {
+				if !advancing[team] {
+					elim[team] = gr - 1
+				}
+			}
+		}
+	}
+	return elim
+}
+
+// standings ranks teams: teams that stayed longer in tournament are better, then teams with better position
+// in group and then teams with more points (and better tie breakers)
+func standings(t DR.Tournament, teams []string) []DR.Standing {
+	r := newRanker(t, teams, results(t.Rounds, allPairings))
+
+	groupOf := map[string]string{}
+	groupRanking := map[string]int{}
+	if t.Format == DR.TF_GROUPS_KNOCKOUT {
+		for i, g := range rankGroups(t, teams, groups(teams, t.Groups)) {
+			for pos, team := range g {
+				groupOf[team] = groupName(i)
+				groupRanking[team] = pos + 1
+			}
+		}
+	}
+
+	elim := eliminations(t, teams)
+	stayed := func(team string) int {
+		if i, ok := elim[team]; ok {
+			return i
+		}
+		return len(t.Rounds)
+	}
+	ordered := orderByKey(teams, stayed, func(block []string) []string {
+		return orderByKey(block, func(team string) int {
+			return -groupRanking[team]
+		}, r.rank)
+	})
+
+	result := []DR.Standing{}
+	for i, team := range ordered {
+		rec := r.recs[team]
+		standing := DR.Standing{
+			TeamName:     team,
+			Group:        groupOf[team],
+			Points:       new(int),
+			Ranking:      new(int),
+			Played:       rec.played,
+			Won:          rec.won,
+			Drawn:        rec.drawn,
+			Lost:         rec.lost,
+			ScoreFor:     rec.scoreFor,
+			ScoreAgainst: rec.scoreAgainst,
+		}
+		*standing.Points = rec.points
+		*standing.Ranking = i + 1
+		if pos, ok := groupRanking[team]; ok {
+			standing.GroupRanking = new(int)
+			*standing.GroupRanking = pos
+		}
+		result = append(result, standing)
+	}
+	return result
+}
@@ -0,0 +1,273 @@
+// Package tournament generates rounds and standings of tournaments.
+//
+// State of tournament is kept in DR.Tournament (rules, rounds with results and standings) and teams
+// are identified by their names, in seeding order. Next round is always derived by replaying submitted
+// rounds, so only rules and rounds have to be persisted for tournament to continue.
+//
+// Supported formats are round robin (circle method), single elimination, double elimination and
+// group stage followed by single elimination knockout.
+package tournament
+
+import (
+	DR "backend/sportos/repo/dto"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotEnoughTeams = errors.New("tournament needs at least 2 teams")
+	ErrAlreadyStarted = errors.New("tournament has already started")
+	ErrNotStarted     = errors.New("tournament hasn't started")
+	ErrOver           = errors.New("tournament is over")
+	ErrRoundMismatch  = errors.New("round doesn't match current round of tournament")
+	ErrResultMissing  = errors.New("not all results are submitted")
+	ErrResultFormat   = errors.New("result format isn't valid")
+	ErrDraw           = errors.New("elimination game can't end in a draw")
+)
+
+// maximal number of groups, groups are named by letters
+const maxGroups = 26
+
+var (
+	DefaultPoints      = DR.PointsRule{Win: 3, Draw: 1, Loss: 0}
+	DefaultTieBreakers = []DR.TieBreaker{DR.TB_HEAD_TO_HEAD, DR.TB_SCORE_DIFFERENCE, DR.TB_SCORES_FOR}
+	// DefaultAdvancing is number of teams that advance from every group if it isn't set
+	DefaultAdvancing = 2
+)
+
+// ApplyDefaults sets rules that are missing, tournaments without rules are played as round robin with 3/1/0 points
+func ApplyDefaults(t *DR.Tournament) {
+	if t.Format == "" {
+		t.Format = DR.TF_ROUND_ROBIN
+	}
+	if t.Points == nil {
+		points := DefaultPoints
+		t.Points = &points
+	}
+	if t.TieBreakers == nil {
+		t.TieBreakers = append([]DR.TieBreaker{}, DefaultTieBreakers...)
+	}
+	if t.Format == DR.TF_GROUPS_KNOCKOUT && t.Advancing == 0 {
+		t.Advancing = DefaultAdvancing
+	}
+}
+
+// ValidateRules checks rules of tournament that don't depend on teams
+func ValidateRules(t DR.Tournament) error {
+	switch t.Format {
+	case "", DR.TF_ROUND_ROBIN, DR.TF_SINGLE_ELIMINATION, DR.TF_DOUBLE_ELIMINATION, DR.TF_GROUPS_KNOCKOUT:
+	default:
+		return fmt.Errorf("unknown tournament format %s", t.Format)
+	}
+	if t.Points != nil && (t.Points.Win <= t.Points.Draw || t.Points.Draw < t.Points.Loss) {
+		return fmt.Errorf("win must bring more points than draw and draw at least as much as loss")
+	}
+	seen := map[DR.TieBreaker]bool{}
+	for _, tb := range t.TieBreakers {
+		switch tb {
+		case DR.TB_HEAD_TO_HEAD, DR.TB_SCORE_DIFFERENCE, DR.TB_SCORES_FOR:
+		default:
+			return fmt.Errorf("unknown tie breaker %s", tb)
+		}
+		if seen[tb] {
+			return fmt.Errorf("tie breaker %s is used more than once", tb)
+		}
+		seen[tb] = true
+	}
+	if t.Format == DR.TF_GROUPS_KNOCKOUT {
+		if t.Groups < 2 || t.Groups > maxGroups {
+			return fmt.Errorf("number of groups must be between 2 and %d", maxGroups)
+		}
+		if t.Advancing < 0 {
+			return fmt.Errorf("number of teams that advance from group can't be negative")
+		}
+	} else if t.Groups != 0 || t.Advancing != 0 {
+		return fmt.Errorf("groups are supported only by %s format", DR.TF_GROUPS_KNOCKOUT)
+	}
+	return nil
+}
+
+func validateTeams(t DR.Tournament, teams []string) error {
+	if len(teams) < 2 {
+		return ErrNotEnoughTeams
+	}
+	seen := map[string]bool{}
+	for _, team := range teams {
+		if team == "" {
+			return fmt.Errorf("team name can't be empty")
+		}
+		if seen[team] {
+			return fmt.Errorf("team %s is in tournament more than once", team)
+		}
+		seen[team] = true
+	}
+	if t.Format == DR.TF_GROUPS_KNOCKOUT {
+		smallest := len(teams) / t.Groups
+		if smallest < 2 {
+			return fmt.Errorf("%d groups need at least %d teams", t.Groups, 2*t.Groups)
+		}
+		if t.Advancing >= smallest {
+			return fmt.Errorf("at most %d teams can advance from group", smallest-1)
+		}
+	}
+	return nil
+}
+
+// Start validates rules and teams, creates standings and generates first round of tournament
+func Start(t *DR.Tournament, teams []string) error {
+	ApplyDefaults(t)
+	if err := ValidateRules(*t); err != nil {
+		return err
+	}
+	if err := validateTeams(*t, teams); err != nil {
+		return err
+	}
+	if len(t.Rounds) != 0 {
+		return ErrAlreadyStarted
+	}
+	t.Rounds = []DR.Round{*nextRound(*t, teams)}
+	t.Standings = standings(*t, teams)
+	return nil
+}
+
+// ValidateRound checks that round has the same pairings as current round of tournament and valid results for all of them
+func ValidateRound(t DR.Tournament, round DR.Round) error {
+	if len(t.Rounds) == 0 {
+		return ErrNotStarted
+	}
+	current := t.Rounds[len(t.Rounds)-1]
+	if len(round.Pairing) != len(current.Pairing) {
+		return ErrRoundMismatch
+	}
+	for i, p := range round.Pairing {
+		c := current.Pairing[i]
+		if p.TeamOne != c.TeamOne || p.TeamTwo != c.TeamTwo {
+			return ErrRoundMismatch
+		}
+		if c.Bye {
+			continue
+		}
+		one, two, err := ParseScore(p.Score)
+		if err != nil {
+			return err
+		}
+		if isElimination(c.Stage) && one == two {
+			return ErrDraw
+		}
+	}
+	return nil
+}
+
+// Submit stores results of current round, generates next round if tournament isn't over and recalculates standings
+func Submit(t *DR.Tournament, teams []string, round DR.Round) error {
+	ApplyDefaults(t)
+	if IsOver(*t, teams) {
+		return ErrOver
+	}
+	if err := ValidateRound(*t, round); err != nil {
+		return err
+	}
+	current := &t.Rounds[len(t.Rounds)-1]
+	for i := range current.Pairing {
+		if !current.Pairing[i].Bye {
+			current.Pairing[i].Score = strings.TrimSpace(round.Pairing[i].Score)
+		}
+	}
+	if next := nextRound(*t, teams); next != nil {
+		t.Rounds = append(t.Rounds, *next)
+	}
+	t.Standings = standings(*t, teams)
+	return nil
+}
+
+// IsOver returns true when all rounds are played and there is no next round
+func IsOver(t DR.Tournament, teams []string) bool {
+	if len(t.Rounds) == 0 || !isScored(t.Rounds[len(t.Rounds)-1]) {
+		return false
+	}
+	ApplyDefaults(&t)
+	return nextRound(t, teams) == nil
+}
+
+// ParseScore parses headline score of result, like 3:1 or 2:1 (6:4 3:6 7:5), see DR.Score
+func ParseScore(score string) (int, int, error) {
+	s, err := DR.ParseScore(score)
+	switch err {
+	case nil:
+		return s.One, s.Two, nil
+	case DR.ErrScoreMissing:
+		return 0, 0, ErrResultMissing
+	}
+	return 0, 0, ErrResultFormat
+}
+
+// ValidateScores checks submitted results of round against scoring of sport of tournament, results that
+// can't be parsed are reported by ValidateRound
+func ValidateScores(round DR.Round, scoring DR.Scoring) error {
+	for _, p := range round.Pairing {
+		s, err := DR.ParseScore(p.Score)
+		if err != nil {
+			continue
+		}
+		if err = scoring.Validate(s); err != nil {
+			return fmt.Errorf("result %s of %s against %s isn't valid: %w", s, p.TeamOne, p.TeamTwo, err)
+		}
+	}
+	return nil
+}
+
+// nextRound generates round that follows last round of tournament, it returns nil when tournament is over
+func nextRound(t DR.Tournament, teams []string) *DR.Round {
+	switch t.Format {
+	case DR.TF_SINGLE_ELIMINATION:
+		return singleElimination(teams, t.Rounds)
+	case DR.TF_DOUBLE_ELIMINATION:
+		return doubleElimination(teams, t.Rounds)
+	case DR.TF_GROUPS_KNOCKOUT:
+		gs := groups(teams, t.Groups)
+		gr := groupRounds(gs)
+		if len(t.Rounds) < gr {
+			return groupRound(gs, len(t.Rounds))
+		}
+		return singleElimination(qualifiers(t, teams, gs), t.Rounds[gr:])
+	default:
+		k := len(t.Rounds)
+		if k >= roundRobinRounds(len(teams)) {
+			return nil
+		}
+		return &DR.Round{Pairing: circleRound(teams, k)}
+	}
+}
+
+// isElimination returns true for stages in which loser of game drops out or down, so games can't end in draw
+func isElimination(stage DR.BracketStage) bool {
+	return stage != "" && stage != DR.BS_GROUP
+}
+
+func isScored(round DR.Round) bool {
+	for _, p := range round.Pairing {
+		if p.Bye {
+			continue
+		}
+		if _, _, err := ParseScore(p.Score); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// winner returns winner and loser of scored pairing, loser is empty for bye and draw
+func winner(p DR.Pairing) (string, string) {
+	if p.Bye {
+		return p.TeamOne, ""
+	}
+	one, two, err := ParseScore(p.Score)
+	if err != nil || one == two {
+		return "", ""
+	}
+	if one > two {
+		return p.TeamOne, p.TeamTwo
+	}
+	return p.TeamTwo, p.TeamOne
+}
@@ -0,0 +1,275 @@
+package tournament_test
+
+import (
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/results"
+	"backend/sportos/tournament"
+	"fmt"
+	"testing"
+)
+
+// play starts tournament and submits rounds with scores returned by score until tournament is over
+func play(t *testing.T, rules DR.Tournament, teams []string, score func(DR.Pairing) string) DR.Tournament {
+	t.Helper()
+	if err := tournament.Start(&rules, teams); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for i := 0; !tournament.IsOver(rules, teams); i++ {
+		if i > 2*len(teams)+2 {
+			t.Fatalf("tournament isn't over after %d rounds: %+v", i, rules.Rounds)
+		}
+		round := DR.Round{Pairing: append([]DR.Pairing{}, rules.Rounds[len(rules.Rounds)-1].Pairing...)}
+		for j := range round.Pairing {
+			if !round.Pairing[j].Bye {
+				round.Pairing[j].Score = score(round.Pairing[j])
+			}
+		}
+		if err := tournament.Submit(&rules, teams, round); err != nil {
+			t.Fatalf("submit round %d: %v", i, err)
+		}
+	}
+	return rules
+}
+
+// betterSeedWins returns score function in which team that is earlier in teams wins
+func betterSeedWins(teams []string) func(DR.Pairing) string {
+	seed := map[string]int{}
+	for i, team := range teams {
+		seed[team] = i
+	}
+	return func(p DR.Pairing) string {
+		if seed[p.TeamOne] < seed[p.TeamTwo] {
+			return "1:0"
+		}
+		return "0:1"
+	}
+}
+
+func ranking(standings []DR.Standing) []string {
+	names := []string{}
+	for _, s := range standings {
+		names = append(names, s.TeamName)
+	}
+	return names
+}
+
+func TestRoundRobinEveryPairMeetsOnce(t *testing.T) {
+	teams := []string{"A", "B", "C", "D", "E"}
+	res := play(t, DR.Tournament{}, teams, betterSeedWins(teams))
+
+	if len(res.Rounds) != 5 {
+		t.Fatalf("expected 5 rounds, got %d", len(res.Rounds))
+	}
+	games, byes := map[string]int{}, map[string]int{}
+	for _, round := range res.Rounds {
+		for _, p := range round.Pairing {
+			if p.Bye {
+				byes[p.TeamOne]++
+				continue
+			}
+			one, two := p.TeamOne, p.TeamTwo
+			if one > two {
+				one, two = two, one
+			}
+			games[one+two]++
+		}
+	}
+	for i := range teams {
+		if byes[teams[i]] != 1 {
+			t.Errorf("team %s: expected 1 bye, got %d", teams[i], byes[teams[i]])
+		}
+		for j := i + 1; j < len(teams); j++ {
+			if games[teams[i]+teams[j]] != 1 {
+				t.Errorf("teams %s and %s: expected 1 game, got %d", teams[i], teams[j], games[teams[i]+teams[j]])
+			}
+		}
+	}
+	if fmt.Sprint(ranking(res.Standings)) != fmt.Sprint(teams) {
+		t.Errorf("expected standings %v, got %v", teams, ranking(res.Standings))
+	}
+	if *res.Standings[0].Points != 12 || res.Standings[0].Played != 4 {
+		t.Errorf("expected leader with 12 points from 4 games, got %+v", res.Standings[0])
+	}
+}
+
+func TestTieBreakersAreAppliedInOrder(t *testing.T) {
+	teams := []string{"A", "B", "C", "D"}
+	scores := map[string]string{
+		"AB": "1:0", "AC": "0:1", "AD": "1:0",
+		"BC": "5:0", "BD": "5:0", "CD": "0:1",
+	}
+	score := func(p DR.Pairing) string {
+		if s, ok := scores[p.TeamOne+p.TeamTwo]; ok {
+			return s
+		}
+		var one, two int
+		fmt.Sscanf(scores[p.TeamTwo+p.TeamOne], "%d:%d", &one, &two)
+		return fmt.Sprintf("%d:%d", two, one)
+	}
+
+	res := play(t, DR.Tournament{TieBreakers: []DR.TieBreaker{DR.TB_HEAD_TO_HEAD, DR.TB_SCORE_DIFFERENCE}}, teams, score)
+	if got := ranking(res.Standings); got[0] != "A" || got[1] != "B" {
+		t.Errorf("expected A before B by head to head, got %v", got)
+	}
+	res = play(t, DR.Tournament{TieBreakers: []DR.TieBreaker{DR.TB_SCORE_DIFFERENCE, DR.TB_HEAD_TO_HEAD}}, teams, score)
+	if got := ranking(res.Standings); got[0] != "B" || got[1] != "A" {
+		t.Errorf("expected B before A by score difference, got %v", got)
+	}
+
+	res = play(t, DR.Tournament{Points: &DR.PointsRule{Win: 2, Draw: 1}}, teams, score)
+	if *res.Standings[0].Points != 4 {
+		t.Errorf("expected leader with 4 points for 2 wins, got %+v", res.Standings[0])
+	}
+}
+
+func TestSingleEliminationWithByes(t *testing.T) {
+	teams := []string{"A", "B", "C", "D", "E", "F"}
+	res := play(t, DR.Tournament{Format: DR.TF_SINGLE_ELIMINATION}, teams, betterSeedWins(teams))
+
+	byes := []string{}
+	for _, p := range res.Rounds[0].Pairing {
+		if p.Bye {
+			byes = append(byes, p.TeamOne)
+		}
+	}
+	if fmt.Sprint(byes) != "[A B]" {
+		t.Errorf("expected byes for A and B, got %v", byes)
+	}
+	if len(res.Rounds) != 3 {
+		t.Errorf("expected 3 rounds, got %d", len(res.Rounds))
+	}
+	if got := ranking(res.Standings); got[0] != "A" || got[1] != "B" {
+		t.Errorf("expected A to win final against B, got %v", got)
+	}
+}
+
+func TestDoubleEliminationBracketReset(t *testing.T) {
+	teams := []string{"A", "B", "C", "D"}
+	finals := 0
+	better := betterSeedWins(teams)
+	res := play(t, DR.Tournament{Format: DR.TF_DOUBLE_ELIMINATION}, teams, func(p DR.Pairing) string {
+		if p.Stage == DR.BS_FINAL {
+			finals++
+			// team from losers bracket wins first final, so final is played again
+			if finals == 1 {
+				return "0:1"
+			}
+		}
+		return better(p)
+	})
+
+	if finals != 2 {
+		t.Errorf("expected final to be played twice, got %d", finals)
+	}
+	if got := fmt.Sprint(ranking(res.Standings)); got != "[A B C D]" {
+		t.Errorf("expected standings [A B C D], got %s", got)
+	}
+	if err := tournament.Submit(&res, teams, res.Rounds[len(res.Rounds)-1]); err != tournament.ErrOver {
+		t.Errorf("expected %v, got %v", tournament.ErrOver, err)
+	}
+}
+
+func TestGroupsKnockoutQualifiers(t *testing.T) {
+	teams := []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+	rules := DR.Tournament{Format: DR.TF_GROUPS_KNOCKOUT, Groups: 2}
+	res := play(t, rules, teams, betterSeedWins(teams))
+
+	// groups are A D E H and B C F G, A B D C advance
+	knockout := res.Rounds[3].Pairing
+	if len(knockout) != 2 || knockout[0].Stage != DR.BS_KNOCKOUT ||
+		knockout[0].TeamOne != "A" || knockout[0].TeamTwo != "C" ||
+		knockout[1].TeamOne != "B" || knockout[1].TeamTwo != "D" {
+		t.Errorf("expected semifinals A-C and B-D, got %+v", knockout)
+	}
+	if res.Standings[0].TeamName != "A" || res.Standings[0].Group != "A" || *res.Standings[0].GroupRanking != 1 {
+		t.Errorf("expected A to win group and tournament, got %+v", res.Standings[0])
+	}
+	if got := ranking(res.Standings)[4:6]; fmt.Sprint(got) != "[E F]" {
+		t.Errorf("expected third placed teams E and F after knockout teams, got %v", got)
+	}
+}
+
+func TestValidateRound(t *testing.T) {
+	teams := []string{"A", "B"}
+	rules := DR.Tournament{Format: DR.TF_SINGLE_ELIMINATION}
+	if err := tournament.Start(&rules, teams); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	round := DR.Round{Pairing: []DR.Pairing{{TeamOne: "A", TeamTwo: "B", Score: "1:1"}}}
+	if err := tournament.ValidateRound(rules, round); err != tournament.ErrDraw {
+		t.Errorf("expected %v, got %v", tournament.ErrDraw, err)
+	}
+	round.Pairing[0].Score = "1-0"
+	if err := tournament.ValidateRound(rules, round); err != tournament.ErrResultFormat {
+		t.Errorf("expected %v, got %v", tournament.ErrResultFormat, err)
+	}
+	round.Pairing[0].TeamTwo = "C"
+	if err := tournament.ValidateRound(rules, round); err != tournament.ErrRoundMismatch {
+		t.Errorf("expected %v, got %v", tournament.ErrRoundMismatch, err)
+	}
+	if err := tournament.Start(&DR.Tournament{Format: DR.TF_GROUPS_KNOCKOUT, Groups: 2}, []string{"A", "B", "C"}); err == nil {
+		t.Errorf("expected error for groups with one team")
+	}
+}
+
+func TestScoresWithSetsAndPeriods(t *testing.T) {
+	tennis, _ := DR.GetSportByName("Tennis")
+	basketball, _ := DR.GetSportByName("Basketball")
+	football, _ := DR.GetSportByName("Football")
+	volleyball, _ := DR.GetSportByName("Volleyball")
+	tableTennis, _ := DR.GetSportByName("Table tennis")
+	cases := []struct {
+		scoring DR.Scoring
+		score   string
+		valid   bool
+	}{
+		{tennis.Scoring, "2:1", true},
+		{tennis.Scoring, "2:1 (6:4, 3:6, 7:5)", true},
+		{tennis.Scoring, "2:1 (6:4 6:3)", false},
+		{tennis.Scoring, "3:0", false},
+		{tennis.Scoring, "2:1 (6:4 6:6 7:5)", false},
+		{tennis.Scoring, "0:2 WO", true},
+		{tennis.Scoring, "1:0 FF", false},
+		{tennis.Scoring, "1:1 (6:4 3:6)", false},
+		{volleyball.Scoring, "1:0 (6:4)", false},
+		{volleyball.Scoring, "3:1 (25:20 23:25 25:18 25:22)", true},
+		{volleyball.Scoring, "0:3 FF", true},
+		{tableTennis.Scoring, "2:1", false},
+		{tableTennis.Scoring, "2:3", true},
+		{basketball.Scoring, "98:95 (20:18 25:22 19:24 21:21 OT 13:10)", true},
+		{basketball.Scoring, "85:80 (20:18 25:22 19:24 21:16 OT 0:0)", false},
+		{basketball.Scoring, "85:80 (45:40 40:40)", false},
+		{basketball.Scoring, "20:0 FF", true},
+		{football.Scoring, "1:1 (0:1 1:0)", true},
+		{football.Scoring, "2:0 WO", false},
+	}
+	for _, c := range cases {
+		round := DR.Round{Pairing: []DR.Pairing{{TeamOne: "A", TeamTwo: "B", Score: c.score}}}
+		if err := tournament.ValidateScores(round, c.scoring); (err == nil) != c.valid {
+			t.Errorf("%s in %s sport: expected valid %v, got %v", c.score, c.scoring.Kind, c.valid, err)
+		}
+	}
+
+	one, two, err := tournament.ParseScore("2:1 (6:4 3:6 7:5)")
+	if err != nil || one != 2 || two != 1 {
+		t.Errorf("expected sets 2:1 as result of tennis match, got %d:%d, %v", one, two, err)
+	}
+	if _, _, err := tournament.ParseScore("2:1 (6:4 3:6"); err != tournament.ErrResultFormat {
+		t.Errorf("expected %v for unclosed sets, got %v", tournament.ErrResultFormat, err)
+	}
+	score, err := DR.ParseScore(" 98:95 (20:18, 25:22, 19:24, 21:21 ot 13:10) ")
+	if err != nil || score.String() != "98:95 (20:18 25:22 19:24 21:21 OT 13:10)" {
+		t.Errorf("expected canonical score with overtime, got %q, %v", score.String(), err)
+	}
+	if _, err := basketball.Scoring.Winner(DR.Score{One: 80, Two: 80}); err != DR.ErrScoreDraw {
+		t.Errorf("expected %v for basketball draw, got %v", DR.ErrScoreDraw, err)
+	}
+	for _, c := range []struct {
+		sport  string
+		result string
+	}{{"Volleyball", "1:0 (6:4)"}, {"Table tennis", "2:1"}} {
+		if _, err := results.Validate(c.sport, c.result); err == nil {
+			t.Errorf("expected unfinished %s result %s to be rejected", c.sport, c.result)
+		}
+	}
+}
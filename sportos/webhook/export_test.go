@@ -0,0 +1,11 @@
+package webhook
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewLoopbackClient returns worker client that can reach test servers on loopback
+func NewLoopbackClient(timeout time.Duration) *http.Client {
+	return newClient(timeout, nil)
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+var ErrPrivateTarget = errors.New("webhook can't target loopback, private or link local address")
+
+// carrier grade NAT range, net.IP.IsPrivate doesn't cover it
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// CheckUrl checks that url is absolute http(s) url and that all addresses of its host are public,
+// so webhook can't be used to read internal services
+func CheckUrl(ctx context.Context, rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("url must be absolute http or https url")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		return checkIP(ip)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("host %s can't be resolved", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || sharedAddressSpace.Contains(ip) {
+		return ErrPrivateTarget
+	}
+	return nil
+}
+
+// dialPublic is net.Dialer Control that refuses connections to addresses rejected by CheckUrl,
+// host could resolve to other address when delivery is sent than when webhook was saved
+func dialPublic(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("dialed address %s isn't ip", address)
+	}
+	return checkIP(ip)
+}
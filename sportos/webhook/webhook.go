@@ -0,0 +1,143 @@
+// Package webhook delivers domain events of places to webhooks of places and integrators.
+//
+// Dispatcher subscriber Enqueue stores one delivery for every active webhook of place that accepts event,
+// Worker sends pending deliveries as json POST signed with HMAC-SHA256 of webhook secret and retries
+// failed deliveries with exponential backoff. Request and response of last attempt are kept on delivery.
+//
+// Signature covers unix time of sending from X-Sportos-Timestamp and body, joined with ".". Receivers should
+// compute it the same way (see Verify) and reject requests whose timestamp is more than SIGNATURE_TOLERANCE
+// from their clock, so captured delivery can't be replayed later. Every attempt is signed with new timestamp,
+// retries of the same delivery have the same X-Sportos-Delivery id and can be skipped by receivers.
+package webhook
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/events"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Headers of delivery request
+const (
+	HEADER_SIGNATURE = "X-Sportos-Signature"
+	HEADER_EVENT     = "X-Sportos-Event"
+	HEADER_DELIVERY  = "X-Sportos-Delivery"
+	HEADER_TIMESTAMP = "X-Sportos-Timestamp"
+)
+
+// SIGNATURE_TOLERANCE is how far from now timestamp of signed request can be for Verify
+const SIGNATURE_TOLERANCE = 5 * time.Minute
+
+var (
+	ErrSignatureMismatch = errors.New("webhook signature doesn't match")
+	ErrSignatureExpired  = errors.New("webhook timestamp is outside of tolerance")
+)
+
+// Config of webhook worker, filled from flags
+type Config struct {
+	Interval    time.Duration
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+var config = Config{Interval: DEFAULT_WORKER_INTERVAL, MaxAttempts: DEFAULT_WORKER_MAX_ATTEMPTS, Timeout: DEFAULT_TIMEOUT}
+
+// Init sets config used by workers created after it
+func Init(c Config) {
+	config = c
+	L.L.Info("webhook params", L.Duration("webhook.interval", c.Interval), L.Int("webhook.attempts", c.MaxAttempts), L.Duration("webhook.timeout", c.Timeout))
+}
+
+// Body is json sent to webhook, EventId is the same for all retries so receiver can skip duplicates
+type Body struct {
+	EventId   string             `json:"eventId"`
+	Type      DR.OutboxEventType `json:"type"`
+	CreatedAt time.Time          `json:"createdAt"`
+	Data      DR.OutboxPayload   `json:"data"`
+}
+
+// Subscribe registers Enqueue on dispatcher for all webhook events
+func Subscribe(d *events.Dispatcher) {
+	for _, eventType := range DR.WebhookEvents {
+		d.Subscribe(eventType, Enqueue)
+	}
+}
+
+// Enqueue creates delivery of event for every webhook of place from event payload
+func Enqueue(ctx context.Context, Repo *crud.Repo, qa crud.QueryAble, oe DR.OutboxEvent) error {
+	var place struct {
+		PlaceId string `json:"placeId"`
+	}
+	if err := json.Unmarshal(oe.Payload, &place); err != nil {
+		return err
+	}
+	if place.PlaceId == "" {
+		return nil
+	}
+	active := true
+	webhooks, err := Repo.WebhookCrud.Search(ctx, DR.WebhookSearchParams{PlaceId: &place.PlaceId, Active: &active, EventType: &oe.Type}, qa)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(Body{EventId: oe.OutboxEventId, Type: oe.Type, CreatedAt: oe.CreatedAt, Data: oe.Payload})
+	if err != nil {
+		return err
+	}
+	for _, wh := range webhooks {
+		_, err := Repo.WebhookDeliveryCrud.Create(ctx, DR.WebhookDelivery{
+			WebhookId:     wh.WebhookId,
+			OutboxEventId: oe.OutboxEventId,
+			EventType:     oe.Type,
+			Request:       string(body),
+		}, qa, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sign returns value of signature header, it is hex encoded HMAC-SHA256 of timestamp header value,
+// "." and body with prefix sha256=
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and timestamp headers of delivery received at now, it is what receivers should do
+func Verify(secret, signature, timestamp string, body []byte, now time.Time) error {
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+		return ErrSignatureMismatch
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > SIGNATURE_TOLERANCE || d < -SIGNATURE_TOLERANCE {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// NewSecret returns random secret for webhook that was created without one
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
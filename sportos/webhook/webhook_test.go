@@ -0,0 +1,233 @@
+package webhook_test
+
+import (
+	L "backend/internal/logging"
+	"backend/sportos/events"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"backend/sportos/repo/memory"
+	"backend/sportos/webhook"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	L.Init()
+	os.Exit(m.Run())
+}
+
+func TestSignedDeliveryIsRetried(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+
+	type received struct {
+		body      []byte
+		signature string
+		timestamp string
+		delivery  string
+		event     string
+	}
+	var requests []received
+	status := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, received{body, r.Header.Get(webhook.HEADER_SIGNATURE), r.Header.Get(webhook.HEADER_TIMESTAMP),
+			r.Header.Get(webhook.HEADER_DELIVERY), r.Header.Get(webhook.HEADER_EVENT)})
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	wh, err := Repo.WebhookCrud.Create(ctx, DR.Webhook{PlaceId: "arena", Url: srv.URL, Secret: "s3cret", Events: []string{string(DR.OE_MATCH_BOOKED)}, Active: true}, nil, nil)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	// webhook of other place and event filtered out by webhook don't create deliveries
+	Repo.WebhookCrud.Create(ctx, DR.Webhook{PlaceId: "other", Url: srv.URL, Secret: "x", Active: true}, nil, nil)
+	start := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	events.Publish(ctx, Repo, nil, events.MatchBooked{MatchId: "m1", PlaceId: "arena", Sport: "Tennis", StartTime: start, EndTime: start.Add(time.Hour)})
+	events.Publish(ctx, Repo, nil, events.MatchCancelled{MatchId: "m1", PlaceId: "arena"})
+
+	d := events.NewDispatcher(Repo)
+	webhook.Subscribe(d)
+	if n, err := d.ProcessPending(ctx); err != nil || n != 2 {
+		t.Fatalf("expected 2 dispatched events, got %d, %v", n, err)
+	}
+	deliveries, _ := Repo.WebhookDeliveryCrud.Search(ctx, DR.WebhookDeliverySearchParams{}, nil)
+	if len(deliveries) != 1 || deliveries[0].WebhookId != wh.WebhookId || deliveries[0].EventType != DR.OE_MATCH_BOOKED {
+		t.Fatalf("expected one match booked delivery, got %+v", deliveries)
+	}
+
+	w := webhook.NewWorker(Repo)
+	w.Client = webhook.NewLoopbackClient(time.Second)
+	if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected failed delivery, got %d, %v", n, err)
+	}
+	wd, _ := Repo.WebhookDeliveryCrud.GetById(ctx, deliveries[0].DeliveryId, nil)
+	if wd.Status != DR.WDS_PENDING || wd.Attempts != 1 || wd.ResponseCode == nil || *wd.ResponseCode != 500 || !wd.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("expected pending delivery with backoff after 500, got %+v", wd)
+	}
+
+	status = http.StatusOK
+	due := time.Now().Add(-time.Second)
+	Repo.WebhookDeliveryCrud.Update(ctx, DR.WebhookDeliveryUpdateParams{Id: wd.DeliveryId, NextAttemptAt: &due}, nil, nil)
+	if n, err := w.ProcessPending(ctx); err != nil || n != 1 {
+		t.Fatalf("expected delivered webhook, got %d, %v", n, err)
+	}
+	wd, _ = Repo.WebhookDeliveryCrud.GetById(ctx, wd.DeliveryId, nil)
+	if wd.Status != DR.WDS_DELIVERED || wd.Attempts != 2 || wd.DeliveredAt == nil || wd.Response == nil || *wd.Response != "ok" {
+		t.Errorf("expected delivered delivery, got %+v", wd)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	last := requests[1]
+	if err := webhook.Verify("s3cret", last.signature, last.timestamp, last.body, time.Now()); err != nil ||
+		last.event != string(DR.OE_MATCH_BOOKED) || last.delivery != wd.DeliveryId || last.delivery != requests[0].delivery {
+		t.Errorf("unexpected headers %q %q %q %q: %v", last.signature, last.timestamp, last.delivery, last.event, err)
+	}
+	var body struct {
+		Type DR.OutboxEventType `json:"type"`
+		Data events.MatchBooked `json:"data"`
+	}
+	if err := json.Unmarshal(last.body, &body); err != nil || body.Type != DR.OE_MATCH_BOOKED || body.Data.MatchId != "m1" || !body.Data.StartTime.Equal(start) {
+		t.Errorf("unexpected body %s, %v", last.body, err)
+	}
+}
+
+func TestDeliveryOfDisabledWebhookFails(t *testing.T) {
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	wh, _ := Repo.WebhookCrud.Create(ctx, DR.Webhook{PlaceId: "arena", Url: "http://127.0.0.1:1", Secret: "s", Active: true}, nil, nil)
+	events.Publish(ctx, Repo, nil, events.MatchCancelled{MatchId: "m1", PlaceId: "arena"})
+	d := events.NewDispatcher(Repo)
+	webhook.Subscribe(d)
+	d.ProcessPending(ctx)
+
+	inactive := false
+	Repo.WebhookCrud.Update(ctx, DR.WebhookUpdateParams{Id: wh.WebhookId, Active: &inactive}, nil, nil)
+	if n, err := webhook.NewWorker(Repo).ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no delivered webhook, got %d, %v", n, err)
+	}
+	deliveries, _ := Repo.WebhookDeliveryCrud.Search(ctx, DR.WebhookDeliverySearchParams{}, nil)
+	if len(deliveries) != 1 || deliveries[0].Status != DR.WDS_FAILED || deliveries[0].Attempts != 1 {
+		t.Errorf("expected failed delivery of disabled webhook, got %+v", deliveries)
+	}
+}
+
+func TestCheckUrl(t *testing.T) {
+	cases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://93.184.216.34/hooks", true},
+		{"http://[2606:2800:220:1:248:1893:25c8:1946]:8080/hooks", true},
+		{"ftp://93.184.216.34/hooks", false},
+		{"/hooks", false},
+		{"http://127.0.0.1:8080/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://10.1.2.3/hooks", false},
+		{"http://192.168.0.10/hooks", false},
+		{"http://172.16.5.4/hooks", false},
+		{"http://100.64.0.1/hooks", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://0.0.0.0/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+	}
+	for _, c := range cases {
+		if err := webhook.CheckUrl(context.Background(), c.url); (err == nil) != c.valid {
+			t.Errorf("%s: expected valid %v, got %v", c.url, c.valid, err)
+		}
+	}
+}
+
+// deliver publishes match cancelled event of place arena and dispatches it to webhook with url
+func deliver(t *testing.T, url string) *crud.Repo {
+	t.Helper()
+	ctx := context.Background()
+	Repo := memory.InitRepo()
+	if _, err := Repo.WebhookCrud.Create(ctx, DR.Webhook{PlaceId: "arena", Url: url, Secret: "s", Active: true}, nil, nil); err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	events.Publish(ctx, Repo, nil, events.MatchCancelled{MatchId: "m1", PlaceId: "arena"})
+	d := events.NewDispatcher(Repo)
+	webhook.Subscribe(d)
+	if n, err := d.ProcessPending(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 dispatched event, got %d, %v", n, err)
+	}
+	return Repo
+}
+
+func TestWorkerDoesNotReachPrivateTargets(t *testing.T) {
+	ctx := context.Background()
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	Repo := deliver(t, srv.URL)
+	if n, err := webhook.NewWorker(Repo).ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected no delivered webhook, got %d, %v", n, err)
+	}
+	deliveries, _ := Repo.WebhookDeliveryCrud.Search(ctx, DR.WebhookDeliverySearchParams{}, nil)
+	if requests != 0 || len(deliveries) != 1 || deliveries[0].LastError == nil ||
+		!strings.Contains(*deliveries[0].LastError, webhook.ErrPrivateTarget.Error()) {
+		t.Errorf("expected delivery to loopback to be refused, got %d requests and %+v", requests, deliveries)
+	}
+}
+
+func TestWorkerDoesNotFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal secret"))
+	}))
+	defer internal.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	Repo := deliver(t, redirect.URL)
+	w := webhook.NewWorker(Repo)
+	w.Client = webhook.NewLoopbackClient(time.Second)
+	if n, err := w.ProcessPending(ctx); err != nil || n != 0 {
+		t.Fatalf("expected redirect to fail delivery, got %d, %v", n, err)
+	}
+	deliveries, _ := Repo.WebhookDeliveryCrud.Search(ctx, DR.WebhookDeliverySearchParams{}, nil)
+	if len(deliveries) != 1 || deliveries[0].ResponseCode == nil || *deliveries[0].ResponseCode != http.StatusFound ||
+		(deliveries[0].Response != nil && strings.Contains(*deliveries[0].Response, "internal secret")) {
+		t.Errorf("expected 302 kept on delivery without following it, got %+v", deliveries)
+	}
+}
+
+func TestVerifyRejectsReplayedDelivery(t *testing.T) {
+	body := []byte(`{"eventId":"1"}`)
+	sent := time.Date(2030, 1, 1, 10, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(sent.Unix(), 10)
+	signature := webhook.Sign("s3cret", timestamp, body)
+
+	if err := webhook.Verify("s3cret", signature, timestamp, body, sent.Add(time.Minute)); err != nil {
+		t.Errorf("expected delivery received within tolerance to verify, got %v", err)
+	}
+	if err := webhook.Verify("s3cret", signature, timestamp, body, sent.Add(webhook.SIGNATURE_TOLERANCE+time.Second)); err != webhook.ErrSignatureExpired {
+		t.Errorf("expected %v for replayed delivery, got %v", webhook.ErrSignatureExpired, err)
+	}
+	// timestamp can't be moved without changing signature
+	fresh := strconv.FormatInt(sent.Add(time.Hour).Unix(), 10)
+	if err := webhook.Verify("s3cret", signature, fresh, body, sent.Add(time.Hour)); err != webhook.ErrSignatureMismatch {
+		t.Errorf("expected %v for changed timestamp, got %v", webhook.ErrSignatureMismatch, err)
+	}
+	if err := webhook.Verify("other", signature, timestamp, body, sent); err != webhook.ErrSignatureMismatch {
+		t.Errorf("expected %v for other secret, got %v", webhook.ErrSignatureMismatch, err)
+	}
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	L "backend/internal/logging"
+	"backend/internal/periodic"
+	"backend/sportos/repo/crud"
+	DR "backend/sportos/repo/dto"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	DEFAULT_WORKER_INTERVAL     = 5 * time.Second
+	DEFAULT_WORKER_MAX_ATTEMPTS = 10
+	DEFAULT_TIMEOUT             = 10 * time.Second
+	workerBatchSize             = 20
+	workerBackoff               = 30 * time.Second
+	workerMaxBackoff            = 6 * time.Hour
+	// workerClaimMargin is added to time needed to send claimed batch before other worker can claim it
+	workerClaimMargin = time.Minute
+	// maxResponseSize is how much of response body is kept in delivery log
+	maxResponseSize = 4096
+)
+
+// Worker periodically sends pending deliveries to webhooks,
+// failed deliveries are retried with exponential backoff until MaxAttempts is reached
+type Worker struct {
+	Repo        *crud.Repo
+	Interval    time.Duration
+	MaxAttempts int
+	Client      *http.Client
+
+	runner periodic.Runner
+}
+
+// NewWorker creates worker with interval, max attempts and timeout from config
+func NewWorker(Repo *crud.Repo) *Worker {
+	interval := config.Interval
+	maxAttempts := config.MaxAttempts
+	timeout := config.Timeout
+	if interval <= 0 {
+		interval = DEFAULT_WORKER_INTERVAL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = DEFAULT_WORKER_MAX_ATTEMPTS
+	}
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+	return &Worker{
+		Repo:        Repo,
+		Interval:    interval,
+		MaxAttempts: maxAttempts,
+		Client:      newClient(timeout, dialPublic),
+	}
+}
+
+// newClient returns client that checks dialed addresses with control and doesn't follow redirects,
+// redirect to internal service would otherwise be followed and its response kept on delivery
+func newClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: timeout, Control: control}).DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Start runs worker in background until Stop is called
+func (w *Worker) Start() {
+	w.runner.Start("Worker.ProcessPending", w.Interval, w.ProcessPending)
+}
+
+// Stop waits for batch in progress to finish
+func (w *Worker) Stop() {
+	w.runner.Stop()
+}
+
+// ProcessPending claims one batch of due deliveries, sends them and returns number of delivered ones.
+// Deliveries are claimed in short transaction, every delivery is then sent and recorded on its own
+// so no lock is held during requests and recorded sends aren't rolled back by later failure
+func (w *Worker) ProcessPending(ctx context.Context) (int, error) {
+	deliveries, err := w.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, wd := range deliveries {
+		ok, err := w.deliver(ctx, wd)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// claim moves next attempt of due deliveries after time needed to send whole batch, so other workers
+// don't pick them up meanwhile, deliveries of worker that stopped during batch are retried after it
+func (w *Worker) claim(ctx context.Context) ([]DR.WebhookDelivery, error) {
+	tx, err := w.Repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	deliveries, err := w.Repo.WebhookDeliveryCrud.GetPending(ctx, workerBatchSize, tx)
+	if err != nil {
+		return nil, err
+	}
+	timeout := w.Client.Timeout
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+	claimedUntil := time.Now().UTC().Add(time.Duration(len(deliveries))*timeout + workerClaimMargin)
+	for _, wd := range deliveries {
+		up := DR.WebhookDeliveryUpdateParams{Id: wd.DeliveryId, NextAttemptAt: &claimedUntil}
+		if err := w.Repo.WebhookDeliveryCrud.Update(ctx, up, tx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return deliveries, tx.Commit()
+}
+
+// deliver sends claimed delivery and records attempt, it reports whether webhook accepted it
+func (w *Worker) deliver(ctx context.Context, wd DR.WebhookDelivery) (bool, error) {
+	up := DR.WebhookDeliveryUpdateParams{Id: wd.DeliveryId}
+	attempts := wd.Attempts + 1
+	up.Attempts = &attempts
+
+	wh, err := w.Repo.WebhookCrud.GetById(ctx, wd.WebhookId, nil)
+	if err != nil {
+		return false, err
+	}
+	var sendErr error
+	if !wh.Active || wh.IsDeleted() {
+		// deliveries of disabled webhook aren't retried
+		failed := DR.WDS_FAILED
+		up.Status = &failed
+		sendErr = fmt.Errorf("webhook %s is disabled", wh.WebhookId)
+	} else {
+		sendErr = w.send(ctx, wh, wd, &up)
+	}
+	if sendErr != nil {
+		L.L.Warn("Worker send failed", L.String("deliveryId", wd.DeliveryId), L.String("webhookId", wd.WebhookId), L.Int("attempt", attempts), L.Error(sendErr))
+		lastError := sendErr.Error()
+		up.LastError = &lastError
+		if up.Status == nil && attempts >= w.MaxAttempts {
+			failed := DR.WDS_FAILED
+			up.Status = &failed
+		} else if up.Status == nil {
+			next := time.Now().UTC().Add(periodic.Backoff(attempts, workerBackoff, workerMaxBackoff))
+			up.NextAttemptAt = &next
+		}
+	} else {
+		status := DR.WDS_DELIVERED
+		now := time.Now().UTC()
+		up.Status = &status
+		up.DeliveredAt = &now
+	}
+	return sendErr == nil, w.Repo.WebhookDeliveryCrud.Update(ctx, up, nil, nil)
+}
+
+// send posts signed request of delivery to webhook and records response, non 2xx status is error
+func (w *Worker) send(ctx context.Context, wh DR.Webhook, wd DR.WebhookDelivery, up *DR.WebhookDeliveryUpdateParams) error {
+	body := []byte(wd.Request)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HEADER_TIMESTAMP, timestamp)
+	req.Header.Set(HEADER_SIGNATURE, Sign(wh.Secret, timestamp, body))
+	req.Header.Set(HEADER_EVENT, string(wd.EventType))
+	req.Header.Set(HEADER_DELIVERY, wd.DeliveryId)
+
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	response := string(resBody)
+	up.Response = &response
+	up.ResponseCode = &res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}